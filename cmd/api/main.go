@@ -79,23 +79,48 @@ func main() {
 	messageService := services.NewMessageService(db)
 	analyticsService := services.NewAnalyticsService(db)
 	calendarService := services.NewCalendarService(db)
-	applicationService := services.NewApplicationService(db)
+	accountInviteService := services.NewAccountInviteService(db, cfg.Accounts.InviteURL, cfg.Accounts.InviteTTL)
+	applicationService := services.NewApplicationService(db, accountInviteService)
 	examService := services.NewExamService(db)
 	portalService := services.NewPortalService(db)
 	parentService := services.NewParentService(db)
 	assignmentService := services.NewAssignmentService(db)
-	waitlistService := services.NewWaitlistService(db)
+	waitlistService := services.NewWaitlistService(db, cfg.Waitlist.OfferURL, cfg.Waitlist.OfferTTL)
 	bulkService := services.NewBulkService(db)
 	recurringInvoiceService := services.NewRecurringInvoiceService(db)
 	advancedSearchService := services.NewAdvancedSearchService(db)
+	gradingScaleService := services.NewGradingScaleService(db)
+	reportCardService := services.NewReportCardService(db)
+	certificateService := services.NewCertificateService(db)
+	classSessionService := services.NewClassSessionService(db)
+	attendanceAlertService := services.NewAttendanceAlertService(db)
+	roomService := services.NewRoomService(db)
+	timetableSolverService := services.NewTimetableSolverService(db)
+	academicTermService := services.NewAcademicTermService(db)
+	syllabusService := services.NewSyllabusService(db)
+	leadService := services.NewLeadService(db)
+	intakeService := services.NewIntakeService(db, cfg.Intake.FormSecret, cfg.Intake.StatusURL)
+	seatService := services.NewSeatService(db)
+	duplicateService := services.NewDuplicateService(db)
+	trialService := services.NewTrialService(db)
+	calendarFeedService := services.NewCalendarFeedService(db, cfg.Database.Timezone)
+
+	// Exam results gained a unique index on exam and student
+	if err := services.DeduplicateExamResults(db); err != nil {
+		logger.Fatal("failed to remove duplicate exam results", err)
+	}
 
 	// Auto-migrate models
 	err = db.AutoMigrate(
 		&models.Teacher{},
+		&models.TeacherAvailability{},
 		&models.Course{},
 		&models.Student{},
 		&models.Group{},
 		&models.Timetable{},
+		&models.TimetableSlot{},
+		&models.TimetableProposal{},
+		&models.Room{},
 		&models.Attendance{},
 		&models.Grade{},
 		&models.User{},
@@ -111,22 +136,53 @@ func main() {
 		&models.Document{},
 		&models.Message{},
 		&models.Event{},
+		&models.CalendarFeed{},
+		&models.AcademicTerm{},
+		&models.TermBreak{},
+		&models.Holiday{},
+		&models.CoursePrerequisite{},
+		&models.SyllabusModule{},
+		&models.SyllabusLesson{},
+		&models.SessionSyllabusLesson{},
+		&models.Lead{},
+		&models.LeadActivity{},
 		&models.Application{},
+		&models.AccountInvite{},
 		&models.Exam{},
 		&models.ExamResult{},
 		&models.Parent{},
 		&models.ParentStudent{},
 		&models.Assignment{},
 		&models.AssignmentSubmission{},
+		&models.SubmissionAttachment{},
 		&models.Waitlist{},
+		&models.SeatHold{},
+		&models.RecordMerge{},
+		&models.TrialLesson{},
 		&models.RecurringInvoice{},
 		&models.StudentTransfer{},
 		&models.CustomField{},
 		&models.CustomFieldValue{},
+		&models.GradingScale{},
+		&models.GradingScaleVersion{},
+		&models.ReportCard{},
+		&models.CertificateTemplate{},
+		&models.Certificate{},
+		&models.CertificateCounter{},
+		&models.ClassSession{},
+		&models.MakeUpCredit{},
+		&models.AttendanceAlert{},
+		&models.AttendanceAlertSettings{},
 	)
 	if err != nil {
 		logger.Fatal("failed to auto-migrate database models", err)
 	}
+	if err := services.MigrateTimetableSlots(db); err != nil {
+		logger.Fatal("failed to migrate timetables to slots", err)
+	}
+	if err := services.BackfillAssignmentLatePolicy(db); err != nil {
+		logger.Fatal("failed to backfill assignment late policy", err)
+	}
 
 	// Initialize handlers
 	h := handlers.NewHandler(
@@ -158,6 +214,22 @@ func main() {
 		bulkService,
 		recurringInvoiceService,
 		advancedSearchService,
+		gradingScaleService,
+		reportCardService,
+		certificateService,
+		classSessionService,
+		attendanceAlertService,
+		roomService,
+		timetableSolverService,
+		calendarFeedService,
+		academicTermService,
+		syllabusService,
+		leadService,
+		intakeService,
+		seatService,
+		duplicateService,
+		trialService,
+		accountInviteService,
 	)
 
 	// Initialize session handler
@@ -187,6 +259,35 @@ func main() {
 	router.GET("/ready", h.ReadinessProbe)
 	router.GET("/live", h.LivenessProbe)
 
+	// Public certificate verification (no auth required)
+	router.GET("/verify/:code", h.VerifyCertificate)
+
+	// Public calendar feeds; the secret token is the credential
+	router.GET("/calendar/feeds/:token", h.GetCalendarFeed)
+
+	// Public application intake; submissions are limited to 3 per IP, then one every 2 minutes
+	intake := router.Group("/public/applications")
+	{
+		intake.GET("/form-token", h.GetApplicationFormToken)
+		intake.POST("/", middlewares.RateLimit(1.0/120, 3), h.SubmitPublicApplication)
+		intake.GET("/status/:token", h.GetPublicApplicationStatus)
+	}
+
+	// Public waitlist seat offers; the offer token is the credential
+	offers := router.Group("/public/waitlist/offers")
+	{
+		offers.GET("/:token", h.GetWaitlistOffer)
+		offers.POST("/:token/accept", h.AcceptWaitlistOffer)
+		offers.POST("/:token/decline", h.DeclineWaitlistOffer)
+	}
+
+	// Public account invites; the invite token is the credential
+	invites := router.Group("/public/account/invites")
+	{
+		invites.GET("/:token", h.GetAccountInvite)
+		invites.POST("/:token/accept", h.AcceptAccountInvite)
+	}
+
 	// Swagger endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -211,22 +312,42 @@ func main() {
 	// Routes
 	router.GET("/teachers", h.GetAllTeachers)
 	router.POST("/teachers", h.CreateTeacher)
+	router.GET("/teachers/workload", h.GetTeacherWorkloads)
 	router.GET("/teachers/:teacherID", h.GetOneTeacher)
 	router.PUT("/teachers/:teacherID", h.UpdateTeacher)
 	router.DELETE("/teachers/:teacherID", h.DeleteTeacher)
+	router.GET("/teachers/:teacherID/workload", h.GetTeacherWorkload)
 
 	router.GET("/courses", h.GetAllCourses)
 	router.POST("/courses", h.CreateCourse)
 	router.GET("/courses/:courseID", h.GetOneCourse)
 	router.PUT("/courses/:courseID", h.UpdateCourse)
 	router.DELETE("/courses/:courseID", h.DeleteCourse)
+	router.PUT("/courses/:courseID/grading-scale", h.AssignCourseGradingScale)
+	router.GET("/courses/:courseID/syllabus", h.GetCourseSyllabus)
+	router.PUT("/courses/:courseID/syllabus", h.UpdateCourseSyllabus)
 
 	router.GET("/timetables", h.GetAllTimetables)
+	router.POST("/timetables/proposals", h.CreateTimetableProposal)
+	router.GET("/timetables/proposals/:proposalID", h.GetTimetableProposal)
+	router.POST("/timetables/proposals/:proposalID/commit", h.CommitTimetableProposal)
+	router.DELETE("/timetables/proposals/:proposalID", h.DeleteTimetableProposal)
 	router.POST("/timetables", h.CreateTimetable)
 	router.GET("/timetables/:timetableID", h.GetOneTimetable)
 	router.PUT("/timetables/:timetableID", h.UpdateTimetable)
 	router.DELETE("/timetables/:timetableID", h.DeleteTimetable)
 
+	// Rooms
+	rooms := router.Group("/rooms")
+	{
+		rooms.POST("/", h.CreateRoom)
+		rooms.GET("/", h.GetAllRooms)
+		rooms.GET("/utilization", h.GetRoomUtilization)
+		rooms.GET("/:roomID", h.GetRoom)
+		rooms.PUT("/:roomID", h.UpdateRoom)
+		rooms.DELETE("/:roomID", h.DeleteRoom)
+	}
+
 	// Global students endpoint
 	router.GET("/students", h.GetAllStudentsGlobal)
 
@@ -242,10 +363,25 @@ func main() {
 		groups.POST("/:groupID/attendance", h.MarkAttendance)
 		groups.POST("/:groupID/attendance/batch", h.BatchMarkAttendance)
 		groups.GET("/:groupID/attendance", h.GetGroupAttendance)
+		groups.GET("/:groupID/attendance/sheet", h.GetAttendanceSheet)
+		groups.GET("/:groupID/attendance-alerts", h.GetGroupAttendanceAlerts)
+
+		// Class session routes for group
+		groups.POST("/:groupID/sessions/generate", h.GenerateClassSessions)
+		groups.GET("/:groupID/sessions", h.GetGroupClassSessions)
+		groups.POST("/:groupID/sessions/cancel", h.CancelGroupClassSessions)
+		groups.POST("/:groupID/sessions/reschedule", h.RescheduleGroupClassSessions)
 
 		// Grade routes for group
 		groups.POST("/:groupID/grades", h.CreateGrade)
 		groups.GET("/:groupID/grades", h.GetGroupGrades)
+		groups.GET("/:groupID/syllabus", h.GetGroupSyllabusProgress)
+
+		// Report card and transcript routes for group
+		groups.POST("/:groupID/report-cards", h.GenerateGroupReportCards)
+		groups.GET("/:groupID/report-cards", h.GetGroupReportCards)
+		groups.POST("/:groupID/transcripts", h.GenerateGroupTranscripts)
+		groups.POST("/:groupID/certificates", h.IssueGroupCertificates)
 
 		students := groups.Group("/:groupID/students")
 		{
@@ -259,8 +395,28 @@ func main() {
 
 	// Student attendance history
 	router.GET("/students/:studentID/attendance", h.GetStudentAttendance)
+	router.POST("/students/:studentID/transfer", h.TransferStudent)
+	router.GET("/students/:studentID/attendance-alerts", h.GetStudentAttendanceAlerts)
 	// Student grade history
 	router.GET("/students/:studentID/grades", h.GetStudentGrades)
+	// Student report cards and cumulative transcript
+	router.GET("/students/:studentID/report-cards", h.GetStudentReportCards)
+	router.GET("/students/:studentID/transcript", h.GetStudentTranscript)
+	router.GET("/students/:studentID/certificates", h.GetStudentCertificates)
+	router.GET("/students/:studentID/make-up-credits", h.GetStudentMakeUpCredits)
+
+	// Attendance alert thresholds
+	router.GET("/attendance-alerts/settings", h.GetAttendanceAlertSettings)
+	router.PUT("/attendance-alerts/settings", h.UpdateAttendanceAlertSettings)
+
+	// Direct class session access
+	router.GET("/class-sessions/:sessionID", h.GetClassSession)
+	router.PUT("/class-sessions/:sessionID", h.UpdateClassSession)
+	router.POST("/class-sessions/:sessionID/cancel", h.CancelClassSession)
+	router.POST("/class-sessions/:sessionID/reschedule", h.RescheduleClassSession)
+	router.GET("/class-sessions/:sessionID/syllabus", h.GetClassSessionSyllabus)
+	router.PUT("/class-sessions/:sessionID/syllabus", h.SetClassSessionSyllabus)
+	router.POST("/make-up-credits/:creditID/redeem", h.RedeemMakeUpCredit)
 
 	// Direct grade access
 	router.PUT("/grades/:gradeID", h.UpdateGrade)
@@ -275,6 +431,7 @@ func main() {
 		users.PUT("/:userID", h.UpdateUser)
 		users.DELETE("/:userID", h.DeleteUser)
 		users.PUT("/:userID/password", h.ChangePassword)
+		users.POST("/:userID/invite", h.ReissueAccountInvite)
 	}
 
 	// Payment Management
@@ -361,9 +518,55 @@ func main() {
 	{
 		calendar.POST("/events", h.CreateEvent)
 		calendar.GET("/events", h.GetCalendarEvents)
+		calendar.GET("/lessons", h.GetCalendarLessons)
 		calendar.GET("/events/:eventID", h.GetEvent)
 		calendar.PUT("/events/:eventID", h.UpdateEvent)
 		calendar.DELETE("/events/:eventID", h.DeleteEvent)
+		calendar.POST("/feeds", h.CreateCalendarFeed)
+		calendar.GET("/feeds", h.GetCalendarFeeds)
+		calendar.DELETE("/feeds/:feedID", h.RevokeCalendarFeed)
+	}
+
+	// Academic terms & holidays
+	terms := router.Group("/terms")
+	{
+		terms.POST("/", h.CreateAcademicTerm)
+		terms.GET("/", h.GetAcademicTerms)
+		terms.GET("/:termID", h.GetAcademicTerm)
+		terms.PUT("/:termID", h.UpdateAcademicTerm)
+		terms.DELETE("/:termID", h.DeleteAcademicTerm)
+	}
+	holidays := router.Group("/holidays")
+	{
+		holidays.POST("/", h.CreateHoliday)
+		holidays.GET("/", h.GetHolidays)
+		holidays.POST("/import", h.ImportHolidays)
+		holidays.DELETE("/:holidayID", h.DeleteHoliday)
+	}
+
+	// Leads & Sales Pipeline
+	leads := router.Group("/leads")
+	{
+		leads.POST("/", h.CreateLead)
+		leads.GET("/", h.GetLeads)
+		leads.GET("/:leadID", h.GetLead)
+		leads.PUT("/:leadID", h.UpdateLead)
+		leads.DELETE("/:leadID", h.DeleteLead)
+		leads.POST("/:leadID/stage", h.ChangeLeadStage)
+		leads.POST("/:leadID/activities", h.CreateLeadActivity)
+		leads.GET("/:leadID/activities", h.GetLeadActivities)
+		leads.POST("/:leadID/convert", h.ConvertLead)
+	}
+
+	// Trial Lessons
+	trials := router.Group("/trials")
+	{
+		trials.POST("/", h.BookTrial)
+		trials.GET("/", h.GetTrials)
+		trials.GET("/conversion-report", h.GetTrialConversionReport)
+		trials.GET("/:trialID", h.GetTrial)
+		trials.PUT("/:trialID/outcome", h.RecordTrialOutcome)
+		trials.POST("/:trialID/cancel", h.CancelTrial)
 	}
 
 	// Applications & Enrollment
@@ -390,12 +593,38 @@ func main() {
 		exams.GET("/group/:groupID", h.GetExamsByGroup)
 		exams.PUT("/:examID", h.UpdateExam)
 		exams.POST("/:examID/results", h.SubmitExamResult)
+		exams.POST("/:examID/results/bulk", h.BulkSubmitExamResults)
+		exams.POST("/:examID/publish", h.PublishExam)
 		exams.GET("/:examID/results", h.GetExamResults)
 		exams.GET("/student/:studentID/results", h.GetStudentExamResults)
 		exams.GET("/:examID/statistics", h.GetExamStatistics)
 		exams.DELETE("/:examID", h.DeleteExam)
 	}
 
+	// Grading Scales
+	gradingScales := router.Group("/grading-scales")
+	{
+		gradingScales.POST("/", h.CreateGradingScale)
+		gradingScales.GET("/", h.GetAllGradingScales)
+		gradingScales.GET("/:scaleID", h.GetGradingScale)
+		gradingScales.PUT("/:scaleID", h.UpdateGradingScale)
+		gradingScales.DELETE("/:scaleID", h.DeleteGradingScale)
+	}
+
+	// Certificates
+	certificateTemplates := router.Group("/certificate-templates")
+	{
+		certificateTemplates.POST("/", h.CreateCertificateTemplate)
+		certificateTemplates.GET("/", h.GetCertificateTemplates)
+		certificateTemplates.PUT("/:templateID", h.UpdateCertificateTemplate)
+	}
+	certificates := router.Group("/certificates")
+	{
+		certificates.POST("/", h.IssueCertificate)
+		certificates.GET("/:certificateID", h.GetCertificate)
+		certificates.POST("/:certificateID/revoke", h.RevokeCertificate)
+	}
+
 	// Portals
 	portal := router.Group("/portal")
 	{
@@ -418,9 +647,11 @@ func main() {
 	assignments := router.Group("/assignments")
 	{
 		assignments.POST("/", h.CreateAssignment)
+		assignments.POST("/close-due", h.CloseDueAssignments)
 		assignments.PUT("/:assignmentID", h.UpdateAssignment)
 		assignments.GET("/:assignmentID", h.GetAssignment)
 		assignments.POST("/:assignmentID/submit/:studentID", h.SubmitAssignment)
+		assignments.GET("/:assignmentID/submissions", h.GetAssignmentSubmissions)
 		assignments.POST("/submissions/:submissionID/grade", h.GradeSubmission)
 	}
 	router.GET("/groups/:groupID/assignments", h.GetGroupAssignments)
@@ -433,6 +664,10 @@ func main() {
 		waitlists.POST("/:entryID/process", h.ProcessWaitlistEntry)
 	}
 	router.GET("/groups/:groupID/waitlist", h.GetGroupWaitlist)
+	router.GET("/groups/:groupID/availability", h.GetGroupAvailability)
+	router.POST("/groups/:groupID/holds", h.HoldGroupSeat)
+	router.DELETE("/seat-holds/:holdID", h.ReleaseSeatHold)
+	router.POST("/groups/:groupID/waitlist/offer", h.OfferGroupSeats)
 
 	// Bulk Operations
 	bulk := router.Group("/bulk")
@@ -458,9 +693,53 @@ func main() {
 		search.POST("/invoices", h.SearchInvoices)
 	}
 
+	// Duplicate Detection
+	duplicates := router.Group("/duplicates")
+	{
+		duplicates.GET("/", h.FindDuplicates)
+		duplicates.POST("/merge", h.MergeDuplicates)
+		duplicates.POST("/merges/:mergeID/undo", h.UndoMerge)
+	}
+
 	// Audit Logs (Admin only)
 	router.GET("/audit-logs", middlewares.RequireRole(models.RoleAdmin), h.GetAuditLogs)
 
+	// Start and complete exams as their scheduled times pass
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := examService.AdvanceStatuses(context.Background(), time.Now()); err != nil {
+				logger.Error("failed to advance exam statuses", err)
+			}
+		}
+	}()
+
+	// Close assignments once their submission window has ended
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := assignmentService.CloseDueAssignments(context.Background(), time.Now()); err != nil {
+				logger.Error("failed to close due assignments", err)
+			}
+		}
+	}()
+
+	// Pass expired seat offers on to the next entries in line
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := waitlistService.ExpireOffers(context.Background(), time.Now()); err != nil {
+				logger.Error("failed to expire waitlist offers", err)
+			}
+		}
+	}()
+
 	// Start server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
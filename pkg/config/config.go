@@ -16,6 +16,9 @@ type Config struct {
 	Logging  LoggingConfig
 	Redis    RedisConfig
 	Metrics  MetricsConfig
+	Intake   IntakeConfig
+	Waitlist WaitlistConfig
+	Accounts AccountsConfig
 }
 
 // ServerConfig holds HTTP server configuration
@@ -79,6 +82,24 @@ type MetricsConfig struct {
 	Path    string
 }
 
+// IntakeConfig holds public application intake configuration
+type IntakeConfig struct {
+	FormSecret string // Signs form tokens; a random secret is used when empty
+	StatusURL  string // Base of the status-check link sent to applicants
+}
+
+// WaitlistConfig holds waitlist seat offer configuration
+type WaitlistConfig struct {
+	OfferURL string        // Base of the accept/decline link sent with seat offers
+	OfferTTL time.Duration // How long a seat offer stays open
+}
+
+// AccountsConfig holds configuration of accounts created for enrolled students
+type AccountsConfig struct {
+	InviteURL string        // Base of the link a new user follows to choose their password
+	InviteTTL time.Duration // How long an invite link stays valid
+}
+
 // Load loads configuration from environment variables and config files
 func Load() (*Config, error) {
 	v := viper.New()
@@ -167,6 +188,24 @@ func Load() (*Config, error) {
 		Path:    v.GetString("METRICS_PATH"),
 	}
 
+	// Intake configuration
+	cfg.Intake = IntakeConfig{
+		FormSecret: v.GetString("INTAKE_FORM_SECRET"),
+		StatusURL:  v.GetString("INTAKE_STATUS_URL"),
+	}
+
+	// Waitlist configuration
+	cfg.Waitlist = WaitlistConfig{
+		OfferURL: v.GetString("WAITLIST_OFFER_URL"),
+		OfferTTL: v.GetDuration("WAITLIST_OFFER_TTL"),
+	}
+
+	// Account configuration
+	cfg.Accounts = AccountsConfig{
+		InviteURL: v.GetString("ACCOUNT_INVITE_URL"),
+		InviteTTL: v.GetDuration("ACCOUNT_INVITE_TTL"),
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -221,6 +260,17 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("METRICS_ENABLED", true)
 	v.SetDefault("METRICS_PORT", "9090")
 	v.SetDefault("METRICS_PATH", "/metrics")
+
+	// Intake defaults
+	v.SetDefault("INTAKE_STATUS_URL", "http://localhost:8080/public/applications/status")
+
+	// Waitlist defaults
+	v.SetDefault("WAITLIST_OFFER_URL", "http://localhost:8080/public/waitlist/offers")
+	v.SetDefault("WAITLIST_OFFER_TTL", 48*time.Hour)
+
+	// Account defaults
+	v.SetDefault("ACCOUNT_INVITE_URL", "http://localhost:8080/public/account/invites")
+	v.SetDefault("ACCOUNT_INVITE_TTL", 72*time.Hour)
 }
 
 // Validate validates the configuration
@@ -262,6 +312,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid ENVIRONMENT: %s (must be development, staging, or production)", c.Server.Environment)
 	}
 
+	// Form tokens must survive restarts and be shared between instances in production
+	if c.Server.Environment == "production" && c.Intake.FormSecret == "" {
+		return fmt.Errorf("INTAKE_FORM_SECRET is required in production")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// TermBreakRequest describes a break within a term
+type TermBreakRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD, inclusive
+}
+
+// CreateAcademicTermRequest represents a request to create an academic term
+type CreateAcademicTermRequest struct {
+	Name      string             `json:"name" binding:"required,max=100"`
+	StartDate string             `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string             `json:"end_date" binding:"required"`   // YYYY-MM-DD, inclusive
+	Breaks    []TermBreakRequest `json:"breaks,omitempty" binding:"omitempty,dive"`
+}
+
+// UpdateAcademicTermRequest represents a request to update an academic term.
+// Breaks, when given, replace the term's breaks.
+type UpdateAcademicTermRequest struct {
+	Name      *string             `json:"name,omitempty" binding:"omitempty,max=100"`
+	StartDate *string             `json:"start_date,omitempty"`
+	EndDate   *string             `json:"end_date,omitempty"`
+	Breaks    *[]TermBreakRequest `json:"breaks,omitempty" binding:"omitempty,dive"`
+}
+
+// TermBreakResponse represents a term break in API responses
+type TermBreakResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// AcademicTermResponse represents an academic term in API responses
+type AcademicTermResponse struct {
+	ID        uuid.UUID           `json:"id"`
+	Name      string              `json:"name"`
+	StartDate time.Time           `json:"start_date"`
+	EndDate   time.Time           `json:"end_date"`
+	Breaks    []TermBreakResponse `json:"breaks"`
+	// Scheduled lessons cancelled because they fell in a break
+	CancelledSessions int       `json:"cancelled_sessions,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// CreateHolidayRequest represents a request to add a holiday
+type CreateHolidayRequest struct {
+	Name      string `json:"name" binding:"required,max=255"`
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date,omitempty"`            // YYYY-MM-DD, inclusive; the start date by default
+}
+
+// HolidayFilter represents filters for listing holidays
+type HolidayFilter struct {
+	From string `form:"from"` // YYYY-MM-DD
+	To   string `form:"to"`   // YYYY-MM-DD
+}
+
+// HolidayResponse represents a holiday in API responses
+type HolidayResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Name        string               `json:"name"`
+	StartDate   time.Time            `json:"start_date"`
+	EndDate     time.Time            `json:"end_date"`
+	Source      models.HolidaySource `json:"source"`
+	ExternalUID string               `json:"external_uid,omitempty"`
+	// Scheduled lessons cancelled because they fell on the holiday
+	CancelledSessions int       `json:"cancelled_sessions,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ImportHolidaysResponse reports the outcome of an iCalendar holiday import
+type ImportHolidaysResponse struct {
+	Created           int               `json:"created"`
+	Updated           int               `json:"updated"`
+	Unchanged         int               `json:"unchanged"`
+	Skipped           int               `json:"skipped"` // Cancelled events and occurrences outside the import window
+	CancelledSessions int               `json:"cancelled_sessions"`
+	Holidays          []HolidayResponse `json:"holidays"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountInviteResponse is what a new user sees through their invite link
+type AccountInviteResponse struct {
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AcceptAccountInviteRequest sets the password of an invited user
+type AcceptAccountInviteRequest struct {
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// ReissuedAccountInviteResponse describes a fresh invite sent to a user
+type ReissuedAccountInviteResponse struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	InvitationSent bool      `json:"invitation_sent"`
+}
@@ -42,6 +42,8 @@ type StudentProgressReport struct {
 type GradeInfo struct {
 	ID        uuid.UUID `json:"id"`
 	Score     float64   `json:"score"`
+	Label     string    `json:"label,omitempty"`
+	GPAPoints float64   `json:"gpa_points"`
 	Type      string    `json:"type"`
 	Notes     string    `json:"notes"`
 	CreatedAt time.Time `json:"created_at"`
@@ -44,7 +44,30 @@ type ReviewApplicationRequest struct {
 
 // EnrollApplicationRequest represents a request to enroll an applicant
 type EnrollApplicationRequest struct {
-	GroupID *uuid.UUID `json:"group_id,omitempty"`
+	GroupID uuid.UUID `json:"group_id" binding:"required"`
+	// Create (or link an existing) parent from the application's metadata["parent"]
+	CreateParent bool `json:"create_parent"`
+	// How to bill the course's monthly fee: a first invoice (default), a
+	// monthly recurring invoice, or not at all
+	Billing string `json:"billing,omitempty" binding:"omitempty,oneof=invoice recurring none"`
+	DueDays int    `json:"due_days,omitempty" binding:"omitempty,min=1,max=90"`
+	// Provision a student account and email an invitation; defaults to true
+	// when the applicant has an email address
+	CreateAccount *bool `json:"create_account,omitempty"`
+	// Seat hold kept for the applicant, e.g. while their first payment was pending
+	HoldID *uuid.UUID `json:"hold_id,omitempty"`
+}
+
+// EnrollmentResponse represents the records created by an enrollment
+type EnrollmentResponse struct {
+	Application        *ApplicationResponse `json:"application"`
+	Student            *StudentResponse     `json:"student"`
+	ParentID           *uuid.UUID           `json:"parent_id,omitempty"`
+	InvoiceID          *uuid.UUID           `json:"invoice_id,omitempty"`
+	RecurringInvoiceID *uuid.UUID           `json:"recurring_invoice_id,omitempty"`
+	UserID             *uuid.UUID           `json:"user_id,omitempty"`
+	InvitationSent     bool                 `json:"invitation_sent"`
+	Warnings           []string             `json:"warnings,omitempty"` // E.g. missing course prerequisites
 }
 
 // ApplicationResponse represents an application response
@@ -68,6 +91,8 @@ type ApplicationResponse struct {
 	EnrolledAs        *uuid.UUID               `json:"enrolled_as,omitempty"`
 	EnrolledAt        *time.Time               `json:"enrolled_at,omitempty"`
 	Metadata          map[string]interface{}   `json:"metadata,omitempty"`
+	LeadID            *uuid.UUID               `json:"lead_id,omitempty"`
+	Warnings          []string                 `json:"warnings,omitempty"` // E.g. missing course prerequisites
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 }
@@ -9,43 +9,50 @@ import (
 
 // CreateAssignmentRequest represents the request to create an assignment
 type CreateAssignmentRequest struct {
-	GroupID       uuid.UUID             `json:"group_id" binding:"required"`
-	CourseID      uuid.UUID             `json:"course_id" binding:"required"`
-	TeacherID     uuid.UUID             `json:"teacher_id" binding:"required"`
-	Title         string                `json:"title" binding:"required"`
-	Description   string                `json:"description,omitempty"`
-	Type          models.AssignmentType `json:"type" binding:"required"`
-	AssignedDate  time.Time             `json:"assigned_date" binding:"required"`
-	DueDate       time.Time             `json:"due_date" binding:"required"`
-	MaxPoints     float64               `json:"max_points"`
-	PassingPoints float64               `json:"passing_points"`
-	WeightPercent float64               `json:"weight_percent"`
-	AllowLate     bool                  `json:"allow_late"`
-	LatePenalty   float64               `json:"late_penalty"`
-	Instructions  string                `json:"instructions,omitempty"`
-	Resources     string                `json:"resources,omitempty"`
+	GroupID        uuid.UUID             `json:"group_id" binding:"required"`
+	CourseID       uuid.UUID             `json:"course_id" binding:"required"`
+	TeacherID      uuid.UUID             `json:"teacher_id" binding:"required"`
+	Title          string                `json:"title" binding:"required"`
+	Description    string                `json:"description,omitempty"`
+	Type           models.AssignmentType `json:"type" binding:"required"`
+	AssignedDate   time.Time             `json:"assigned_date" binding:"required"`
+	DueDate        time.Time             `json:"due_date" binding:"required"`
+	MaxPoints      float64               `json:"max_points"`
+	PassingPoints  float64               `json:"passing_points"`
+	WeightPercent  float64               `json:"weight_percent"`
+	AllowLate      bool                  `json:"allow_late"`
+	LatePenalty    float64               `json:"late_penalty" binding:"min=0,max=100"`                         // Percent of max points per day late
+	MaxLatePenalty *float64              `json:"max_late_penalty,omitempty" binding:"omitempty,min=0,max=100"` // Defaults to 100
+	LateWindowDays *int                  `json:"late_window_days,omitempty" binding:"omitempty,min=0,max=365"` // Defaults to 7
+	MaxAttempts    int                   `json:"max_attempts" binding:"min=0"`                                 // 0 allows unlimited resubmissions
+	Instructions   string                `json:"instructions,omitempty"`
+	Resources      string                `json:"resources,omitempty"`
 }
 
 // UpdateAssignmentRequest represents the request to update an assignment
 type UpdateAssignmentRequest struct {
-	Title         *string                  `json:"title,omitempty"`
-	Description   *string                  `json:"description,omitempty"`
-	Type          *models.AssignmentType   `json:"type,omitempty"`
-	Status        *models.AssignmentStatus `json:"status,omitempty"`
-	DueDate       *time.Time               `json:"due_date,omitempty"`
-	MaxPoints     *float64                 `json:"max_points,omitempty"`
-	PassingPoints *float64                 `json:"passing_points,omitempty"`
-	WeightPercent *float64                 `json:"weight_percent,omitempty"`
-	AllowLate     *bool                    `json:"allow_late,omitempty"`
-	LatePenalty   *float64                 `json:"late_penalty,omitempty"`
-	Instructions  *string                  `json:"instructions,omitempty"`
-	Resources     *string                  `json:"resources,omitempty"`
+	Title          *string                  `json:"title,omitempty"`
+	Description    *string                  `json:"description,omitempty"`
+	Type           *models.AssignmentType   `json:"type,omitempty"`
+	Status         *models.AssignmentStatus `json:"status,omitempty"`
+	DueDate        *time.Time               `json:"due_date,omitempty"`
+	MaxPoints      *float64                 `json:"max_points,omitempty"`
+	PassingPoints  *float64                 `json:"passing_points,omitempty"`
+	WeightPercent  *float64                 `json:"weight_percent,omitempty"`
+	AllowLate      *bool                    `json:"allow_late,omitempty"`
+	LatePenalty    *float64                 `json:"late_penalty,omitempty" binding:"omitempty,min=0,max=100"`
+	MaxLatePenalty *float64                 `json:"max_late_penalty,omitempty" binding:"omitempty,min=0,max=100"`
+	LateWindowDays *int                     `json:"late_window_days,omitempty" binding:"omitempty,min=0,max=365"`
+	MaxAttempts    *int                     `json:"max_attempts,omitempty" binding:"omitempty,min=0"`
+	Instructions   *string                  `json:"instructions,omitempty"`
+	Resources      *string                  `json:"resources,omitempty"`
 }
 
 // SubmitAssignmentRequest represents a student submission
+// (JSON, or multipart/form-data with the files in "files")
 type SubmitAssignmentRequest struct {
-	Content     string `json:"content,omitempty"`
-	Attachments string `json:"attachments,omitempty"` // JSON array
+	Content     string `json:"content,omitempty" form:"content"`
+	Attachments string `json:"attachments,omitempty" form:"attachments"` // Free-form links
 }
 
 // GradeSubmissionRequest represents grading a submission
@@ -72,6 +79,10 @@ type AssignmentResponse struct {
 	WeightPercent   float64                 `json:"weight_percent"`
 	AllowLate       bool                    `json:"allow_late"`
 	LatePenalty     float64                 `json:"late_penalty"`
+	MaxLatePenalty  float64                 `json:"max_late_penalty"`
+	LateWindowDays  int                     `json:"late_window_days"`
+	SubmissionsEnd  time.Time               `json:"submissions_end"`
+	MaxAttempts     int                     `json:"max_attempts"`
 	Instructions    string                  `json:"instructions,omitempty"`
 	Resources       string                  `json:"resources,omitempty"`
 	Group           *GroupSimple            `json:"group,omitempty"`
@@ -96,23 +107,25 @@ type SubmissionStats struct {
 
 // SubmissionResponse represents a submission in API responses
 type SubmissionResponse struct {
-	ID             uuid.UUID               `json:"id"`
-	AssignmentID   uuid.UUID               `json:"assignment_id"`
-	StudentID      uuid.UUID               `json:"student_id"`
-	Status         models.SubmissionStatus `json:"status"`
-	SubmittedAt    *time.Time              `json:"submitted_at,omitempty"`
-	Content        string                  `json:"content,omitempty"`
-	Attachments    string                  `json:"attachments,omitempty"`
-	Points         *float64                `json:"points,omitempty"`
-	Feedback       string                  `json:"feedback,omitempty"`
-	GradedAt       *time.Time              `json:"graded_at,omitempty"`
-	IsLate         bool                    `json:"is_late"`
-	DaysLate       int                     `json:"days_late"`
-	PenaltyApplied float64                 `json:"penalty_applied"`
-	AttemptNumber  int                     `json:"attempt_number"`
-	Student        *StudentSimple          `json:"student,omitempty"`
-	CreatedAt      time.Time               `json:"created_at"`
-	UpdatedAt      time.Time               `json:"updated_at"`
+	ID             uuid.UUID                `json:"id"`
+	AssignmentID   uuid.UUID                `json:"assignment_id"`
+	StudentID      uuid.UUID                `json:"student_id"`
+	Status         models.SubmissionStatus  `json:"status"`
+	SubmittedAt    *time.Time               `json:"submitted_at,omitempty"`
+	Content        string                   `json:"content,omitempty"`
+	Attachments    string                   `json:"attachments,omitempty"`
+	Points         *float64                 `json:"points,omitempty"`
+	Feedback       string                   `json:"feedback,omitempty"`
+	GradedAt       *time.Time               `json:"graded_at,omitempty"`
+	IsLate         bool                     `json:"is_late"`
+	DaysLate       int                      `json:"days_late"`
+	PenaltyApplied float64                  `json:"penalty_applied"`
+	AttemptNumber  int                      `json:"attempt_number"`
+	IsFinal        bool                     `json:"is_final"`
+	Files          []SubmissionFileResponse `json:"files"`
+	Student        *StudentSimple           `json:"student,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
 }
 
 // AssignmentSimple is a simplified assignment for nested responses
@@ -123,3 +136,19 @@ type AssignmentSimple struct {
 	DueDate   time.Time             `json:"due_date"`
 	MaxPoints float64               `json:"max_points"`
 }
+
+// CloseAssignmentsResponse reports the result of closing assignments past their deadline
+type CloseAssignmentsResponse struct {
+	TotalClosed         int         `json:"total_closed"`
+	MissingSubmissions  int         `json:"missing_submissions"` // Zero-score records created for students who never submitted
+	ClosedAssignmentIDs []uuid.UUID `json:"closed_assignment_ids"`
+}
+
+// SubmissionFileResponse represents a file attached to a submission attempt
+type SubmissionFileResponse struct {
+	DocumentID  uuid.UUID `json:"document_id"`
+	FileName    string    `json:"file_name"`
+	FileSize    int64     `json:"file_size"`
+	MimeType    string    `json:"mime_type,omitempty"`
+	DownloadURL string    `json:"download_url"`
+}
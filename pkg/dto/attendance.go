@@ -4,14 +4,16 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
 )
 
 // CreateAttendanceRequest represents a request to mark attendance
 type CreateAttendanceRequest struct {
-	StudentID uuid.UUID `json:"student_id" binding:"required"`
-	Date      string    `json:"date" binding:"required,datetime=2006-01-02"` // YYYY-MM-DD
-	Status    string    `json:"status" binding:"required,oneof=present absent late excused"`
-	Notes     string    `json:"notes" binding:"max=500"`
+	StudentID uuid.UUID  `json:"student_id" binding:"required"`
+	Date      string     `json:"date" binding:"required,datetime=2006-01-02"` // YYYY-MM-DD
+	SessionID *uuid.UUID `json:"session_id,omitempty"`                        // Required when the group has several lessons that day
+	Status    string     `json:"status" binding:"required,oneof=present absent late excused"`
+	Notes     string     `json:"notes" binding:"max=500"`
 }
 
 // UpdateAttendanceRequest represents a request to update attendance
@@ -25,6 +27,7 @@ type AttendanceResponse struct {
 	ID        uuid.UUID     `json:"id"`
 	StudentID uuid.UUID     `json:"student_id"`
 	GroupID   uuid.UUID     `json:"group_id"`
+	SessionID *uuid.UUID    `json:"session_id,omitempty"`
 	Date      string        `json:"date"`
 	Status    string        `json:"status"`
 	Notes     string        `json:"notes"`
@@ -36,6 +39,7 @@ type AttendanceResponse struct {
 // BatchAttendanceRequest represents a request to mark attendance for multiple students
 type BatchAttendanceRequest struct {
 	Date        string                  `json:"date" binding:"required,datetime=2006-01-02"`
+	SessionID   *uuid.UUID              `json:"session_id,omitempty"`
 	Attendances []StudentAttendanceItem `json:"attendances" binding:"required,dive"`
 }
 
@@ -45,3 +49,34 @@ type StudentAttendanceItem struct {
 	Status    string    `json:"status" binding:"required,oneof=present absent late excused"`
 	Notes     string    `json:"notes" binding:"max=500"`
 }
+
+// AttendanceSheetResponse is the roster of a lesson: enrolled students with
+// any attendance marked so far, and trial guests
+type AttendanceSheetResponse struct {
+	GroupID   uuid.UUID              `json:"group_id"`
+	SessionID uuid.UUID              `json:"session_id"`
+	TeacherID uuid.UUID              `json:"teacher_id"`
+	Date      string                 `json:"date"`
+	StartTime string                 `json:"start_time"`
+	EndTime   string                 `json:"end_time"`
+	Room      string                 `json:"room,omitempty"`
+	Students  []AttendanceSheetEntry `json:"students"`
+	Guests    []AttendanceSheetGuest `json:"guests"`
+}
+
+// AttendanceSheetEntry is an enrolled student on an attendance sheet
+type AttendanceSheetEntry struct {
+	Student      StudentSimple `json:"student"`
+	AttendanceID *uuid.UUID    `json:"attendance_id,omitempty"`
+	Status       string        `json:"status,omitempty"` // Empty until marked
+	Notes        string        `json:"notes,omitempty"`
+}
+
+// AttendanceSheetGuest is a prospect attending a trial lesson
+type AttendanceSheetGuest struct {
+	TrialID uuid.UUID           `json:"trial_id"`
+	Name    string              `json:"name"`
+	Phone   string              `json:"phone,omitempty"`
+	Status  models.TrialStatus  `json:"status"`
+	Outcome models.TrialOutcome `json:"outcome,omitempty"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// UpdateAttendanceAlertSettingsRequest represents a request to change alert thresholds
+type UpdateAttendanceAlertSettingsRequest struct {
+	ParentAlertsEnabled     *bool    `json:"parent_alerts_enabled,omitempty"`
+	AlertOnLate             *bool    `json:"alert_on_late,omitempty"`
+	ConsecutiveAbsences     *int     `json:"consecutive_absences,omitempty" binding:"omitempty,min=0,max=100"`
+	LowAttendancePercent    *float64 `json:"low_attendance_percent,omitempty" binding:"omitempty,min=0,max=100"`
+	LowAttendanceWindowDays *int     `json:"low_attendance_window_days,omitempty" binding:"omitempty,min=1,max=366"`
+	LowAttendanceMinRecords *int     `json:"low_attendance_min_records,omitempty" binding:"omitempty,min=1"`
+	EscalateToTeacher       *bool    `json:"escalate_to_teacher,omitempty"`
+	EscalateToAdmins        *bool    `json:"escalate_to_admins,omitempty"`
+}
+
+// AttendanceAlertSettingsResponse represents the alert thresholds in API responses
+type AttendanceAlertSettingsResponse struct {
+	ParentAlertsEnabled     bool      `json:"parent_alerts_enabled"`
+	AlertOnLate             bool      `json:"alert_on_late"`
+	ConsecutiveAbsences     int       `json:"consecutive_absences"`
+	LowAttendancePercent    float64   `json:"low_attendance_percent"`
+	LowAttendanceWindowDays int       `json:"low_attendance_window_days"`
+	LowAttendanceMinRecords int       `json:"low_attendance_min_records"`
+	EscalateToTeacher       bool      `json:"escalate_to_teacher"`
+	EscalateToAdmins        bool      `json:"escalate_to_admins"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// AttendanceAlertResponse represents a logged attendance alert in API responses
+type AttendanceAlertResponse struct {
+	ID                uuid.UUID                  `json:"id"`
+	StudentID         uuid.UUID                  `json:"student_id"`
+	StudentName       string                     `json:"student_name,omitempty"`
+	GroupID           uuid.UUID                  `json:"group_id"`
+	AttendanceID      *uuid.UUID                 `json:"attendance_id,omitempty"`
+	Date              string                     `json:"date"`
+	Type              models.AttendanceAlertType `json:"type"`
+	Escalated         bool                       `json:"escalated"`
+	Subject           string                     `json:"subject"`
+	Message           string                     `json:"message"`
+	Recipients        []string                   `json:"recipients"`
+	NotificationsSent int                        `json:"notifications_sent"`
+	CreatedAt         time.Time                  `json:"created_at"`
+}
@@ -33,6 +33,7 @@ type BulkFailedItem struct {
 type BulkAttendanceRequest struct {
 	GroupID     uuid.UUID               `json:"group_id" binding:"required"`
 	Date        string                  `json:"date" binding:"required"`
+	SessionID   *uuid.UUID              `json:"session_id,omitempty"`
 	Attendances []StudentAttendanceItem `json:"attendances" binding:"required,min=1"`
 }
 
@@ -104,17 +105,20 @@ type CreateRecurringInvoiceRequest struct {
 	AutoSend       bool                      `json:"auto_send"`
 	DueDays        int                       `json:"due_days"`
 	ReminderDays   int                       `json:"reminder_days"`
+	// Reduce monthly invoices of a group by the lessons lost to holidays and breaks
+	ProRateHolidays bool `json:"pro_rate_holidays"`
 }
 
 // UpdateRecurringInvoiceRequest represents recurring invoice update
 type UpdateRecurringInvoiceRequest struct {
-	Status         *models.RecurringStatus `json:"status,omitempty"`
-	BaseAmount     *float64                `json:"base_amount,omitempty"`
-	DiscountAmount *float64                `json:"discount_amount,omitempty"`
-	EndDate        *time.Time              `json:"end_date,omitempty"`
-	AutoSend       *bool                   `json:"auto_send,omitempty"`
-	DueDays        *int                    `json:"due_days,omitempty"`
-	ReminderDays   *int                    `json:"reminder_days,omitempty"`
+	Status          *models.RecurringStatus `json:"status,omitempty"`
+	BaseAmount      *float64                `json:"base_amount,omitempty"`
+	DiscountAmount  *float64                `json:"discount_amount,omitempty"`
+	EndDate         *time.Time              `json:"end_date,omitempty"`
+	AutoSend        *bool                   `json:"auto_send,omitempty"`
+	DueDays         *int                    `json:"due_days,omitempty"`
+	ReminderDays    *int                    `json:"reminder_days,omitempty"`
+	ProRateHolidays *bool                   `json:"pro_rate_holidays,omitempty"`
 }
 
 // RecurringInvoiceResponse represents recurring invoice in API responses
@@ -138,6 +142,7 @@ type RecurringInvoiceResponse struct {
 	AutoSend        bool                      `json:"auto_send"`
 	DueDays         int                       `json:"due_days"`
 	ReminderDays    int                       `json:"reminder_days"`
+	ProRateHolidays bool                      `json:"pro_rate_holidays"`
 	Student         *StudentSimple            `json:"student,omitempty"`
 	CreatedAt       time.Time                 `json:"created_at"`
 	UpdatedAt       time.Time                 `json:"updated_at"`
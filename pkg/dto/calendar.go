@@ -16,6 +16,7 @@ type CreateEventRequest struct {
 	EndTime        time.Time              `json:"end_time" binding:"required"`
 	AllDay         bool                   `json:"all_day,omitempty"`
 	Location       string                 `json:"location,omitempty"`
+	RoomID         *uuid.UUID             `json:"room_id,omitempty"` // Sets location to the room's name
 	GroupID        *uuid.UUID             `json:"group_id,omitempty"`
 	CourseID       *uuid.UUID             `json:"course_id,omitempty"`
 	TeacherID      *uuid.UUID             `json:"teacher_id,omitempty"`
@@ -34,12 +35,30 @@ type UpdateEventRequest struct {
 	EndTime        *time.Time             `json:"end_time,omitempty"`
 	AllDay         *bool                  `json:"all_day,omitempty"`
 	Location       *string                `json:"location,omitempty"`
+	RoomID         *uuid.UUID             `json:"room_id,omitempty"`
 	GroupID        *uuid.UUID             `json:"group_id,omitempty"`
 	CourseID       *uuid.UUID             `json:"course_id,omitempty"`
 	TeacherID      *uuid.UUID             `json:"teacher_id,omitempty"`
-	RecurrenceRule *string                `json:"recurrence_rule,omitempty"`
+	RecurrenceRule *string                `json:"recurrence_rule,omitempty"` // "" stops the event repeating
 	Color          *string                `json:"color,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	// For recurring events: which occurrences to change, and the recurrence_id
+	// of the occurrence the change starts from for "this" and "following"
+	Scope      string     `json:"scope,omitempty" binding:"omitempty,oneof=all this following"`
+	Occurrence *time.Time `json:"occurrence,omitempty"`
+}
+
+// Scopes of a change to a recurring event
+const (
+	EventScopeAll       = "all"       // The whole series
+	EventScopeThis      = "this"      // One occurrence, kept as an exception row
+	EventScopeFollowing = "following" // The occurrence and all later ones
+)
+
+// EventScopeRequest selects the occurrences of a recurring event to delete
+type EventScopeRequest struct {
+	Scope      string     `form:"scope" binding:"omitempty,oneof=all this following"`
+	Occurrence *time.Time `form:"occurrence"`
 }
 
 // EventResponse represents an event response
@@ -52,12 +71,14 @@ type EventResponse struct {
 	EndTime        time.Time              `json:"end_time"`
 	AllDay         bool                   `json:"all_day"`
 	Location       string                 `json:"location,omitempty"`
+	RoomID         *uuid.UUID             `json:"room_id,omitempty"`
 	GroupID        *uuid.UUID             `json:"group_id,omitempty"`
 	CourseID       *uuid.UUID             `json:"course_id,omitempty"`
 	TeacherID      *uuid.UUID             `json:"teacher_id,omitempty"`
 	IsRecurring    bool                   `json:"is_recurring"`
 	RecurrenceRule string                 `json:"recurrence_rule,omitempty"`
 	ParentEventID  *uuid.UUID             `json:"parent_event_id,omitempty"`
+	RecurrenceID   *time.Time             `json:"recurrence_id,omitempty"` // Original start of an occurrence or exception
 	Color          string                 `json:"color,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 	CreatedBy      uuid.UUID              `json:"created_by"`
@@ -74,3 +95,38 @@ type CalendarRequest struct {
 	TeacherID *uuid.UUID `form:"teacher_id,omitempty"`
 	Type      *string    `form:"type,omitempty"`
 }
+
+// LessonCalendarRequest represents a query for lessons on real dates
+type LessonCalendarRequest struct {
+	StartDate string     `form:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string     `form:"end_date" binding:"required"`   // YYYY-MM-DD
+	GroupID   *uuid.UUID `form:"group_id"`
+	TeacherID *uuid.UUID `form:"teacher_id"`
+}
+
+// CreateCalendarFeedRequest represents a request to publish an ICS feed
+type CreateCalendarFeedRequest struct {
+	OwnerType models.CalendarFeedOwner `json:"owner_type" binding:"required,oneof=teacher group student parent"`
+	OwnerID   uuid.UUID                `json:"owner_id" binding:"required"`
+	Name      string                   `json:"name,omitempty" binding:"max=255"`
+}
+
+// CalendarFeedFilter represents filters for listing ICS feeds
+type CalendarFeedFilter struct {
+	OwnerType      *models.CalendarFeedOwner `form:"owner_type"`
+	OwnerID        *uuid.UUID                `form:"owner_id"`
+	IncludeRevoked bool                      `form:"include_revoked"`
+}
+
+// CalendarFeedResponse represents an ICS feed in API responses
+type CalendarFeedResponse struct {
+	ID             uuid.UUID                `json:"id"`
+	OwnerType      models.CalendarFeedOwner `json:"owner_type"`
+	OwnerID        uuid.UUID                `json:"owner_id"`
+	Name           string                   `json:"name,omitempty"`
+	URL            string                   `json:"url"` // Path of the feed; empty once revoked
+	CreatedBy      uuid.UUID                `json:"created_by"`
+	RevokedAt      *time.Time               `json:"revoked_at,omitempty"`
+	LastAccessedAt *time.Time               `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// CreateCertificateTemplateRequest represents a request to create a certificate layout
+type CreateCertificateTemplateRequest struct {
+	Name           string `json:"name" binding:"required,min=2,max=100"`
+	Title          string `json:"title" binding:"required,max=255"`
+	Body           string `json:"body" binding:"required"`
+	Orientation    string `json:"orientation,omitempty" binding:"omitempty,oneof=L P"`
+	SignatoryName  string `json:"signatory_name,omitempty"`
+	SignatoryTitle string `json:"signatory_title,omitempty"`
+	IsDefault      bool   `json:"is_default"`
+}
+
+// UpdateCertificateTemplateRequest represents a request to update a certificate layout
+type UpdateCertificateTemplateRequest struct {
+	Name           *string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Title          *string `json:"title,omitempty" binding:"omitempty,max=255"`
+	Body           *string `json:"body,omitempty"`
+	Orientation    *string `json:"orientation,omitempty" binding:"omitempty,oneof=L P"`
+	SignatoryName  *string `json:"signatory_name,omitempty"`
+	SignatoryTitle *string `json:"signatory_title,omitempty"`
+	IsDefault      *bool   `json:"is_default,omitempty"`
+	IsActive       *bool   `json:"is_active,omitempty"`
+}
+
+// IssueCertificateRequest represents a request to issue a single certificate
+type IssueCertificateRequest struct {
+	StudentID      uuid.UUID  `json:"student_id" binding:"required"`
+	CourseID       uuid.UUID  `json:"course_id" binding:"required"`
+	GroupID        *uuid.UUID `json:"group_id,omitempty"`
+	TemplateID     *uuid.UUID `json:"template_id,omitempty"`
+	CompletionDate *time.Time `json:"completion_date,omitempty"`
+}
+
+// IssueGroupCertificatesRequest represents a request to issue certificates to
+// every eligible student in a group
+type IssueGroupCertificatesRequest struct {
+	TemplateID     *uuid.UUID `json:"template_id,omitempty"`
+	CompletionDate *time.Time `json:"completion_date,omitempty"`
+	// Report card term to check eligibility against; defaults to the latest
+	Term string `json:"term,omitempty"`
+	// Eligibility rules
+	MinAttendance       float64 `json:"min_attendance" binding:"min=0,max=100"`
+	RequirePassingGrade *bool   `json:"require_passing_grade,omitempty"` // defaults to true
+}
+
+// RevokeCertificateRequest represents a request to revoke a certificate
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CertificateTemplateResponse represents a certificate layout in API responses
+type CertificateTemplateResponse struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	Orientation    string    `json:"orientation"`
+	SignatoryName  string    `json:"signatory_name,omitempty"`
+	SignatoryTitle string    `json:"signatory_title,omitempty"`
+	IsDefault      bool      `json:"is_default"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CertificateResponse represents a certificate in API responses
+type CertificateResponse struct {
+	ID               uuid.UUID                `json:"id"`
+	Number           string                   `json:"number"`
+	VerificationCode string                   `json:"verification_code"`
+	VerificationURL  string                   `json:"verification_url"`
+	StudentID        uuid.UUID                `json:"student_id"`
+	StudentName      string                   `json:"student_name"`
+	CourseID         uuid.UUID                `json:"course_id"`
+	CourseName       string                   `json:"course_name"`
+	GroupID          *uuid.UUID               `json:"group_id,omitempty"`
+	TemplateID       *uuid.UUID               `json:"template_id,omitempty"`
+	FinalGrade       string                   `json:"final_grade,omitempty"`
+	FinalPercentage  float64                  `json:"final_percentage"`
+	CompletionDate   time.Time                `json:"completion_date"`
+	Status           models.CertificateStatus `json:"status"`
+	IssuedAt         time.Time                `json:"issued_at"`
+	DocumentID       *uuid.UUID               `json:"document_id,omitempty"`
+	RevokedAt        *time.Time               `json:"revoked_at,omitempty"`
+	RevocationReason string                   `json:"revocation_reason,omitempty"`
+}
+
+// SkippedCertificate explains why a student did not receive a certificate
+type SkippedCertificate struct {
+	StudentID   uuid.UUID `json:"student_id"`
+	StudentName string    `json:"student_name"`
+	Reason      string    `json:"reason"`
+}
+
+// IssueGroupCertificatesResponse represents the outcome of bulk issuing
+type IssueGroupCertificatesResponse struct {
+	Issued  []CertificateResponse `json:"issued"`
+	Skipped []SkippedCertificate  `json:"skipped"`
+}
+
+// CertificateVerificationResponse is the public view of a certificate
+type CertificateVerificationResponse struct {
+	Valid            bool                     `json:"valid"`
+	Status           models.CertificateStatus `json:"status"`
+	Number           string                   `json:"number"`
+	HolderName       string                   `json:"holder_name"`
+	CourseName       string                   `json:"course_name"`
+	CompletionDate   time.Time                `json:"completion_date"`
+	IssuedAt         time.Time                `json:"issued_at"`
+	RevokedAt        *time.Time               `json:"revoked_at,omitempty"`
+	RevocationReason string                   `json:"revocation_reason,omitempty"`
+}
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// GenerateClassSessionsRequest represents a request to materialize a group's
+// lessons from its timetable
+type GenerateClassSessionsRequest struct {
+	From string `json:"from" binding:"required,datetime=2006-01-02"` // YYYY-MM-DD, inclusive
+	To   string `json:"to" binding:"required,datetime=2006-01-02"`   // YYYY-MM-DD, inclusive
+}
+
+// UpdateClassSessionRequest represents a request to update a lesson
+type UpdateClassSessionRequest struct {
+	Topic     *string                    `json:"topic,omitempty" binding:"omitempty,max=255"`
+	Notes     *string                    `json:"notes,omitempty"`
+	TeacherID *uuid.UUID                 `json:"teacher_id,omitempty"`
+	Room      *string                    `json:"room,omitempty" binding:"omitempty,max=100"`
+	Status    *models.ClassSessionStatus `json:"status,omitempty" binding:"omitempty,oneof=scheduled held"`
+}
+
+// CancelClassSessionRequest represents a request to cancel a lesson
+type CancelClassSessionRequest struct {
+	Reason             string `json:"reason" binding:"required,max=500"`
+	CreateMakeUpCredit bool   `json:"create_make_up_credit"`
+	// Days a make-up credit stays valid; 0 means it does not expire
+	MakeUpCreditValidDays int   `json:"make_up_credit_valid_days" binding:"min=0"`
+	Notify                *bool `json:"notify,omitempty"` // defaults to true
+}
+
+// CancelClassSessionsRequest represents a request to cancel a group's lessons in a date range
+type CancelClassSessionsRequest struct {
+	From string `json:"from" binding:"required,datetime=2006-01-02"`
+	To   string `json:"to" binding:"required,datetime=2006-01-02"`
+	CancelClassSessionRequest
+}
+
+// RescheduleClassSessionRequest represents a request to move a lesson to a new slot
+type RescheduleClassSessionRequest struct {
+	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+	StartTime string `json:"start_time" binding:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" binding:"required,datetime=15:04"`
+	Room      string `json:"room,omitempty" binding:"max=100"` // defaults to the current room
+	Reason    string `json:"reason" binding:"max=500"`
+	Notify    *bool  `json:"notify,omitempty"` // defaults to true
+}
+
+// RescheduleClassSessionsRequest represents a request to move a group's lessons
+// in a date range by a number of days and/or to new times
+type RescheduleClassSessionsRequest struct {
+	From      string `json:"from" binding:"required,datetime=2006-01-02"`
+	To        string `json:"to" binding:"required,datetime=2006-01-02"`
+	ShiftDays int    `json:"shift_days"`
+	StartTime string `json:"start_time,omitempty" binding:"omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time,omitempty" binding:"omitempty,datetime=15:04"`
+	Room      string `json:"room,omitempty" binding:"max=100"`
+	Reason    string `json:"reason" binding:"max=500"`
+	Notify    *bool  `json:"notify,omitempty"` // defaults to true
+}
+
+// RedeemMakeUpCreditRequest represents a request to use a make-up credit
+type RedeemMakeUpCreditRequest struct {
+	SessionID uuid.UUID `json:"session_id" binding:"required"`
+}
+
+// ClassSessionResponse represents a lesson in API responses
+type ClassSessionResponse struct {
+	ID          uuid.UUID                 `json:"id"`
+	GroupID     uuid.UUID                 `json:"group_id"`
+	GroupName   string                    `json:"group_name,omitempty"`
+	TimetableID *uuid.UUID                `json:"timetable_id,omitempty"`
+	TeacherID   uuid.UUID                 `json:"teacher_id"`
+	Date        string                    `json:"date"`
+	StartTime   string                    `json:"start_time"`
+	EndTime     string                    `json:"end_time"`
+	Room        string                    `json:"room,omitempty"`
+	Topic       string                    `json:"topic,omitempty"`
+	Status      models.ClassSessionStatus `json:"status"`
+	Notes       string                    `json:"notes,omitempty"`
+
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	RescheduledFromID  *uuid.UUID `json:"rescheduled_from_id,omitempty"`
+	RescheduledToID    *uuid.UUID `json:"rescheduled_to_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GenerateClassSessionsResponse reports the outcome of materializing lessons
+type GenerateClassSessionsResponse struct {
+	Created        int                    `json:"created"`
+	Existing       int                    `json:"existing"`
+	SkippedDaysOff int                    `json:"skipped_days_off"` // Lessons not generated on holidays and term breaks
+	Sessions       []ClassSessionResponse `json:"sessions"`
+}
+
+// SessionChangeResponse reports the outcome of cancelling or rescheduling lessons
+type SessionChangeResponse struct {
+	Sessions          []ClassSessionResponse `json:"sessions"`
+	NotificationsSent int                    `json:"notifications_sent"`
+	MakeUpCredits     int                    `json:"make_up_credits"`
+}
+
+// MakeUpCreditResponse represents a make-up credit in API responses
+type MakeUpCreditResponse struct {
+	ID            uuid.UUID                 `json:"id"`
+	StudentID     uuid.UUID                 `json:"student_id"`
+	GroupID       uuid.UUID                 `json:"group_id"`
+	SessionID     uuid.UUID                 `json:"session_id"`
+	SessionDate   string                    `json:"session_date,omitempty"`
+	Reason        string                    `json:"reason,omitempty"`
+	Status        models.MakeUpCreditStatus `json:"status"`
+	ExpiresAt     *time.Time                `json:"expires_at,omitempty"`
+	UsedSessionID *uuid.UUID                `json:"used_session_id,omitempty"`
+	UsedAt        *time.Time                `json:"used_at,omitempty"`
+	CreatedAt     time.Time                 `json:"created_at"`
+}
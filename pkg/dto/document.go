@@ -9,15 +9,16 @@ import (
 
 // UploadDocumentRequest represents a document upload request
 type UploadDocumentRequest struct {
-	Name        string                 `json:"name" binding:"required,min=3,max=255"`
-	Description string                 `json:"description,omitempty"`
-	Type        models.DocumentType    `json:"type" binding:"required"`
-	StudentID   *uuid.UUID             `json:"student_id,omitempty"`
-	TeacherID   *uuid.UUID             `json:"teacher_id,omitempty"`
-	CourseID    *uuid.UUID             `json:"course_id,omitempty"`
-	GroupID     *uuid.UUID             `json:"group_id,omitempty"`
-	Tags        []string               `json:"tags,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Name          string                 `json:"name" binding:"required,min=3,max=255"`
+	Description   string                 `json:"description,omitempty"`
+	Type          models.DocumentType    `json:"type" binding:"required"`
+	StudentID     *uuid.UUID             `json:"student_id,omitempty"`
+	TeacherID     *uuid.UUID             `json:"teacher_id,omitempty"`
+	CourseID      *uuid.UUID             `json:"course_id,omitempty"`
+	GroupID       *uuid.UUID             `json:"group_id,omitempty"`
+	ApplicationID *uuid.UUID             `json:"application_id,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	// File will be uploaded via multipart form
 }
 
@@ -39,28 +40,29 @@ type ApproveDocumentRequest struct {
 
 // DocumentResponse represents a document response
 type DocumentResponse struct {
-	ID          uuid.UUID              `json:"id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description,omitempty"`
-	Type        models.DocumentType    `json:"type"`
-	Status      models.DocumentStatus  `json:"status"`
-	FileName    string                 `json:"file_name"`
-	FilePath    string                 `json:"file_path"`
-	FileSize    int64                  `json:"file_size"`
-	MimeType    string                 `json:"mime_type"`
-	StudentID   *uuid.UUID             `json:"student_id,omitempty"`
-	TeacherID   *uuid.UUID             `json:"teacher_id,omitempty"`
-	CourseID    *uuid.UUID             `json:"course_id,omitempty"`
-	GroupID     *uuid.UUID             `json:"group_id,omitempty"`
-	UploadedBy  uuid.UUID              `json:"uploaded_by"`
-	UploadedAt  time.Time              `json:"uploaded_at"`
-	ApprovedBy  *uuid.UUID             `json:"approved_by,omitempty"`
-	ApprovedAt  *time.Time             `json:"approved_at,omitempty"`
-	Tags        []string               `json:"tags,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
-	DownloadURL string                 `json:"download_url"`
+	ID            uuid.UUID              `json:"id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	Type          models.DocumentType    `json:"type"`
+	Status        models.DocumentStatus  `json:"status"`
+	FileName      string                 `json:"file_name"`
+	FilePath      string                 `json:"file_path"`
+	FileSize      int64                  `json:"file_size"`
+	MimeType      string                 `json:"mime_type"`
+	StudentID     *uuid.UUID             `json:"student_id,omitempty"`
+	TeacherID     *uuid.UUID             `json:"teacher_id,omitempty"`
+	CourseID      *uuid.UUID             `json:"course_id,omitempty"`
+	GroupID       *uuid.UUID             `json:"group_id,omitempty"`
+	ApplicationID *uuid.UUID             `json:"application_id,omitempty"`
+	UploadedBy    *uuid.UUID             `json:"uploaded_by,omitempty"`
+	UploadedAt    time.Time              `json:"uploaded_at"`
+	ApprovedBy    *uuid.UUID             `json:"approved_by,omitempty"`
+	ApprovedAt    *time.Time             `json:"approved_at,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	DownloadURL   string                 `json:"download_url"`
 }
 
 // DocumentSimple represents a simplified document response
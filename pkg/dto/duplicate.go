@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// DuplicateFilter selects duplicate candidates
+type DuplicateFilter struct {
+	// Only pairs involving this kind of record: student, parent, application or waitlist
+	Type     string  `form:"type" binding:"omitempty,oneof=student parent application waitlist"`
+	MinScore float64 `form:"min_score" binding:"omitempty,min=0,max=1"` // Defaults to 0.7
+	Limit    int     `form:"limit" binding:"omitempty,min=1,max=500"`   // Defaults to 100
+}
+
+// DuplicateRecord is one side of a duplicate candidate pair
+type DuplicateRecord struct {
+	Type  string    `json:"type"` // student, parent, application or waitlist
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email,omitempty"`
+	Phone string    `json:"phone,omitempty"` // E.164
+}
+
+// DuplicateCandidate is a pair of records that probably describe the same person
+type DuplicateCandidate struct {
+	Left    DuplicateRecord `json:"left"`
+	Right   DuplicateRecord `json:"right"`
+	Score   float64         `json:"score"`   // 0-1
+	Reasons []string        `json:"reasons"` // same_email, same_phone, similar_name
+	// Both records are students or both are parents, so they can be merged
+	Mergeable bool `json:"mergeable"`
+}
+
+// MergeRecordsRequest represents a request to merge a duplicate into the record that survives
+type MergeRecordsRequest struct {
+	EntityType models.MergeEntity `json:"entity_type" binding:"required,oneof=student parent"`
+	SurvivorID uuid.UUID          `json:"survivor_id" binding:"required"`
+	MergedID   uuid.UUID          `json:"merged_id" binding:"required"`
+	Score      float64            `json:"score,omitempty"`
+}
+
+// RecordMergeResponse represents a merge in API responses
+type RecordMergeResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	EntityType models.MergeEntity     `json:"entity_type"`
+	SurvivorID uuid.UUID              `json:"survivor_id"`
+	MergedID   uuid.UUID              `json:"merged_id"`
+	Score      float64                `json:"score,omitempty"`
+	Moved      map[string]int         `json:"moved"` // Rows re-pointed per "table.column"
+	Filled     map[string]interface{} `json:"filled,omitempty"`
+	MergedBy   *uuid.UUID             `json:"merged_by,omitempty"`
+	MergedAt   time.Time              `json:"merged_at"`
+	UndoneBy   *uuid.UUID             `json:"undone_by,omitempty"`
+	UndoneAt   *time.Time             `json:"undone_at,omitempty"`
+}
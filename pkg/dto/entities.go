@@ -4,34 +4,68 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
 )
 
 // CreateTeacherRequest represents a request to create a teacher
 type CreateTeacherRequest struct {
-	Name    string `json:"name" binding:"required,min=2,max=100"`
-	Surname string `json:"surname" binding:"required,min=2,max=100"`
-	Phone   string `json:"phone" binding:"required,len=12"`
-	Email   string `json:"email" binding:"omitempty,email"`
+	Name           string                       `json:"name" binding:"required,min=2,max=100"`
+	Surname        string                       `json:"surname" binding:"required,min=2,max=100"`
+	Phone          string                       `json:"phone" binding:"required,len=12"`
+	Email          string                       `json:"email" binding:"omitempty,email"`
+	Status         models.TeacherStatus         `json:"status,omitempty" binding:"omitempty,oneof=active on_leave left"` // Defaults to active
+	Qualifications []string                     `json:"qualifications,omitempty"`
+	Subjects       []string                     `json:"subjects,omitempty"`
+	HourlyRate     float64                      `json:"hourly_rate,omitempty" binding:"min=0"`
+	MaxWeeklyHours float64                      `json:"max_weekly_hours,omitempty" binding:"min=0,max=168"` // 0 uses the default limit
+	Availability   []TeacherAvailabilityRequest `json:"availability,omitempty" binding:"omitempty,dive"`    // None means any time
 }
 
-// UpdateTeacherRequest represents a request to update a teacher
+// UpdateTeacherRequest represents a request to update a teacher. Omitted
+// profile fields and availability are kept.
 type UpdateTeacherRequest struct {
-	Name    string `json:"name" binding:"required,min=2,max=100"`
-	Surname string `json:"surname" binding:"required,min=2,max=100"`
-	Phone   string `json:"phone" binding:"required,len=12"`
-	Email   string `json:"email" binding:"omitempty,email"`
+	Name           string                       `json:"name" binding:"required,min=2,max=100"`
+	Surname        string                       `json:"surname" binding:"required,min=2,max=100"`
+	Phone          string                       `json:"phone" binding:"required,len=12"`
+	Email          string                       `json:"email" binding:"omitempty,email"`
+	Status         models.TeacherStatus         `json:"status,omitempty" binding:"omitempty,oneof=active on_leave left"`
+	Qualifications []string                     `json:"qualifications,omitempty"`
+	Subjects       []string                     `json:"subjects,omitempty"`
+	HourlyRate     *float64                     `json:"hourly_rate,omitempty" binding:"omitempty,min=0"`
+	MaxWeeklyHours *float64                     `json:"max_weekly_hours,omitempty" binding:"omitempty,min=0,max=168"`
+	Availability   []TeacherAvailabilityRequest `json:"availability,omitempty" binding:"omitempty,dive"` // Replaces the windows; [] clears them
+}
+
+// TeacherAvailabilityRequest is a weekly window a teacher can teach in
+type TeacherAvailabilityRequest struct {
+	Weekday   string `json:"weekday" binding:"required"`    // mon..sun or the full day name
+	StartTime string `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string `json:"end_time" binding:"required"`   // HH:MM
+}
+
+// TeacherAvailabilityResponse represents a weekly availability window
+type TeacherAvailabilityResponse struct {
+	Weekday   models.Weekday `json:"weekday"`
+	StartTime string         `json:"start_time"`
+	EndTime   string         `json:"end_time"`
 }
 
 // TeacherResponse represents a teacher response
 type TeacherResponse struct {
-	ID        uuid.UUID     `json:"id"`
-	Name      string        `json:"name"`
-	Surname   string        `json:"surname"`
-	Phone     string        `json:"phone"`
-	Email     string        `json:"email"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
-	Groups    []GroupSimple `json:"groups,omitempty"`
+	ID             uuid.UUID                     `json:"id"`
+	Name           string                        `json:"name"`
+	Surname        string                        `json:"surname"`
+	Phone          string                        `json:"phone"`
+	Email          string                        `json:"email"`
+	Status         models.TeacherStatus          `json:"status"`
+	Qualifications []string                      `json:"qualifications"`
+	Subjects       []string                      `json:"subjects"`
+	HourlyRate     float64                       `json:"hourly_rate"`
+	MaxWeeklyHours float64                       `json:"max_weekly_hours"` // Limit in effect
+	Availability   []TeacherAvailabilityResponse `json:"availability"`     // Empty means any time
+	CreatedAt      time.Time                     `json:"created_at"`
+	UpdatedAt      time.Time                     `json:"updated_at"`
+	Groups         []GroupSimple                 `json:"groups,omitempty"`
 }
 
 // TeacherSimple represents a simplified teacher (for nested responses)
@@ -44,35 +78,56 @@ type TeacherSimple struct {
 
 // CreateCourseRequest represents a request to create a course
 type CreateCourseRequest struct {
-	Title      string  `json:"title" binding:"required,min=2,max=200"`
-	MonthlyFee float64 `json:"monthly_fee" binding:"required,min=0"`
-	Duration   int     `json:"duration" binding:"required,min=1,max=60"`
+	Title           string             `json:"title" binding:"required,min=2,max=200"`
+	MonthlyFee      float64            `json:"monthly_fee" binding:"required,min=0"`
+	Duration        int                `json:"duration" binding:"required,min=1,max=60"`
+	Level           models.CourseLevel `json:"level,omitempty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	Category        string             `json:"category,omitempty" binding:"max=100"`
+	Description     string             `json:"description,omitempty"`
+	PrerequisiteIDs []uuid.UUID        `json:"prerequisite_ids,omitempty"`
 }
 
-// UpdateCourseRequest represents a request to update a course
+// UpdateCourseRequest represents a request to update a course.
+// PrerequisiteIDs, when given, replace the course's prerequisites.
 type UpdateCourseRequest struct {
-	Title      string  `json:"title" binding:"required,min=2,max=200"`
-	MonthlyFee float64 `json:"monthly_fee" binding:"required,min=0"`
-	Duration   int     `json:"duration" binding:"required,min=1,max=60"`
+	Title           string             `json:"title" binding:"required,min=2,max=200"`
+	MonthlyFee      float64            `json:"monthly_fee" binding:"required,min=0"`
+	Duration        int                `json:"duration" binding:"required,min=1,max=60"`
+	Level           models.CourseLevel `json:"level,omitempty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	Category        string             `json:"category,omitempty" binding:"max=100"`
+	Description     string             `json:"description,omitempty"`
+	PrerequisiteIDs *[]uuid.UUID       `json:"prerequisite_ids,omitempty"`
+}
+
+// CourseFilter represents filters for listing courses
+type CourseFilter struct {
+	Level    models.CourseLevel `form:"level"`
+	Category string             `form:"category"`
 }
 
 // CourseResponse represents a course response
 type CourseResponse struct {
-	ID         uuid.UUID     `json:"id"`
-	Title      string        `json:"title"`
-	MonthlyFee float64       `json:"monthly_fee"`
-	Duration   int           `json:"duration"`
-	CreatedAt  time.Time     `json:"created_at"`
-	UpdatedAt  time.Time     `json:"updated_at"`
-	Groups     []GroupSimple `json:"groups,omitempty"`
+	ID             uuid.UUID          `json:"id"`
+	Title          string             `json:"title"`
+	MonthlyFee     float64            `json:"monthly_fee"`
+	Duration       int                `json:"duration"`
+	Level          models.CourseLevel `json:"level,omitempty"`
+	Category       string             `json:"category,omitempty"`
+	Description    string             `json:"description,omitempty"`
+	GradingScaleID *uuid.UUID         `json:"grading_scale_id,omitempty"`
+	Prerequisites  []CourseSimple     `json:"prerequisites"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Groups         []GroupSimple      `json:"groups,omitempty"`
 }
 
 // CourseSimple represents a simplified course
 type CourseSimple struct {
-	ID         uuid.UUID `json:"id"`
-	Title      string    `json:"title"`
-	MonthlyFee float64   `json:"monthly_fee"`
-	Duration   int       `json:"duration"`
+	ID         uuid.UUID          `json:"id"`
+	Title      string             `json:"title"`
+	MonthlyFee float64            `json:"monthly_fee"`
+	Duration   int                `json:"duration"`
+	Level      models.CourseLevel `json:"level,omitempty"`
 }
 
 // CreateStudentRequest represents a request to create a student
@@ -81,6 +136,8 @@ type CreateStudentRequest struct {
 	Surname string `json:"surname" binding:"required,min=2,max=100"`
 	Phone   string `json:"phone" binding:"required,len=12"`
 	Email   string `json:"email" binding:"omitempty,email"`
+	// Seat hold the student takes up, e.g. one kept while their payment was pending
+	HoldID *uuid.UUID `json:"hold_id,omitempty"`
 }
 
 // UpdateStudentRequest represents a request to update a student
@@ -91,6 +148,14 @@ type UpdateStudentRequest struct {
 	Email   string `json:"email" binding:"omitempty,email"`
 }
 
+// TransferStudentRequest represents a request to move a student to another group
+type TransferStudentRequest struct {
+	ToGroupID uuid.UUID             `json:"to_group_id" binding:"required"`
+	Reason    models.TransferReason `json:"reason,omitempty" binding:"omitempty,oneof=schedule_conflict teacher_request student_request performance capacity other"`
+	Notes     string                `json:"notes,omitempty"`
+	HoldID    *uuid.UUID            `json:"hold_id,omitempty"` // Seat hold in the destination group to take up
+}
+
 // StudentResponse represents a student response
 type StudentResponse struct {
 	ID        uuid.UUID   `json:"id"`
@@ -100,6 +165,8 @@ type StudentResponse struct {
 	Email     string      `json:"email"`
 	GroupID   uuid.UUID   `json:"group_id"`
 	Group     GroupSimple `json:"group,omitempty"`
+	LeadID    *uuid.UUID  `json:"lead_id,omitempty"`
+	Warnings  []string    `json:"warnings,omitempty"` // E.g. missing course prerequisites
 	CreatedAt time.Time   `json:"created_at"`
 	UpdatedAt time.Time   `json:"updated_at"`
 }
@@ -142,6 +209,7 @@ type GroupResponse struct {
 	TimetableID  uuid.UUID       `json:"timetable_id"`
 	Capacity     int             `json:"capacity"`
 	StudentCount int             `json:"student_count"`
+	TermID       *uuid.UUID      `json:"term_id,omitempty"`
 	Course       CourseSimple    `json:"course,omitempty"`
 	Teacher      TeacherSimple   `json:"teacher,omitempty"`
 	Timetable    TimetableSimple `json:"timetable,omitempty"`
@@ -158,39 +226,67 @@ type GroupSimple struct {
 	Capacity  int       `json:"capacity"`
 }
 
-// CreateTimetableRequest represents a request to create a timetable
+// TimetableSlotRequest represents one weekly lesson of a timetable
+type TimetableSlotRequest struct {
+	Weekday   string     `json:"weekday" binding:"required"`    // mon..sun or the full day name
+	StartTime string     `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string     `json:"end_time" binding:"required"`   // HH:MM
+	RoomID    *uuid.UUID `json:"room_id,omitempty"`             // Defaults to the timetable's room
+	Classroom string     `json:"classroom,omitempty" binding:"max=50"`
+}
+
+// CreateTimetableRequest represents a request to create a timetable. Either
+// slots are given, or days with one start and end time for all of them.
 type CreateTimetableRequest struct {
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Days      string `json:"days" binding:"required"`
-	Classroom string `json:"classroom" binding:"required,min=1,max=50"`
+	Slots     []TimetableSlotRequest `json:"slots,omitempty" binding:"omitempty,dive"`
+	StartTime string                 `json:"start_time" binding:"required_without=Slots"`
+	EndTime   string                 `json:"end_time" binding:"required_without=Slots"`
+	Days      string                 `json:"days" binding:"required_without=Slots"`
+	Classroom string                 `json:"classroom" binding:"required_without=RoomID,max=50"`
+	RoomID    *uuid.UUID             `json:"room_id,omitempty"` // Sets classroom to the room's name
 }
 
-// UpdateTimetableRequest represents a request to update a timetable
+// UpdateTimetableRequest represents a request to update a timetable; the
+// slots replace the existing ones
 type UpdateTimetableRequest struct {
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Days      string `json:"days" binding:"required"`
-	Classroom string `json:"classroom" binding:"required,min=1,max=50"`
+	Slots     []TimetableSlotRequest `json:"slots,omitempty" binding:"omitempty,dive"`
+	StartTime string                 `json:"start_time" binding:"required_without=Slots"`
+	EndTime   string                 `json:"end_time" binding:"required_without=Slots"`
+	Days      string                 `json:"days" binding:"required_without=Slots"`
+	Classroom string                 `json:"classroom" binding:"required_without=RoomID,max=50"`
+	RoomID    *uuid.UUID             `json:"room_id,omitempty"`
+}
+
+// TimetableSlotResponse represents a timetable slot
+type TimetableSlotResponse struct {
+	ID        uuid.UUID      `json:"id"`
+	Weekday   models.Weekday `json:"weekday"`
+	StartTime string         `json:"start_time"`
+	EndTime   string         `json:"end_time"`
+	RoomID    *uuid.UUID     `json:"room_id,omitempty"`
+	Classroom string         `json:"classroom"`
 }
 
 // TimetableResponse represents a timetable response
 type TimetableResponse struct {
-	ID        uuid.UUID     `json:"id"`
-	StartTime string        `json:"start_time"`
-	EndTime   string        `json:"end_time"`
-	Days      string        `json:"days"`
-	Classroom string        `json:"classroom"`
-	Groups    []GroupSimple `json:"groups,omitempty"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	ID        uuid.UUID               `json:"id"`
+	StartTime string                  `json:"start_time"`
+	EndTime   string                  `json:"end_time"`
+	Days      string                  `json:"days"`
+	Classroom string                  `json:"classroom"`
+	RoomID    *uuid.UUID              `json:"room_id,omitempty"`
+	Slots     []TimetableSlotResponse `json:"slots"`
+	Groups    []GroupSimple           `json:"groups,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
 }
 
 // TimetableSimple represents a simplified timetable
 type TimetableSimple struct {
-	ID        uuid.UUID `json:"id"`
-	StartTime string    `json:"start_time"`
-	EndTime   string    `json:"end_time"`
-	Days      string    `json:"days"`
-	Classroom string    `json:"classroom"`
+	ID        uuid.UUID               `json:"id"`
+	StartTime string                  `json:"start_time"`
+	EndTime   string                  `json:"end_time"`
+	Days      string                  `json:"days"`
+	Classroom string                  `json:"classroom"`
+	Slots     []TimetableSlotResponse `json:"slots,omitempty"`
 }
@@ -17,9 +17,10 @@ type CreateExamRequest struct {
 	StartTime    time.Time              `json:"start_time" binding:"required"`
 	EndTime      time.Time              `json:"end_time" binding:"required"`
 	Duration     int                    `json:"duration" binding:"required"`
-	TotalMarks   int                    `json:"total_marks" binding:"required"`
-	PassingMarks int                    `json:"passing_marks" binding:"required"`
+	TotalMarks   int                    `json:"total_marks" binding:"required,min=1"`
+	PassingMarks int                    `json:"passing_marks" binding:"min=0"` // 0 uses the course grading scale
 	Location     string                 `json:"location,omitempty"`
+	RoomID       *uuid.UUID             `json:"room_id,omitempty"` // Sets location to the room's name
 	Instructions string                 `json:"instructions,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -33,9 +34,10 @@ type UpdateExamRequest struct {
 	StartTime    *time.Time             `json:"start_time,omitempty"`
 	EndTime      *time.Time             `json:"end_time,omitempty"`
 	Duration     *int                   `json:"duration,omitempty"`
-	TotalMarks   *int                   `json:"total_marks,omitempty"`
-	PassingMarks *int                   `json:"passing_marks,omitempty"`
+	TotalMarks   *int                   `json:"total_marks,omitempty" binding:"omitempty,min=1"`
+	PassingMarks *int                   `json:"passing_marks,omitempty" binding:"omitempty,min=0"`
 	Location     *string                `json:"location,omitempty"`
+	RoomID       *uuid.UUID             `json:"room_id,omitempty"`
 	Instructions *string                `json:"instructions,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 }
@@ -55,36 +57,63 @@ type ExamResponse struct {
 	TotalMarks   int                    `json:"total_marks"`
 	PassingMarks int                    `json:"passing_marks"`
 	Location     string                 `json:"location,omitempty"`
+	RoomID       *uuid.UUID             `json:"room_id,omitempty"`
+	TermID       *uuid.UUID             `json:"term_id,omitempty"`
 	Instructions string                 `json:"instructions,omitempty"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 	CreatedBy    uuid.UUID              `json:"created_by"`
+	PublishedAt  *time.Time             `json:"published_at,omitempty"`
 	CreatedAt    time.Time              `json:"created_at"`
 	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
-// SubmitExamResultRequest represents a request to submit exam result
+// SubmitExamResultRequest represents a request to submit exam result.
+// Submitting again for the same student replaces the earlier result.
 type SubmitExamResultRequest struct {
 	StudentID     uuid.UUID `json:"student_id" binding:"required"`
-	MarksObtained float64   `json:"marks_obtained" binding:"required"`
+	MarksObtained float64   `json:"marks_obtained" binding:"min=0"`
 	Remarks       string    `json:"remarks,omitempty"`
 	Absent        bool      `json:"absent,omitempty"`
 }
 
+// BulkExamResultsRequest represents result entry for several students of the exam's group
+type BulkExamResultsRequest struct {
+	Results []SubmitExamResultRequest `json:"results" binding:"required,min=1,dive"`
+}
+
+// BulkExamResultsResponse represents the outcome of bulk result entry
+type BulkExamResultsResponse struct {
+	TotalRequested int                  `json:"total_requested"`
+	TotalSaved     int                  `json:"total_saved"`
+	TotalFailed    int                  `json:"total_failed"`
+	Saved          []ExamResultResponse `json:"saved"`
+	Failed         []BulkFailedItem     `json:"failed,omitempty"`
+}
+
+// PublishExamResponse represents the outcome of publishing exam results
+type PublishExamResponse struct {
+	Exam              ExamResponse `json:"exam"`
+	ResultsPublished  int          `json:"results_published"`
+	NotificationsSent int          `json:"notifications_sent"`
+}
+
 // ExamResultResponse represents an exam result response
 type ExamResultResponse struct {
-	ID            uuid.UUID  `json:"id"`
-	ExamID        uuid.UUID  `json:"exam_id"`
-	StudentID     uuid.UUID  `json:"student_id"`
-	MarksObtained float64    `json:"marks_obtained"`
-	Percentage    float64    `json:"percentage"`
-	Grade         string     `json:"grade,omitempty"`
-	Passed        bool       `json:"passed"`
-	Remarks       string     `json:"remarks,omitempty"`
-	Absent        bool       `json:"absent"`
-	GradedBy      uuid.UUID  `json:"graded_by,omitempty"`
-	GradedAt      *time.Time `json:"graded_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID                    uuid.UUID  `json:"id"`
+	ExamID                uuid.UUID  `json:"exam_id"`
+	StudentID             uuid.UUID  `json:"student_id"`
+	MarksObtained         float64    `json:"marks_obtained"`
+	Percentage            float64    `json:"percentage"`
+	Grade                 string     `json:"grade,omitempty"`
+	GradePoints           float64    `json:"grade_points"`
+	Passed                bool       `json:"passed"`
+	Remarks               string     `json:"remarks,omitempty"`
+	Absent                bool       `json:"absent"`
+	GradedBy              uuid.UUID  `json:"graded_by,omitempty"`
+	GradedAt              *time.Time `json:"graded_at,omitempty"`
+	GradingScaleVersionID *uuid.UUID `json:"grading_scale_version_id,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
 // ExamStatistics represents exam statistics
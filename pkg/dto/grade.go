@@ -25,15 +25,20 @@ type UpdateGradeRequest struct {
 
 // GradeResponse represents a grade response
 type GradeResponse struct {
-	ID        uuid.UUID     `json:"id"`
-	StudentID uuid.UUID     `json:"student_id"`
-	GroupID   uuid.UUID     `json:"group_id"`
-	CourseID  uuid.UUID     `json:"course_id"`
-	Value     int           `json:"value"`
-	Type      string        `json:"type"`
-	Date      string        `json:"date"`
-	Notes     string        `json:"notes"`
-	Student   StudentSimple `json:"student,omitempty"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	ID                    uuid.UUID     `json:"id"`
+	StudentID             uuid.UUID     `json:"student_id"`
+	GroupID               uuid.UUID     `json:"group_id"`
+	CourseID              uuid.UUID     `json:"course_id"`
+	Value                 int           `json:"value"`
+	Type                  string        `json:"type"`
+	Date                  string        `json:"date"`
+	Notes                 string        `json:"notes"`
+	Label                 string        `json:"label,omitempty"`
+	GPAPoints             float64       `json:"gpa_points"`
+	Passed                bool          `json:"passed"`
+	GradingScaleVersionID *uuid.UUID    `json:"grading_scale_version_id,omitempty"`
+	TermID                *uuid.UUID    `json:"term_id,omitempty"`
+	Student               StudentSimple `json:"student,omitempty"`
+	CreatedAt             time.Time     `json:"created_at"`
+	UpdatedAt             time.Time     `json:"updated_at"`
 }
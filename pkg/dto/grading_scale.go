@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// GradingBandRequest represents a single band in a grading scale request
+type GradingBandRequest struct {
+	Label         string  `json:"label" binding:"required,max=20"`
+	MinPercentage float64 `json:"min_percentage" binding:"min=0,max=100"`
+	GPAPoints     float64 `json:"gpa_points" binding:"min=0"`
+}
+
+// CreateGradingScaleRequest represents a request to create a grading scale
+type CreateGradingScaleRequest struct {
+	Name          string                  `json:"name" binding:"required,min=2,max=100"`
+	Description   string                  `json:"description,omitempty"`
+	Type          models.GradingScaleType `json:"type" binding:"required,oneof=letter five_point ten_point pass_fail"`
+	Bands         []GradingBandRequest    `json:"bands" binding:"required,min=1,dive"`
+	PassThreshold float64                 `json:"pass_threshold" binding:"min=0,max=100"`
+	IsDefault     bool                    `json:"is_default"`
+}
+
+// UpdateGradingScaleRequest represents a request to update a grading scale.
+// Changing bands or the pass threshold creates a new version.
+type UpdateGradingScaleRequest struct {
+	Name          *string              `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Description   *string              `json:"description,omitempty"`
+	Bands         []GradingBandRequest `json:"bands,omitempty" binding:"omitempty,min=1,dive"`
+	PassThreshold *float64             `json:"pass_threshold,omitempty" binding:"omitempty,min=0,max=100"`
+	IsDefault     *bool                `json:"is_default,omitempty"`
+	IsActive      *bool                `json:"is_active,omitempty"`
+	ChangeNotes   string               `json:"change_notes,omitempty"`
+}
+
+// AssignGradingScaleRequest represents a request to assign a grading scale to a course
+type AssignGradingScaleRequest struct {
+	GradingScaleID *uuid.UUID `json:"grading_scale_id"` // nil clears the assignment
+}
+
+// GradingScaleVersionResponse represents a grading scale version in API responses
+type GradingScaleVersionResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	Version       int                  `json:"version"`
+	Bands         []models.GradingBand `json:"bands"`
+	PassThreshold float64              `json:"pass_threshold"`
+	ChangeNotes   string               `json:"change_notes,omitempty"`
+	CreatedBy     *uuid.UUID           `json:"created_by,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// GradingScaleResponse represents a grading scale in API responses
+type GradingScaleResponse struct {
+	ID             uuid.UUID                     `json:"id"`
+	Name           string                        `json:"name"`
+	Description    string                        `json:"description,omitempty"`
+	Type           models.GradingScaleType       `json:"type"`
+	IsDefault      bool                          `json:"is_default"`
+	IsActive       bool                          `json:"is_active"`
+	CurrentVersion *GradingScaleVersionResponse  `json:"current_version,omitempty"`
+	Versions       []GradingScaleVersionResponse `json:"versions,omitempty"`
+	CreatedAt      time.Time                     `json:"created_at"`
+	UpdatedAt      time.Time                     `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// PublicApplicationRequest represents an application submitted from the
+// website. Supporting files are sent as "documents" in a multipart form.
+type PublicApplicationRequest struct {
+	FirstName         string `json:"first_name" form:"first_name" binding:"required,max=100"`
+	LastName          string `json:"last_name" form:"last_name" binding:"required,max=100"`
+	Email             string `json:"email" form:"email" binding:"required,email"`
+	Phone             string `json:"phone" form:"phone" binding:"required,max=20"`
+	DateOfBirth       string `json:"date_of_birth" form:"date_of_birth" binding:"required,datetime=2006-01-02"`
+	Gender            string `json:"gender,omitempty" form:"gender" binding:"max=20"`
+	Address           string `json:"address,omitempty" form:"address"`
+	CourseID          string `json:"course_id" form:"course_id" binding:"required,uuid"`
+	PreviousEducation string `json:"previous_education,omitempty" form:"previous_education"`
+	FormToken         string `json:"form_token" form:"form_token" binding:"required"`
+	Website           string `json:"website,omitempty" form:"website"` // Honeypot: hidden from people, filled in by bots
+}
+
+// FormTokenResponse represents a signed token for the public application form
+type FormTokenResponse struct {
+	Token     string    `json:"token"`
+	NotBefore time.Time `json:"not_before"` // Forms submitted earlier are rejected
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PublicApplicationResponse represents an accepted public application
+type PublicApplicationResponse struct {
+	ApplicationID uuid.UUID                `json:"application_id"`
+	Status        models.ApplicationStatus `json:"status"`
+	StatusURL     string                   `json:"status_url"`
+	Documents     int                      `json:"documents"`
+}
+
+// PublicApplicationStatusResponse represents what an applicant sees on the
+// status-check page
+type PublicApplicationStatusResponse struct {
+	FirstName       string                   `json:"first_name"`
+	CourseTitle     string                   `json:"course_title"`
+	Status          models.ApplicationStatus `json:"status"`
+	ApplicationDate time.Time                `json:"application_date"`
+	ReviewedAt      *time.Time               `json:"reviewed_at,omitempty"`
+	EnrolledAt      *time.Time               `json:"enrolled_at,omitempty"`
+	Documents       int                      `json:"documents"`
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// CreateLeadRequest represents a request to record an inquiry
+type CreateLeadRequest struct {
+	FirstName      string            `json:"first_name" binding:"required,max=100"`
+	LastName       string            `json:"last_name,omitempty" binding:"max=100"`
+	Email          string            `json:"email,omitempty" binding:"omitempty,email"`
+	Phone          string            `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Source         models.LeadSource `json:"source" binding:"required,oneof=website phone walk_in referral social event other"`
+	Campaign       string            `json:"campaign,omitempty" binding:"max=200"`
+	CourseID       *uuid.UUID        `json:"course_id,omitempty"`
+	OwnerID        *uuid.UUID        `json:"owner_id,omitempty"` // Defaults to the creating user
+	NextFollowUpAt *time.Time        `json:"next_follow_up_at,omitempty"`
+	Notes          string            `json:"notes,omitempty"`
+}
+
+// UpdateLeadRequest represents a request to update a lead's details.
+// The stage is changed through ChangeLeadStageRequest.
+type UpdateLeadRequest struct {
+	FirstName      *string            `json:"first_name,omitempty" binding:"omitempty,min=1,max=100"`
+	LastName       *string            `json:"last_name,omitempty" binding:"omitempty,max=100"`
+	Email          *string            `json:"email,omitempty" binding:"omitempty,email"`
+	Phone          *string            `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Source         *models.LeadSource `json:"source,omitempty" binding:"omitempty,oneof=website phone walk_in referral social event other"`
+	Campaign       *string            `json:"campaign,omitempty" binding:"omitempty,max=200"`
+	CourseID       *uuid.UUID         `json:"course_id,omitempty"`
+	OwnerID        *uuid.UUID         `json:"owner_id,omitempty"`
+	NextFollowUpAt *time.Time         `json:"next_follow_up_at,omitempty"`
+	ClearFollowUp  bool               `json:"clear_follow_up,omitempty"`
+	Notes          *string            `json:"notes,omitempty"`
+}
+
+// ChangeLeadStageRequest moves a lead through the pipeline
+type ChangeLeadStageRequest struct {
+	Stage      models.LeadStage `json:"stage" binding:"required,oneof=new contacted trial negotiation won lost"`
+	LostReason string           `json:"lost_reason,omitempty"` // Required when the stage is lost
+	Notes      string           `json:"notes,omitempty"`
+}
+
+// CreateLeadActivityRequest logs an interaction with a lead
+type CreateLeadActivityRequest struct {
+	Type           models.LeadActivityType `json:"type" binding:"required,oneof=call message meeting note"`
+	Summary        string                  `json:"summary" binding:"required,max=500"`
+	Details        string                  `json:"details,omitempty"`
+	OccurredAt     *time.Time              `json:"occurred_at,omitempty"`       // Defaults to now
+	NextFollowUpAt *time.Time              `json:"next_follow_up_at,omitempty"` // Replaces the lead's follow-up
+}
+
+// ConvertLeadRequest turns a won lead into an application or a student
+type ConvertLeadRequest struct {
+	Target      string     `json:"target" binding:"required,oneof=application student"`
+	CourseID    *uuid.UUID `json:"course_id,omitempty"`     // Application course; defaults to the lead's course
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"` // Required for applications
+	GroupID     *uuid.UUID `json:"group_id,omitempty"`      // Required for students
+}
+
+// LeadFilter represents the filters of the lead list
+type LeadFilter struct {
+	Stage       models.LeadStage  `form:"stage" binding:"omitempty,oneof=new contacted trial negotiation won lost"`
+	Source      models.LeadSource `form:"source"`
+	OwnerID     string            `form:"owner_id" binding:"omitempty,uuid"`
+	CourseID    string            `form:"course_id" binding:"omitempty,uuid"`
+	FollowUpDue bool              `form:"follow_up_due"` // Open leads whose follow-up is due, earliest first
+}
+
+// LeadResponse represents a lead in API responses
+type LeadResponse struct {
+	ID              uuid.UUID         `json:"id"`
+	FirstName       string            `json:"first_name"`
+	LastName        string            `json:"last_name,omitempty"`
+	Email           string            `json:"email,omitempty"`
+	Phone           string            `json:"phone,omitempty"`
+	Source          models.LeadSource `json:"source"`
+	Campaign        string            `json:"campaign,omitempty"`
+	CourseID        *uuid.UUID        `json:"course_id,omitempty"`
+	CourseTitle     string            `json:"course_title,omitempty"`
+	OwnerID         *uuid.UUID        `json:"owner_id,omitempty"`
+	OwnerName       string            `json:"owner_name,omitempty"`
+	Stage           models.LeadStage  `json:"stage"`
+	LostReason      string            `json:"lost_reason,omitempty"`
+	StageChangedAt  time.Time         `json:"stage_changed_at"`
+	ClosedAt        *time.Time        `json:"closed_at,omitempty"`
+	NextFollowUpAt  *time.Time        `json:"next_follow_up_at,omitempty"`
+	FollowUpOverdue bool              `json:"follow_up_overdue,omitempty"`
+	LastContactedAt *time.Time        `json:"last_contacted_at,omitempty"`
+	ApplicationID   *uuid.UUID        `json:"application_id,omitempty"`
+	StudentID       *uuid.UUID        `json:"student_id,omitempty"`
+	ConvertedAt     *time.Time        `json:"converted_at,omitempty"`
+	Notes           string            `json:"notes,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// LeadActivityResponse represents a lead history entry in API responses
+type LeadActivityResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	LeadID     uuid.UUID               `json:"lead_id"`
+	UserID     *uuid.UUID              `json:"user_id,omitempty"`
+	UserName   string                  `json:"user_name,omitempty"`
+	Type       models.LeadActivityType `json:"type"`
+	Summary    string                  `json:"summary"`
+	Details    string                  `json:"details,omitempty"`
+	FromStage  models.LeadStage        `json:"from_stage,omitempty"`
+	ToStage    models.LeadStage        `json:"to_stage,omitempty"`
+	OccurredAt time.Time               `json:"occurred_at"`
+}
+
+// LeadConversionResponse represents the result of converting a lead
+type LeadConversionResponse struct {
+	Lead        *LeadResponse        `json:"lead"`
+	Application *ApplicationResponse `json:"application,omitempty"`
+	Student     *StudentResponse     `json:"student,omitempty"`
+}
@@ -72,6 +72,7 @@ type InvoiceResponse struct {
 	StudentID      uuid.UUID            `json:"student_id"`
 	CourseID       *uuid.UUID           `json:"course_id,omitempty"`
 	GroupID        *uuid.UUID           `json:"group_id,omitempty"`
+	TermID         *uuid.UUID           `json:"term_id,omitempty"`
 	SubTotal       float64              `json:"sub_total"`
 	DiscountAmount float64              `json:"discount_amount"`
 	TaxAmount      float64              `json:"tax_amount"`
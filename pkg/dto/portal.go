@@ -8,16 +8,16 @@ import (
 
 // StudentPortalDashboard represents student portal dashboard data
 type StudentPortalDashboard struct {
-	Student         StudentSimple     `json:"student"`
-	Group           *GroupSimple      `json:"group,omitempty"`
-	Course          *CourseSimple     `json:"course,omitempty"`
-	UpcomingClasses []TimetableSimple `json:"upcoming_classes"`
-	RecentGrades    []GradeInfo       `json:"recent_grades"`
-	UpcomingExams   []ExamSimple      `json:"upcoming_exams"`
-	AttendanceRate  float64           `json:"attendance_rate"`
-	UnreadMessages  int64             `json:"unread_messages"`
-	PendingPayments float64           `json:"pending_payments"`
-	Announcements   []MessageSimple   `json:"announcements"`
+	Student         StudentSimple      `json:"student"`
+	Group           *GroupSimple       `json:"group,omitempty"`
+	Course          *CourseSimple      `json:"course,omitempty"`
+	UpcomingClasses []LessonOccurrence `json:"upcoming_classes"`
+	RecentGrades    []GradeInfo        `json:"recent_grades"`
+	UpcomingExams   []ExamSimple       `json:"upcoming_exams"`
+	AttendanceRate  float64            `json:"attendance_rate"`
+	UnreadMessages  int64              `json:"unread_messages"`
+	PendingPayments float64            `json:"pending_payments"`
+	Announcements   []MessageSimple    `json:"announcements"`
 }
 
 // TeacherPortalDashboard represents teacher portal dashboard data
@@ -25,7 +25,7 @@ type TeacherPortalDashboard struct {
 	Teacher          TeacherSimple      `json:"teacher"`
 	TotalStudents    int64              `json:"total_students"`
 	TotalGroups      int64              `json:"total_groups"`
-	TodayClasses     []TimetableSimple  `json:"today_classes"`
+	TodayClasses     []LessonOccurrence `json:"today_classes"`
 	UpcomingExams    []ExamSimple       `json:"upcoming_exams"`
 	PendingGrading   int64              `json:"pending_grading"`
 	UnreadMessages   int64              `json:"unread_messages"`
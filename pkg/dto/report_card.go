@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GenerateReportCardsRequest represents a request to generate report cards for a group
+type GenerateReportCardsRequest struct {
+	Term string `json:"term" binding:"required,max=50"`
+	// Period the results cover, YYYY-MM-DD inclusive; defaults to the dates
+	// of the academic term named by Term
+	From string `json:"from,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	To   string `json:"to,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	// Weights of the assignment and exam averages in the final grade; default 40/60
+	AssignmentWeight *float64 `json:"assignment_weight,omitempty" binding:"omitempty,min=0,max=100"`
+	ExamWeight       *float64 `json:"exam_weight,omitempty" binding:"omitempty,min=0,max=100"`
+	// Teacher comments keyed by student ID; students without an entry keep their previous comment
+	Comments     map[uuid.UUID]string `json:"comments,omitempty"`
+	EmailParents bool                 `json:"email_parents"`
+}
+
+// GenerateTranscriptsRequest represents a request to generate transcripts for a group
+type GenerateTranscriptsRequest struct {
+	EmailParents bool `json:"email_parents"`
+}
+
+// ReportCardResponse represents a report card in API responses
+type ReportCardResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	StudentID             uuid.UUID  `json:"student_id"`
+	StudentName           string     `json:"student_name"`
+	GroupID               uuid.UUID  `json:"group_id"`
+	GroupName             string     `json:"group_name"`
+	CourseID              uuid.UUID  `json:"course_id"`
+	CourseName            string     `json:"course_name"`
+	Term                  string     `json:"term"`
+	AttendanceRate        float64    `json:"attendance_rate"`
+	AssignmentAverage     float64    `json:"assignment_average"`
+	ExamAverage           float64    `json:"exam_average"`
+	FinalPercentage       float64    `json:"final_percentage"`
+	FinalGrade            string     `json:"final_grade"`
+	GPAPoints             float64    `json:"gpa_points"`
+	Passed                bool       `json:"passed"`
+	GradingScaleVersionID *uuid.UUID `json:"grading_scale_version_id,omitempty"`
+	TeacherComment        string     `json:"teacher_comment,omitempty"`
+	DocumentID            *uuid.UUID `json:"document_id,omitempty"`
+	ParentsNotified       int        `json:"parents_notified"`
+	GeneratedAt           time.Time  `json:"generated_at"`
+}
+
+// TranscriptEntry represents one completed course on a transcript
+type TranscriptEntry struct {
+	CourseID        uuid.UUID `json:"course_id"`
+	CourseName      string    `json:"course_name"`
+	GroupName       string    `json:"group_name"`
+	Term            string    `json:"term"`
+	FinalPercentage float64   `json:"final_percentage"`
+	FinalGrade      string    `json:"final_grade"`
+	GPAPoints       float64   `json:"gpa_points"`
+	Passed          bool      `json:"passed"`
+}
+
+// TranscriptResponse represents a student's cumulative transcript
+type TranscriptResponse struct {
+	StudentID        uuid.UUID         `json:"student_id"`
+	StudentName      string            `json:"student_name"`
+	Courses          []TranscriptEntry `json:"courses"`
+	CoursesCompleted int               `json:"courses_completed"`
+	CoursesPassed    int               `json:"courses_passed"`
+	CumulativeGPA    float64           `json:"cumulative_gpa"`
+	AveragePercent   float64           `json:"average_percentage"`
+	DocumentID       *uuid.UUID        `json:"document_id,omitempty"`
+	ParentsNotified  int               `json:"parents_notified"`
+	GeneratedAt      time.Time         `json:"generated_at"`
+}
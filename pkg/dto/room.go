@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateRoomRequest represents a request to create a room
+type CreateRoomRequest struct {
+	Name           string   `json:"name" binding:"required,min=1,max=50"`
+	Building       string   `json:"building,omitempty" binding:"max=100"`
+	Capacity       int      `json:"capacity" binding:"required,min=1"`
+	Equipment      []string `json:"equipment,omitempty"`
+	AvailableHours float64  `json:"available_hours,omitempty" binding:"min=0,max=168"` // 0 uses 60 hours per week
+}
+
+// UpdateRoomRequest represents a request to update a room
+type UpdateRoomRequest struct {
+	Name           *string  `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
+	Building       *string  `json:"building,omitempty" binding:"omitempty,max=100"`
+	Capacity       *int     `json:"capacity,omitempty" binding:"omitempty,min=1"`
+	Equipment      []string `json:"equipment,omitempty"`
+	AvailableHours *float64 `json:"available_hours,omitempty" binding:"omitempty,min=1,max=168"`
+	IsActive       *bool    `json:"is_active,omitempty"`
+}
+
+// RoomFilter represents filters for listing rooms
+type RoomFilter struct {
+	Building    string `form:"building"`
+	Equipment   string `form:"equipment"`    // Comma-separated tags the room must have
+	MinCapacity int    `form:"min_capacity"` // Seats needed
+	Active      *bool  `form:"active"`
+}
+
+// RoomResponse represents a room in API responses
+type RoomResponse struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Building       string    `json:"building,omitempty"`
+	Capacity       int       `json:"capacity"`
+	Equipment      []string  `json:"equipment"`
+	AvailableHours float64   `json:"available_hours"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RoomUtilization reports how much of a room's weekly hours are booked
+type RoomUtilization struct {
+	RoomID             uuid.UUID `json:"room_id"`
+	Name               string    `json:"name"`
+	Building           string    `json:"building,omitempty"`
+	Capacity           int       `json:"capacity"`
+	AvailableHours     float64   `json:"available_hours"`
+	BookedHours        float64   `json:"booked_hours"`
+	UtilizationPercent float64   `json:"utilization_percent"`
+	LessonHours        float64   `json:"lesson_hours"` // From timetables used by groups
+	ExamHours          float64   `json:"exam_hours"`
+	EventHours         float64   `json:"event_hours"`
+}
+
+// RoomUtilizationReport represents booked against available hours for one week
+type RoomUtilizationReport struct {
+	WeekStart          time.Time         `json:"week_start"`
+	WeekEnd            time.Time         `json:"week_end"`
+	AvailableHours     float64           `json:"available_hours"`
+	BookedHours        float64           `json:"booked_hours"`
+	UtilizationPercent float64           `json:"utilization_percent"`
+	Rooms              []RoomUtilization `json:"rooms"`
+}
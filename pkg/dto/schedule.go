@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// ScheduleConflict describes a booking that clashes with a requested slot
+type ScheduleConflict struct {
+	Kind        string     `json:"kind"`                   // lesson, timetable, exam or event
+	SessionID   *uuid.UUID `json:"session_id,omitempty"`   // nil for timetabled lessons not generated yet
+	TimetableID *uuid.UUID `json:"timetable_id,omitempty"` // set for lessons and weekly slots from a timetable
+	ExamID      *uuid.UUID `json:"exam_id,omitempty"`
+	EventID     *uuid.UUID `json:"event_id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	GroupID     *uuid.UUID `json:"group_id,omitempty"`
+	TeacherID   *uuid.UUID `json:"teacher_id,omitempty"`
+	Date        string     `json:"date,omitempty"` // empty for weekly timetable slots
+	Days        string     `json:"days,omitempty"` // weekly timetable slots only
+	StartTime   string     `json:"start_time"`
+	EndTime     string     `json:"end_time"`
+	Room        string     `json:"room,omitempty"`
+	Reason      string     `json:"reason"` // room, teacher, group or student
+}
+
+// LessonOccurrence is a lesson on a real date: a generated class session, or
+// a timetable slot whose session has not been generated yet
+type LessonOccurrence struct {
+	SessionID   *uuid.UUID                `json:"session_id,omitempty"`
+	TimetableID *uuid.UUID                `json:"timetable_id,omitempty"`
+	GroupID     uuid.UUID                 `json:"group_id"`
+	GroupName   string                    `json:"group_name"`
+	TeacherID   uuid.UUID                 `json:"teacher_id"`
+	Date        time.Time                 `json:"date"`
+	StartTime   string                    `json:"start_time"`
+	EndTime     string                    `json:"end_time"`
+	Room        string                    `json:"room,omitempty"`
+	Status      models.ClassSessionStatus `json:"status"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// CreateSeatHoldRequest represents a request to hold a group seat
+type CreateSeatHoldRequest struct {
+	Reason     models.SeatHoldReason `json:"reason" binding:"required,oneof=payment manual"`
+	HolderName string                `json:"holder_name" binding:"required,max=200"`
+	InvoiceID  *uuid.UUID            `json:"invoice_id,omitempty"`
+	Notes      string                `json:"notes,omitempty"`
+	// When the hold lapses; defaults to 48 hours from now
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SeatHoldResponse represents a seat hold in API responses
+type SeatHoldResponse struct {
+	ID         uuid.UUID             `json:"id"`
+	GroupID    uuid.UUID             `json:"group_id"`
+	Reason     models.SeatHoldReason `json:"reason"`
+	HolderName string                `json:"holder_name,omitempty"`
+	WaitlistID *uuid.UUID            `json:"waitlist_id,omitempty"`
+	InvoiceID  *uuid.UUID            `json:"invoice_id,omitempty"`
+	Notes      string                `json:"notes,omitempty"`
+	ExpiresAt  time.Time             `json:"expires_at"`
+	ConsumedAt *time.Time            `json:"consumed_at,omitempty"`
+	StudentID  *uuid.UUID            `json:"student_id,omitempty"`
+	ReleasedAt *time.Time            `json:"released_at,omitempty"`
+	Active     bool                  `json:"active"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+// GroupAvailabilityResponse represents the seats of a group
+type GroupAvailabilityResponse struct {
+	GroupID   uuid.UUID          `json:"group_id"`
+	Capacity  int                `json:"capacity"`
+	Enrolled  int                `json:"enrolled"`
+	Held      int                `json:"held"`
+	Available int                `json:"available"`
+	Holds     []SeatHoldResponse `json:"holds"` // Active holds, soonest to expire first
+}
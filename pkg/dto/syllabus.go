@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// SyllabusLessonRequest describes a lesson of a syllabus module. Lessons
+// with an ID keep their coverage records.
+type SyllabusLessonRequest struct {
+	ID          *uuid.UUID `json:"id,omitempty"`
+	Title       string     `json:"title" binding:"required,max=255"`
+	Description string     `json:"description,omitempty"`
+}
+
+// SyllabusModuleRequest describes a module of a course syllabus
+type SyllabusModuleRequest struct {
+	ID          *uuid.UUID              `json:"id,omitempty"`
+	Title       string                  `json:"title" binding:"required,max=255"`
+	Description string                  `json:"description,omitempty"`
+	Week        int                     `json:"week,omitempty" binding:"min=0"`
+	Lessons     []SyllabusLessonRequest `json:"lessons" binding:"dive"`
+}
+
+// UpdateSyllabusRequest replaces a course syllabus. Modules and lessons are
+// ordered as given; existing ones left out are deleted.
+type UpdateSyllabusRequest struct {
+	Modules []SyllabusModuleRequest `json:"modules" binding:"dive"`
+}
+
+// SyllabusLessonResponse represents a syllabus lesson in API responses.
+// The coverage fields are set in group progress.
+type SyllabusLessonResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Position    int        `json:"position"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Covered     bool       `json:"covered,omitempty"`
+	SessionID   *uuid.UUID `json:"session_id,omitempty"` // First session that covered it
+	CoveredOn   string     `json:"covered_on,omitempty"` // Date of that session
+}
+
+// SyllabusModuleResponse represents a syllabus module in API responses
+type SyllabusModuleResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Position    int                      `json:"position"`
+	Week        int                      `json:"week,omitempty"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description,omitempty"`
+	Lessons     []SyllabusLessonResponse `json:"lessons"`
+}
+
+// SyllabusResponse represents a course syllabus
+type SyllabusResponse struct {
+	CourseID     uuid.UUID                `json:"course_id"`
+	Modules      []SyllabusModuleResponse `json:"modules"`
+	TotalLessons int                      `json:"total_lessons"`
+}
+
+// SetSessionSyllabusRequest sets the syllabus lessons covered in a class
+// session, replacing earlier ticks
+type SetSessionSyllabusRequest struct {
+	LessonIDs []uuid.UUID `json:"lesson_ids"`
+}
+
+// SessionSyllabusResponse lists the syllabus lessons covered in a session
+type SessionSyllabusResponse struct {
+	SessionID uuid.UUID                `json:"session_id"`
+	Lessons   []SyllabusLessonResponse `json:"lessons"`
+}
+
+// SyllabusProgressResponse reports how far a group is through its course
+// syllabus
+type SyllabusProgressResponse struct {
+	GroupID        uuid.UUID                `json:"group_id"`
+	CourseID       uuid.UUID                `json:"course_id"`
+	TotalLessons   int                      `json:"total_lessons"`
+	CoveredLessons int                      `json:"covered_lessons"`
+	Percent        float64                  `json:"percent"`
+	NextLesson     *SyllabusLessonResponse  `json:"next_lesson,omitempty"`
+	Modules        []SyllabusModuleResponse `json:"modules"`
+}
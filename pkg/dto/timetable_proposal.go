@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// SolveTimetableRequest asks the solver to propose weekly lessons for groups
+type SolveTimetableRequest struct {
+	Groups              []SolverGroupRequest        `json:"groups" binding:"required,min=1,dive"`
+	TeacherAvailability []TeacherAvailabilityWindow `json:"teacher_availability,omitempty" binding:"omitempty,dive"`
+	RoomIDs             []uuid.UUID                 `json:"room_ids,omitempty"`  // Rooms to use; all active rooms by default
+	Days                []string                    `json:"days,omitempty"`      // Teaching days; Monday to Saturday by default
+	DayStart            string                      `json:"day_start,omitempty"` // "HH:MM"; 08:00 by default
+	DayEnd              string                      `json:"day_end,omitempty"`   // "HH:MM"; 20:00 by default
+	// Most lessons a group or teacher may have on one day; 0 means no limit
+	MaxLessonsPerDay int `json:"max_lessons_per_day,omitempty" binding:"min=0,max=10"`
+	// Lessons of a group or teacher on the same day must run back to back
+	NoGaps bool `json:"no_gaps"`
+}
+
+// SolverGroupRequest describes the weekly teaching a group needs
+type SolverGroupRequest struct {
+	GroupID       uuid.UUID   `json:"group_id" binding:"required"`
+	WeeklyHours   float64     `json:"weekly_hours" binding:"required,gt=0,max=40"`
+	LessonMinutes int         `json:"lesson_minutes,omitempty" binding:"omitempty,min=30,max=360"` // 120 by default
+	TeacherID     *uuid.UUID  `json:"teacher_id,omitempty"`                                        // The group's teacher by default
+	Equipment     []string    `json:"equipment,omitempty"`                                         // Tags every room used must have
+	RoomIDs       []uuid.UUID `json:"room_ids,omitempty"`                                          // Rooms the group may use
+}
+
+// TeacherAvailabilityWindow is a weekly period a teacher can teach in. A
+// teacher without windows in the request keeps their saved availability, and
+// one without any windows can teach at any time.
+type TeacherAvailabilityWindow struct {
+	TeacherID uuid.UUID `json:"teacher_id" binding:"required"`
+	Weekday   string    `json:"weekday" binding:"required"`
+	StartTime string    `json:"start_time" binding:"required"`
+	EndTime   string    `json:"end_time" binding:"required"`
+}
+
+// ProposedLessonResponse represents a weekly lesson placed by the solver
+type ProposedLessonResponse struct {
+	GroupID   uuid.UUID      `json:"group_id"`
+	GroupName string         `json:"group_name"`
+	TeacherID uuid.UUID      `json:"teacher_id"`
+	Weekday   models.Weekday `json:"weekday"`
+	StartTime string         `json:"start_time"`
+	EndTime   string         `json:"end_time"`
+	RoomID    uuid.UUID      `json:"room_id"`
+	Room      string         `json:"room"`
+}
+
+// TimetableProposalResponse represents a solver proposal in API responses
+type TimetableProposalResponse struct {
+	ID           uuid.UUID                      `json:"id"`
+	Status       models.TimetableProposalStatus `json:"status"`
+	GroupIDs     []uuid.UUID                    `json:"group_ids"`
+	Lessons      []ProposedLessonResponse       `json:"lessons"`
+	Issues       []models.ProposalIssue         `json:"issues,omitempty"`
+	TimetableIDs []uuid.UUID                    `json:"timetable_ids,omitempty"`
+	CommittedAt  *time.Time                     `json:"committed_at,omitempty"`
+	CreatedAt    time.Time                      `json:"created_at"`
+}
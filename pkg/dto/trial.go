@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// BookTrialRequest represents a request to book a trial lesson for a lead
+// or waitlist prospect
+type BookTrialRequest struct {
+	LeadID     *uuid.UUID `json:"lead_id,omitempty"`     // Either lead_id or waitlist_id
+	WaitlistID *uuid.UUID `json:"waitlist_id,omitempty"` // Waitlist entry of a prospect
+	GroupID    uuid.UUID  `json:"group_id" binding:"required"`
+	Date       string     `json:"date,omitempty" binding:"omitempty,datetime=2006-01-02"` // Required without session_id
+	SessionID  *uuid.UUID `json:"session_id,omitempty"`                                   // Required when the group has several lessons that day
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// RecordTrialOutcomeRequest records whether the prospect came and the teacher's assessment
+type RecordTrialOutcomeRequest struct {
+	Attended *bool               `json:"attended" binding:"required"`
+	Outcome  models.TrialOutcome `json:"outcome,omitempty" binding:"omitempty,oneof=recommended other_level not_recommended undecided"` // Required when attended
+	Feedback string              `json:"feedback,omitempty"`
+}
+
+// CancelTrialRequest represents a request to cancel a trial booking
+type CancelTrialRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// TrialFilter filters trial lessons
+type TrialFilter struct {
+	GroupID    string `form:"group_id"`
+	TeacherID  string `form:"teacher_id"`
+	LeadID     string `form:"lead_id"`
+	WaitlistID string `form:"waitlist_id"`
+	Status     string `form:"status" binding:"omitempty,oneof=booked attended no_show cancelled"`
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// TrialResponse represents a trial lesson in API responses
+type TrialResponse struct {
+	ID                 uuid.UUID           `json:"id"`
+	LeadID             *uuid.UUID          `json:"lead_id,omitempty"`
+	WaitlistID         *uuid.UUID          `json:"waitlist_id,omitempty"`
+	ProspectName       string              `json:"prospect_name"`
+	ProspectEmail      string              `json:"prospect_email,omitempty"`
+	ProspectPhone      string              `json:"prospect_phone,omitempty"`
+	GroupID            uuid.UUID           `json:"group_id"`
+	GroupName          string              `json:"group_name,omitempty"`
+	SessionID          uuid.UUID           `json:"session_id"`
+	CourseID           uuid.UUID           `json:"course_id"`
+	CourseTitle        string              `json:"course_title,omitempty"`
+	TeacherID          uuid.UUID           `json:"teacher_id"`
+	TeacherName        string              `json:"teacher_name,omitempty"`
+	Date               string              `json:"date"`
+	StartTime          string              `json:"start_time,omitempty"`
+	EndTime            string              `json:"end_time,omitempty"`
+	Room               string              `json:"room,omitempty"`
+	Status             models.TrialStatus  `json:"status"`
+	Notes              string              `json:"notes,omitempty"`
+	Outcome            models.TrialOutcome `json:"outcome,omitempty"`
+	Feedback           string              `json:"feedback,omitempty"`
+	RecordedBy         *uuid.UUID          `json:"recorded_by,omitempty"`
+	RecordedAt         *time.Time          `json:"recorded_at,omitempty"`
+	CancellationReason string              `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time          `json:"cancelled_at,omitempty"`
+	BookedBy           *uuid.UUID          `json:"booked_by,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+}
+
+// TrialConversionFilter selects the trials of a conversion report
+type TrialConversionFilter struct {
+	From      string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To        string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	CourseID  string `form:"course_id"`
+	TeacherID string `form:"teacher_id"`
+}
+
+// TrialConversionRow counts the trials of a teacher, a course or all of them
+type TrialConversionRow struct {
+	ID             *uuid.UUID `json:"id,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	Booked         int        `json:"booked"` // Not cancelled
+	Attended       int        `json:"attended"`
+	NoShows        int        `json:"no_shows"`
+	Converted      int        `json:"converted"`       // Attended trials whose prospect became a paying student
+	ConversionRate float64    `json:"conversion_rate"` // Percentage of attended trials converted
+}
+
+// TrialConversionReport shows how many trials became paid enrollments
+type TrialConversionReport struct {
+	From      string               `json:"from,omitempty"`
+	To        string               `json:"to,omitempty"`
+	Total     TrialConversionRow   `json:"total"`
+	ByTeacher []TrialConversionRow `json:"by_teacher"`
+	ByCourse  []TrialConversionRow `json:"by_course"`
+}
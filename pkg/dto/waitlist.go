@@ -73,3 +73,14 @@ type WaitlistStats struct {
 	TotalDeclined int64   `json:"total_declined"`
 	AverageWaitDays float64 `json:"average_wait_days"`
 }
+
+// WaitlistOfferResponse is what a prospect sees through their seat offer link
+type WaitlistOfferResponse struct {
+	ProspectName string                `json:"prospect_name,omitempty"`
+	GroupName    string                `json:"group_name"`
+	CourseTitle  string                `json:"course_title"`
+	StartDate    *time.Time            `json:"start_date,omitempty"`
+	Status       models.WaitlistStatus `json:"status"`
+	ExpiresAt    *time.Time            `json:"expires_at,omitempty"`
+	EnrolledAt   *time.Time            `json:"enrolled_at,omitempty"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// TeacherWorkloadResponse shows a teacher's scheduled hours in one week
+type TeacherWorkloadResponse struct {
+	TeacherID      uuid.UUID            `json:"teacher_id"`
+	TeacherName    string               `json:"teacher_name"`
+	Status         models.TeacherStatus `json:"status"`
+	WeekStart      string               `json:"week_start"` // Monday
+	WeekEnd        string               `json:"week_end"`   // Sunday
+	MaxWeeklyHours float64              `json:"max_weekly_hours"`
+	// Weekly hours of the timetables of the teacher's groups, the baseline
+	// checked when assigning groups
+	TimetableHours float64         `json:"timetable_hours"`
+	LessonHours    float64         `json:"lesson_hours"` // Lessons this week, with substitutions, cancellations and days off
+	ExamHours      float64         `json:"exam_hours"`   // Exams of the teacher's groups this week
+	TotalHours     float64         `json:"total_hours"`
+	Overloaded     bool            `json:"overloaded"` // Total hours above the limit
+	Groups         []WorkloadGroup `json:"groups"`
+	Exams          []WorkloadExam  `json:"exams"`
+}
+
+// WorkloadGroup is a group's share of a teacher's week
+type WorkloadGroup struct {
+	GroupID   uuid.UUID `json:"group_id"`
+	GroupName string    `json:"group_name"`
+	Lessons   int       `json:"lessons"`
+	Hours     float64   `json:"hours"`
+}
+
+// WorkloadExam is an exam in a teacher's week
+type WorkloadExam struct {
+	ExamID    uuid.UUID `json:"exam_id"`
+	Title     string    `json:"title"`
+	GroupID   uuid.UUID `json:"group_id"`
+	StartTime time.Time `json:"start_time"`
+	Hours     float64   `json:"hours"`
+}
@@ -246,6 +246,14 @@ func IsValidation(err error) bool {
 	return false
 }
 
+// IsCapacityExceeded checks if error is a capacity exceeded error
+func IsCapacityExceeded(err error) bool {
+	if appErr, ok := err.(*AppError); ok {
+		return appErr.Code == ErrCodeCapacityExceeded
+	}
+	return false
+}
+
 // IsUnauthorized checks if error is an unauthorized error
 func IsUnauthorized(err error) bool {
 	if appErr, ok := err.(*AppError); ok {
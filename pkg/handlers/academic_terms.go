@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// maxHolidayCalendarSize limits uploaded iCalendar files
+const maxHolidayCalendarSize = 2 << 20
+
+// CreateAcademicTerm godoc
+// @Summary Create an academic term
+// @Description Create a term with its breaks. Existing groups, grades, exams and invoices dated within it are scoped to it, and scheduled lessons in its breaks are cancelled.
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateAcademicTermRequest true "Term"
+// @Success 201 {object} dto.AcademicTermResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /terms [post]
+func (h *Handler) CreateAcademicTerm(c *gin.Context) {
+	var req dto.CreateAcademicTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	term, err := h.academicTermService.Create(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, term, "Academic term created successfully")
+}
+
+// GetAcademicTerms godoc
+// @Summary List academic terms
+// @Tags terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} dto.AcademicTermResponse
+// @Router /terms [get]
+func (h *Handler) GetAcademicTerms(c *gin.Context) {
+	terms, err := h.academicTermService.GetAll(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, terms, "Academic terms retrieved successfully")
+}
+
+// GetAcademicTerm godoc
+// @Summary Get an academic term
+// @Tags terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param termID path string true "Term ID"
+// @Success 200 {object} dto.AcademicTermResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /terms/{termID} [get]
+func (h *Handler) GetAcademicTerm(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("termID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid term ID"))
+		return
+	}
+
+	term, err := h.academicTermService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, term, "Academic term retrieved successfully")
+}
+
+// UpdateAcademicTerm godoc
+// @Summary Update an academic term
+// @Description Update a term. Given breaks replace the existing ones; records are rescoped when the dates change.
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param termID path string true "Term ID"
+// @Param body body dto.UpdateAcademicTermRequest true "Changes"
+// @Success 200 {object} dto.AcademicTermResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /terms/{termID} [put]
+func (h *Handler) UpdateAcademicTerm(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("termID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid term ID"))
+		return
+	}
+
+	var req dto.UpdateAcademicTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	term, err := h.academicTermService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, term, "Academic term updated successfully")
+}
+
+// DeleteAcademicTerm godoc
+// @Summary Delete an academic term
+// @Description Delete a term and its breaks. Records scoped to it are left without a term.
+// @Tags terms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param termID path string true "Term ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /terms/{termID} [delete]
+func (h *Handler) DeleteAcademicTerm(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("termID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid term ID"))
+		return
+	}
+
+	if err := h.academicTermService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Academic term deleted successfully")
+}
+
+// CreateHoliday godoc
+// @Summary Add a holiday
+// @Description Add a school-wide holiday. Scheduled lessons on its days are cancelled.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateHolidayRequest true "Holiday"
+// @Success 201 {object} dto.HolidayResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /holidays [post]
+func (h *Handler) CreateHoliday(c *gin.Context) {
+	var req dto.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	holiday, err := h.academicTermService.CreateHoliday(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, holiday, "Holiday created successfully")
+}
+
+// GetHolidays godoc
+// @Summary List holidays
+// @Tags holidays
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string false "Holidays ending on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Holidays starting on or before this date (YYYY-MM-DD)"
+// @Success 200 {array} dto.HolidayResponse
+// @Router /holidays [get]
+func (h *Handler) GetHolidays(c *gin.Context) {
+	var filter dto.HolidayFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	holidays, err := h.academicTermService.GetHolidays(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, holidays, "Holidays retrieved successfully")
+}
+
+// DeleteHoliday godoc
+// @Summary Delete a holiday
+// @Description Delete a holiday. Lessons already cancelled for it are not restored.
+// @Tags holidays
+// @Produce json
+// @Security ApiKeyAuth
+// @Param holidayID path string true "Holiday ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /holidays/{holidayID} [delete]
+func (h *Handler) DeleteHoliday(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("holidayID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid holiday ID"))
+		return
+	}
+
+	if err := h.academicTermService.DeleteHoliday(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Holiday deleted successfully")
+}
+
+// ImportHolidays godoc
+// @Summary Import holidays from iCalendar
+// @Description Import the events of an .ics file as holidays, either uploaded as "file" or sent as a text/calendar body. Re-importing a file updates the holidays it created.
+// @Tags holidays
+// @Accept multipart/form-data
+// @Accept text/calendar
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file false "iCalendar file"
+// @Success 200 {object} dto.ImportHolidaysResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /holidays/import [post]
+func (h *Handler) ImportHolidays(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxHolidayCalendarSize)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest("An iCalendar file is required"))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest("Could not read the uploaded file"))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Could not read the calendar; files are limited to 2 MB"))
+		return
+	}
+
+	result, err := h.academicTermService.ImportHolidays(c.Request.Context(), string(data))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Holidays imported successfully")
+}
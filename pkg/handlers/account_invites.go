@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GetAccountInvite gets an account invite
+// @Summary Get an account invite
+// @Description Get the account behind the invite link sent to a new user
+// @Tags accounts
+// @Produce json
+// @Param token path string true "Invite token"
+// @Success 200 {object} dto.AccountInviteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /public/account/invites/{token} [get]
+func (h *Handler) GetAccountInvite(c *gin.Context) {
+	resp, err := h.accountInviteService.Get(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptAccountInvite accepts an account invite
+// @Summary Accept an account invite
+// @Description Choose the password of a new account; the invite link can be used once
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param token path string true "Invite token"
+// @Param body body dto.AcceptAccountInviteRequest true "Password"
+// @Success 200 {object} helpers.APIResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /public/account/invites/{token}/accept [post]
+func (h *Handler) AcceptAccountInvite(c *gin.Context) {
+	var req dto.AcceptAccountInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	if err := h.accountInviteService.Accept(c.Request.Context(), c.Param("token"), req); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Password set, you can now sign in")
+}
+
+// ReissueAccountInvite sends a user a fresh account invite
+// @Summary Reissue an account invite
+// @Description Email a user who has not chosen a password yet a new single-use invite link, for when the first one was not delivered or has expired; earlier links stop working
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "User ID"
+// @Success 200 {object} dto.ReissuedAccountInviteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /users/{userID}/invite [post]
+func (h *Handler) ReissueAccountInvite(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	resp, err := h.accountInviteService.Reissue(c.Request.Context(), userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, resp, "Account invite sent")
+}
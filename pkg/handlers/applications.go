@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
 )
 
@@ -224,15 +225,16 @@ func (h *Handler) ReviewApplication(c *gin.Context) {
 
 // EnrollApplication godoc
 // @Summary Enroll an applicant
-// @Description Enroll an approved applicant as a student
+// @Description Enroll an approved applicant into a group in one transaction: creates the student under the group's capacity rules, optionally links a parent from metadata.parent, bills the course's monthly fee and provisions a student account; the applicant is emailed a single-use link to choose its password, which expires after the invite TTL (72 hours by default) and can be sent again through POST /users/{userID}/invite
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param applicationID path string true "Application ID"
 // @Param body body dto.EnrollApplicationRequest true "Enrollment details"
-// @Success 200 {object} dto.ApplicationResponse
+// @Success 200 {object} dto.EnrollmentResponse
 // @Failure 400 {object} helpers.APIResponse
+// @Failure 409 {object} helpers.APIResponse
 // @Router /applications/{applicationID}/enroll [post]
 func (h *Handler) EnrollApplication(c *gin.Context) {
 	applicationID := c.Param("applicationID")
@@ -243,13 +245,13 @@ func (h *Handler) EnrollApplication(c *gin.Context) {
 		return
 	}
 
-	application, err := h.applicationService.Enroll(c.Request.Context(), applicationID, req)
+	enrollment, err := h.applicationService.Enroll(c.Request.Context(), applicationID, req)
 	if err != nil {
 		handleAppErr(c, err)
 		return
 	}
 
-	helpers.SuccessResponse(c, application, "Applicant enrolled successfully")
+	helpers.SuccessResponse(c, enrollment, "Applicant enrolled successfully")
 }
 
 // DeleteApplication godoc
@@ -276,6 +278,11 @@ func (h *Handler) DeleteApplication(c *gin.Context) {
 
 // handleAppErr handles application-related errors
 func handleAppErr(c *gin.Context, err error) {
+	if _, ok := err.(*errors.AppError); ok {
+		errors.HandleError(c, err)
+		return
+	}
+
 	errMsg := err.Error()
 	if strings.Contains(strings.ToLower(errMsg), "not found") {
 		helpers.NotFound(c, errMsg)
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"mime/multipart"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
 )
 
@@ -128,16 +132,17 @@ func (h *Handler) GetGroupAssignments(c *gin.Context) {
 
 // SubmitAssignment submits an assignment
 // @Summary Submit assignment
-// @Description Submit an assignment
+// @Description Submit a new attempt. Send JSON, or multipart/form-data with "content" and one or more "files"; files are stored as assignment documents. Earlier attempts are kept.
 // @Tags assignments
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param assignmentID path string true "Assignment ID"
 // @Param studentID path string true "Student ID"
-// @Param input body dto.SubmitAssignmentRequest true "Submission data"
+// @Param input body dto.SubmitAssignmentRequest false "Submission data"
+// @Param files formData file false "Attached files"
 // @Success 200 {object} dto.SubmissionResponse
 // @Failure 400 {object} helpers.ErrorResponse
-// @Failure 500 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
 // @Router /assignments/{assignmentID}/submit/{studentID} [post]
 func (h *Handler) SubmitAssignment(c *gin.Context) {
 	assignmentID, err := uuid.Parse(c.Param("assignmentID"))
@@ -153,14 +158,61 @@ func (h *Handler) SubmitAssignment(c *gin.Context) {
 	}
 
 	var req dto.SubmitAssignmentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBind(&req); err != nil {
 		helpers.NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	resp, err := h.assignmentService.SubmitAssignment(c.Request.Context(), assignmentID, studentID, req)
+	var files []*multipart.FileHeader
+	if c.ContentType() == binding.MIMEMultipartPOSTForm {
+		form, err := c.MultipartForm()
+		if err != nil {
+			helpers.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		files = form.File["files"]
+	}
+
+	resp, err := h.assignmentService.SubmitAssignment(c.Request.Context(), assignmentID, studentID, req, files, helpers.CurrentUserID(c))
 	if err != nil {
-		helpers.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAssignmentSubmissions lists submission attempts
+// @Summary List assignment submissions
+// @Description List every attempt on an assignment with download links for attached files
+// @Tags assignments
+// @Produce json
+// @Param assignmentID path string true "Assignment ID"
+// @Param student_id query string false "Only this student's attempts"
+// @Success 200 {array} dto.SubmissionResponse
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Router /assignments/{assignmentID}/submissions [get]
+func (h *Handler) GetAssignmentSubmissions(c *gin.Context) {
+	assignmentID, err := uuid.Parse(c.Param("assignmentID"))
+	if err != nil {
+		helpers.NewErrorResponse(c, http.StatusBadRequest, "invalid assignment id")
+		return
+	}
+
+	var studentID *uuid.UUID
+	if raw := c.Query("student_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			helpers.NewErrorResponse(c, http.StatusBadRequest, "invalid student id")
+			return
+		}
+		studentID = &id
+	}
+
+	resp, err := h.assignmentService.GetSubmissions(c.Request.Context(), assignmentID, studentID)
+	if err != nil {
+		errors.HandleError(c, err)
 		return
 	}
 
@@ -169,7 +221,7 @@ func (h *Handler) SubmitAssignment(c *gin.Context) {
 
 // GradeSubmission grades a submission
 // @Summary Grade submission
-// @Description Grade a student submission
+// @Description Grade one attempt of a student's submission; it becomes the attempt that counts
 // @Tags assignments
 // @Accept json
 // @Produce json
@@ -192,12 +244,32 @@ func (h *Handler) GradeSubmission(c *gin.Context) {
 		return
 	}
 
-	// TODO: Get grader ID from context (auth)
-	graderID := uuid.Nil 
+	graderID := uuid.Nil
+	if userID := helpers.CurrentUserID(c); userID != nil {
+		graderID = *userID
+	}
 
 	resp, err := h.assignmentService.GradeSubmission(c.Request.Context(), submissionID, graderID, req)
 	if err != nil {
-		helpers.NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CloseDueAssignments closes assignments past their submission deadline
+// @Summary Close due assignments
+// @Description Close published assignments whose due date (plus late window) has passed and record zero-score submissions for students who never submitted
+// @Tags assignments
+// @Produce json
+// @Success 200 {object} dto.CloseAssignmentsResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /assignments/close-due [post]
+func (h *Handler) CloseDueAssignments(c *gin.Context) {
+	resp, err := h.assignmentService.CloseDueAssignments(c.Request.Context(), time.Now())
+	if err != nil {
+		errors.HandleError(c, err)
 		return
 	}
 
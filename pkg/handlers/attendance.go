@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 )
@@ -95,6 +96,40 @@ func (h *Handler) GetGroupAttendance(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetAttendanceSheet godoc
+// @Summary      Get a lesson's attendance sheet
+// @Description  Get the roster of a group's lesson: enrolled students with their marks so far, and trial lesson guests
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        groupID     path      string  true   "Group ID"
+// @Param        date        query     string  true   "Date (YYYY-MM-DD)"
+// @Param        session_id  query     string  false  "Class session ID, required when the group has several lessons that day"
+// @Success      200         {object}  dto.AttendanceSheetResponse
+// @Failure      400         {object}  dto.ErrorResponse
+// @Failure      404         {object}  dto.ErrorResponse
+// @Failure      422         {object}  dto.ErrorResponse
+// @Router       /groups/{groupID}/attendance/sheet [get]
+func (h *Handler) GetAttendanceSheet(c *gin.Context) {
+	var sessionID *uuid.UUID
+	if raw := c.Query("session_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest("Invalid session ID"))
+			return
+		}
+		sessionID = &id
+	}
+
+	response, err := h.attendanceService.GetAttendanceSheet(c.Request.Context(), c.Param("groupID"), c.Query("date"), sessionID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetStudentAttendance godoc
 // @Summary      Get student attendance
 // @Description  Get attendance records for a specific student
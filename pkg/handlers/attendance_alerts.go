@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GetAttendanceAlertSettings godoc
+// @Summary Get attendance alert settings
+// @Description Get the parent alert switches and the escalation thresholds
+// @Tags attendance-alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dto.AttendanceAlertSettingsResponse
+// @Router /attendance-alerts/settings [get]
+func (h *Handler) GetAttendanceAlertSettings(c *gin.Context) {
+	settings, err := h.attendanceAlertService.GetSettings(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, settings, "Attendance alert settings retrieved successfully")
+}
+
+// UpdateAttendanceAlertSettings godoc
+// @Summary Update attendance alert settings
+// @Description Change the parent alert switches and the escalation thresholds; a threshold of 0 disables its rule
+// @Tags attendance-alerts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.UpdateAttendanceAlertSettingsRequest true "Settings"
+// @Success 200 {object} dto.AttendanceAlertSettingsResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /attendance-alerts/settings [put]
+func (h *Handler) UpdateAttendanceAlertSettings(c *gin.Context) {
+	var req dto.UpdateAttendanceAlertSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	settings, err := h.attendanceAlertService.UpdateSettings(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, settings, "Attendance alert settings updated successfully")
+}
+
+// GetStudentAttendanceAlerts godoc
+// @Summary List a student's attendance alerts
+// @Description List the absence, late and escalation alerts logged against a student
+// @Tags attendance-alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param studentID path string true "Student ID"
+// @Success 200 {array} dto.AttendanceAlertResponse
+// @Router /students/{studentID}/attendance-alerts [get]
+func (h *Handler) GetStudentAttendanceAlerts(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid student ID"))
+		return
+	}
+
+	alerts, err := h.attendanceAlertService.GetByStudent(c.Request.Context(), studentID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, alerts, "Attendance alerts retrieved successfully")
+}
+
+// GetGroupAttendanceAlerts godoc
+// @Summary List a group's attendance alerts
+// @Description List the attendance alerts raised for a group's students
+// @Tags attendance-alerts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param escalated query bool false "Only escalated alerts"
+// @Success 200 {array} dto.AttendanceAlertResponse
+// @Router /groups/{groupID}/attendance-alerts [get]
+func (h *Handler) GetGroupAttendanceAlerts(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	alerts, err := h.attendanceAlertService.GetByGroup(c.Request.Context(), groupID, c.Query("escalated") == "true")
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, alerts, "Attendance alerts retrieved successfully")
+}
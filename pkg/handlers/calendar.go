@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
 )
 
@@ -74,7 +75,7 @@ func (h *Handler) GetEvent(c *gin.Context) {
 
 // GetCalendarEvents godoc
 // @Summary Get calendar events
-// @Description Get events within a date range with filters
+// @Description Get events starting within a date range with filters. Recurring events are expanded into occurrences carrying a recurrence_id.
 // @Tags calendar
 // @Accept json
 // @Produce json
@@ -108,9 +109,38 @@ func (h *Handler) GetCalendarEvents(c *gin.Context) {
 	})
 }
 
+// GetCalendarLessons godoc
+// @Summary Get lessons on real dates
+// @Description Lessons within a date range, from timetable slots and generated class sessions. Cancelled lessons are left out and rescheduled ones appear on their new date.
+// @Tags calendar
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param group_id query string false "Group ID"
+// @Param teacher_id query string false "Teacher ID"
+// @Success 200 {array} dto.LessonOccurrence
+// @Failure 400 {object} helpers.APIResponse
+// @Router /calendar/lessons [get]
+func (h *Handler) GetCalendarLessons(c *gin.Context) {
+	var req dto.LessonCalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		helpers.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	lessons, err := h.calendarService.GetLessons(c.Request.Context(), req)
+	if err != nil {
+		handleCalErr(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lessons, "Lessons retrieved successfully")
+}
+
 // UpdateEvent godoc
 // @Summary Update an event
-// @Description Update event details
+// @Description Update event details. For a recurring event, scope=this changes one occurrence and scope=following changes it and all later ones; occurrence is the recurrence_id of the occurrence.
 // @Tags calendar
 // @Accept json
 // @Produce json
@@ -140,19 +170,27 @@ func (h *Handler) UpdateEvent(c *gin.Context) {
 
 // DeleteEvent godoc
 // @Summary Delete an event
-// @Description Delete a calendar event
+// @Description Delete a calendar event. For a recurring event, scope=this deletes one occurrence and scope=following ends the series at that occurrence.
 // @Tags calendar
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param eventID path string true "Event ID"
+// @Param scope query string false "all, this or following"
+// @Param occurrence query string false "recurrence_id of the occurrence (RFC3339)"
 // @Success 200 {object} helpers.APIResponse
 // @Failure 404 {object} helpers.APIResponse
 // @Router /calendar/events/{eventID} [delete]
 func (h *Handler) DeleteEvent(c *gin.Context) {
 	eventID := c.Param("eventID")
 
-	if err := h.calendarService.Delete(c.Request.Context(), eventID); err != nil {
+	var req dto.EventScopeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		helpers.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	if err := h.calendarService.Delete(c.Request.Context(), eventID, req); err != nil {
 		handleCalErr(c, err)
 		return
 	}
@@ -162,6 +200,10 @@ func (h *Handler) DeleteEvent(c *gin.Context) {
 
 // handleCalErr handles calendar-related errors
 func handleCalErr(c *gin.Context, err error) {
+	if _, ok := err.(*errors.AppError); ok {
+		errors.HandleError(c, err)
+		return
+	}
 	errMsg := err.Error()
 	if strings.Contains(strings.ToLower(errMsg), "not found") {
 		helpers.NotFound(c, errMsg)
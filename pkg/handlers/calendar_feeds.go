@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateCalendarFeed godoc
+// @Summary Create a calendar feed
+// @Description Publish the lessons, exams, assignment due dates and events of a teacher, group, student or parent as an iCalendar subscription URL with a secret token
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateCalendarFeedRequest true "Feed owner"
+// @Success 201 {object} dto.CalendarFeedResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /calendar/feeds [post]
+func (h *Handler) CreateCalendarFeed(c *gin.Context) {
+	var req dto.CreateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	feed, err := h.calendarFeedService.Create(c.Request.Context(), req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, feed, "Calendar feed created successfully")
+}
+
+// GetCalendarFeeds godoc
+// @Summary List calendar feeds
+// @Tags calendar
+// @Produce json
+// @Security ApiKeyAuth
+// @Param owner_type query string false "teacher, group, student or parent"
+// @Param owner_id query string false "Owner ID"
+// @Param include_revoked query bool false "Include revoked feeds"
+// @Success 200 {array} dto.CalendarFeedResponse
+// @Router /calendar/feeds [get]
+func (h *Handler) GetCalendarFeeds(c *gin.Context) {
+	var filter dto.CalendarFeedFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	feeds, err := h.calendarFeedService.List(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, feeds, "Calendar feeds retrieved successfully")
+}
+
+// RevokeCalendarFeed godoc
+// @Summary Revoke a calendar feed
+// @Description Stop the feed's URL from serving the calendar
+// @Tags calendar
+// @Produce json
+// @Security ApiKeyAuth
+// @Param feedID path string true "Feed ID"
+// @Success 200 {object} dto.CalendarFeedResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /calendar/feeds/{feedID} [delete]
+func (h *Handler) RevokeCalendarFeed(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("feedID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid feed ID"))
+		return
+	}
+
+	feed, err := h.calendarFeedService.Revoke(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, feed, "Calendar feed revoked successfully")
+}
+
+// GetCalendarFeed godoc
+// @Summary Subscribe to a calendar feed
+// @Description Public iCalendar (RFC 5545) feed; the secret token in the URL is the only credential
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token followed by .ics"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /calendar/feeds/{token}.ics [get]
+func (h *Handler) GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	body, err := h.calendarFeedService.Render(c.Request.Context(), token)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
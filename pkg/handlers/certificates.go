@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateCertificateTemplate godoc
+// @Summary Create a certificate template
+// @Description Create a certificate layout. The body may use {{student_name}}, {{course_name}}, {{completion_date}}, {{grade}} and {{certificate_number}}.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateCertificateTemplateRequest true "Certificate template"
+// @Success 201 {object} dto.CertificateTemplateResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /certificate-templates [post]
+func (h *Handler) CreateCertificateTemplate(c *gin.Context) {
+	var req dto.CreateCertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	template, err := h.certificateService.CreateTemplate(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, template, "Certificate template created successfully")
+}
+
+// GetCertificateTemplates godoc
+// @Summary List certificate templates
+// @Description List all certificate layouts
+// @Tags certificates
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} dto.CertificateTemplateResponse
+// @Router /certificate-templates [get]
+func (h *Handler) GetCertificateTemplates(c *gin.Context) {
+	templates, err := h.certificateService.GetTemplates(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, templates, "Certificate templates retrieved successfully")
+}
+
+// UpdateCertificateTemplate godoc
+// @Summary Update a certificate template
+// @Description Update a certificate layout; certificates already issued keep their PDF
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param templateID path string true "Template ID"
+// @Param body body dto.UpdateCertificateTemplateRequest true "Template updates"
+// @Success 200 {object} dto.CertificateTemplateResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /certificate-templates/{templateID} [put]
+func (h *Handler) UpdateCertificateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("templateID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid template ID"))
+		return
+	}
+
+	var req dto.UpdateCertificateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	template, err := h.certificateService.UpdateTemplate(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, template, "Certificate template updated successfully")
+}
+
+// IssueCertificate godoc
+// @Summary Issue a certificate
+// @Description Issue a numbered completion certificate PDF with a verification code to a single student
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.IssueCertificateRequest true "Certificate"
+// @Success 201 {object} dto.CertificateResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /certificates [post]
+func (h *Handler) IssueCertificate(c *gin.Context) {
+	var req dto.IssueCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	cert, err := h.certificateService.Issue(c.Request.Context(), req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, cert, "Certificate issued successfully")
+}
+
+// IssueGroupCertificates godoc
+// @Summary Issue certificates to a group
+// @Description Issue certificates to every student in a group who meets the attendance and passing grade rules
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.IssueGroupCertificatesRequest true "Eligibility rules"
+// @Success 201 {object} dto.IssueGroupCertificatesResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/certificates [post]
+func (h *Handler) IssueGroupCertificates(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.IssueGroupCertificatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.certificateService.IssueForGroup(c.Request.Context(), groupID, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, result, "Certificates issued successfully")
+}
+
+// GetCertificate godoc
+// @Summary Get a certificate
+// @Description Get certificate details
+// @Tags certificates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param certificateID path string true "Certificate ID"
+// @Success 200 {object} dto.CertificateResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /certificates/{certificateID} [get]
+func (h *Handler) GetCertificate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("certificateID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid certificate ID"))
+		return
+	}
+
+	cert, err := h.certificateService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, cert, "Certificate retrieved successfully")
+}
+
+// GetStudentCertificates godoc
+// @Summary List a student's certificates
+// @Description List every certificate issued to a student, including revoked ones
+// @Tags certificates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param studentID path string true "Student ID"
+// @Success 200 {array} dto.CertificateResponse
+// @Router /students/{studentID}/certificates [get]
+func (h *Handler) GetStudentCertificates(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid student ID"))
+		return
+	}
+
+	certs, err := h.certificateService.GetByStudent(c.Request.Context(), studentID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, certs, "Certificates retrieved successfully")
+}
+
+// RevokeCertificate godoc
+// @Summary Revoke a certificate
+// @Description Revoke a certificate; verification will report it as no longer valid
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param certificateID path string true "Certificate ID"
+// @Param body body dto.RevokeCertificateRequest true "Revocation reason"
+// @Success 200 {object} dto.CertificateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /certificates/{certificateID}/revoke [post]
+func (h *Handler) RevokeCertificate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("certificateID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid certificate ID"))
+		return
+	}
+
+	var req dto.RevokeCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	cert, err := h.certificateService.Revoke(c.Request.Context(), id, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, cert, "Certificate revoked successfully")
+}
+
+// VerifyCertificate godoc
+// @Summary Verify a certificate
+// @Description Public endpoint confirming a certificate's holder, course and completion date
+// @Tags certificates
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} dto.CertificateVerificationResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /verify/{code} [get]
+func (h *Handler) VerifyCertificate(c *gin.Context) {
+	result, err := h.certificateService.Verify(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Certificate verified")
+}
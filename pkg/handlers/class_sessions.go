@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GenerateClassSessions godoc
+// @Summary Generate class sessions
+// @Description Materialize a group's lessons from its timetable for a date range; existing lessons are kept
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.GenerateClassSessionsRequest true "Date range"
+// @Success 201 {object} dto.GenerateClassSessionsResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/sessions/generate [post]
+func (h *Handler) GenerateClassSessions(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.GenerateClassSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.classSessionService.Generate(c.Request.Context(), groupID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, result, "Class sessions generated successfully")
+}
+
+// GetGroupClassSessions godoc
+// @Summary List a group's class sessions
+// @Description List a group's lessons, optionally filtered by date range and status
+// @Tags class-sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param status query string false "Status (scheduled, held, cancelled, rescheduled)"
+// @Success 200 {array} dto.ClassSessionResponse
+// @Router /groups/{groupID}/sessions [get]
+func (h *Handler) GetGroupClassSessions(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	sessions, err := h.classSessionService.GetByGroup(c.Request.Context(), groupID, c.Query("from"), c.Query("to"), c.Query("status"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, sessions, "Class sessions retrieved successfully")
+}
+
+// GetClassSession godoc
+// @Summary Get a class session
+// @Description Get a single lesson
+// @Tags class-sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Session ID"
+// @Success 200 {object} dto.ClassSessionResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID} [get]
+func (h *Handler) GetClassSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid session ID"))
+		return
+	}
+
+	session, err := h.classSessionService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, session, "Class session retrieved successfully")
+}
+
+// UpdateClassSession godoc
+// @Summary Update a class session
+// @Description Update a lesson's topic, notes, teacher or room, or mark it as held
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Session ID"
+// @Param body body dto.UpdateClassSessionRequest true "Session updates"
+// @Success 200 {object} dto.ClassSessionResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID} [put]
+func (h *Handler) UpdateClassSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid session ID"))
+		return
+	}
+
+	var req dto.UpdateClassSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	session, err := h.classSessionService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, session, "Class session updated successfully")
+}
+
+// CancelClassSession godoc
+// @Summary Cancel a class session
+// @Description Cancel a scheduled lesson, optionally granting make-up credits, and notify students and parents
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Session ID"
+// @Param body body dto.CancelClassSessionRequest true "Cancellation"
+// @Success 200 {object} dto.SessionChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID}/cancel [post]
+func (h *Handler) CancelClassSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid session ID"))
+		return
+	}
+
+	var req dto.CancelClassSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.classSessionService.Cancel(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Class session cancelled successfully")
+}
+
+// RescheduleClassSession godoc
+// @Summary Reschedule a class session
+// @Description Move a scheduled lesson to a new date and time after checking room, teacher and group conflicts
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Session ID"
+// @Param body body dto.RescheduleClassSessionRequest true "New slot"
+// @Success 200 {object} dto.SessionChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID}/reschedule [post]
+func (h *Handler) RescheduleClassSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid session ID"))
+		return
+	}
+
+	var req dto.RescheduleClassSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.classSessionService.Reschedule(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Class session rescheduled successfully")
+}
+
+// CancelGroupClassSessions godoc
+// @Summary Cancel a group's class sessions in a date range
+// @Description Cancel every scheduled lesson of a group between two dates and notify students and parents
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.CancelClassSessionsRequest true "Date range and cancellation"
+// @Success 200 {object} dto.SessionChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/sessions/cancel [post]
+func (h *Handler) CancelGroupClassSessions(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.CancelClassSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.classSessionService.CancelRange(c.Request.Context(), groupID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Class sessions cancelled successfully")
+}
+
+// RescheduleGroupClassSessions godoc
+// @Summary Reschedule a group's class sessions in a date range
+// @Description Shift every scheduled lesson of a group between two dates by a number of days and/or to new times or a room
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.RescheduleClassSessionsRequest true "Date range and change"
+// @Success 200 {object} dto.SessionChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/sessions/reschedule [post]
+func (h *Handler) RescheduleGroupClassSessions(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.RescheduleClassSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	result, err := h.classSessionService.RescheduleRange(c.Request.Context(), groupID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, result, "Class sessions rescheduled successfully")
+}
+
+// GetStudentMakeUpCredits godoc
+// @Summary List a student's make-up credits
+// @Description List the make-up credits a student received for cancelled lessons
+// @Tags class-sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param studentID path string true "Student ID"
+// @Success 200 {array} dto.MakeUpCreditResponse
+// @Router /students/{studentID}/make-up-credits [get]
+func (h *Handler) GetStudentMakeUpCredits(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid student ID"))
+		return
+	}
+
+	credits, err := h.classSessionService.GetStudentMakeUpCredits(c.Request.Context(), studentID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, credits, "Make-up credits retrieved successfully")
+}
+
+// RedeemMakeUpCredit godoc
+// @Summary Redeem a make-up credit
+// @Description Use an available make-up credit for a replacement lesson
+// @Tags class-sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param creditID path string true "Make-up credit ID"
+// @Param body body dto.RedeemMakeUpCreditRequest true "Replacement lesson"
+// @Success 200 {object} dto.MakeUpCreditResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /make-up-credits/{creditID}/redeem [post]
+func (h *Handler) RedeemMakeUpCredit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("creditID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid make-up credit ID"))
+		return
+	}
+
+	var req dto.RedeemMakeUpCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	credit, err := h.classSessionService.RedeemMakeUpCredit(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, credit, "Make-up credit redeemed successfully")
+}
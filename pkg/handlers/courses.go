@@ -18,6 +18,8 @@ import (
 // @Param        page      query     int     false  "Page number"
 // @Param        page_size query     int     false  "Page size"
 // @Param        search    query     string  false  "Search term"
+// @Param        level     query     string  false  "Level (beginner, intermediate, advanced)"
+// @Param        category  query     string  false  "Category"
 // @Success      200       {object}  dto.PaginatedResponse
 // @Failure      500       {object}  dto.ErrorResponse
 // @Router       /courses [get]
@@ -25,7 +27,13 @@ func (h *Handler) GetAllCourses(c *gin.Context) {
 	pagination := helpers.GetPaginationParams(c)
 	// Search is already in pagination params now
 
-	response, err := h.courseService.GetAll(c.Request.Context(), pagination)
+	var filter dto.CourseFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	response, err := h.courseService.GetAll(c.Request.Context(), pagination, filter)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -51,7 +51,7 @@ func (h *Handler) UploadDocument(c *gin.Context) {
 		return
 	}
 
-	document, err := h.documentService.Upload(c.Request.Context(), req, file, uploaderID)
+	document, err := h.documentService.Upload(c.Request.Context(), req, file, &uploaderID)
 	if err != nil {
 		handleDocErr(c, err)
 		return
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// FindDuplicates godoc
+// @Summary Find duplicate records
+// @Description List pairs of students, parents, applications and waitlist prospects that probably describe the same person, scored on normalized phone, email and fuzzy name matches
+// @Tags duplicates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param type query string false "Only pairs involving this record type (student, parent, application, waitlist)"
+// @Param min_score query number false "Minimum score from 0 to 1 (default 0.7)"
+// @Param limit query int false "Maximum number of pairs (default 100)"
+// @Success 200 {array} dto.DuplicateCandidate
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /duplicates [get]
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	var filter dto.DuplicateFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	candidates, err := h.duplicateService.FindCandidates(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, candidates, "Duplicate candidates retrieved successfully")
+}
+
+// MergeDuplicates godoc
+// @Summary Merge duplicate records
+// @Description Merge a duplicate student or parent into the record that survives. Attendance, grades, invoices, payments, documents, parent links and other references move to the survivor, blank survivor contacts are filled in, and the duplicate is deleted. The merge is audited and can be undone.
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.MergeRecordsRequest true "Merge"
+// @Success 201 {object} dto.RecordMergeResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /duplicates/merge [post]
+func (h *Handler) MergeDuplicates(c *gin.Context) {
+	var req dto.MergeRecordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	merge, err := h.duplicateService.Merge(c.Request.Context(), req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, merge, "Records merged successfully")
+}
+
+// UndoMerge godoc
+// @Summary Undo a merge
+// @Description Restore the duplicate of a merge, move its rows back and revert the filled-in survivor fields
+// @Tags duplicates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param mergeID path string true "Merge ID"
+// @Success 200 {object} dto.RecordMergeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /duplicates/merges/{mergeID}/undo [post]
+func (h *Handler) UndoMerge(c *gin.Context) {
+	mergeID, err := uuid.Parse(c.Param("mergeID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid merge ID"))
+		return
+	}
+
+	merge, err := h.duplicateService.UndoMerge(c.Request.Context(), mergeID, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, merge, "Merge undone successfully")
+}
@@ -5,8 +5,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
 )
 
@@ -28,19 +28,13 @@ func (h *Handler) CreateExam(c *gin.Context) {
 		return
 	}
 
-	creatorIDStr, exists := c.Get("userID")
-	if !exists {
+	creatorID := helpers.CurrentUserID(c)
+	if creatorID == nil {
 		helpers.Unauthorized(c, "User not authenticated")
 		return
 	}
 
-	creatorID, err := uuid.Parse(creatorIDStr.(string))
-	if err != nil {
-		helpers.BadRequest(c, "Invalid user ID")
-		return
-	}
-
-	exam, err := h.examService.Create(c.Request.Context(), req, creatorID)
+	exam, err := h.examService.Create(c.Request.Context(), req, *creatorID)
 	if err != nil {
 		handleExamErr(c, err)
 		return
@@ -82,6 +76,7 @@ func (h *Handler) GetExam(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
 // @Param search query string false "Search term"
+// @Param term_id query string false "Academic term ID filter"
 // @Success 200 {object} dto.PaginatedResponse
 // @Failure 400 {object} helpers.APIResponse
 // @Router /exams [get]
@@ -92,7 +87,7 @@ func (h *Handler) GetAllExams(c *gin.Context) {
 		return
 	}
 
-	result, err := h.examService.GetAll(c.Request.Context(), req)
+	result, err := h.examService.GetAll(c.Request.Context(), req, c.Query("term_id"))
 	if err != nil {
 		handleExamErr(c, err)
 		return
@@ -142,6 +137,7 @@ func (h *Handler) GetExamsByCourse(c *gin.Context) {
 // @Param groupID path string true "Group ID"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
+// @Param term_id query string false "Academic term ID filter"
 // @Success 200 {object} dto.PaginatedResponse
 // @Failure 400 {object} helpers.APIResponse
 // @Router /exams/group/{groupID} [get]
@@ -154,7 +150,7 @@ func (h *Handler) GetExamsByGroup(c *gin.Context) {
 		return
 	}
 
-	result, err := h.examService.GetByGroup(c.Request.Context(), groupID, req)
+	result, err := h.examService.GetByGroup(c.Request.Context(), groupID, req, c.Query("term_id"))
 	if err != nil {
 		handleExamErr(c, err)
 		return
@@ -208,31 +204,92 @@ func (h *Handler) UpdateExam(c *gin.Context) {
 func (h *Handler) SubmitExamResult(c *gin.Context) {
 	examID := c.Param("examID")
 
-	graderIDStr, exists := c.Get("userID")
-	if !exists {
+	graderID := helpers.CurrentUserID(c)
+	if graderID == nil {
 		helpers.Unauthorized(c, "User not authenticated")
 		return
 	}
 
-	graderID, err := uuid.Parse(graderIDStr.(string))
+	var req dto.SubmitExamResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.examService.SubmitResult(c.Request.Context(), examID, *graderID, req)
 	if err != nil {
-		helpers.BadRequest(c, "Invalid user ID")
+		handleExamErr(c, err)
 		return
 	}
 
-	var req dto.SubmitExamResultRequest
+	helpers.CreatedResponse(c, result, "Result submitted successfully")
+}
+
+// BulkSubmitExamResults godoc
+// @Summary Submit exam results in bulk
+// @Description Submit results for several students of the exam's group; existing results are replaced
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param examID path string true "Exam ID"
+// @Param body body dto.BulkExamResultsRequest true "Results"
+// @Success 200 {object} dto.BulkExamResultsResponse
+// @Failure 400 {object} helpers.APIResponse
+// @Failure 404 {object} helpers.APIResponse
+// @Router /exams/{examID}/results/bulk [post]
+func (h *Handler) BulkSubmitExamResults(c *gin.Context) {
+	examID := c.Param("examID")
+
+	graderID := helpers.CurrentUserID(c)
+	if graderID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req dto.BulkExamResultsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		helpers.BadRequest(c, "Invalid request body")
 		return
 	}
 
-	result, err := h.examService.SubmitResult(c.Request.Context(), examID, graderID, req)
+	resp, err := h.examService.BulkSubmitResults(c.Request.Context(), examID, *graderID, req)
 	if err != nil {
 		handleExamErr(c, err)
 		return
 	}
 
-	helpers.CreatedResponse(c, result, "Result submitted successfully")
+	helpers.SuccessResponse(c, resp, "Results processed")
+}
+
+// PublishExam godoc
+// @Summary Publish exam results
+// @Description Publish a completed exam's results, lock them and notify students
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param examID path string true "Exam ID"
+// @Success 200 {object} dto.PublishExamResponse
+// @Failure 404 {object} helpers.APIResponse
+// @Failure 422 {object} helpers.APIResponse
+// @Router /exams/{examID}/publish [post]
+func (h *Handler) PublishExam(c *gin.Context) {
+	examID := c.Param("examID")
+
+	publisherID := helpers.CurrentUserID(c)
+	if publisherID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	resp, err := h.examService.Publish(c.Request.Context(), examID, *publisherID)
+	if err != nil {
+		handleExamErr(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, resp, "Exam results published")
 }
 
 // GetExamResults godoc
@@ -336,6 +393,10 @@ func (h *Handler) DeleteExam(c *gin.Context) {
 
 // handleExamErr handles exam-related errors
 func handleExamErr(c *gin.Context, err error) {
+	if _, ok := err.(*errors.AppError); ok {
+		errors.HandleError(c, err)
+		return
+	}
 	errMsg := err.Error()
 	if strings.Contains(strings.ToLower(errMsg), "not found") {
 		helpers.NotFound(c, errMsg)
@@ -103,6 +103,7 @@ func (h *Handler) DeleteGrade(c *gin.Context) {
 // @Produce      json
 // @Param        groupID  path      string  true   "Group ID"
 // @Param        course_id query     string  false  "Course ID filter"
+// @Param        term_id   query     string  false  "Academic term ID filter"
 // @Success      200      {array}   dto.GradeResponse
 // @Failure      400      {object}  dto.ErrorResponse
 // @Failure      500      {object}  dto.ErrorResponse
@@ -111,7 +112,7 @@ func (h *Handler) GetGroupGrades(c *gin.Context) {
 	groupID := c.Param("groupID")
 	courseID := c.Query("course_id")
 
-	response, err := h.gradeService.GetGroupGrades(c.Request.Context(), groupID, courseID)
+	response, err := h.gradeService.GetGroupGrades(c.Request.Context(), groupID, courseID, c.Query("term_id"))
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -128,6 +129,7 @@ func (h *Handler) GetGroupGrades(c *gin.Context) {
 // @Produce      json
 // @Param        studentID  path      string  true   "Student ID"
 // @Param        group_id   query     string  false  "Group ID filter"
+// @Param        term_id    query     string  false  "Academic term ID filter"
 // @Success      200        {array}   dto.GradeResponse
 // @Failure      404        {object}  dto.ErrorResponse
 // @Failure      500        {object}  dto.ErrorResponse
@@ -136,7 +138,7 @@ func (h *Handler) GetStudentGrades(c *gin.Context) {
 	studentID := c.Param("studentID")
 	groupID := c.Query("group_id")
 
-	response, err := h.gradeService.GetStudentGrades(c.Request.Context(), studentID, groupID)
+	response, err := h.gradeService.GetStudentGrades(c.Request.Context(), studentID, groupID, c.Query("term_id"))
 	if err != nil {
 		errors.HandleError(c, err)
 		return
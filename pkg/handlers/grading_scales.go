@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateGradingScale godoc
+// @Summary Create a grading scale
+// @Description Create a grading scale with bands, labels, GPA points and a pass threshold
+// @Tags grading-scales
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateGradingScaleRequest true "Grading scale"
+// @Success 201 {object} dto.GradingScaleResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /grading-scales [post]
+func (h *Handler) CreateGradingScale(c *gin.Context) {
+	var req dto.CreateGradingScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	scale, err := h.gradingScaleService.Create(c.Request.Context(), req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, scale, "Grading scale created successfully")
+}
+
+// GetAllGradingScales godoc
+// @Summary List grading scales
+// @Description List all grading scales with their current version
+// @Tags grading-scales
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} dto.GradingScaleResponse
+// @Router /grading-scales [get]
+func (h *Handler) GetAllGradingScales(c *gin.Context) {
+	scales, err := h.gradingScaleService.GetAll(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, scales, "Grading scales retrieved successfully")
+}
+
+// GetGradingScale godoc
+// @Summary Get a grading scale
+// @Description Get a grading scale with its full version history
+// @Tags grading-scales
+// @Produce json
+// @Security ApiKeyAuth
+// @Param scaleID path string true "Grading scale ID"
+// @Success 200 {object} dto.GradingScaleResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /grading-scales/{scaleID} [get]
+func (h *Handler) GetGradingScale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("scaleID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid grading scale ID"))
+		return
+	}
+
+	scale, err := h.gradingScaleService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, scale, "Grading scale retrieved successfully")
+}
+
+// UpdateGradingScale godoc
+// @Summary Update a grading scale
+// @Description Update a grading scale. Changing bands or the pass threshold creates a new version.
+// @Tags grading-scales
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param scaleID path string true "Grading scale ID"
+// @Param body body dto.UpdateGradingScaleRequest true "Grading scale updates"
+// @Success 200 {object} dto.GradingScaleResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /grading-scales/{scaleID} [put]
+func (h *Handler) UpdateGradingScale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("scaleID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid grading scale ID"))
+		return
+	}
+
+	var req dto.UpdateGradingScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	scale, err := h.gradingScaleService.Update(c.Request.Context(), id, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, scale, "Grading scale updated successfully")
+}
+
+// DeleteGradingScale godoc
+// @Summary Delete a grading scale
+// @Description Delete a grading scale that is not assigned to any course
+// @Tags grading-scales
+// @Produce json
+// @Security ApiKeyAuth
+// @Param scaleID path string true "Grading scale ID"
+// @Success 200 {object} helpers.APIResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /grading-scales/{scaleID} [delete]
+func (h *Handler) DeleteGradingScale(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("scaleID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid grading scale ID"))
+		return
+	}
+
+	if err := h.gradingScaleService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Grading scale deleted successfully")
+}
+
+// AssignCourseGradingScale godoc
+// @Summary Assign a grading scale to a course
+// @Description Set the grading scale used for new results in a course; send null to fall back to the default scale
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param courseID path string true "Course ID"
+// @Param body body dto.AssignGradingScaleRequest true "Grading scale assignment"
+// @Success 200 {object} helpers.APIResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /courses/{courseID}/grading-scale [put]
+func (h *Handler) AssignCourseGradingScale(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid course ID"))
+		return
+	}
+
+	var req dto.AssignGradingScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	if err := h.gradingScaleService.AssignToCourse(c.Request.Context(), courseID, req); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Grading scale assigned successfully")
+}
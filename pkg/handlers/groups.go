@@ -18,6 +18,7 @@ import (
 // @Param        page      query     int     false  "Page number"
 // @Param        page_size query     int     false  "Page size"
 // @Param        search    query     string  false  "Search term"
+// @Param        term_id   query     string  false  "Academic term ID filter"
 // @Success      200       {object}  dto.PaginatedResponse
 // @Failure      500       {object}  dto.ErrorResponse
 // @Router       /groups [get]
@@ -25,7 +26,7 @@ func (h *Handler) GetAllGroups(c *gin.Context) {
 	pagination := helpers.GetPaginationParams(c)
 	// Search is already in pagination params now
 
-	response, err := h.groupService.GetAll(c.Request.Context(), pagination)
+	response, err := h.groupService.GetAll(c.Request.Context(), pagination, c.Query("term_id"))
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -112,6 +113,9 @@ func (h *Handler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
+	// A raised capacity opens seats for the waitlist
+	h.waitlistService.SeatsFreed(c.Request.Context(), response.ID)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -34,6 +34,22 @@ type Handler struct {
 	bulkService             *services.BulkService
 	recurringInvoiceService *services.RecurringInvoiceService
 	advancedSearchService   *services.AdvancedSearchService
+	gradingScaleService     *services.GradingScaleService
+	reportCardService       *services.ReportCardService
+	certificateService      *services.CertificateService
+	classSessionService     *services.ClassSessionService
+	attendanceAlertService  *services.AttendanceAlertService
+	roomService             *services.RoomService
+	timetableSolverService  *services.TimetableSolverService
+	calendarFeedService     *services.CalendarFeedService
+	academicTermService     *services.AcademicTermService
+	syllabusService         *services.SyllabusService
+	leadService             *services.LeadService
+	intakeService           *services.IntakeService
+	seatService             *services.SeatService
+	duplicateService        *services.DuplicateService
+	trialService            *services.TrialService
+	accountInviteService    *services.AccountInviteService
 }
 
 // NewHandler creates a new Handler instance
@@ -66,6 +82,22 @@ func NewHandler(
 	bulkService *services.BulkService,
 	recurringInvoiceService *services.RecurringInvoiceService,
 	advancedSearchService *services.AdvancedSearchService,
+	gradingScaleService *services.GradingScaleService,
+	reportCardService *services.ReportCardService,
+	certificateService *services.CertificateService,
+	classSessionService *services.ClassSessionService,
+	attendanceAlertService *services.AttendanceAlertService,
+	roomService *services.RoomService,
+	timetableSolverService *services.TimetableSolverService,
+	calendarFeedService *services.CalendarFeedService,
+	academicTermService *services.AcademicTermService,
+	syllabusService *services.SyllabusService,
+	leadService *services.LeadService,
+	intakeService *services.IntakeService,
+	seatService *services.SeatService,
+	duplicateService *services.DuplicateService,
+	trialService *services.TrialService,
+	accountInviteService *services.AccountInviteService,
 ) *Handler {
 	return &Handler{
 		teacherService:          teacherService,
@@ -96,5 +128,21 @@ func NewHandler(
 		bulkService:             bulkService,
 		recurringInvoiceService: recurringInvoiceService,
 		advancedSearchService:   advancedSearchService,
+		gradingScaleService:     gradingScaleService,
+		reportCardService:       reportCardService,
+		certificateService:      certificateService,
+		classSessionService:     classSessionService,
+		attendanceAlertService:  attendanceAlertService,
+		roomService:             roomService,
+		timetableSolverService:  timetableSolverService,
+		calendarFeedService:     calendarFeedService,
+		academicTermService:     academicTermService,
+		syllabusService:         syllabusService,
+		leadService:             leadService,
+		intakeService:           intakeService,
+		seatService:             seatService,
+		duplicateService:        duplicateService,
+		trialService:            trialService,
+		accountInviteService:    accountInviteService,
 	}
 }
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+	"github.com/softclub-go-0-0/crm-service/pkg/services"
+)
+
+// maxIntakeRequestSize limits a public application with all its documents
+const maxIntakeRequestSize = services.MaxIntakeDocuments*10<<20 + 1<<20
+
+// GetApplicationFormToken godoc
+// @Summary Get an application form token
+// @Description Get a signed token to embed in the public application form. It is accepted from a few seconds after it is issued until it expires.
+// @Tags intake
+// @Produce json
+// @Success 200 {object} dto.FormTokenResponse
+// @Router /public/applications/form-token [get]
+func (h *Handler) GetApplicationFormToken(c *gin.Context) {
+	token, err := h.intakeService.IssueFormToken(c.Request.Context())
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, token, "Form token issued successfully")
+}
+
+// SubmitPublicApplication godoc
+// @Summary Apply from the website
+// @Description Submit an application without an account. Send a multipart form with supporting files as "documents", or JSON without files. The applicant receives an acknowledgement with a status-check link. Rate limited per IP.
+// @Tags intake
+// @Accept multipart/form-data
+// @Accept json
+// @Produce json
+// @Param body body dto.PublicApplicationRequest true "Application"
+// @Param documents formData file false "Supporting documents"
+// @Success 201 {object} dto.PublicApplicationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /public/applications [post]
+func (h *Handler) SubmitPublicApplication(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxIntakeRequestSize)
+
+	var req dto.PublicApplicationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		form, err := c.MultipartForm()
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest("Could not read the uploaded documents"))
+			return
+		}
+		files = form.File["documents"]
+	}
+
+	application, err := h.intakeService.Submit(c.Request.Context(), req, files)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, application, "Application submitted successfully")
+}
+
+// GetPublicApplicationStatus godoc
+// @Summary Check an application's status
+// @Description Check the status of an application with the secret token from the acknowledgement link
+// @Tags intake
+// @Produce json
+// @Param token path string true "Status token"
+// @Success 200 {object} dto.PublicApplicationStatusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /public/applications/status/{token} [get]
+func (h *Handler) GetPublicApplicationStatus(c *gin.Context) {
+	status, err := h.intakeService.GetStatus(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, status, "Application status retrieved successfully")
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateLead godoc
+// @Summary Record a lead
+// @Description Record an inquiry from a prospective student. Without an owner, the lead is assigned to the staff user recording it.
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateLeadRequest true "Lead"
+// @Success 201 {object} dto.LeadResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /leads [post]
+func (h *Handler) CreateLead(c *gin.Context) {
+	var req dto.CreateLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	lead, err := h.leadService.Create(c.Request.Context(), req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, lead, "Lead created successfully")
+}
+
+// GetLeads godoc
+// @Summary List leads
+// @Tags leads
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param search query string false "Name, email or phone"
+// @Param stage query string false "Pipeline stage (new, contacted, trial, negotiation, won, lost)"
+// @Param source query string false "Source"
+// @Param owner_id query string false "Owner user ID"
+// @Param course_id query string false "Course of interest"
+// @Param follow_up_due query bool false "Only open leads whose follow-up is due, earliest first"
+// @Success 200 {object} dto.PaginatedResponse
+// @Router /leads [get]
+func (h *Handler) GetLeads(c *gin.Context) {
+	pagination := helpers.GetPaginationParams(c)
+
+	var filter dto.LeadFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	response, err := h.leadService.GetAll(c.Request.Context(), pagination, filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLead godoc
+// @Summary Get a lead
+// @Tags leads
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Success 200 {object} dto.LeadResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /leads/{leadID} [get]
+func (h *Handler) GetLead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	lead, err := h.leadService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lead, "Lead retrieved successfully")
+}
+
+// UpdateLead godoc
+// @Summary Update a lead
+// @Description Update a lead's contact details, source, course of interest, owner or follow-up date
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Param body body dto.UpdateLeadRequest true "Changes"
+// @Success 200 {object} dto.LeadResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /leads/{leadID} [put]
+func (h *Handler) UpdateLead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	var req dto.UpdateLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	lead, err := h.leadService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lead, "Lead updated successfully")
+}
+
+// DeleteLead godoc
+// @Summary Delete a lead
+// @Tags leads
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /leads/{leadID} [delete]
+func (h *Handler) DeleteLead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	if err := h.leadService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Lead deleted successfully")
+}
+
+// ChangeLeadStage godoc
+// @Summary Move a lead through the pipeline
+// @Description Change a lead's stage. Lost leads need a reason; won and lost leads are closed and lose their follow-up.
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Param body body dto.ChangeLeadStageRequest true "Stage"
+// @Success 200 {object} dto.LeadResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /leads/{leadID}/stage [post]
+func (h *Handler) ChangeLeadStage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	var req dto.ChangeLeadStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	lead, err := h.leadService.ChangeStage(c.Request.Context(), id, req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lead, "Lead stage changed successfully")
+}
+
+// CreateLeadActivity godoc
+// @Summary Log a lead activity
+// @Description Log a call, message, meeting or note. Reaching a new lead moves it to contacted.
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Param body body dto.CreateLeadActivityRequest true "Activity"
+// @Success 201 {object} dto.LeadActivityResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /leads/{leadID}/activities [post]
+func (h *Handler) CreateLeadActivity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	var req dto.CreateLeadActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	activity, err := h.leadService.AddActivity(c.Request.Context(), id, req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, activity, "Lead activity logged successfully")
+}
+
+// GetLeadActivities godoc
+// @Summary Get a lead's history
+// @Description List a lead's calls, messages, meetings, notes and stage changes, most recent first
+// @Tags leads
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Success 200 {array} dto.LeadActivityResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /leads/{leadID}/activities [get]
+func (h *Handler) GetLeadActivities(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	activities, err := h.leadService.GetActivities(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, activities, "Lead activities retrieved successfully")
+}
+
+// ConvertLead godoc
+// @Summary Convert a won lead
+// @Description Turn a won lead into an application (needs a course and date_of_birth) or a student (needs group_id). The created record keeps the lead ID for attribution.
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param leadID path string true "Lead ID"
+// @Param body body dto.ConvertLeadRequest true "Conversion"
+// @Success 201 {object} dto.LeadConversionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /leads/{leadID}/convert [post]
+func (h *Handler) ConvertLead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("leadID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid lead ID"))
+		return
+	}
+
+	var req dto.ConvertLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.leadService.Convert(c.Request.Context(), id, req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, result, "Lead converted successfully")
+}
@@ -257,6 +257,7 @@ func (h *Handler) GetInvoice(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
 // @Param search query string false "Search term"
+// @Param term_id query string false "Academic term ID filter"
 // @Success 200 {object} dto.PaginatedResponse
 // @Failure 400 {object} helpers.APIResponse
 // @Router /invoices [get]
@@ -267,7 +268,7 @@ func (h *Handler) GetAllInvoices(c *gin.Context) {
 		return
 	}
 
-	result, err := h.invoiceService.GetAll(c.Request.Context(), req)
+	result, err := h.invoiceService.GetAll(c.Request.Context(), req, c.Query("term_id"))
 	if err != nil {
 		handlePaymentError(c, err)
 		return
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GenerateGroupReportCards godoc
+// @Summary Generate report cards for a group
+// @Description Compute the results of a term, or of an explicit from/to range, for every student in a group, store them as PDF documents and optionally email linked parents
+// @Tags report-cards
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.GenerateReportCardsRequest true "Report card options"
+// @Success 201 {array} dto.ReportCardResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/report-cards [post]
+func (h *Handler) GenerateGroupReportCards(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.GenerateReportCardsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	cards, err := h.reportCardService.GenerateForGroup(c.Request.Context(), groupID, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, cards, "Report cards generated successfully")
+}
+
+// GetGroupReportCards godoc
+// @Summary List a group's report cards
+// @Description List report cards issued in a group, optionally for a single term
+// @Tags report-cards
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param term query string false "Term"
+// @Success 200 {array} dto.ReportCardResponse
+// @Router /groups/{groupID}/report-cards [get]
+func (h *Handler) GetGroupReportCards(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	cards, err := h.reportCardService.GetByGroup(c.Request.Context(), groupID, c.Query("term"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, cards, "Report cards retrieved successfully")
+}
+
+// GetStudentReportCards godoc
+// @Summary List a student's report cards
+// @Description List every report card issued to a student
+// @Tags report-cards
+// @Produce json
+// @Security ApiKeyAuth
+// @Param studentID path string true "Student ID"
+// @Success 200 {array} dto.ReportCardResponse
+// @Router /students/{studentID}/report-cards [get]
+func (h *Handler) GetStudentReportCards(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid student ID"))
+		return
+	}
+
+	cards, err := h.reportCardService.GetByStudent(c.Request.Context(), studentID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, cards, "Report cards retrieved successfully")
+}
+
+// GetStudentTranscript godoc
+// @Summary Get a student's transcript
+// @Description Get the cumulative transcript across every course the student has completed
+// @Tags report-cards
+// @Produce json
+// @Security ApiKeyAuth
+// @Param studentID path string true "Student ID"
+// @Success 200 {object} dto.TranscriptResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /students/{studentID}/transcript [get]
+func (h *Handler) GetStudentTranscript(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid student ID"))
+		return
+	}
+
+	transcript, err := h.reportCardService.GetTranscript(c.Request.Context(), studentID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, transcript, "Transcript retrieved successfully")
+}
+
+// GenerateGroupTranscripts godoc
+// @Summary Generate transcripts for a group
+// @Description Render and store a cumulative transcript PDF for every student in a group, optionally emailing linked parents
+// @Tags report-cards
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.GenerateTranscriptsRequest false "Transcript options"
+// @Success 201 {array} dto.TranscriptResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/transcripts [post]
+func (h *Handler) GenerateGroupTranscripts(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.GenerateTranscriptsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.HandleError(c, errors.Validation(err.Error()))
+			return
+		}
+	}
+
+	transcripts, err := h.reportCardService.GenerateTranscriptsForGroup(c.Request.Context(), groupID, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, transcripts, "Transcripts generated successfully")
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateRoom godoc
+// @Summary Create a room
+// @Description Create a room with its seat capacity and equipment
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.CreateRoomRequest true "Room"
+// @Success 201 {object} dto.RoomResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /rooms [post]
+func (h *Handler) CreateRoom(c *gin.Context) {
+	var req dto.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	room, err := h.roomService.Create(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, room, "Room created successfully")
+}
+
+// GetAllRooms godoc
+// @Summary List rooms
+// @Description List rooms, optionally by building, minimum seats, equipment tags or active flag
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param building query string false "Building"
+// @Param min_capacity query int false "Minimum seats"
+// @Param equipment query string false "Comma-separated equipment tags"
+// @Param active query bool false "Active rooms only"
+// @Success 200 {array} dto.RoomResponse
+// @Router /rooms [get]
+func (h *Handler) GetAllRooms(c *gin.Context) {
+	var filter dto.RoomFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	rooms, err := h.roomService.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, rooms, "Rooms retrieved successfully")
+}
+
+// GetRoom godoc
+// @Summary Get a room
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param roomID path string true "Room ID"
+// @Success 200 {object} dto.RoomResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /rooms/{roomID} [get]
+func (h *Handler) GetRoom(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("roomID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid room ID"))
+		return
+	}
+
+	room, err := h.roomService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, room, "Room retrieved successfully")
+}
+
+// UpdateRoom godoc
+// @Summary Update a room
+// @Description Update a room. A new name is applied to the timetables, exams and events booking it.
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param roomID path string true "Room ID"
+// @Param body body dto.UpdateRoomRequest true "Room updates"
+// @Success 200 {object} dto.RoomResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /rooms/{roomID} [put]
+func (h *Handler) UpdateRoom(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("roomID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid room ID"))
+		return
+	}
+
+	var req dto.UpdateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	room, err := h.roomService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, room, "Room updated successfully")
+}
+
+// DeleteRoom godoc
+// @Summary Delete a room
+// @Description Delete a room no timetable or upcoming exam or event books
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param roomID path string true "Room ID"
+// @Success 200 {object} helpers.APIResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /rooms/{roomID} [delete]
+func (h *Handler) DeleteRoom(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("roomID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid room ID"))
+		return
+	}
+
+	if err := h.roomService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Room deleted successfully")
+}
+
+// GetRoomUtilization godoc
+// @Summary Room utilization report
+// @Description Booked hours against available hours per room for one week (Monday to Sunday)
+// @Tags rooms
+// @Produce json
+// @Security ApiKeyAuth
+// @Param week query string false "Any date in the week (YYYY-MM-DD), defaults to this week"
+// @Success 200 {object} dto.RoomUtilizationReport
+// @Router /rooms/utilization [get]
+func (h *Handler) GetRoomUtilization(c *gin.Context) {
+	week := time.Now()
+	if value := c.Query("week"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errors.HandleError(c, errors.BadRequest("Invalid week date, expected YYYY-MM-DD"))
+			return
+		}
+		week = parsed
+	}
+
+	report, err := h.roomService.Utilization(c.Request.Context(), week)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, report, "Room utilization retrieved successfully")
+}
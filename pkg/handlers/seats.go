@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GetGroupAvailability godoc
+// @Summary Get group seat availability
+// @Description Get a group's capacity, enrolled students, active seat holds and free seats
+// @Tags groups
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Success 200 {object} dto.GroupAvailabilityResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/availability [get]
+func (h *Handler) GetGroupAvailability(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	availability, err := h.seatService.GetAvailability(c.Request.Context(), groupID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, availability, "Group availability retrieved successfully")
+}
+
+// HoldGroupSeat godoc
+// @Summary Hold a group seat
+// @Description Hold a free seat of a group until it expires, e.g. while a first payment is pending. Enrolling with the hold's ID takes the seat up.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Param body body dto.CreateSeatHoldRequest true "Seat hold"
+// @Success 201 {object} dto.SeatHoldResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/holds [post]
+func (h *Handler) HoldGroupSeat(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	var req dto.CreateSeatHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	hold, err := h.seatService.HoldSeat(c.Request.Context(), groupID, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, hold, "Seat held successfully")
+}
+
+// ReleaseSeatHold godoc
+// @Summary Release a seat hold
+// @Description Give a held seat back to its group; it is offered to the group's waitlist
+// @Tags groups
+// @Produce json
+// @Security ApiKeyAuth
+// @Param holdID path string true "Seat hold ID"
+// @Success 200 {object} dto.SeatHoldResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /seat-holds/{holdID} [delete]
+func (h *Handler) ReleaseSeatHold(c *gin.Context) {
+	holdID, err := uuid.Parse(c.Param("holdID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid seat hold ID"))
+		return
+	}
+
+	hold, err := h.seatService.ReleaseHold(c.Request.Context(), holdID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	h.waitlistService.SeatsFreed(c.Request.Context(), hold.GroupID)
+
+	helpers.SuccessResponse(c, hold, "Seat hold released successfully")
+}
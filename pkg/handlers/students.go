@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
@@ -163,11 +164,63 @@ func (h *Handler) UpdateStudent(c *gin.Context) {
 // @Router       /groups/{groupID}/students/{studentID} [delete]
 func (h *Handler) DeleteStudent(c *gin.Context) {
 	id := c.Param("studentID")
-	err := h.studentService.Delete(c.Request.Context(), id)
+	student, err := h.studentService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	if err := h.studentService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	// The student's seat goes to the next in line on the waitlist
+	h.waitlistService.SeatsFreed(c.Request.Context(), student.GroupID)
+
 	c.Status(http.StatusOK)
 }
+
+// TransferStudent godoc
+// @Summary      Transfer a student
+// @Description  Move a student to another group with a free seat. The seat they leave is offered to the old group's waitlist.
+// @Tags         students
+// @Accept       json
+// @Produce      json
+// @Param        studentID  path      string                      true  "Student ID"
+// @Param        transfer   body      dto.TransferStudentRequest  true  "Transfer Request"
+// @Success      200        {object}  dto.StudentResponse
+// @Failure      400        {object}  dto.ErrorResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      409        {object}  dto.ErrorResponse
+// @Failure      500        {object}  dto.ErrorResponse
+// @Router       /students/{studentID}/transfer [post]
+func (h *Handler) TransferStudent(c *gin.Context) {
+	id := c.Param("studentID")
+	var req dto.TransferStudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	student, err := h.studentService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	var requestedBy uuid.UUID
+	if userID := helpers.CurrentUserID(c); userID != nil {
+		requestedBy = *userID
+	}
+
+	response, err := h.studentService.Transfer(c.Request.Context(), id, req, requestedBy)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	h.waitlistService.SeatsFreed(c.Request.Context(), student.GroupID)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// GetCourseSyllabus godoc
+// @Summary Get a course syllabus
+// @Description Get the ordered modules and lessons of a course
+// @Tags syllabus
+// @Produce json
+// @Security ApiKeyAuth
+// @Param courseID path string true "Course ID"
+// @Success 200 {object} dto.SyllabusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /courses/{courseID}/syllabus [get]
+func (h *Handler) GetCourseSyllabus(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid course ID"))
+		return
+	}
+
+	syllabus, err := h.syllabusService.Get(c.Request.Context(), courseID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, syllabus, "Syllabus retrieved successfully")
+}
+
+// UpdateCourseSyllabus godoc
+// @Summary Replace a course syllabus
+// @Description Replace the modules and lessons of a course in the given order. Existing modules and lessons are kept by ID; those left out are deleted with their coverage.
+// @Tags syllabus
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param courseID path string true "Course ID"
+// @Param body body dto.UpdateSyllabusRequest true "Syllabus"
+// @Success 200 {object} dto.SyllabusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /courses/{courseID}/syllabus [put]
+func (h *Handler) UpdateCourseSyllabus(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid course ID"))
+		return
+	}
+
+	var req dto.UpdateSyllabusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	syllabus, err := h.syllabusService.Replace(c.Request.Context(), courseID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, syllabus, "Syllabus updated successfully")
+}
+
+// GetClassSessionSyllabus godoc
+// @Summary Get the syllabus lessons covered in a class session
+// @Tags syllabus
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Class session ID"
+// @Success 200 {object} dto.SessionSyllabusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID}/syllabus [get]
+func (h *Handler) GetClassSessionSyllabus(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid class session ID"))
+		return
+	}
+
+	lessons, err := h.syllabusService.GetSessionLessons(c.Request.Context(), sessionID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lessons, "Session lessons retrieved successfully")
+}
+
+// SetClassSessionSyllabus godoc
+// @Summary Tick off syllabus lessons for a class session
+// @Description Set the syllabus lessons covered in a class session, replacing earlier ticks
+// @Tags syllabus
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionID path string true "Class session ID"
+// @Param body body dto.SetSessionSyllabusRequest true "Covered lessons"
+// @Success 200 {object} dto.SessionSyllabusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /class-sessions/{sessionID}/syllabus [put]
+func (h *Handler) SetClassSessionSyllabus(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid class session ID"))
+		return
+	}
+
+	var req dto.SetSessionSyllabusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	userID := helpers.CurrentUserID(c)
+	if userID == nil {
+		helpers.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	lessons, err := h.syllabusService.SetSessionLessons(c.Request.Context(), sessionID, req, *userID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, lessons, "Session lessons updated successfully")
+}
+
+// GetGroupSyllabusProgress godoc
+// @Summary Get a group's syllabus progress
+// @Description Get the syllabus of the group's course with the lessons covered so far and the next one due
+// @Tags syllabus
+// @Produce json
+// @Security ApiKeyAuth
+// @Param groupID path string true "Group ID"
+// @Success 200 {object} dto.SyllabusProgressResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/syllabus [get]
+func (h *Handler) GetGroupSyllabusProgress(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid group ID"))
+		return
+	}
+
+	progress, err := h.syllabusService.GetGroupProgress(c.Request.Context(), groupID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, progress, "Syllabus progress retrieved successfully")
+}
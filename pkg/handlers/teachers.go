@@ -18,6 +18,7 @@ import (
 // @Param        page      query     int     false  "Page number"
 // @Param        page_size query     int     false  "Page size"
 // @Param        search    query     string  false  "Search term"
+// @Param        status    query     string  false  "Status (active, on_leave, left)"
 // @Success      200       {object}  dto.PaginatedResponse
 // @Failure      500       {object}  dto.ErrorResponse
 // @Router       /teachers [get]
@@ -26,7 +27,7 @@ func (h *Handler) GetAllTeachers(c *gin.Context) {
 	pagination := helpers.GetPaginationParams(c)
 	// Search is already in pagination params now
 
-	response, err := h.teacherService.GetAll(c.Request.Context(), pagination)
+	response, err := h.teacherService.GetAll(c.Request.Context(), pagination, c.Query("status"))
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -137,3 +138,45 @@ func (h *Handler) DeleteTeacher(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// GetTeacherWorkload godoc
+// @Summary      Get a teacher's workload
+// @Description  Get a teacher's scheduled lesson and exam hours in a week against their weekly hours limit
+// @Tags         teachers
+// @Produce      json
+// @Param        teacherID  path      string  true   "Teacher ID"
+// @Param        week       query     string  false  "Any date in the week (YYYY-MM-DD); the current week by default"
+// @Success      200        {object}  dto.TeacherWorkloadResponse
+// @Failure      404        {object}  dto.ErrorResponse
+// @Failure      422        {object}  dto.ErrorResponse
+// @Router       /teachers/{teacherID}/workload [get]
+func (h *Handler) GetTeacherWorkload(c *gin.Context) {
+	id := c.Param("teacherID")
+	response, err := h.teacherService.GetWorkload(c.Request.Context(), id, c.Query("week"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTeacherWorkloads godoc
+// @Summary      Get all teachers' workload
+// @Description  Get the weekly workload of every teacher with a status, busiest first
+// @Tags         teachers
+// @Produce      json
+// @Param        week    query     string  false  "Any date in the week (YYYY-MM-DD); the current week by default"
+// @Param        status  query     string  false  "Status (active, on_leave, left); active by default"
+// @Success      200     {array}   dto.TeacherWorkloadResponse
+// @Failure      422     {object}  dto.ErrorResponse
+// @Router       /teachers/workload [get]
+func (h *Handler) GetTeacherWorkloads(c *gin.Context) {
+	response, err := h.teacherService.GetWorkloads(c.Request.Context(), c.Query("week"), c.Query("status"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
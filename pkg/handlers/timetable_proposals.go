@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// CreateTimetableProposal godoc
+// @Summary Solve a timetable
+// @Description Propose conflict-free weekly lessons for groups from their weekly hours, teacher availability, rooms and daily limits. Infeasible requests return a proposal listing the unmet constraints.
+// @Tags timetables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.SolveTimetableRequest true "Groups and constraints"
+// @Success 201 {object} dto.TimetableProposalResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /timetables/proposals [post]
+func (h *Handler) CreateTimetableProposal(c *gin.Context) {
+	var req dto.SolveTimetableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	proposal, err := h.timetableSolverService.Propose(c.Request.Context(), req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, proposal, "Timetable proposal created successfully")
+}
+
+// GetTimetableProposal godoc
+// @Summary Get a timetable proposal
+// @Tags timetables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param proposalID path string true "Proposal ID"
+// @Success 200 {object} dto.TimetableProposalResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /timetables/proposals/{proposalID} [get]
+func (h *Handler) GetTimetableProposal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("proposalID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid proposal ID"))
+		return
+	}
+
+	proposal, err := h.timetableSolverService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, proposal, "Timetable proposal retrieved successfully")
+}
+
+// CommitTimetableProposal godoc
+// @Summary Commit a timetable proposal
+// @Description Create a timetable for each group in a draft proposal and assign it, all in one transaction
+// @Tags timetables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param proposalID path string true "Proposal ID"
+// @Success 200 {object} dto.TimetableProposalResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /timetables/proposals/{proposalID}/commit [post]
+func (h *Handler) CommitTimetableProposal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("proposalID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid proposal ID"))
+		return
+	}
+
+	proposal, err := h.timetableSolverService.Commit(c.Request.Context(), id)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, proposal, "Timetable proposal committed successfully")
+}
+
+// DeleteTimetableProposal godoc
+// @Summary Delete a timetable proposal
+// @Description Discard a proposal that has not been committed
+// @Tags timetables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param proposalID path string true "Proposal ID"
+// @Success 200 {object} helpers.APIResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /timetables/proposals/{proposalID} [delete]
+func (h *Handler) DeleteTimetableProposal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("proposalID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid proposal ID"))
+		return
+	}
+
+	if err := h.timetableSolverService.Delete(c.Request.Context(), id); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, nil, "Timetable proposal deleted successfully")
+}
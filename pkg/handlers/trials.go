@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
+)
+
+// BookTrial godoc
+// @Summary Book a trial lesson
+// @Description Book a free trial lesson in a group for a lead or waitlist prospect, by date or class session. The lesson must have a free seat next to its students and other guests. A new or contacted lead moves to the trial stage.
+// @Tags trials
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body dto.BookTrialRequest true "Trial booking"
+// @Success 201 {object} dto.TrialResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /trials [post]
+func (h *Handler) BookTrial(c *gin.Context) {
+	var req dto.BookTrialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	trial, err := h.trialService.Book(c.Request.Context(), req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.CreatedResponse(c, trial, "Trial lesson booked successfully")
+}
+
+// GetTrials godoc
+// @Summary List trial lessons
+// @Tags trials
+// @Produce json
+// @Security ApiKeyAuth
+// @Param group_id query string false "Group ID"
+// @Param teacher_id query string false "Teacher ID"
+// @Param lead_id query string false "Lead ID"
+// @Param waitlist_id query string false "Waitlist entry ID"
+// @Param status query string false "Status (booked, attended, no_show, cancelled)"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {array} dto.TrialResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /trials [get]
+func (h *Handler) GetTrials(c *gin.Context) {
+	var filter dto.TrialFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	trials, err := h.trialService.List(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, trials, "Trial lessons retrieved successfully")
+}
+
+// GetTrial godoc
+// @Summary Get a trial lesson
+// @Tags trials
+// @Produce json
+// @Security ApiKeyAuth
+// @Param trialID path string true "Trial lesson ID"
+// @Success 200 {object} dto.TrialResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /trials/{trialID} [get]
+func (h *Handler) GetTrial(c *gin.Context) {
+	trialID, err := uuid.Parse(c.Param("trialID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid trial lesson ID"))
+		return
+	}
+
+	trial, err := h.trialService.GetByID(c.Request.Context(), trialID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, trial, "Trial lesson retrieved successfully")
+}
+
+// RecordTrialOutcome godoc
+// @Summary Record a trial lesson outcome
+// @Description Record whether the prospect attended and the teacher's outcome and feedback, once the lesson has taken place
+// @Tags trials
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param trialID path string true "Trial lesson ID"
+// @Param body body dto.RecordTrialOutcomeRequest true "Outcome"
+// @Success 200 {object} dto.TrialResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /trials/{trialID}/outcome [put]
+func (h *Handler) RecordTrialOutcome(c *gin.Context) {
+	trialID, err := uuid.Parse(c.Param("trialID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid trial lesson ID"))
+		return
+	}
+
+	var req dto.RecordTrialOutcomeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	trial, err := h.trialService.RecordOutcome(c.Request.Context(), trialID, req, helpers.CurrentUserID(c))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, trial, "Trial lesson outcome recorded successfully")
+}
+
+// CancelTrial godoc
+// @Summary Cancel a trial lesson
+// @Tags trials
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param trialID path string true "Trial lesson ID"
+// @Param body body dto.CancelTrialRequest false "Cancellation"
+// @Success 200 {object} dto.TrialResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /trials/{trialID}/cancel [post]
+func (h *Handler) CancelTrial(c *gin.Context) {
+	trialID, err := uuid.Parse(c.Param("trialID"))
+	if err != nil {
+		errors.HandleError(c, errors.BadRequest("Invalid trial lesson ID"))
+		return
+	}
+
+	var req dto.CancelTrialRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errors.HandleError(c, errors.Validation(err.Error()))
+			return
+		}
+	}
+
+	trial, err := h.trialService.Cancel(c.Request.Context(), trialID, req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, trial, "Trial lesson cancelled successfully")
+}
+
+// GetTrialConversionReport godoc
+// @Summary Trial conversion report
+// @Description Count booked, attended and converted trial lessons in total, per teacher and per course. An attended trial converts when its prospect has become a student with a completed payment.
+// @Tags trials
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Param course_id query string false "Course ID"
+// @Param teacher_id query string false "Teacher ID"
+// @Success 200 {object} dto.TrialConversionReport
+// @Failure 422 {object} dto.ErrorResponse
+// @Router /trials/conversion-report [get]
+func (h *Handler) GetTrialConversionReport(c *gin.Context) {
+	var filter dto.TrialConversionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		errors.HandleError(c, errors.Validation(err.Error()))
+		return
+	}
+
+	report, err := h.trialService.GetConversionReport(c.Request.Context(), filter)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, report, "Trial conversion report generated successfully")
+}
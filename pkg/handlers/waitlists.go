@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/helpers"
 )
 
@@ -131,3 +132,88 @@ func (h *Handler) GetGroupWaitlist(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// GetWaitlistOffer gets a seat offer
+// @Summary Get a seat offer
+// @Description Get the seat offer behind an accept/decline link sent to a waitlisted prospect
+// @Tags waitlists
+// @Produce json
+// @Param token path string true "Offer token"
+// @Success 200 {object} dto.WaitlistOfferResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /public/waitlist/offers/{token} [get]
+func (h *Handler) GetWaitlistOffer(c *gin.Context) {
+	resp, err := h.waitlistService.GetOffer(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptWaitlistOffer accepts a seat offer
+// @Summary Accept a seat offer
+// @Description Accept an open seat offer; the prospect is enrolled in the group, becoming a student if they are not one yet
+// @Tags waitlists
+// @Produce json
+// @Param token path string true "Offer token"
+// @Success 200 {object} dto.WaitlistOfferResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /public/waitlist/offers/{token}/accept [post]
+func (h *Handler) AcceptWaitlistOffer(c *gin.Context) {
+	resp, err := h.waitlistService.AcceptOffer(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeclineWaitlistOffer declines a seat offer
+// @Summary Decline a seat offer
+// @Description Decline an open seat offer; the seat is offered to the next entry on the waitlist
+// @Tags waitlists
+// @Produce json
+// @Param token path string true "Offer token"
+// @Success 200 {object} dto.WaitlistOfferResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /public/waitlist/offers/{token}/decline [post]
+func (h *Handler) DeclineWaitlistOffer(c *gin.Context) {
+	resp, err := h.waitlistService.DeclineOffer(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// OfferGroupSeats offers a group's free seats to its waitlist
+// @Summary Offer free seats
+// @Description Offer each free seat of a group to the next pending waitlist entry
+// @Tags groups
+// @Produce json
+// @Param groupID path string true "Group ID"
+// @Success 200 {array} dto.WaitlistResponse
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /groups/{groupID}/waitlist/offer [post]
+func (h *Handler) OfferGroupSeats(c *gin.Context) {
+	groupID, err := uuid.Parse(c.Param("groupID"))
+	if err != nil {
+		helpers.NewErrorResponse(c, http.StatusBadRequest, "invalid group id")
+		return
+	}
+
+	resp, err := h.waitlistService.OfferOpenSeats(c.Request.Context(), groupID)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CurrentUserID returns the authenticated user's ID, or nil when the request
+// is unauthenticated or the ID is not a valid UUID
+func CurrentUserID(c *gin.Context) *uuid.UUID {
+	for _, key := range []string{"user_id", "userID"} {
+		value, exists := c.Get(key)
+		if !exists {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if id, err := uuid.Parse(str); err == nil {
+			return &id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AcademicTerm is a term or semester of the school year. Groups, grades,
+// exams and invoices are scoped to the term their date falls in.
+type AcademicTerm struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	StartDate time.Time `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null;index" json:"end_date"` // Inclusive
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Breaks within the term; there are no lessons on their days
+	Breaks []TermBreak `gorm:"foreignKey:TermID;constraint:OnDelete:CASCADE" json:"breaks,omitempty"`
+}
+
+// TableName specifies the table name for AcademicTerm model
+func (AcademicTerm) TableName() string {
+	return "academic_terms"
+}
+
+// Contains reports whether the date falls within the term
+func (t *AcademicTerm) Contains(date time.Time) bool {
+	day := DateOf(date)
+	return !day.Before(DateOf(t.StartDate)) && !day.After(DateOf(t.EndDate))
+}
+
+// TermBreak is a period without lessons within a term, e.g. a winter break
+type TermBreak struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TermID uuid.UUID `gorm:"type:uuid;not null;index" json:"term_id"`
+
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"` // Inclusive
+}
+
+// TableName specifies the table name for TermBreak model
+func (TermBreak) TableName() string {
+	return "term_breaks"
+}
+
+// HolidaySource tells how a holiday was added
+type HolidaySource string
+
+const (
+	HolidayManual HolidaySource = "manual"
+	HolidayICS    HolidaySource = "ics" // Imported from an iCalendar file
+)
+
+// Holiday is a school-wide day or period without lessons
+type Holiday struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	StartDate time.Time `gorm:"type:date;not null;index" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null;index" json:"end_date"` // Inclusive
+
+	Source HolidaySource `gorm:"type:varchar(20);not null;default:'manual'" json:"source"`
+	// UID of the imported event, so re-importing a file updates it
+	ExternalUID string `gorm:"type:varchar(255);index" json:"external_uid,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Holiday model
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// DateOf returns the calendar date of t at midnight UTC, the way date
+// columns are stored
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TermAt returns the ID of the term the date falls in, or nil when no term
+// covers it. Lookup failures leave the record without a term.
+func TermAt(tx *gorm.DB, date time.Time) *uuid.UUID {
+	if date.IsZero() {
+		return nil
+	}
+	var term AcademicTerm
+	day := DateOf(date)
+	if err := tx.Session(&gorm.Session{NewDB: true}).Select("id").
+		Where("start_date <= ? AND end_date >= ?", day, day).
+		Limit(1).Find(&term).Error; err != nil || term.ID == uuid.Nil {
+		return nil
+	}
+	return &term.ID
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountInvite is a single-use link for a new user to choose their
+// password. Only a hash of the token is stored.
+type AccountInvite struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"` // SHA-256 of the link token
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for AccountInvite model
+func (AccountInvite) TableName() string {
+	return "account_invites"
+}
+
+// IsOpen reports whether the invite can still be used
+func (i *AccountInvite) IsOpen(now time.Time) bool {
+	return i.UsedAt == nil && now.Before(i.ExpiresAt)
+}
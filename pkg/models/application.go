@@ -37,7 +37,7 @@ type Application struct {
 	ApplicationDate time.Time         `gorm:"not null" json:"application_date"`
 
 	// Documents
-	Documents []string `gorm:"type:jsonb" json:"documents,omitempty"`
+	Documents []string `gorm:"type:jsonb;serializer:json" json:"documents,omitempty"`
 
 	// Education background
 	PreviousEducation string `gorm:"type:text" json:"previous_education,omitempty"`
@@ -51,8 +51,14 @@ type Application struct {
 	EnrolledAs *uuid.UUID `gorm:"type:uuid" json:"enrolled_as,omitempty"` // Student ID if enrolled
 	EnrolledAt *time.Time `json:"enrolled_at,omitempty"`
 
+	// Attribution
+	LeadID *uuid.UUID `gorm:"type:uuid;index" json:"lead_id,omitempty"` // Lead the application was converted from
+
+	// Public intake
+	StatusToken string `gorm:"type:varchar(64);index" json:"-"` // Secret of the applicant's status-check link
+
 	// Additional info
-	Metadata map[string]interface{} `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Metadata map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
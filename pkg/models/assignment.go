@@ -55,6 +55,12 @@ type Assignment struct {
 	WeightPercent float64 `gorm:"default:0" json:"weight_percent"` // Weight in final grade
 	AllowLate     bool    `gorm:"default:true" json:"allow_late"`
 	LatePenalty   float64 `gorm:"default:10" json:"late_penalty"` // Percentage deducted per day
+	MaxAttempts   int     `gorm:"default:0" json:"max_attempts"`  // 0 allows unlimited resubmissions
+
+	// Late policy. No column defaults: zero is a real value (no deduction,
+	// no late window), so the service fills in the defaults
+	MaxLatePenalty float64 `json:"max_late_penalty"` // Cap on the total late deduction, in percent
+	LateWindowDays int     `json:"late_window_days"` // Days after the due date late work is accepted
 
 	// Instructions
 	Instructions string `gorm:"type:text" json:"instructions,omitempty"`
@@ -80,11 +86,20 @@ func (Assignment) TableName() string {
 	return "assignments"
 }
 
+// SubmissionDeadline returns the moment the assignment stops accepting work:
+// the due date, or the end of the late window when late work is allowed
+func (a *Assignment) SubmissionDeadline() time.Time {
+	if !a.AllowLate {
+		return a.DueDate
+	}
+	return a.DueDate.AddDate(0, 0, a.LateWindowDays)
+}
+
 // SubmissionStatus represents the status of a submission
 type SubmissionStatus string
 
 const (
-	SubmissionPending   SubmissionStatus = "pending"
+	SubmissionPending   SubmissionStatus = "pending" // Nothing submitted; created with zero points when the assignment closes
 	SubmissionSubmitted SubmissionStatus = "submitted"
 	SubmissionLate      SubmissionStatus = "late"
 	SubmissionGraded    SubmissionStatus = "graded"
@@ -103,7 +118,7 @@ type AssignmentSubmission struct {
 	Status      SubmissionStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
 	SubmittedAt *time.Time       `json:"submitted_at,omitempty"`
 	Content     string           `gorm:"type:text" json:"content,omitempty"`
-	Attachments string           `gorm:"type:text" json:"attachments,omitempty"` // JSON array
+	Attachments string           `gorm:"type:text" json:"attachments,omitempty"` // Legacy free-form links; uploaded files are in Files
 
 	// Grading
 	Points   *float64   `json:"points,omitempty"`
@@ -116,8 +131,9 @@ type AssignmentSubmission struct {
 	DaysLate       int     `gorm:"default:0" json:"days_late"`
 	PenaltyApplied float64 `gorm:"default:0" json:"penalty_applied"`
 
-	// Attempts (for resubmission)
-	AttemptNumber int `gorm:"default:1" json:"attempt_number"`
+	// Attempts (for resubmission); every attempt is its own submission record
+	AttemptNumber int  `gorm:"default:1" json:"attempt_number"`
+	IsFinal       bool `gorm:"default:false" json:"is_final"` // The graded attempt that counts
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
@@ -125,11 +141,31 @@ type AssignmentSubmission struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// Relations
-	Assignment *Assignment `gorm:"foreignKey:AssignmentID" json:"assignment,omitempty"`
-	Student    *Student    `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Assignment *Assignment            `gorm:"foreignKey:AssignmentID" json:"assignment,omitempty"`
+	Student    *Student               `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Files      []SubmissionAttachment `gorm:"foreignKey:SubmissionID" json:"files,omitempty"`
 }
 
 // TableName specifies the table name for AssignmentSubmission model
 func (AssignmentSubmission) TableName() string {
 	return "assignment_submissions"
 }
+
+// SubmissionAttachment links an uploaded document to a submission attempt
+type SubmissionAttachment struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	SubmissionID uuid.UUID `gorm:"type:uuid;not null;index" json:"submission_id"`
+	DocumentID   uuid.UUID `gorm:"type:uuid;not null" json:"document_id"`
+
+	FileName string `gorm:"type:varchar(255);not null" json:"file_name"`
+	FileSize int64  `json:"file_size"`
+	MimeType string `gorm:"type:varchar(100)" json:"mime_type"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for SubmissionAttachment model
+func (SubmissionAttachment) TableName() string {
+	return "submission_attachments"
+}
@@ -22,6 +22,7 @@ type Attendance struct {
 	ID        uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
 	StudentID uuid.UUID        `gorm:"type:uuid;not null" json:"student_id"`
 	GroupID   uuid.UUID        `gorm:"type:uuid;not null" json:"group_id"`
+	SessionID *uuid.UUID       `gorm:"type:uuid;index" json:"session_id,omitempty"` // Lesson the record belongs to
 	Date      time.Time        `gorm:"type:date;not null" json:"date"`
 	Status    AttendanceStatus `gorm:"type:varchar(20);not null" json:"status"`
 	Notes     string           `gorm:"type:text" json:"notes"`
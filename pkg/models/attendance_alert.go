@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttendanceAlertType represents what triggered an attendance alert
+type AttendanceAlertType string
+
+const (
+	AlertAbsence       AttendanceAlertType = "absence"        // Student marked absent; sent to parents
+	AlertLate          AttendanceAlertType = "late"           // Student marked late; sent to parents
+	AlertAbsenceStreak AttendanceAlertType = "absence_streak" // Consecutive absences threshold reached; escalated
+	AlertLowAttendance AttendanceAlertType = "low_attendance" // Attendance rate fell below threshold; escalated
+)
+
+// AttendanceAlert logs an alert sent about a student's attendance
+type AttendanceAlert struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	StudentID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_attendance_alert_day" json:"student_id"`
+	GroupID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"group_id"`
+	AttendanceID *uuid.UUID `gorm:"type:uuid" json:"attendance_id,omitempty"`
+
+	Date      time.Time           `gorm:"type:date;not null;uniqueIndex:idx_attendance_alert_day" json:"date"`
+	Type      AttendanceAlertType `gorm:"type:varchar(20);not null;uniqueIndex:idx_attendance_alert_day" json:"type"`
+	Escalated bool                `gorm:"default:false" json:"escalated"` // Sent to the teacher and admins
+
+	// Delivery
+	Subject           string   `gorm:"type:varchar(500)" json:"subject"`
+	Message           string   `gorm:"type:text" json:"message"`
+	Recipients        []string `gorm:"serializer:json" json:"recipients"`
+	NotificationsSent int      `json:"notifications_sent"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Student *Student `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Group   *Group   `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+}
+
+// TableName specifies the table name for AttendanceAlert model
+func (AttendanceAlert) TableName() string {
+	return "attendance_alerts"
+}
+
+// AttendanceAlertSettings holds the thresholds of the attendance alert pipeline.
+// A single row is kept; it is created with DefaultAttendanceAlertSettings on first use.
+type AttendanceAlertSettings struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	// Parent alerts
+	ParentAlertsEnabled bool `json:"parent_alerts_enabled"`
+	AlertOnLate         bool `json:"alert_on_late"`
+
+	// Escalation thresholds; 0 disables the rule
+	ConsecutiveAbsences     int     `json:"consecutive_absences"`
+	LowAttendancePercent    float64 `json:"low_attendance_percent"`
+	LowAttendanceWindowDays int     `json:"low_attendance_window_days"`
+	LowAttendanceMinRecords int     `json:"low_attendance_min_records"` // Records needed before the rate is judged
+
+	// Escalation recipients
+	EscalateToTeacher bool `json:"escalate_to_teacher"`
+	EscalateToAdmins  bool `json:"escalate_to_admins"`
+
+	// Audit fields
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for AttendanceAlertSettings model
+func (AttendanceAlertSettings) TableName() string {
+	return "attendance_alert_settings"
+}
+
+// DefaultAttendanceAlertSettings returns the settings used until they are configured
+func DefaultAttendanceAlertSettings() AttendanceAlertSettings {
+	return AttendanceAlertSettings{
+		ParentAlertsEnabled:     true,
+		AlertOnLate:             true,
+		ConsecutiveAbsences:     3,
+		LowAttendancePercent:    70,
+		LowAttendanceWindowDays: 30,
+		LowAttendanceMinRecords: 4,
+		EscalateToTeacher:       true,
+		EscalateToAdmins:        true,
+	}
+}
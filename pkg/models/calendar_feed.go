@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedOwner is whose calendar a feed publishes
+type CalendarFeedOwner string
+
+const (
+	FeedOwnerTeacher CalendarFeedOwner = "teacher"
+	FeedOwnerGroup   CalendarFeedOwner = "group"
+	FeedOwnerStudent CalendarFeedOwner = "student"
+	FeedOwnerParent  CalendarFeedOwner = "parent"
+)
+
+// CalendarFeed is an iCalendar subscription for a teacher, group, student or
+// parent. The secret token in its URL is the only credential, so a leaked
+// feed is revoked and replaced by a new one.
+type CalendarFeed struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Token     string            `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	OwnerType CalendarFeedOwner `gorm:"type:varchar(20);not null;index:idx_calendar_feed_owner" json:"owner_type"`
+	OwnerID   uuid.UUID         `gorm:"type:uuid;not null;index:idx_calendar_feed_owner" json:"owner_id"`
+	Name      string            `gorm:"type:varchar(255)" json:"name,omitempty"` // Calendar name shown by clients
+
+	CreatedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for CalendarFeed model
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// IsRevoked reports whether the feed no longer serves its calendar
+func (f *CalendarFeed) IsRevoked() bool {
+	return f.RevokedAt != nil
+}
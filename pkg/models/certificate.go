@@ -0,0 +1,128 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CertificateStatus represents the status of an issued certificate
+type CertificateStatus string
+
+const (
+	CertificateIssued  CertificateStatus = "issued"
+	CertificateRevoked CertificateStatus = "revoked"
+)
+
+// CertificateTemplate is a configurable certificate layout. Body text may use
+// the placeholders {{student_name}}, {{course_name}}, {{completion_date}},
+// {{grade}} and {{certificate_number}}.
+type CertificateTemplate struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Name        string `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Title       string `gorm:"type:varchar(255);not null" json:"title"`
+	Body        string `gorm:"type:text;not null" json:"body"`
+	Orientation string `gorm:"type:varchar(1);not null;default:'L'" json:"orientation"` // L (landscape) or P (portrait)
+
+	// Signature block
+	SignatoryName  string `gorm:"type:varchar(255)" json:"signatory_name,omitempty"`
+	SignatoryTitle string `gorm:"type:varchar(255)" json:"signatory_title,omitempty"`
+
+	IsDefault bool `gorm:"default:false" json:"is_default"`
+	IsActive  bool `gorm:"default:true" json:"is_active"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// Certificate represents a numbered course completion certificate
+type Certificate struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Number           string `gorm:"type:varchar(30);not null;uniqueIndex" json:"number"`
+	VerificationCode string `gorm:"type:varchar(20);not null;uniqueIndex" json:"verification_code"`
+
+	// Holder and course
+	StudentID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_id"`
+	CourseID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"course_id"`
+	GroupID    *uuid.UUID `gorm:"type:uuid;index" json:"group_id,omitempty"`
+	TemplateID *uuid.UUID `gorm:"type:uuid" json:"template_id,omitempty"`
+
+	// Result snapshot at issue time
+	ReportCardID    *uuid.UUID `gorm:"type:uuid" json:"report_card_id,omitempty"`
+	FinalGrade      string     `gorm:"type:varchar(20)" json:"final_grade,omitempty"`
+	FinalPercentage float64    `gorm:"default:0" json:"final_percentage"`
+	CompletionDate  time.Time  `gorm:"type:date;not null" json:"completion_date"`
+
+	// Issuing
+	Status     CertificateStatus `gorm:"type:varchar(20);not null;default:'issued'" json:"status"`
+	IssuedAt   time.Time         `gorm:"not null" json:"issued_at"`
+	IssuedBy   *uuid.UUID        `gorm:"type:uuid" json:"issued_by,omitempty"`
+	DocumentID *uuid.UUID        `gorm:"type:uuid" json:"document_id,omitempty"`
+
+	// Revocation
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy        *uuid.UUID `gorm:"type:uuid" json:"revoked_by,omitempty"`
+	RevocationReason string     `gorm:"type:text" json:"revocation_reason,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Student  *Student             `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Course   *Course              `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	Template *CertificateTemplate `gorm:"foreignKey:TemplateID" json:"template,omitempty"`
+}
+
+// CertificateCounter tracks certificate number sequences per year
+type CertificateCounter struct {
+	ID        uint   `gorm:"primaryKey"`
+	Year      string `gorm:"type:varchar(4);unique;not null"`
+	Counter   int64  `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for CertificateTemplate model
+func (CertificateTemplate) TableName() string {
+	return "certificate_templates"
+}
+
+// TableName specifies the table name for Certificate model
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+// TableName specifies the table name for CertificateCounter model
+func (CertificateCounter) TableName() string {
+	return "certificate_counters"
+}
+
+// DefaultCertificateTemplate returns the built-in layout used when no template
+// is configured
+func DefaultCertificateTemplate() *CertificateTemplate {
+	return &CertificateTemplate{
+		Name:        "Default",
+		Title:       "Certificate of Completion",
+		Body:        "This certifies that {{student_name}} has successfully completed the course {{course_name}} on {{completion_date}}.",
+		Orientation: "L",
+	}
+}
+
+// RenderBody fills the template placeholders
+func (t *CertificateTemplate) RenderBody(c *Certificate, studentName, courseName string) string {
+	return strings.NewReplacer(
+		"{{student_name}}", studentName,
+		"{{course_name}}", courseName,
+		"{{completion_date}}", c.CompletionDate.Format("January 2, 2006"),
+		"{{grade}}", c.FinalGrade,
+		"{{certificate_number}}", c.Number,
+	).Replace(t.Body)
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassSessionStatus represents the lifecycle state of a single lesson
+type ClassSessionStatus string
+
+const (
+	SessionScheduled   ClassSessionStatus = "scheduled"
+	SessionHeld        ClassSessionStatus = "held"
+	SessionCancelled   ClassSessionStatus = "cancelled"
+	SessionRescheduled ClassSessionStatus = "rescheduled"
+)
+
+// ClassSession is a single lesson of a group, materialized from its timetable.
+// Cancelled and rescheduled lessons stay on record but free up their slot.
+type ClassSession struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	GroupID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_class_session_slot,where:status <> 'cancelled' AND status <> 'rescheduled'" json:"group_id"`
+	TimetableID *uuid.UUID `gorm:"type:uuid;index" json:"timetable_id,omitempty"`
+	TeacherID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"teacher_id"`
+
+	// Scheduling
+	Date      time.Time `gorm:"type:date;not null;index;uniqueIndex:idx_class_session_slot" json:"date"`
+	StartTime string    `gorm:"type:varchar(5);not null;uniqueIndex:idx_class_session_slot" json:"start_time"` // HH:MM
+	EndTime   string    `gorm:"type:varchar(5);not null" json:"end_time"`                                      // HH:MM
+	Room      string    `gorm:"type:varchar(100)" json:"room,omitempty"`
+
+	// Lesson details
+	Topic  string             `gorm:"type:varchar(255)" json:"topic,omitempty"`
+	Status ClassSessionStatus `gorm:"type:varchar(20);not null;default:'scheduled';index" json:"status"`
+	Notes  string             `gorm:"type:text" json:"notes,omitempty"`
+
+	// Cancellation and rescheduling
+	CancellationReason string     `gorm:"type:text" json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	RescheduledFromID  *uuid.UUID `gorm:"type:uuid;index" json:"rescheduled_from_id,omitempty"`
+	RescheduledToID    *uuid.UUID `gorm:"type:uuid" json:"rescheduled_to_id,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Group     *Group     `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Timetable *Timetable `gorm:"foreignKey:TimetableID" json:"timetable,omitempty"`
+	Teacher   *Teacher   `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+}
+
+// TableName specifies the table name for ClassSession model
+func (ClassSession) TableName() string {
+	return "class_sessions"
+}
+
+// AcceptsAttendance reports whether attendance can be recorded for the session
+func (cs *ClassSession) AcceptsAttendance() bool {
+	return cs.Status == SessionScheduled || cs.Status == SessionHeld
+}
+
+// MakeUpCreditStatus represents the state of a make-up credit
+type MakeUpCreditStatus string
+
+const (
+	MakeUpCreditAvailable MakeUpCreditStatus = "available"
+	MakeUpCreditUsed      MakeUpCreditStatus = "used"
+	MakeUpCreditExpired   MakeUpCreditStatus = "expired"
+)
+
+// MakeUpCredit entitles a student to a replacement lesson for a cancelled one
+type MakeUpCredit struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	StudentID uuid.UUID `gorm:"type:uuid;not null;index" json:"student_id"`
+	GroupID   uuid.UUID `gorm:"type:uuid;not null;index" json:"group_id"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index" json:"session_id"` // The cancelled lesson
+
+	Reason    string             `gorm:"type:text" json:"reason,omitempty"`
+	Status    MakeUpCreditStatus `gorm:"type:varchar(20);not null;default:'available'" json:"status"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+
+	// Redemption
+	UsedSessionID *uuid.UUID `gorm:"type:uuid" json:"used_session_id,omitempty"`
+	UsedAt        *time.Time `json:"used_at,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Student *Student      `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Session *ClassSession `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+}
+
+// TableName specifies the table name for MakeUpCredit model
+func (MakeUpCredit) TableName() string {
+	return "make_up_credits"
+}
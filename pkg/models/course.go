@@ -7,19 +7,49 @@ import (
 	"gorm.io/gorm"
 )
 
+// CourseLevel is the level of a course within a leveled program
+type CourseLevel string
+
+const (
+	LevelBeginner     CourseLevel = "beginner"
+	LevelIntermediate CourseLevel = "intermediate"
+	LevelAdvanced     CourseLevel = "advanced"
+)
+
 type Course struct {
-	ID         uuid.UUID      `json:"id" gorm:"primarykey"`
-	Title      string         `json:"title" binding:"required"`
-	MonthlyFee float64        `json:"monthly_fee" binding:"omitempty,number"`
-	Duration   int            `json:"duration" binding:"omitempty,number"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
-
-	Groups []Group `json:"groups"`
+	ID             uuid.UUID      `json:"id" gorm:"primarykey"`
+	Title          string         `json:"title" binding:"required"`
+	MonthlyFee     float64        `json:"monthly_fee" binding:"omitempty,number"`
+	Duration       int            `json:"duration" binding:"omitempty,number"`
+	Level          CourseLevel    `json:"level,omitempty" gorm:"type:varchar(20);index"`
+	Category       string         `json:"category,omitempty" gorm:"type:varchar(100);index"`
+	Description    string         `json:"description,omitempty" gorm:"type:text"`
+	GradingScaleID *uuid.UUID     `json:"grading_scale_id,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Groups        []Group              `json:"groups"`
+	GradingScale  *GradingScale        `json:"grading_scale,omitempty" gorm:"foreignKey:GradingScaleID"`
+	Prerequisites []CoursePrerequisite `json:"prerequisites,omitempty" gorm:"foreignKey:CourseID"`
 }
 
 func (c *Course) BeforeCreate(tx *gorm.DB) (err error) {
 	c.ID, err = uuid.NewUUID()
 	return err
 }
+
+// CoursePrerequisite is an edge of the course graph: a course that must be
+// completed before another. The graph has no cycles.
+type CoursePrerequisite struct {
+	CourseID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"course_id"`
+	PrerequisiteID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"prerequisite_id"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	Prerequisite *Course `gorm:"foreignKey:PrerequisiteID" json:"prerequisite,omitempty"`
+}
+
+// TableName specifies the table name for CoursePrerequisite model
+func (CoursePrerequisite) TableName() string {
+	return "course_prerequisites"
+}
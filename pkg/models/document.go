@@ -53,17 +53,20 @@ type Document struct {
 	CourseID  *uuid.UUID `gorm:"type:uuid;index" json:"course_id,omitempty"`
 	GroupID   *uuid.UUID `gorm:"type:uuid;index" json:"group_id,omitempty"`
 
+	// Supporting file of an application
+	ApplicationID *uuid.UUID `gorm:"type:uuid;index" json:"application_id,omitempty"`
+
 	// Upload tracking
-	UploadedBy uuid.UUID `gorm:"type:uuid;not null" json:"uploaded_by"`
-	UploadedAt time.Time `gorm:"not null" json:"uploaded_at"`
+	UploadedBy *uuid.UUID `gorm:"type:uuid" json:"uploaded_by,omitempty"` // Empty for applicant uploads and files generated without a user
+	UploadedAt time.Time  `gorm:"not null" json:"uploaded_at"`
 
 	// Approval tracking
 	ApprovedBy *uuid.UUID `gorm:"type:uuid" json:"approved_by,omitempty"`
 	ApprovedAt *time.Time `json:"approved_at,omitempty"`
 
 	// Metadata
-	Tags     []string               `gorm:"type:jsonb" json:"tags,omitempty"`
-	Metadata map[string]interface{} `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Tags     []string               `gorm:"type:jsonb;serializer:json" json:"tags,omitempty"`
+	Metadata map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
@@ -75,7 +78,7 @@ type Document struct {
 	Teacher  *Teacher `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
 	Course   *Course  `gorm:"foreignKey:CourseID" json:"course,omitempty"`
 	Group    *Group   `gorm:"foreignKey:GroupID" json:"group,omitempty"`
-	Uploader User     `gorm:"foreignKey:UploadedBy" json:"uploader,omitempty"`
+	Uploader *User    `gorm:"foreignKey:UploadedBy" json:"uploader,omitempty"`
 	Approver *User    `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
 }
 
@@ -32,7 +32,8 @@ type Event struct {
 	AllDay    bool      `gorm:"default:false" json:"all_day"`
 
 	// Location
-	Location string `gorm:"type:varchar(255)" json:"location,omitempty"`
+	Location string     `gorm:"type:varchar(255)" json:"location,omitempty"`
+	RoomID   *uuid.UUID `gorm:"type:uuid;index" json:"room_id,omitempty"`
 
 	// Associated entities
 	GroupID   *uuid.UUID `gorm:"type:uuid;index" json:"group_id,omitempty"`
@@ -41,12 +42,14 @@ type Event struct {
 
 	// Recurring events
 	IsRecurring    bool       `gorm:"default:false" json:"is_recurring"`
-	RecurrenceRule string     `gorm:"type:varchar(255)" json:"recurrence_rule,omitempty"` // e.g., "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR"
-	ParentEventID  *uuid.UUID `gorm:"type:uuid" json:"parent_event_id,omitempty"`
+	RecurrenceRule string     `gorm:"type:text" json:"recurrence_rule,omitempty"` // e.g., "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR", optionally followed by EXDATE lines
+	ParentEventID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_event_id,omitempty"`
+	// Start of the parent's occurrence an exception row replaces
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty"`
 
 	// Metadata
 	Color    string                 `gorm:"type:varchar(20)" json:"color,omitempty"`
-	Metadata map[string]interface{} `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Metadata map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
 
 	// Creator
 	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
@@ -68,3 +71,8 @@ type Event struct {
 func (Event) TableName() string {
 	return "events"
 }
+
+// IsSeries reports whether the event repeats by its recurrence rule
+func (e *Event) IsSeries() bool {
+	return e.IsRecurring && e.RecurrenceRule != ""
+}
@@ -24,9 +24,27 @@ const (
 	ExamStatusScheduled  ExamStatus = "scheduled"
 	ExamStatusInProgress ExamStatus = "in_progress"
 	ExamStatusCompleted  ExamStatus = "completed"
+	ExamStatusPublished  ExamStatus = "published" // Results released to students and locked
 	ExamStatusCancelled  ExamStatus = "cancelled"
 )
 
+// examTransitions lists the statuses an exam may move to from each status
+var examTransitions = map[ExamStatus][]ExamStatus{
+	ExamStatusScheduled:  {ExamStatusInProgress, ExamStatusCancelled},
+	ExamStatusInProgress: {ExamStatusCompleted, ExamStatusCancelled},
+	ExamStatusCompleted:  {ExamStatusPublished},
+}
+
+// CanTransitionTo reports whether an exam may move from s to next
+func (s ExamStatus) CanTransitionTo(next ExamStatus) bool {
+	for _, allowed := range examTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // Exam represents an exam
 type Exam struct {
 	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
@@ -44,9 +62,11 @@ type Exam struct {
 	Duration  int       `gorm:"not null" json:"duration"` // in minutes
 
 	// Exam details
-	TotalMarks   int    `gorm:"not null" json:"total_marks"`
-	PassingMarks int    `gorm:"not null" json:"passing_marks"`
-	Location     string `gorm:"type:varchar(255)" json:"location,omitempty"`
+	TotalMarks   int        `gorm:"not null" json:"total_marks"`
+	PassingMarks int        `gorm:"not null" json:"passing_marks"`
+	Location     string     `gorm:"type:varchar(255)" json:"location,omitempty"`
+	RoomID       *uuid.UUID `gorm:"type:uuid;index" json:"room_id,omitempty"`
+	TermID       *uuid.UUID `gorm:"type:uuid;index" json:"term_id,omitempty"` // Term of the start time unless set
 
 	// Instructions
 	Instructions string `gorm:"type:text" json:"instructions,omitempty"`
@@ -57,6 +77,10 @@ type Exam struct {
 	// Creator
 	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
 
+	// Publication
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	PublishedBy *uuid.UUID `gorm:"type:uuid" json:"published_by,omitempty"`
+
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -73,14 +97,18 @@ type Exam struct {
 type ExamResult struct {
 	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
 
-	ExamID    uuid.UUID `gorm:"type:uuid;not null;index" json:"exam_id"`
-	StudentID uuid.UUID `gorm:"type:uuid;not null;index" json:"student_id"`
+	ExamID    uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_exam_result_student,where:deleted_at IS NULL" json:"exam_id"`
+	StudentID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_exam_result_student,where:deleted_at IS NULL" json:"student_id"`
 
 	MarksObtained float64 `gorm:"not null" json:"marks_obtained"`
 	Percentage    float64 `gorm:"not null" json:"percentage"`
-	Grade         string  `gorm:"type:varchar(5)" json:"grade,omitempty"`
+	Grade         string  `gorm:"type:varchar(20)" json:"grade,omitempty"`
+	GradePoints   float64 `gorm:"default:0" json:"grade_points"`
 	Passed        bool    `gorm:"not null" json:"passed"`
 
+	// Grading scale version the grade was computed with
+	GradingScaleVersionID *uuid.UUID `gorm:"type:uuid" json:"grading_scale_version_id,omitempty"`
+
 	// Additional info
 	Remarks string `gorm:"type:text" json:"remarks,omitempty"`
 	Absent  bool   `gorm:"default:false" json:"absent"`
@@ -105,33 +133,40 @@ func (Exam) TableName() string {
 	return "exams"
 }
 
+// BeforeCreate scopes the exam to the term of its start time
+func (e *Exam) BeforeCreate(tx *gorm.DB) error {
+	if e.TermID == nil {
+		e.TermID = TermAt(tx, e.StartTime)
+	}
+	return nil
+}
+
+// StatusAt returns the status an exam should have at the given time. Scheduled
+// and running exams move forward once their start and end times pass; other
+// statuses are only changed explicitly.
+func (e *Exam) StatusAt(now time.Time) ExamStatus {
+	switch e.Status {
+	case ExamStatusScheduled, ExamStatusInProgress:
+		if !now.Before(e.EndTime) {
+			return ExamStatusCompleted
+		}
+		if !now.Before(e.StartTime) {
+			return ExamStatusInProgress
+		}
+	}
+	return e.Status
+}
+
 // TableName specifies the table name for ExamResult model
 func (ExamResult) TableName() string {
 	return "exam_results"
 }
 
-// CalculateGrade calculates the grade based on percentage
-func (er *ExamResult) CalculateGrade() {
-	switch {
-	case er.Percentage >= 90:
-		er.Grade = "A+"
-	case er.Percentage >= 85:
-		er.Grade = "A"
-	case er.Percentage >= 80:
-		er.Grade = "A-"
-	case er.Percentage >= 75:
-		er.Grade = "B+"
-	case er.Percentage >= 70:
-		er.Grade = "B"
-	case er.Percentage >= 65:
-		er.Grade = "B-"
-	case er.Percentage >= 60:
-		er.Grade = "C+"
-	case er.Percentage >= 55:
-		er.Grade = "C"
-	case er.Percentage >= 50:
-		er.Grade = "C-"
-	default:
-		er.Grade = "F"
-	}
+// ApplyGradingScale sets the grade label and GPA points from a grading scale
+// version and records which version was used
+func (er *ExamResult) ApplyGradingScale(v *GradingScaleVersion) {
+	band := v.Band(er.Percentage)
+	er.Grade = band.Label
+	er.GradePoints = band.GPAPoints
+	er.GradingScaleVersionID = v.VersionID()
 }
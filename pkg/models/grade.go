@@ -16,13 +16,42 @@ type Grade struct {
 	Value     int            `gorm:"not null" json:"value"`                 // 0-100 or similar scale
 	Type      string         `gorm:"type:varchar(50);not null" json:"type"` // e.g., "homework", "exam", "quiz"
 	Date      time.Time      `gorm:"type:date;not null" json:"date"`
+	TermID    *uuid.UUID     `gorm:"type:uuid;index" json:"term_id,omitempty"` // Term of the date unless set
 	Notes     string         `gorm:"type:text" json:"notes"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
+	// Derived from the course's grading scale at the time of grading
+	Label                 string     `gorm:"type:varchar(20)" json:"label,omitempty"`
+	GPAPoints             float64    `gorm:"default:0" json:"gpa_points"`
+	Passed                bool       `gorm:"default:false" json:"passed"`
+	GradingScaleVersionID *uuid.UUID `gorm:"type:uuid" json:"grading_scale_version_id,omitempty"`
+
 	// Associations
 	Student Student `gorm:"foreignKey:StudentID" json:"student,omitempty"`
 	Group   Group   `gorm:"foreignKey:GroupID" json:"group,omitempty"`
 	Course  Course  `gorm:"foreignKey:CourseID" json:"course,omitempty"`
 }
+
+// BeforeCreate scopes the grade to the term of its date
+func (g *Grade) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	if g.TermID == nil {
+		g.TermID = TermAt(tx, g.Date)
+	}
+	return nil
+}
+
+// ApplyGradingScale derives the label, GPA points and pass flag from a grading
+// scale version, treating Value as a percentage
+func (g *Grade) ApplyGradingScale(v *GradingScaleVersion) {
+	percentage := float64(g.Value)
+	band := v.Band(percentage)
+	g.Label = band.Label
+	g.GPAPoints = band.GPAPoints
+	g.Passed = v.Passed(percentage)
+	g.GradingScaleVersionID = v.VersionID()
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GradingScaleType represents the kind of grading system a scale implements
+type GradingScaleType string
+
+const (
+	GradingScaleLetter    GradingScaleType = "letter"
+	GradingScaleFivePoint GradingScaleType = "five_point"
+	GradingScaleTenPoint  GradingScaleType = "ten_point"
+	GradingScalePassFail  GradingScaleType = "pass_fail"
+)
+
+// GradingBand maps a percentage range to a label and GPA points.
+// A band covers every percentage from MinPercentage up to the next band's minimum.
+type GradingBand struct {
+	Label         string  `json:"label"`
+	MinPercentage float64 `json:"min_percentage"`
+	GPAPoints     float64 `json:"gpa_points"`
+}
+
+// GradingScale represents a named grading system that can be assigned to courses
+type GradingScale struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Name        string           `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string           `gorm:"type:text" json:"description,omitempty"`
+	Type        GradingScaleType `gorm:"type:varchar(20);not null" json:"type"`
+	IsDefault   bool             `gorm:"default:false" json:"is_default"`
+	IsActive    bool             `gorm:"default:true" json:"is_active"`
+
+	// CurrentVersion points at the version used for new results
+	CurrentVersion   int        `gorm:"not null;default:1" json:"current_version"`
+	CurrentVersionID *uuid.UUID `gorm:"type:uuid" json:"current_version_id,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Versions []GradingScaleVersion `gorm:"foreignKey:GradingScaleID" json:"versions,omitempty"`
+}
+
+// GradingScaleVersion is an immutable snapshot of a scale's bands and pass threshold.
+// Results reference the version they were graded with so later edits never re-grade them.
+type GradingScaleVersion struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	GradingScaleID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_grading_scale_version" json:"grading_scale_id"`
+	Version        int       `gorm:"not null;uniqueIndex:idx_grading_scale_version" json:"version"`
+
+	Bands         []GradingBand `gorm:"serializer:json" json:"bands"`
+	PassThreshold float64       `gorm:"not null" json:"pass_threshold"` // Minimum percentage to pass
+
+	ChangeNotes string     `gorm:"type:text" json:"change_notes,omitempty"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relations
+	GradingScale *GradingScale `gorm:"foreignKey:GradingScaleID" json:"grading_scale,omitempty"`
+}
+
+// TableName specifies the table name for GradingScale model
+func (GradingScale) TableName() string {
+	return "grading_scales"
+}
+
+// TableName specifies the table name for GradingScaleVersion model
+func (GradingScaleVersion) TableName() string {
+	return "grading_scale_versions"
+}
+
+// DefaultGradingBands returns the built-in A+ to F letter scale used when a
+// course has no grading scale assigned.
+func DefaultGradingBands() []GradingBand {
+	return []GradingBand{
+		{Label: "A+", MinPercentage: 90, GPAPoints: 4.0},
+		{Label: "A", MinPercentage: 85, GPAPoints: 4.0},
+		{Label: "A-", MinPercentage: 80, GPAPoints: 3.7},
+		{Label: "B+", MinPercentage: 75, GPAPoints: 3.3},
+		{Label: "B", MinPercentage: 70, GPAPoints: 3.0},
+		{Label: "B-", MinPercentage: 65, GPAPoints: 2.7},
+		{Label: "C+", MinPercentage: 60, GPAPoints: 2.3},
+		{Label: "C", MinPercentage: 55, GPAPoints: 2.0},
+		{Label: "C-", MinPercentage: 50, GPAPoints: 1.7},
+		{Label: "F", MinPercentage: 0, GPAPoints: 0},
+	}
+}
+
+// DefaultGradingScaleVersion returns an unsaved version wrapping the built-in bands
+func DefaultGradingScaleVersion() *GradingScaleVersion {
+	return &GradingScaleVersion{
+		Version:       0,
+		Bands:         DefaultGradingBands(),
+		PassThreshold: 50,
+	}
+}
+
+// Band returns the band a percentage falls into. Bands are matched from the
+// highest minimum down, so their stored order does not matter.
+func (v *GradingScaleVersion) Band(percentage float64) GradingBand {
+	bands := make([]GradingBand, len(v.Bands))
+	copy(bands, v.Bands)
+	sort.Slice(bands, func(i, j int) bool {
+		return bands[i].MinPercentage > bands[j].MinPercentage
+	})
+
+	for _, band := range bands {
+		if percentage >= band.MinPercentage {
+			return band
+		}
+	}
+	if len(bands) > 0 {
+		return bands[len(bands)-1]
+	}
+	return GradingBand{}
+}
+
+// Passed reports whether a percentage meets the version's pass threshold
+func (v *GradingScaleVersion) Passed(percentage float64) bool {
+	return percentage >= v.PassThreshold
+}
+
+// VersionID returns the version's ID, or nil for the unsaved built-in default
+func (v *GradingScaleVersion) VersionID() *uuid.UUID {
+	if v.ID == uuid.Nil {
+		return nil
+	}
+	id := v.ID
+	return &id
+}
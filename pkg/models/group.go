@@ -15,6 +15,7 @@ type Group struct {
 	Name        string         `json:"name" binding:"required"`
 	StartDate   time.Time      `json:"start_date" binding:"required"`
 	Capacity    int            `json:"capacity" binding:"required,min=1"`
+	TermID      *uuid.UUID     `json:"term_id,omitempty" gorm:"type:uuid;index"` // Term of the start date unless set
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -26,6 +27,9 @@ type Group struct {
 }
 
 func (g *Group) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.TermID == nil {
+		g.TermID = TermAt(tx, g.StartDate)
+	}
 	g.ID, err = uuid.NewUUID()
 	return err
 }
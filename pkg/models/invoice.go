@@ -29,6 +29,7 @@ type Invoice struct {
 	CourseID           *uuid.UUID `gorm:"type:uuid;index" json:"course_id,omitempty"`
 	GroupID            *uuid.UUID `gorm:"type:uuid;index" json:"group_id,omitempty"`
 	RecurringInvoiceID *uuid.UUID `gorm:"type:uuid;index" json:"recurring_invoice_id,omitempty"`
+	TermID             *uuid.UUID `gorm:"type:uuid;index" json:"term_id,omitempty"` // Term of the issue date unless set
 
 	// Amounts
 	SubTotal       float64 `gorm:"not null" json:"sub_total"`
@@ -70,6 +71,14 @@ func (Invoice) TableName() string {
 	return "invoices"
 }
 
+// BeforeCreate scopes the invoice to the term of its issue date
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.TermID == nil {
+		i.TermID = TermAt(tx, i.IssueDate)
+	}
+	return nil
+}
+
 // UpdateBalance recalculates the balance based on total and paid amounts
 func (i *Invoice) UpdateBalance() {
 	i.BalanceAmount = i.TotalAmount - i.PaidAmount
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LeadStage represents where a lead is in the sales pipeline
+type LeadStage string
+
+const (
+	LeadNew         LeadStage = "new"
+	LeadContacted   LeadStage = "contacted"
+	LeadTrial       LeadStage = "trial"
+	LeadNegotiation LeadStage = "negotiation"
+	LeadWon         LeadStage = "won"
+	LeadLost        LeadStage = "lost"
+)
+
+// IsClosed reports whether the stage ends the pipeline
+func (s LeadStage) IsClosed() bool {
+	return s == LeadWon || s == LeadLost
+}
+
+// LeadSource represents where an inquiry came from
+type LeadSource string
+
+const (
+	LeadSourceWebsite  LeadSource = "website"
+	LeadSourcePhone    LeadSource = "phone"
+	LeadSourceWalkIn   LeadSource = "walk_in"
+	LeadSourceReferral LeadSource = "referral"
+	LeadSourceSocial   LeadSource = "social"
+	LeadSourceEvent    LeadSource = "event"
+	LeadSourceOther    LeadSource = "other"
+)
+
+// Lead represents a prospective student inquiry moving through the sales pipeline
+type Lead struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	// Contact
+	FirstName string `gorm:"type:varchar(100);not null" json:"first_name"`
+	LastName  string `gorm:"type:varchar(100)" json:"last_name,omitempty"`
+	Email     string `gorm:"type:varchar(255);index" json:"email,omitempty"`
+	Phone     string `gorm:"type:varchar(20);index" json:"phone,omitempty"`
+
+	// Attribution
+	Source   LeadSource `gorm:"type:varchar(20);not null;index" json:"source"`
+	Campaign string     `gorm:"type:varchar(200)" json:"campaign,omitempty"`
+	CourseID *uuid.UUID `gorm:"type:uuid;index" json:"course_id,omitempty"` // Course the lead is interested in
+	OwnerID  *uuid.UUID `gorm:"type:uuid;index" json:"owner_id,omitempty"`  // Staff user responsible for the lead
+
+	// Pipeline
+	Stage          LeadStage  `gorm:"type:varchar(20);not null;default:'new';index" json:"stage"`
+	LostReason     string     `gorm:"type:text" json:"lost_reason,omitempty"`
+	StageChangedAt time.Time  `gorm:"not null" json:"stage_changed_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"` // Set when won or lost
+
+	// Follow-up
+	NextFollowUpAt  *time.Time `gorm:"index" json:"next_follow_up_at,omitempty"`
+	LastContactedAt *time.Time `json:"last_contacted_at,omitempty"`
+
+	// Conversion
+	ApplicationID *uuid.UUID `gorm:"type:uuid" json:"application_id,omitempty"`
+	StudentID     *uuid.UUID `gorm:"type:uuid" json:"student_id,omitempty"`
+	ConvertedAt   *time.Time `json:"converted_at,omitempty"`
+
+	Notes string `gorm:"type:text" json:"notes,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Course *Course `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	Owner  *User   `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+}
+
+// TableName specifies the table name for Lead model
+func (Lead) TableName() string {
+	return "leads"
+}
+
+// LeadActivityType represents a kind of interaction with a lead
+type LeadActivityType string
+
+const (
+	LeadActivityCall        LeadActivityType = "call"
+	LeadActivityMessage     LeadActivityType = "message"
+	LeadActivityMeeting     LeadActivityType = "meeting"
+	LeadActivityNote        LeadActivityType = "note"
+	LeadActivityStageChange LeadActivityType = "stage_change" // Recorded by the system
+)
+
+// IsContact reports whether the activity reached the lead
+func (t LeadActivityType) IsContact() bool {
+	return t == LeadActivityCall || t == LeadActivityMessage || t == LeadActivityMeeting
+}
+
+// LeadActivity is an entry in a lead's history
+type LeadActivity struct {
+	ID     uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	LeadID uuid.UUID  `gorm:"type:uuid;not null;index" json:"lead_id"`
+	UserID *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+
+	Type       LeadActivityType `gorm:"type:varchar(20);not null" json:"type"`
+	Summary    string           `gorm:"type:varchar(500);not null" json:"summary"`
+	Details    string           `gorm:"type:text" json:"details,omitempty"`
+	FromStage  LeadStage        `gorm:"type:varchar(20)" json:"from_stage,omitempty"`
+	ToStage    LeadStage        `gorm:"type:varchar(20)" json:"to_stage,omitempty"`
+	OccurredAt time.Time        `gorm:"not null;index" json:"occurred_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for LeadActivity model
+func (LeadActivity) TableName() string {
+	return "lead_activities"
+}
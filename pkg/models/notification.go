@@ -50,7 +50,7 @@ type Notification struct {
 	RetryCount int        `gorm:"default:0" json:"retry_count"`
 
 	// Metadata
-	Metadata map[string]interface{} `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Metadata map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
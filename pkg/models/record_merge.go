@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MergeEntity is the kind of record two duplicates were merged into one of
+type MergeEntity string
+
+const (
+	MergeStudent MergeEntity = "student"
+	MergeParent  MergeEntity = "parent"
+)
+
+// RecordMerge records a merge of a duplicate record into the surviving one,
+// with everything needed to undo it
+type RecordMerge struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	EntityType MergeEntity `gorm:"type:varchar(20);not null;index" json:"entity_type"`
+	SurvivorID uuid.UUID   `gorm:"type:uuid;not null;index" json:"survivor_id"`
+	MergedID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"merged_id"`
+	Score      float64     `json:"score,omitempty"` // Duplicate score when the merge was proposed
+
+	// Rows re-pointed from the merged record to the survivor, keyed by
+	// "table.column"
+	Moved map[string][]uuid.UUID `gorm:"serializer:json" json:"moved"`
+	// Survivor columns filled in from the merged record, with their previous values
+	Filled map[string]interface{} `gorm:"serializer:json" json:"filled,omitempty"`
+
+	MergedBy *uuid.UUID `gorm:"type:uuid" json:"merged_by,omitempty"`
+	MergedAt time.Time  `gorm:"not null" json:"merged_at"`
+	UndoneBy *uuid.UUID `gorm:"type:uuid" json:"undone_by,omitempty"`
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for RecordMerge model
+func (RecordMerge) TableName() string {
+	return "record_merges"
+}
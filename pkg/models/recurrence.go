@@ -0,0 +1,468 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies supported from RFC 5545
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxRecurrencePeriods bounds how many days, weeks, months or years a rule
+// is expanded over, so a rule that never matches cannot loop forever
+const maxRecurrencePeriods = 100000
+
+// Date and date-time layouts used by iCalendar
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405"
+)
+
+// icsWeekdays maps iCalendar day codes to weekdays
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Recurrence is a parsed RFC 5545 recurrence: an RRULE with the FREQ,
+// INTERVAL, BYDAY, COUNT, UNTIL and WKST parts, plus EXDATE exclusions
+type Recurrence struct {
+	Freq      string
+	Interval  int
+	ByDay     []RecurrenceDay
+	Count     int        // Occurrences including the first; 0 when unbounded
+	Until     *time.Time // Last possible start, inclusive
+	WeekStart time.Weekday
+
+	ExDates []time.Time // Excluded occurrence starts
+	ExDays  []time.Time // Excluded dates (EXDATE;VALUE=DATE), at midnight UTC
+}
+
+// RecurrenceDay is a BYDAY entry such as "MO" or "-1FR"
+type RecurrenceDay struct {
+	Weekday time.Weekday
+	Ordinal int // Nth weekday of the month or year, negative from the end; 0 for all
+}
+
+// ParseRecurrence parses an RRULE, given with or without the "RRULE:" prefix
+// and optionally followed by EXDATE lines
+func ParseRecurrence(value string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1, WeekStart: time.Monday}
+	hasRule := false
+	for _, line := range strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, params, content := "RRULE", "", line
+		// A bare rule has no colon; property lines are NAME[;PARAMS]:VALUE
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, content = line[:i], line[i+1:]
+			if j := strings.Index(name, ";"); j >= 0 {
+				name, params = name[:j], name[j+1:]
+			}
+			name = strings.ToUpper(name)
+		}
+
+		switch name {
+		case "RRULE":
+			if hasRule {
+				return nil, fmt.Errorf("only one RRULE is supported")
+			}
+			hasRule = true
+			if err := r.parseRule(content); err != nil {
+				return nil, err
+			}
+		case "EXDATE":
+			for _, item := range strings.Split(content, ",") {
+				t, allDay, err := ParseICSTime(strings.TrimSpace(item), params)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE: %w", err)
+				}
+				if allDay {
+					r.ExDays = append(r.ExDays, t)
+				} else {
+					r.ExDates = append(r.ExDates, t)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence property %s", name)
+		}
+	}
+	if !hasRule {
+		return nil, fmt.Errorf("recurrence has no RRULE")
+	}
+	return r, nil
+}
+
+func (r *Recurrence) parseRule(rule string) error {
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid RRULE part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch key {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = value
+			default:
+				return fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("INTERVAL must be a positive number")
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("COUNT must be a positive number")
+			}
+			r.Count = n
+		case "UNTIL":
+			t, allDay, err := ParseICSTime(value, "")
+			if err != nil {
+				return fmt.Errorf("invalid UNTIL: %w", err)
+			}
+			if allDay {
+				// A date covers occurrences starting any time that day
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			r.Until = &t
+		case "BYDAY":
+			for _, item := range strings.Split(value, ",") {
+				day, err := parseRecurrenceDay(item)
+				if err != nil {
+					return err
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "WKST":
+			day, ok := icsWeekdays[value]
+			if !ok {
+				return fmt.Errorf("invalid WKST %s", value)
+			}
+			r.WeekStart = day
+		default:
+			return fmt.Errorf("unsupported RRULE part %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return fmt.Errorf("RRULE requires FREQ")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return fmt.Errorf("RRULE cannot have both COUNT and UNTIL")
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return fmt.Errorf("numbered BYDAY is only allowed with MONTHLY or YEARLY")
+		}
+	}
+	return nil
+}
+
+func parseRecurrenceDay(value string) (RecurrenceDay, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	day, ok := icsWeekdays[value[len(value)-2:]]
+	if !ok {
+		return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	ordinal := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+		ordinal = n
+	}
+	return RecurrenceDay{Weekday: day, Ordinal: ordinal}, nil
+}
+
+// ParseICSTime parses an iCalendar DATE or DATE-TIME value with its property
+// parameters, e.g. "TZID=Asia/Dushanbe" or "VALUE=DATE". Dates are returned
+// at midnight UTC with allDay set; floating times are read as local time.
+func ParseICSTime(value, params string) (t time.Time, allDay bool, err error) {
+	loc := time.Local
+	for _, param := range strings.Split(params, ";") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 && strings.EqualFold(kv[0], "TZID") {
+			if loc, err = time.LoadLocation(strings.Trim(kv[1], `"`)); err != nil {
+				return time.Time{}, false, fmt.Errorf("unknown time zone %s", kv[1])
+			}
+		}
+	}
+
+	switch {
+	case len(value) == len(icsDateLayout):
+		t, err = time.Parse(icsDateLayout, value)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icsDateTimeLayout, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	default:
+		t, err = time.ParseInLocation(icsDateTimeLayout, value, loc)
+		return t, false, err
+	}
+}
+
+// String formats the recurrence as an RRULE line followed by EXDATE lines
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(icsDateTimeLayout)+"Z")
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+icsDayCode(r.WeekStart))
+	}
+
+	lines := []string{"RRULE:" + strings.Join(parts, ";")}
+	if len(r.ExDates) > 0 {
+		values := make([]string, len(r.ExDates))
+		for i, t := range r.ExDates {
+			values[i] = t.UTC().Format(icsDateTimeLayout) + "Z"
+		}
+		lines = append(lines, "EXDATE:"+strings.Join(values, ","))
+	}
+	if len(r.ExDays) > 0 {
+		values := make([]string, len(r.ExDays))
+		for i, t := range r.ExDays {
+			values[i] = t.Format(icsDateLayout)
+		}
+		lines = append(lines, "EXDATE;VALUE=DATE:"+strings.Join(values, ","))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String formats the day as in BYDAY, e.g. "MO" or "-1FR"
+func (d RecurrenceDay) String() string {
+	if d.Ordinal == 0 {
+		return icsDayCode(d.Weekday)
+	}
+	return strconv.Itoa(d.Ordinal) + icsDayCode(d.Weekday)
+}
+
+func icsDayCode(day time.Weekday) string {
+	return strings.ToUpper(day.String()[:2])
+}
+
+// Between returns the occurrence starts of a series first starting at start
+// that fall between from and to inclusive, leaving out excluded dates
+func (r *Recurrence) Between(start, from, to time.Time) []time.Time {
+	var starts []time.Time
+	r.each(start, func(t time.Time, excluded bool) bool {
+		if t.After(to) {
+			return false
+		}
+		if !excluded && !t.Before(from) {
+			starts = append(starts, t)
+		}
+		return true
+	})
+	return starts
+}
+
+// Occurs reports whether t is the start of an occurrence that is not excluded
+func (r *Recurrence) Occurs(start, t time.Time) bool {
+	found := false
+	r.each(start, func(o time.Time, excluded bool) bool {
+		if o.Equal(t) {
+			found = !excluded
+		}
+		return o.Before(t)
+	})
+	return found
+}
+
+// CountBefore counts the occurrences, excluded ones included, that start
+// before t; COUNT is measured the same way
+func (r *Recurrence) CountBefore(start, t time.Time) int {
+	n := 0
+	r.each(start, func(o time.Time, _ bool) bool {
+		if !o.Before(t) {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// Exclude adds an occurrence start to the EXDATEs
+func (r *Recurrence) Exclude(t time.Time) {
+	r.ExDates = append(r.ExDates, t.UTC())
+}
+
+// excluded reports whether an occurrence start is an EXDATE
+func (r *Recurrence) excluded(t time.Time) bool {
+	for _, ex := range r.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	if len(r.ExDays) > 0 {
+		y, m, d := t.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		for _, ex := range r.ExDays {
+			if ex.Equal(day) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// each calls yield with every occurrence start in order, the first being
+// start itself, until yield returns false or the rule ends
+func (r *Recurrence) each(start time.Time, yield func(t time.Time, excluded bool) bool) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		n++
+		if r.Count > 0 && n > r.Count {
+			return false
+		}
+		return yield(t, r.excluded(t))
+	}
+
+	if !emit(start) {
+		return
+	}
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, t := range r.periodStarts(start, period) {
+			if t.After(start) && !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// periodStarts lists the candidate starts in the nth day, week, month or year
+// of the series, in order
+func (r *Recurrence) periodStarts(start time.Time, n int) []time.Time {
+	y, m, d := start.Date()
+	hour, minute, sec := start.Clock()
+	loc := start.Location()
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, sec, start.Nanosecond(), loc)
+	}
+	step := n * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		day := time.Date(y, m, d+step, 0, 0, 0, 0, loc)
+		if r.matchesWeekday(day.Weekday()) {
+			days = append(days, day)
+		}
+	case FreqWeekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := time.Date(y, m, d-offset+7*step, 0, 0, 0, 0, loc)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			wanted := r.matchesWeekday(day.Weekday())
+			if len(r.ByDay) == 0 {
+				wanted = day.Weekday() == start.Weekday()
+			}
+			if wanted {
+				days = append(days, day)
+			}
+		}
+	case FreqMonthly:
+		first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, loc)
+		if len(r.ByDay) > 0 {
+			days = r.daysIn(first, first.AddDate(0, 1, 0))
+		} else if day := time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, loc); day.Month() == first.Month() {
+			days = append(days, day)
+		}
+	case FreqYearly:
+		first := time.Date(y+step, time.January, 1, 0, 0, 0, 0, loc)
+		if len(r.ByDay) > 0 {
+			days = r.daysIn(first, first.AddDate(1, 0, 0))
+		} else if day := time.Date(y+step, m, d, 0, 0, 0, 0, loc); day.Month() == m {
+			days = append(days, day)
+		}
+	}
+
+	starts := make([]time.Time, len(days))
+	for i, day := range days {
+		starts[i] = at(day)
+	}
+	return starts
+}
+
+// matchesWeekday reports whether BYDAY allows the weekday; no BYDAY allows all
+func (r *Recurrence) matchesWeekday(day time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, by := range r.ByDay {
+		if by.Weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn returns the days in [from, to) that BYDAY selects, honouring
+// ordinals such as the first Monday or the last Friday
+func (r *Recurrence) daysIn(from, to time.Time) []time.Time {
+	byWeekday := make(map[time.Weekday][]time.Time)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for _, by := range r.ByDay {
+		matches := byWeekday[by.Weekday]
+		selected := matches
+		if by.Ordinal > 0 && by.Ordinal <= len(matches) {
+			selected = matches[by.Ordinal-1 : by.Ordinal]
+		} else if by.Ordinal < 0 && -by.Ordinal <= len(matches) {
+			selected = matches[len(matches)+by.Ordinal : len(matches)+by.Ordinal+1]
+		} else if by.Ordinal != 0 {
+			selected = nil
+		}
+		for _, day := range selected {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
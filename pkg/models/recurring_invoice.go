@@ -65,6 +65,9 @@ type RecurringInvoice struct {
 	AutoSend     bool `gorm:"default:true" json:"auto_send"`  // Automatically send notification
 	DueDays      int  `gorm:"default:30" json:"due_days"`     // Days until due after generation
 	ReminderDays int  `gorm:"default:7" json:"reminder_days"` // Days before due to send reminder
+	// Reduce monthly invoices of a group by the share of its lessons that
+	// fall on holidays and term breaks
+	ProRateHolidays bool `gorm:"default:false" json:"pro_rate_holidays"`
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
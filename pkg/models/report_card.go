@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportCard is a student's end-of-term result in a group. It is the record
+// transcripts are built from, and regenerating it for the same term replaces it.
+type ReportCard struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	StudentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_report_card_term" json:"student_id"`
+	GroupID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_report_card_term" json:"group_id"`
+	CourseID  uuid.UUID `gorm:"type:uuid;not null;index" json:"course_id"`
+	Term      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_report_card_term" json:"term"`
+
+	// Components (percentages)
+	AttendanceRate    float64 `gorm:"default:0" json:"attendance_rate"`
+	AssignmentAverage float64 `gorm:"default:0" json:"assignment_average"`
+	ExamAverage       float64 `gorm:"default:0" json:"exam_average"`
+
+	// Final result
+	FinalPercentage       float64    `gorm:"default:0" json:"final_percentage"`
+	FinalGrade            string     `gorm:"type:varchar(20)" json:"final_grade"`
+	GPAPoints             float64    `gorm:"default:0" json:"gpa_points"`
+	Passed                bool       `gorm:"default:false" json:"passed"`
+	GradingScaleVersionID *uuid.UUID `gorm:"type:uuid" json:"grading_scale_version_id,omitempty"`
+
+	TeacherComment string `gorm:"type:text" json:"teacher_comment,omitempty"`
+
+	// Generated PDF
+	DocumentID  *uuid.UUID `gorm:"type:uuid" json:"document_id,omitempty"`
+	GeneratedBy *uuid.UUID `gorm:"type:uuid" json:"generated_by,omitempty"`
+	GeneratedAt time.Time  `gorm:"not null" json:"generated_at"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Student *Student `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Group   *Group   `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Course  *Course  `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+}
+
+// TableName specifies the table name for ReportCard model
+func (ReportCard) TableName() string {
+	return "report_cards"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Room represents a physical classroom that timetables, exams and events book
+type Room struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Name      string   `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Building  string   `gorm:"type:varchar(100)" json:"building,omitempty"`
+	Capacity  int      `gorm:"not null" json:"capacity"` // Seats
+	Equipment []string `gorm:"serializer:json" json:"equipment,omitempty"`
+	IsActive  bool     `gorm:"default:true" json:"is_active"`
+
+	// Hours per week the room can be booked, used for utilization reports
+	AvailableHours float64 `gorm:"default:60" json:"available_hours"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName specifies the table name for Room model
+func (Room) TableName() string {
+	return "rooms"
+}
+
+// HasEquipment reports whether the room has every listed equipment tag
+func (r *Room) HasEquipment(tags ...string) bool {
+	have := make(map[string]bool, len(r.Equipment))
+	for _, tag := range r.Equipment {
+		have[tag] = true
+	}
+	for _, tag := range tags {
+		if !have[tag] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeatHoldReason says why a group seat is being held
+type SeatHoldReason string
+
+const (
+	SeatHoldPayment       SeatHoldReason = "payment"        // Awaiting the first payment
+	SeatHoldWaitlistOffer SeatHoldReason = "waitlist_offer" // Offered to a waitlist entry
+	SeatHoldManual        SeatHoldReason = "manual"
+)
+
+// SeatHold keeps a group seat free for someone until it expires, is released
+// or is consumed by their enrollment
+type SeatHold struct {
+	ID      uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	GroupID uuid.UUID      `gorm:"type:uuid;not null;index" json:"group_id"`
+	Reason  SeatHoldReason `gorm:"type:varchar(20);not null" json:"reason"`
+
+	// Who the seat is held for
+	HolderName string     `gorm:"type:varchar(200)" json:"holder_name,omitempty"`
+	WaitlistID *uuid.UUID `gorm:"type:uuid;index" json:"waitlist_id,omitempty"`
+	InvoiceID  *uuid.UUID `gorm:"type:uuid;index" json:"invoice_id,omitempty"`
+	Notes      string     `gorm:"type:text" json:"notes,omitempty"`
+
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+	StudentID  *uuid.UUID `gorm:"type:uuid" json:"student_id,omitempty"` // Student the seat went to
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Group *Group `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+}
+
+// TableName specifies the table name for SeatHold model
+func (SeatHold) TableName() string {
+	return "seat_holds"
+}
+
+// IsActive reports whether the hold still keeps its seat at the given time
+func (h *SeatHold) IsActive(at time.Time) bool {
+	return h.ConsumedAt == nil && h.ReleasedAt == nil && h.ExpiresAt.After(at)
+}
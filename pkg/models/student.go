@@ -13,6 +13,7 @@ type Student struct {
 	Surname   string         `json:"surname" binding:"required,alphaunicode"`
 	Phone     string         `json:"phone" binding:"required,len=12,numeric"`
 	Email     string         `json:"email" binding:"omitempty,email"`
+	LeadID    *uuid.UUID     `json:"lead_id,omitempty" gorm:"type:uuid;index"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
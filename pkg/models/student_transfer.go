@@ -61,7 +61,7 @@ type StudentTransfer struct {
 	FeeAdjustmentNote string  `gorm:"type:text" json:"fee_adjustment_note,omitempty"`
 
 	// Metadata
-	Metadata map[string]interface{} `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Metadata map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
 
 	// Audit fields
 	CreatedAt time.Time      `json:"created_at"`
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyllabusModule is a unit of a course syllabus, usually a week, holding
+// ordered lessons
+type SyllabusModule struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;index" json:"course_id"`
+
+	Position    int    `gorm:"not null" json:"position"` // Order within the course, from 1
+	Week        int    `gorm:"default:0" json:"week,omitempty"`
+	Title       string `gorm:"type:varchar(255);not null" json:"title"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Lessons []SyllabusLesson `gorm:"foreignKey:ModuleID;constraint:OnDelete:CASCADE" json:"lessons,omitempty"`
+}
+
+// TableName specifies the table name for SyllabusModule model
+func (SyllabusModule) TableName() string {
+	return "syllabus_modules"
+}
+
+// SyllabusLesson is a lesson of a syllabus module
+type SyllabusLesson struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	ModuleID uuid.UUID `gorm:"type:uuid;not null;index" json:"module_id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;index" json:"course_id"`
+
+	Position    int    `gorm:"not null" json:"position"` // Order within the module, from 1
+	Title       string `gorm:"type:varchar(255);not null" json:"title"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for SyllabusLesson model
+func (SyllabusLesson) TableName() string {
+	return "syllabus_lessons"
+}
+
+// SessionSyllabusLesson records that a syllabus lesson was covered in a
+// class session of a group
+type SessionSyllabusLesson struct {
+	SessionID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"session_id"`
+	LessonID  uuid.UUID  `gorm:"type:uuid;primaryKey;index" json:"lesson_id"`
+	GroupID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"group_id"`
+	CoveredBy *uuid.UUID `gorm:"type:uuid" json:"covered_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for SessionSyllabusLesson model
+func (SessionSyllabusLesson) TableName() string {
+	return "session_syllabus_lessons"
+}
@@ -6,20 +6,61 @@ import (
 	"time"
 )
 
+// TeacherStatus represents whether a teacher is currently teaching
+type TeacherStatus string
+
+const (
+	TeacherActive  TeacherStatus = "active"
+	TeacherOnLeave TeacherStatus = "on_leave"
+	TeacherLeft    TeacherStatus = "left"
+)
+
 type Teacher struct {
 	ID        uuid.UUID      `json:"id" gorm:"primarykey"`
 	Name      string         `json:"name" binding:"required,alphaunicode"`
 	Surname   string         `json:"surname" binding:"required,alphaunicode"`
 	Phone     string         `json:"phone" binding:"required,len=12,numeric"`
 	Email     string         `json:"email" binding:"omitempty,email"`
+	Status    TeacherStatus  `json:"status" gorm:"type:varchar(20);not null;default:'active';index"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
-	Groups []Group `json:"groups,omitempty"`
+	// Profile
+	Qualifications []string `json:"qualifications,omitempty" gorm:"serializer:json"` // Degrees and certificates
+	Subjects       []string `json:"subjects,omitempty" gorm:"serializer:json"`       // Subjects the teacher can teach
+	HourlyRate     float64  `json:"hourly_rate" gorm:"type:decimal(10,2);default:0"`
+	MaxWeeklyHours float64  `json:"max_weekly_hours" gorm:"default:0"` // Teaching hours limit; 0 uses the default
+
+	Groups       []Group               `json:"groups,omitempty"`
+	Availability []TeacherAvailability `json:"availability,omitempty" gorm:"foreignKey:TeacherID"`
 }
 
 func (t *Teacher) BeforeCreate(tx *gorm.DB) (err error) {
 	t.ID, err = uuid.NewUUID()
 	return err
 }
+
+// TeacherAvailability is a weekly window a teacher can teach in. A teacher
+// without windows can teach at any time.
+type TeacherAvailability struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	TeacherID   uuid.UUID `gorm:"type:uuid;not null;index" json:"teacher_id"`
+	Weekday     Weekday   `gorm:"type:varchar(3);not null" json:"weekday"`
+	StartMinute int       `gorm:"not null" json:"start_minute"`
+	EndMinute   int       `gorm:"not null" json:"end_minute"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TeacherAvailability model
+func (TeacherAvailability) TableName() string {
+	return "teacher_availabilities"
+}
+
+// Covers reports whether the window contains a lesson on a weekday
+func (a *TeacherAvailability) Covers(day Weekday, startMinute, endMinute int) bool {
+	return a.Weekday == day && a.StartMinute <= startMinute && endMinute <= a.EndMinute
+}
@@ -1,15 +1,21 @@
 package models
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Timetable is a weekly schedule made of slots. Days, StartTime and EndTime
+// summarize the slots for older clients; Classroom is the default room.
 type Timetable struct {
 	ID        uuid.UUID      `json:"id" gorm:"primarykey"`
 	Classroom string         `json:"classroom" binding:"required"`
+	RoomID    *uuid.UUID     `json:"room_id,omitempty" gorm:"type:uuid;index"`
 	StartTime string         `json:"start_time" binding:"required"`
 	EndTime   string         `json:"end_time" binding:"required"`
 	Days      string         `json:"days" binding:"required"`
@@ -17,10 +23,193 @@ type Timetable struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
-	Groups []Group `json:"groups,omitempty"`
+	Room   *Room           `json:"room,omitempty" gorm:"foreignKey:RoomID"`
+	Slots  []TimetableSlot `json:"slots,omitempty" gorm:"foreignKey:TimetableID"`
+	Groups []Group         `json:"groups,omitempty"`
 }
 
 func (t *Timetable) BeforeCreate(tx *gorm.DB) (err error) {
 	t.ID, err = uuid.NewUUID()
 	return err
 }
+
+// Weekdays returns the days the timetable has a slot on
+func (t *Timetable) Weekdays() map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool, len(t.Slots))
+	for _, slot := range t.Slots {
+		days[slot.Weekday.TimeWeekday()] = true
+	}
+	return days
+}
+
+// SlotsOn returns the slots on a weekday, earliest first
+func (t *Timetable) SlotsOn(day time.Weekday) []TimetableSlot {
+	var slots []TimetableSlot
+	for _, slot := range t.Slots {
+		if slot.Weekday.TimeWeekday() == day {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].StartMinute < slots[j].StartMinute })
+	return slots
+}
+
+// SlotClassroom returns the room a slot meets in: its own or the timetable's
+func (t *Timetable) SlotClassroom(slot *TimetableSlot) string {
+	if slot.Classroom != "" {
+		return slot.Classroom
+	}
+	return t.Classroom
+}
+
+// Summarize fills Days, StartTime and EndTime from the slots
+func (t *Timetable) Summarize() {
+	sort.Slice(t.Slots, func(i, j int) bool {
+		a, b := t.Slots[i], t.Slots[j]
+		if a.Weekday.order() != b.Weekday.order() {
+			return a.Weekday.order() < b.Weekday.order()
+		}
+		return a.StartMinute < b.StartMinute
+	})
+
+	var days []string
+	seen := make(map[Weekday]bool)
+	start, end := -1, -1
+	for _, slot := range t.Slots {
+		if !seen[slot.Weekday] {
+			seen[slot.Weekday] = true
+			days = append(days, slot.Weekday.Label())
+		}
+		if start < 0 || slot.StartMinute < start {
+			start = slot.StartMinute
+		}
+		if slot.EndMinute > end {
+			end = slot.EndMinute
+		}
+	}
+	t.Days = strings.Join(days, ",")
+	t.StartTime, t.EndTime = "", ""
+	if start >= 0 {
+		t.StartTime, t.EndTime = FormatClock(start), FormatClock(end)
+	}
+}
+
+// Weekday is the day of the week a timetable slot repeats on
+type Weekday string
+
+const (
+	Monday    Weekday = "mon"
+	Tuesday   Weekday = "tue"
+	Wednesday Weekday = "wed"
+	Thursday  Weekday = "thu"
+	Friday    Weekday = "fri"
+	Saturday  Weekday = "sat"
+	Sunday    Weekday = "sun"
+)
+
+// weekdays lists the weekdays in timetable order, Monday first
+var weekdays = []Weekday{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+// weekdayNames maps the accepted day tokens to weekdays
+var weekdayNames = map[string]Weekday{
+	"mon": Monday, "monday": Monday,
+	"tue": Tuesday, "tues": Tuesday, "tuesday": Tuesday,
+	"wed": Wednesday, "wednesday": Wednesday,
+	"thu": Thursday, "thur": Thursday, "thurs": Thursday, "thursday": Thursday,
+	"fri": Friday, "friday": Friday,
+	"sat": Saturday, "saturday": Saturday,
+	"sun": Sunday, "sunday": Sunday,
+}
+
+// ParseWeekday parses a day name such as "Mon" or "monday"
+func ParseWeekday(value string) (Weekday, error) {
+	day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return "", fmt.Errorf("unknown weekday %q", value)
+	}
+	return day, nil
+}
+
+// WeekdayOf returns the Weekday of a time.Weekday
+func WeekdayOf(day time.Weekday) Weekday {
+	return weekdays[(int(day)+6)%7]
+}
+
+// TimeWeekday converts the weekday to a time.Weekday
+func (w Weekday) TimeWeekday() time.Weekday {
+	return time.Weekday((w.order() + 1) % 7)
+}
+
+// Label returns the capitalized short name, e.g. "Mon"
+func (w Weekday) Label() string {
+	if w == "" {
+		return ""
+	}
+	return strings.ToUpper(string(w[:1])) + string(w[1:])
+}
+
+// order returns 0 for Monday through 6 for Sunday
+func (w Weekday) order() int {
+	for i, day := range weekdays {
+		if day == w {
+			return i
+		}
+	}
+	return len(weekdays)
+}
+
+// TimetableSlot is one weekly lesson of a timetable. Times are minutes since
+// midnight; a slot without a room of its own meets in the timetable's room.
+type TimetableSlot struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	TimetableID uuid.UUID `gorm:"type:uuid;not null;index" json:"timetable_id"`
+	Weekday     Weekday   `gorm:"type:varchar(3);not null" json:"weekday"`
+	StartMinute int       `gorm:"not null" json:"start_minute"`
+	EndMinute   int       `gorm:"not null" json:"end_minute"`
+
+	RoomID    *uuid.UUID `gorm:"type:uuid;index" json:"room_id,omitempty"`
+	Classroom string     `gorm:"type:varchar(100)" json:"classroom,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TimetableSlot model
+func (TimetableSlot) TableName() string {
+	return "timetable_slots"
+}
+
+// StartTime returns the slot's start as "HH:MM"
+func (s *TimetableSlot) StartTime() string {
+	return FormatClock(s.StartMinute)
+}
+
+// EndTime returns the slot's end as "HH:MM"
+func (s *TimetableSlot) EndTime() string {
+	return FormatClock(s.EndMinute)
+}
+
+// ParseClock parses a 24-hour "HH:MM" time into minutes since midnight
+func ParseClock(value string) (int, error) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	digits := [4]int{}
+	for i, c := range value[:2] + value[3:] {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+		}
+		digits[i] = int(c - '0')
+	}
+	hours, minutes := digits[0]*10+digits[1], digits[2]*10+digits[3]
+	if hours > 23 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// FormatClock formats minutes since midnight as "HH:MM"
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
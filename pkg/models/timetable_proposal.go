@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimetableProposalStatus represents the state of a generated timetable
+type TimetableProposalStatus string
+
+const (
+	ProposalDraft      TimetableProposalStatus = "draft"      // Awaiting review
+	ProposalInfeasible TimetableProposalStatus = "infeasible" // Constraints could not all be met
+	ProposalCommitted  TimetableProposalStatus = "committed"
+)
+
+// TimetableProposal is a timetable produced by the solver for a set of
+// groups. Nothing is scheduled until an administrator commits it.
+type TimetableProposal struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	Status   TimetableProposalStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	GroupIDs []uuid.UUID             `gorm:"serializer:json" json:"group_ids"`
+	Lessons  []ProposedLesson        `gorm:"serializer:json" json:"lessons"`
+	Issues   []ProposalIssue         `gorm:"serializer:json" json:"issues,omitempty"`
+
+	// Timetables created for the groups when the proposal was committed
+	TimetableIDs []uuid.UUID `gorm:"serializer:json" json:"timetable_ids,omitempty"`
+	CommittedAt  *time.Time  `json:"committed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for TimetableProposal model
+func (TimetableProposal) TableName() string {
+	return "timetable_proposals"
+}
+
+// ProposedLesson is one weekly lesson placed by the solver
+type ProposedLesson struct {
+	GroupID     uuid.UUID `json:"group_id"`
+	GroupName   string    `json:"group_name"`
+	TeacherID   uuid.UUID `json:"teacher_id"`
+	Weekday     Weekday   `json:"weekday"`
+	StartMinute int       `json:"start_minute"`
+	EndMinute   int       `json:"end_minute"`
+	RoomID      uuid.UUID `json:"room_id"`
+	Room        string    `json:"room"`
+}
+
+// ProposalIssue explains a constraint the solver could not satisfy
+type ProposalIssue struct {
+	GroupID    *uuid.UUID `json:"group_id,omitempty"`
+	TeacherID  *uuid.UUID `json:"teacher_id,omitempty"`
+	Constraint string     `json:"constraint"`
+	Message    string     `json:"message"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrialStatus represents the state of a trial lesson booking
+type TrialStatus string
+
+const (
+	TrialBooked    TrialStatus = "booked"
+	TrialAttended  TrialStatus = "attended"
+	TrialNoShow    TrialStatus = "no_show"
+	TrialCancelled TrialStatus = "cancelled"
+)
+
+// TrialOutcome is the teacher's assessment after a trial lesson
+type TrialOutcome string
+
+const (
+	TrialRecommended    TrialOutcome = "recommended"     // Ready to join the group
+	TrialOtherLevel     TrialOutcome = "other_level"     // Suited to another course or level
+	TrialNotRecommended TrialOutcome = "not_recommended" // Not a fit
+	TrialUndecided      TrialOutcome = "undecided"
+)
+
+// TrialLesson is a free lesson a lead or waitlist prospect attends as a
+// guest of a group before enrolling
+type TrialLesson struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+
+	// Prospect; exactly one is set
+	LeadID     *uuid.UUID `gorm:"type:uuid;index" json:"lead_id,omitempty"`
+	WaitlistID *uuid.UUID `gorm:"type:uuid;index" json:"waitlist_id,omitempty"`
+
+	// Contact details at booking time
+	ProspectName  string `gorm:"type:varchar(200);not null" json:"prospect_name"`
+	ProspectEmail string `gorm:"type:varchar(255)" json:"prospect_email,omitempty"`
+	ProspectPhone string `gorm:"type:varchar(20)" json:"prospect_phone,omitempty"`
+
+	// Lesson
+	GroupID   uuid.UUID `gorm:"type:uuid;not null;index" json:"group_id"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index" json:"session_id"`
+	CourseID  uuid.UUID `gorm:"type:uuid;not null;index" json:"course_id"`
+	TeacherID uuid.UUID `gorm:"type:uuid;not null;index" json:"teacher_id"` // Teacher of the lesson
+	Date      time.Time `gorm:"type:date;not null;index" json:"date"`
+
+	Status TrialStatus `gorm:"type:varchar(20);not null;default:'booked';index" json:"status"`
+	Notes  string      `gorm:"type:text" json:"notes,omitempty"`
+
+	// Teacher's assessment
+	Outcome    TrialOutcome `gorm:"type:varchar(20)" json:"outcome,omitempty"`
+	Feedback   string       `gorm:"type:text" json:"feedback,omitempty"`
+	RecordedBy *uuid.UUID   `gorm:"type:uuid" json:"recorded_by,omitempty"`
+	RecordedAt *time.Time   `json:"recorded_at,omitempty"`
+
+	CancellationReason string     `gorm:"type:text" json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+
+	BookedBy *uuid.UUID `gorm:"type:uuid" json:"booked_by,omitempty"`
+
+	// Audit fields
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Group   *Group        `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Session *ClassSession `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+	Course  *Course       `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	Teacher *Teacher      `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+}
+
+// TableName specifies the table name for TrialLesson model
+func (TrialLesson) TableName() string {
+	return "trial_lessons"
+}
+
+// TakesSeat reports whether the trial still occupies a seat in its lesson
+func (t *TrialLesson) TakesSeat() bool {
+	return t.Status == TrialBooked || t.Status == TrialAttended
+}
@@ -54,6 +54,9 @@ type Waitlist struct {
 	EnrolledAt  *time.Time `json:"enrolled_at,omitempty"`
 	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
 
+	// Seat offers are accepted or declined through a link carrying this token
+	OfferToken string `gorm:"type:varchar(64);index" json:"-"`
+
 	// Notes
 	Notes         string `gorm:"type:text" json:"notes,omitempty"`
 	InternalNotes string `gorm:"type:text" json:"internal_notes,omitempty"`
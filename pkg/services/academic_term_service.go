@@ -0,0 +1,557 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Recurring holidays in an imported file are expanded from the start of the
+// current year this far ahead
+const holidayImportYears = 2
+
+// termScopedColumns are the records scoped to terms and the date column that
+// decides their term
+var termScopedColumns = []struct {
+	model  interface{}
+	column string
+}{
+	{&models.Group{}, "start_date"},
+	{&models.Grade{}, "date"},
+	{&models.Exam{}, "start_time"},
+	{&models.Invoice{}, "issue_date"},
+}
+
+// AcademicTermService manages academic terms, their breaks and the holiday
+// calendar. Breaks and holidays have no lessons: scheduled lessons on them
+// are cancelled and new ones are not generated.
+type AcademicTermService struct {
+	db *gorm.DB
+}
+
+// NewAcademicTermService creates a new academic term service
+func NewAcademicTermService(db *gorm.DB) *AcademicTermService {
+	return &AcademicTermService{db: db}
+}
+
+// Create adds a term and scopes the groups, grades, exams and invoices
+// dated within it that have no term yet
+func (s *AcademicTermService) Create(ctx context.Context, req dto.CreateAcademicTermRequest) (*dto.AcademicTermResponse, error) {
+	term := models.AcademicTerm{ID: uuid.New(), Name: req.Name}
+	var err error
+	if term.StartDate, term.EndDate, err = parseDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+	breaks, err := s.buildBreaks(&term, req.Breaks)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := 0
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureTermIsFree(tx, &term); err != nil {
+			return err
+		}
+		if err := tx.Omit("Breaks").Create(&term).Error; err != nil {
+			return errors.DatabaseError("creating academic term", err)
+		}
+		cancelled, err = s.replaceBreaks(tx, &term, breaks)
+		if err != nil {
+			return err
+		}
+		return scopeRecordsToTerm(tx, &term)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toAcademicTermResponse(&term)
+	response.CancelledSessions = cancelled
+	return response, nil
+}
+
+// Update changes a term. Records are rescoped to the new dates and given
+// breaks replace the old ones.
+func (s *AcademicTermService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateAcademicTermRequest) (*dto.AcademicTermResponse, error) {
+	term, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		term.Name = *req.Name
+	}
+	start, end := term.StartDate.Format("2006-01-02"), term.EndDate.Format("2006-01-02")
+	if req.StartDate != nil {
+		start = *req.StartDate
+	}
+	if req.EndDate != nil {
+		end = *req.EndDate
+	}
+	if term.StartDate, term.EndDate, err = parseDateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	breaks := term.Breaks
+	if req.Breaks != nil {
+		if breaks, err = s.buildBreaks(term, *req.Breaks); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, b := range breaks {
+			if !term.Contains(b.StartDate) || !term.Contains(b.EndDate) {
+				return nil, errors.Validation(fmt.Sprintf("Break %q must lie within the term", b.Name))
+			}
+		}
+	}
+
+	cancelled := 0
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureTermIsFree(tx, term); err != nil {
+			return err
+		}
+		if err := tx.Omit("Breaks").Save(term).Error; err != nil {
+			return errors.DatabaseError("updating academic term", err)
+		}
+		if req.Breaks != nil {
+			if cancelled, err = s.replaceBreaks(tx, term, breaks); err != nil {
+				return err
+			}
+		}
+		return scopeRecordsToTerm(tx, term)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	term.Breaks = breaks
+	response := toAcademicTermResponse(term)
+	response.CancelledSessions = cancelled
+	return response, nil
+}
+
+// GetByID returns a term with its breaks
+func (s *AcademicTermService) GetByID(ctx context.Context, id uuid.UUID) (*dto.AcademicTermResponse, error) {
+	term, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAcademicTermResponse(term), nil
+}
+
+// GetAll lists terms in date order
+func (s *AcademicTermService) GetAll(ctx context.Context) ([]dto.AcademicTermResponse, error) {
+	var terms []models.AcademicTerm
+	if err := s.db.Preload("Breaks", func(db *gorm.DB) *gorm.DB { return db.Order("start_date") }).
+		Order("start_date").Find(&terms).Error; err != nil {
+		return nil, errors.DatabaseError("listing academic terms", err)
+	}
+	responses := make([]dto.AcademicTermResponse, len(terms))
+	for i := range terms {
+		responses[i] = *toAcademicTermResponse(&terms[i])
+	}
+	return responses, nil
+}
+
+// Delete removes a term and its breaks; its records are left without a term
+func (s *AcademicTermService) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.find(s.db, id); err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, scoped := range termScopedColumns {
+			if err := tx.Model(scoped.model).Where("term_id = ?", id).Update("term_id", nil).Error; err != nil {
+				return errors.DatabaseError("unscoping term records", err)
+			}
+		}
+		if err := tx.Where("term_id = ?", id).Delete(&models.TermBreak{}).Error; err != nil {
+			return errors.DatabaseError("deleting term breaks", err)
+		}
+		if err := tx.Delete(&models.AcademicTerm{}, "id = ?", id).Error; err != nil {
+			return errors.DatabaseError("deleting academic term", err)
+		}
+		return nil
+	})
+}
+
+// CreateHoliday adds a holiday and cancels the lessons scheduled on it
+func (s *AcademicTermService) CreateHoliday(ctx context.Context, req dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	end := req.EndDate
+	if end == "" {
+		end = req.StartDate
+	}
+	holiday := models.Holiday{ID: uuid.New(), Name: req.Name, Source: models.HolidayManual}
+	var err error
+	if holiday.StartDate, holiday.EndDate, err = parseDateRange(req.StartDate, end); err != nil {
+		return nil, err
+	}
+
+	cancelled := 0
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&holiday).Error; err != nil {
+			return errors.DatabaseError("creating holiday", err)
+		}
+		cancelled, err = cancelSessionsOnDaysOff(tx, holiday.StartDate, holiday.EndDate, holiday.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toHolidayResponse(&holiday)
+	response.CancelledSessions = cancelled
+	return response, nil
+}
+
+// GetHolidays lists holidays overlapping a date range, in date order
+func (s *AcademicTermService) GetHolidays(ctx context.Context, filter dto.HolidayFilter) ([]dto.HolidayResponse, error) {
+	query := s.db.Order("start_date")
+	if filter.From != "" {
+		from, err := time.Parse("2006-01-02", filter.From)
+		if err != nil {
+			return nil, errors.Validation("Invalid from date format (YYYY-MM-DD)")
+		}
+		query = query.Where("end_date >= ?", from)
+	}
+	if filter.To != "" {
+		to, err := time.Parse("2006-01-02", filter.To)
+		if err != nil {
+			return nil, errors.Validation("Invalid to date format (YYYY-MM-DD)")
+		}
+		query = query.Where("start_date <= ?", to)
+	}
+
+	var holidays []models.Holiday
+	if err := query.Find(&holidays).Error; err != nil {
+		return nil, errors.DatabaseError("listing holidays", err)
+	}
+	responses := make([]dto.HolidayResponse, len(holidays))
+	for i := range holidays {
+		responses[i] = *toHolidayResponse(&holidays[i])
+	}
+	return responses, nil
+}
+
+// DeleteHoliday removes a holiday. Lessons cancelled for it stay cancelled
+// and can be rescheduled.
+func (s *AcademicTermService) DeleteHoliday(ctx context.Context, id uuid.UUID) error {
+	result := s.db.Delete(&models.Holiday{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("deleting holiday", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundWithID("Holiday", id.String())
+	}
+	return nil
+}
+
+// ImportHolidays adds the events of an iCalendar file as holidays. Events
+// are matched by UID, so importing an updated file again updates them;
+// recurring events become one holiday per occurrence.
+func (s *AcademicTermService) ImportHolidays(ctx context.Context, data string) (*dto.ImportHolidaysResponse, error) {
+	events, err := parseICSEvents(data)
+	if err != nil {
+		return nil, errors.Validation("Invalid iCalendar file: " + err.Error())
+	}
+
+	now := time.Now()
+	windowFrom := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	windowTo := windowFrom.AddDate(holidayImportYears, 0, 0).Add(-time.Nanosecond)
+	var holidays []models.Holiday
+	result := &dto.ImportHolidaysResponse{Holidays: make([]dto.HolidayResponse, 0)}
+	for _, event := range events {
+		if event.Cancelled {
+			result.Skipped++
+			continue
+		}
+		length := event.End.Sub(event.Start)
+		starts := []time.Time{event.Start}
+		if event.Rule != nil {
+			starts = event.Rule.Between(event.Start, windowFrom, windowTo)
+			if len(starts) == 0 {
+				result.Skipped++
+			}
+		}
+		for _, start := range starts {
+			holiday := models.Holiday{Name: event.Summary, Source: models.HolidayICS, ExternalUID: event.UID}
+			holiday.StartDate, holiday.EndDate = icsEventDates(start, length, event.AllDay)
+			if holiday.Name == "" {
+				holiday.Name = "Holiday"
+			}
+			if holiday.ExternalUID == "" {
+				holiday.ExternalUID = holiday.Name
+			}
+			if event.Rule != nil || event.UID == "" {
+				// One UID covers every occurrence; the date tells them apart
+				holiday.ExternalUID += "/" + holiday.StartDate.Format("20060102")
+			}
+			holidays = append(holidays, holiday)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range holidays {
+			holiday := &holidays[i]
+			var existing models.Holiday
+			err := tx.Where("source = ? AND external_uid = ?", models.HolidayICS, holiday.ExternalUID).First(&existing).Error
+			switch {
+			case err == gorm.ErrRecordNotFound:
+				holiday.ID = uuid.New()
+				if err := tx.Create(holiday).Error; err != nil {
+					return errors.DatabaseError("creating holiday", err)
+				}
+				result.Created++
+			case err != nil:
+				return errors.DatabaseError("finding holiday", err)
+			case existing.Name == holiday.Name && existing.StartDate.Equal(holiday.StartDate) && existing.EndDate.Equal(holiday.EndDate):
+				result.Unchanged++
+				result.Holidays = append(result.Holidays, *toHolidayResponse(&existing))
+				continue
+			default:
+				holiday.ID, holiday.CreatedAt = existing.ID, existing.CreatedAt
+				if err := tx.Save(holiday).Error; err != nil {
+					return errors.DatabaseError("updating holiday", err)
+				}
+				result.Updated++
+			}
+
+			cancelled, err := cancelSessionsOnDaysOff(tx, holiday.StartDate, holiday.EndDate, holiday.Name)
+			if err != nil {
+				return err
+			}
+			result.CancelledSessions += cancelled
+			response := toHolidayResponse(holiday)
+			response.CancelledSessions = cancelled
+			result.Holidays = append(result.Holidays, *response)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result.Holidays, func(i, j int) bool {
+		return result.Holidays[i].StartDate.Before(result.Holidays[j].StartDate)
+	})
+	return result, nil
+}
+
+// icsEventDates returns the first and last date of an imported event. DTEND
+// is exclusive; an event without one lasts a day.
+func icsEventDates(start time.Time, length time.Duration, allDay bool) (time.Time, time.Time) {
+	first := dateOnly(start)
+	if length <= 0 {
+		return first, first
+	}
+	last := dateOnly(start.Add(length - time.Nanosecond))
+	if allDay {
+		last = dateOnly(start.Add(length).AddDate(0, 0, -1))
+	}
+	if last.Before(first) {
+		last = first
+	}
+	return first, last
+}
+
+func (s *AcademicTermService) find(db *gorm.DB, id uuid.UUID) (*models.AcademicTerm, error) {
+	var term models.AcademicTerm
+	if err := db.Preload("Breaks", func(db *gorm.DB) *gorm.DB { return db.Order("start_date") }).
+		First(&term, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Academic term", id.String())
+		}
+		return nil, errors.DatabaseError("finding academic term", err)
+	}
+	return &term, nil
+}
+
+// buildBreaks validates breaks: each must lie within the term and they must
+// not overlap
+func (s *AcademicTermService) buildBreaks(term *models.AcademicTerm, requests []dto.TermBreakRequest) ([]models.TermBreak, error) {
+	breaks := make([]models.TermBreak, len(requests))
+	for i, req := range requests {
+		start, end, err := parseDateRange(req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		if !term.Contains(start) || !term.Contains(end) {
+			return nil, errors.Validation(fmt.Sprintf("Break %q must lie within the term", req.Name))
+		}
+		breaks[i] = models.TermBreak{ID: uuid.New(), TermID: term.ID, Name: req.Name, StartDate: start, EndDate: end}
+	}
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i].StartDate.Before(breaks[j].StartDate) })
+	for i := 1; i < len(breaks); i++ {
+		if !breaks[i].StartDate.After(breaks[i-1].EndDate) {
+			return nil, errors.Validation(fmt.Sprintf("Breaks %q and %q overlap", breaks[i-1].Name, breaks[i].Name))
+		}
+	}
+	return breaks, nil
+}
+
+// ensureTermIsFree rejects a term that overlaps another one, so every date
+// belongs to at most one term
+func (s *AcademicTermService) ensureTermIsFree(tx *gorm.DB, term *models.AcademicTerm) error {
+	var other models.AcademicTerm
+	err := tx.Where("id <> ? AND start_date <= ? AND end_date >= ?", term.ID, term.EndDate, term.StartDate).
+		Limit(1).Find(&other).Error
+	if err != nil {
+		return errors.DatabaseError("checking academic terms", err)
+	}
+	if other.ID != uuid.Nil {
+		return errors.New(errors.ErrCodeScheduleConflict, fmt.Sprintf("Term overlaps %q", other.Name)).
+			WithDetail("term_id", other.ID)
+	}
+	var named int64
+	if err := tx.Model(&models.AcademicTerm{}).Where("id <> ? AND name = ?", term.ID, term.Name).Count(&named).Error; err != nil {
+		return errors.DatabaseError("checking academic terms", err)
+	}
+	if named > 0 {
+		return errors.DuplicateEntry("Academic term", "name")
+	}
+	return nil
+}
+
+// replaceBreaks stores the term's breaks and cancels the lessons scheduled
+// in them
+func (s *AcademicTermService) replaceBreaks(tx *gorm.DB, term *models.AcademicTerm, breaks []models.TermBreak) (int, error) {
+	if err := tx.Where("term_id = ?", term.ID).Delete(&models.TermBreak{}).Error; err != nil {
+		return 0, errors.DatabaseError("deleting term breaks", err)
+	}
+	cancelled := 0
+	for i := range breaks {
+		if err := tx.Create(&breaks[i]).Error; err != nil {
+			return 0, errors.DatabaseError("creating term break", err)
+		}
+		n, err := cancelSessionsOnDaysOff(tx, breaks[i].StartDate, breaks[i].EndDate, breaks[i].Name)
+		if err != nil {
+			return 0, err
+		}
+		cancelled += n
+	}
+	term.Breaks = breaks
+	return cancelled, nil
+}
+
+// scopeRecordsToTerm moves records dated outside the term out of it and
+// scopes records dated within it that have no term
+func scopeRecordsToTerm(tx *gorm.DB, term *models.AcademicTerm) error {
+	start, end := term.StartDate, term.EndDate.AddDate(0, 0, 1)
+	for _, scoped := range termScopedColumns {
+		if err := tx.Model(scoped.model).
+			Where("term_id = ? AND ("+scoped.column+" < ? OR "+scoped.column+" >= ?)", term.ID, start, end).
+			Update("term_id", nil).Error; err != nil {
+			return errors.DatabaseError("rescoping term records", err)
+		}
+		if err := tx.Model(scoped.model).
+			Where("term_id IS NULL AND "+scoped.column+" >= ? AND "+scoped.column+" < ?", start, end).
+			Update("term_id", term.ID).Error; err != nil {
+			return errors.DatabaseError("scoping term records", err)
+		}
+	}
+	return nil
+}
+
+// daysOff maps the dates between from and to that fall on a holiday or a
+// term break to its name
+func daysOff(db *gorm.DB, from, to time.Time) (map[time.Time]string, error) {
+	from, to = dateOnly(from), dateOnly(to)
+	var holidays []models.Holiday
+	if err := db.Select("name", "start_date", "end_date").
+		Where("start_date <= ? AND end_date >= ?", to, from).Find(&holidays).Error; err != nil {
+		return nil, errors.DatabaseError("finding holidays", err)
+	}
+	var breaks []models.TermBreak
+	if err := db.Select("name", "start_date", "end_date").
+		Where("start_date <= ? AND end_date >= ?", to, from).Find(&breaks).Error; err != nil {
+		return nil, errors.DatabaseError("finding term breaks", err)
+	}
+
+	off := make(map[time.Time]string)
+	mark := func(name string, start, end time.Time) {
+		for day := dateOnly(start); !day.After(dateOnly(end)) && !day.After(to); day = day.AddDate(0, 0, 1) {
+			if _, taken := off[day]; !taken && !day.Before(from) {
+				off[day] = name
+			}
+		}
+	}
+	for _, h := range holidays {
+		mark(h.Name, h.StartDate, h.EndDate)
+	}
+	for _, b := range breaks {
+		mark(b.Name, b.StartDate, b.EndDate)
+	}
+	return off, nil
+}
+
+// cancelSessionsOnDaysOff cancels the lessons scheduled from today on
+// between two dates, without make-up credits, and returns how many were
+// cancelled
+func cancelSessionsOnDaysOff(tx *gorm.DB, from, to time.Time, reason string) (int, error) {
+	now := time.Now()
+	if today := dateOnly(now); from.Before(today) {
+		from = today
+	}
+	if to.Before(from) {
+		return 0, nil
+	}
+	result := tx.Model(&models.ClassSession{}).
+		Where("status = ? AND date >= ? AND date <= ?", models.SessionScheduled, dateOnly(from), dateOnly(to)).
+		Updates(map[string]interface{}{
+			"status":              models.SessionCancelled,
+			"cancellation_reason": "No lessons: " + reason,
+			"cancelled_at":        now,
+		})
+	if result.Error != nil {
+		return 0, errors.DatabaseError("cancelling class sessions", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// parseDateRange parses an inclusive YYYY-MM-DD date range
+func parseDateRange(startStr, endStr string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Validation("Invalid start_date format (YYYY-MM-DD)")
+	}
+	end, err := time.Parse("2006-01-02", endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Validation("Invalid end_date format (YYYY-MM-DD)")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.Validation("end_date must not be before start_date")
+	}
+	return start, end, nil
+}
+
+func toAcademicTermResponse(term *models.AcademicTerm) *dto.AcademicTermResponse {
+	breaks := make([]dto.TermBreakResponse, len(term.Breaks))
+	for i, b := range term.Breaks {
+		breaks[i] = dto.TermBreakResponse{ID: b.ID, Name: b.Name, StartDate: b.StartDate, EndDate: b.EndDate}
+	}
+	return &dto.AcademicTermResponse{
+		ID:        term.ID,
+		Name:      term.Name,
+		StartDate: term.StartDate,
+		EndDate:   term.EndDate,
+		Breaks:    breaks,
+		CreatedAt: term.CreatedAt,
+		UpdatedAt: term.UpdatedAt,
+	}
+}
+
+func toHolidayResponse(holiday *models.Holiday) *dto.HolidayResponse {
+	return &dto.HolidayResponse{
+		ID:          holiday.ID,
+		Name:        holiday.Name,
+		StartDate:   holiday.StartDate,
+		EndDate:     holiday.EndDate,
+		Source:      holiday.Source,
+		ExternalUID: holiday.ExternalUID,
+		CreatedAt:   holiday.CreatedAt,
+	}
+}
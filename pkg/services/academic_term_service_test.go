@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcademicTermService_TermsAndHolidays(t *testing.T) {
+	db := setupTestDB()
+	terms := NewAcademicTermService(db)
+	sessions := NewClassSessionService(db)
+	ctx := context.Background()
+
+	// Next year, so cancellations are in the future
+	year := time.Now().Year() + 1
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	day := func(month time.Month, day int) string {
+		return date(month, day).Format("2006-01-02")
+	}
+
+	timetable := models.Timetable{Classroom: "Room 1", StartTime: "09:00", EndTime: "10:30", Days: "Mon,Tue,Wed,Thu,Fri"}
+	db.Create(&timetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10, StartDate: date(time.February, 2)}
+	db.Create(&group)
+	assert.Nil(t, group.TermID)
+
+	// Lessons already generated for a week that becomes a break are cancelled
+	generated, err := sessions.Generate(ctx, group.ID, dto.GenerateClassSessionsRequest{From: day(time.March, 1), To: day(time.March, 31)})
+	assert.NoError(t, err)
+	assert.Greater(t, generated.Created, 0)
+
+	spring, err := terms.Create(ctx, dto.CreateAcademicTermRequest{
+		Name: "Spring", StartDate: day(time.February, 1), EndDate: day(time.June, 30),
+		Breaks: []dto.TermBreakRequest{{Name: "Spring break", StartDate: day(time.March, 16), EndDate: day(time.March, 22)}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, spring.CancelledSessions)
+	var reloaded models.Group
+	db.First(&reloaded, "id = ?", group.ID)
+	if assert.NotNil(t, reloaded.TermID) {
+		assert.Equal(t, spring.ID, *reloaded.TermID)
+	}
+
+	// New records pick up their term
+	grade := models.Grade{GroupID: group.ID, Value: 90, Type: "exam", Date: date(time.April, 10)}
+	db.Create(&grade)
+	if assert.NotNil(t, grade.TermID) {
+		assert.Equal(t, spring.ID, *grade.TermID)
+	}
+
+	_, err = terms.Create(ctx, dto.CreateAcademicTermRequest{Name: "Overlap", StartDate: day(time.June, 1), EndDate: day(time.August, 31)})
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeScheduleConflict, err.(*errors.AppError).Code)
+	}
+	_, err = terms.Create(ctx, dto.CreateAcademicTermRequest{
+		Name: "Autumn", StartDate: day(time.September, 1), EndDate: day(time.December, 31),
+		Breaks: []dto.TermBreakRequest{{Name: "Outside", StartDate: day(time.August, 25), EndDate: day(time.September, 2)}},
+	})
+	assert.Error(t, err)
+
+	// A holiday on a weekday cancels its lesson
+	weekday := date(time.March, 24)
+	for weekday.Weekday() == time.Saturday || weekday.Weekday() == time.Sunday {
+		weekday = weekday.AddDate(0, 0, 1)
+	}
+	holiday, err := terms.CreateHoliday(ctx, dto.CreateHolidayRequest{Name: "Founders' Day", StartDate: weekday.Format("2006-01-02")})
+	assert.NoError(t, err)
+	assert.Equal(t, weekday, holiday.EndDate)
+	assert.Equal(t, 1, holiday.CancelledSessions)
+
+	var cancelled int64
+	db.Model(&models.ClassSession{}).Where("group_id = ? AND status = ?", group.ID, models.SessionCancelled).Count(&cancelled)
+	assert.EqualValues(t, 6, cancelled)
+	_, err = sessionForAttendance(db, group.ID, date(time.March, 18), nil)
+	assert.Error(t, err)
+
+	// Lessons are not generated on holidays
+	may, err := terms.CreateHoliday(ctx, dto.CreateHolidayRequest{Name: "May holidays", StartDate: day(time.May, 1), EndDate: day(time.May, 10)})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, may.CancelledSessions)
+	generated, err = sessions.Generate(ctx, group.ID, dto.GenerateClassSessionsRequest{From: day(time.May, 1), To: day(time.May, 31)})
+	assert.NoError(t, err)
+	workdays := 0
+	for d := date(time.May, 1); d.Month() == time.May; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			workdays++
+		}
+	}
+	assert.Equal(t, workdays, generated.Created+generated.SkippedDaysOff)
+	assert.Greater(t, generated.SkippedDaysOff, 0)
+
+	holidays, err := terms.GetHolidays(ctx, dto.HolidayFilter{From: day(time.April, 1)})
+	assert.NoError(t, err)
+	assert.Len(t, holidays, 1)
+}
+
+func TestAcademicTermService_ImportHolidays(t *testing.T) {
+	db := setupTestDB()
+	terms := NewAcademicTermService(db)
+	ctx := context.Background()
+	year := time.Now().Year()
+
+	ics := fmt.Sprintf("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n"+
+		"BEGIN:VEVENT\r\nUID:navruz@example.com\r\nSUMMARY:Navruz\r\nDTSTART;VALUE=DATE:%d0321\r\nDTEND;VALUE=DATE:%d0325\r\n"+
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nSUMMARY:Reminder\r\nEND:VALARM\r\nEND:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\nUID:new-year@example.com\r\nSUMMARY:New Year\\, day off\r\nDTSTART;VALUE=DATE:%d0101\r\nRRULE:FREQ=YEARLY\r\nEND:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\nUID:cancelled@example.com\r\nSUMMARY:Cancelled\r\nSTATUS:CANCELLED\r\nDTSTART;VALUE=DATE:%d0601\r\nEND:VEVENT\r\n"+
+		"END:VCALENDAR\r\n", year, year, year, year)
+
+	result, err := terms.ImportHolidays(ctx, ics)
+	assert.NoError(t, err)
+	assert.Equal(t, 1+holidayImportYears, result.Created)
+	assert.Equal(t, 1, result.Skipped)
+
+	var navruz models.Holiday
+	db.First(&navruz, "external_uid = ?", "navruz@example.com")
+	assert.Equal(t, time.Date(year, 3, 24, 0, 0, 0, 0, time.UTC), navruz.EndDate)
+	assert.Equal(t, models.HolidayICS, navruz.Source)
+	var newYear models.Holiday
+	db.Order("start_date").First(&newYear, "name = ?", "New Year, day off")
+	assert.Equal(t, time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), newYear.StartDate)
+
+	// Importing the same file again changes nothing
+	result, err = terms.ImportHolidays(ctx, ics)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1+holidayImportYears, result.Unchanged)
+
+	_, err = terms.ImportHolidays(ctx, "not a calendar")
+	assert.Error(t, err)
+}
+
+func TestRecurringInvoiceService_Terms(t *testing.T) {
+	db := setupTestDB()
+	terms := NewAcademicTermService(db)
+	recurring := NewRecurringInvoiceService(db)
+	ctx := context.Background()
+
+	timetable := models.Timetable{Classroom: "Room 1", StartTime: "09:00", EndTime: "10:30", Days: "Mon,Wed,Fri"}
+	db.Create(&timetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&group)
+	student := models.Student{GroupID: group.ID, Name: "Farid", Surname: "Saidov", Phone: "992900000081"}
+	db.Create(&student)
+
+	// March 2027 has 14 Monday, Wednesday and Friday lessons; a holiday week
+	// from Monday the 8th takes three
+	_, err := terms.Create(ctx, dto.CreateAcademicTermRequest{Name: "Spring 2027", StartDate: "2027-02-01", EndDate: "2027-06-30"})
+	assert.NoError(t, err)
+	autumn, err := terms.Create(ctx, dto.CreateAcademicTermRequest{Name: "Autumn 2027", StartDate: "2027-09-01", EndDate: "2027-12-31"})
+	assert.NoError(t, err)
+	_, err = terms.CreateHoliday(ctx, dto.CreateHolidayRequest{Name: "Holiday week", StartDate: "2027-03-08", EndDate: "2027-03-14"})
+	assert.NoError(t, err)
+
+	_, err = recurring.CreateRecurringInvoice(ctx, dto.CreateRecurringInvoiceRequest{StudentID: student.ID, Frequency: models.FrequencyQuarterly,
+		BaseAmount: 100, StartDate: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC), ProRateHolidays: true})
+	assert.Error(t, err)
+
+	monthly, err := recurring.CreateRecurringInvoice(ctx, dto.CreateRecurringInvoiceRequest{StudentID: student.ID, GroupID: &group.ID,
+		Frequency: models.FrequencyMonthly, BaseAmount: 140, Description: "Tuition", StartDate: time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC),
+		ProRateHolidays: true})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC), monthly.NextInvoiceDate)
+
+	result, err := recurring.GenerateInvoices(ctx, dto.GenerateInvoicesRequest{RecurringInvoiceIDs: []uuid.UUID{monthly.ID}})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Generated, 1) {
+		var invoice models.Invoice
+		db.First(&invoice, "id = ?", result.Generated[0])
+		assert.Equal(t, 110.0, invoice.TotalAmount)
+		assert.Equal(t, "Tuition (11 of 14 lessons; 3 fall on holidays or breaks)", invoice.Description)
+		assert.NotNil(t, invoice.TermID)
+	}
+
+	// Semester invoices follow the terms
+	semester, err := recurring.CreateRecurringInvoice(ctx, dto.CreateRecurringInvoiceRequest{StudentID: student.ID,
+		Frequency: models.FrequencySemester, BaseAmount: 600, StartDate: time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Equal(t, autumn.StartDate, semester.NextInvoiceDate)
+}
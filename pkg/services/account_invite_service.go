@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultAccountInviteTTL is how long an invite link stays valid when no
+// TTL is configured
+const defaultAccountInviteTTL = 72 * time.Hour
+
+// AccountInviteService issues and redeems the links new users follow to
+// choose their password
+type AccountInviteService struct {
+	db        *gorm.DB
+	inviteURL string
+	inviteTTL time.Duration
+}
+
+// NewAccountInviteService creates a new account invite service. Invite links
+// are inviteURL followed by the token.
+func NewAccountInviteService(db *gorm.DB, inviteURL string, inviteTTL time.Duration) *AccountInviteService {
+	if inviteTTL <= 0 {
+		inviteTTL = defaultAccountInviteTTL
+	}
+	return &AccountInviteService{
+		db:        db,
+		inviteURL: strings.TrimRight(inviteURL, "/"),
+		inviteTTL: inviteTTL,
+	}
+}
+
+// issue stores an invite for a user in tx and returns its link and expiry
+func (s *AccountInviteService) issue(tx *gorm.DB, userID uuid.UUID) (string, time.Time, error) {
+	token, err := generateSecureToken(32)
+	if err != nil {
+		return "", time.Time{}, errors.Internal("failed to generate invite token", err)
+	}
+	invite := models.AccountInvite{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: hashInviteToken(token),
+		ExpiresAt: time.Now().Add(s.inviteTTL),
+	}
+	if err := tx.Create(&invite).Error; err != nil {
+		return "", time.Time{}, errors.DatabaseError("creating account invite", err)
+	}
+	return s.inviteURL + "/" + token, invite.ExpiresAt, nil
+}
+
+// Reissue sends a user who has not chosen a password yet a fresh invite,
+// for when the first one was not delivered or has expired. Earlier links of
+// the user stop working.
+func (s *AccountInviteService) Reissue(ctx context.Context, userID uuid.UUID) (*dto.ReissuedAccountInviteResponse, error) {
+	var user models.User
+	var inviteLink string
+	var expiresAt time.Time
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("User", userID.String())
+			}
+			return errors.DatabaseError("finding user", err)
+		}
+
+		var invites []models.AccountInvite
+		if err := tx.Where("user_id = ?", userID).Find(&invites).Error; err != nil {
+			return errors.DatabaseError("finding account invites", err)
+		}
+		if len(invites) == 0 {
+			return errors.New(errors.ErrCodeInvalidOperation, "This user was not invited to choose a password")
+		}
+		for i := range invites {
+			if invites[i].UsedAt != nil {
+				return errors.New(errors.ErrCodeInvalidOperation, "This user has already chosen a password")
+			}
+		}
+
+		// Earlier links expire now, so only the new one can set the password
+		now := time.Now()
+		if err := tx.Model(&models.AccountInvite{}).
+			Where("user_id = ? AND expires_at > ?", userID, now).
+			Update("expires_at", now).Error; err != nil {
+			return errors.DatabaseError("expiring account invites", err)
+		}
+		var err error
+		inviteLink, expiresAt, err = s.issue(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = NewNotificationService(s.db).SendNotification(ctx, dto.SendNotificationRequest{
+		Type:      models.NotificationEmail,
+		Recipient: user.Email,
+		UserID:    &user.ID,
+		StudentID: user.StudentID,
+		Subject:   "Your new account link",
+		Message: fmt.Sprintf("Hello %s,\n\nChoose the password of your account here: %s\n\nThe link can be used once and expires in %d hours. Earlier links no longer work.",
+			user.FirstName, inviteLink, int(s.inviteTTL.Hours())),
+	})
+	return &dto.ReissuedAccountInviteResponse{
+		UserID:         user.ID,
+		Email:          user.Email,
+		ExpiresAt:      expiresAt,
+		InvitationSent: err == nil,
+	}, nil
+}
+
+// Get returns what a new user may see about their invite
+func (s *AccountInviteService) Get(ctx context.Context, token string) (*dto.AccountInviteResponse, error) {
+	invite, err := s.findInvite(s.db, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureInviteOpen(invite, time.Now()); err != nil {
+		return nil, err
+	}
+	return &dto.AccountInviteResponse{
+		Email:     invite.User.Email,
+		FirstName: invite.User.FirstName,
+		ExpiresAt: invite.ExpiresAt,
+	}, nil
+}
+
+// Accept sets the invited user's password and uses up the invite. Other
+// open invites of the user are used up with it.
+func (s *AccountInviteService) Accept(ctx context.Context, token string, req dto.AcceptAccountInviteRequest) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.Internal("failed to hash password", err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		invite, err := s.findInvite(tx.Clauses(clause.Locking{Strength: "UPDATE"}), token)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := ensureInviteOpen(invite, now); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", invite.UserID).Update("password", string(hashed)).Error; err != nil {
+			return errors.DatabaseError("setting password", err)
+		}
+		if err := tx.Model(&models.AccountInvite{}).
+			Where("user_id = ? AND used_at IS NULL", invite.UserID).
+			Update("used_at", now).Error; err != nil {
+			return errors.DatabaseError("using account invite", err)
+		}
+		return nil
+	})
+}
+
+func (s *AccountInviteService) findInvite(db *gorm.DB, token string) (*models.AccountInvite, error) {
+	if token == "" {
+		return nil, errors.NotFound("Account invite")
+	}
+
+	var invite models.AccountInvite
+	if err := db.Preload("User").First(&invite, "token_hash = ?", hashInviteToken(token)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("Account invite")
+		}
+		return nil, errors.DatabaseError("finding account invite", err)
+	}
+	if invite.User == nil {
+		return nil, errors.NotFound("Account invite")
+	}
+	return &invite, nil
+}
+
+// ensureInviteOpen rejects invites that were used or have expired
+func ensureInviteOpen(invite *models.AccountInvite, now time.Time) error {
+	if invite.UsedAt != nil {
+		return errors.New(errors.ErrCodeInvalidOperation, "This invite has already been used")
+	}
+	if !invite.IsOpen(now) {
+		return errors.New(errors.ErrCodeInvalidOperation, "This invite has expired")
+	}
+	return nil
+}
+
+// hashInviteToken returns the stored form of an invite token
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
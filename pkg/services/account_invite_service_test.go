@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountInviteService_Reissue(t *testing.T) {
+	db := setupTestDB()
+	invites := NewAccountInviteService(db, "https://example.com/account/invites", 0)
+	ctx := context.Background()
+
+	user, err := NewUserService(db).Create(ctx, "zarina@example.com", "unguessable", models.RoleStudent, "Zarina", "Karimova")
+	assert.NoError(t, err)
+	expired, _, err := invites.issue(db, user.ID)
+	assert.NoError(t, err)
+	db.Model(&models.AccountInvite{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute))
+
+	_, err = invites.Reissue(ctx, uuid.New())
+	assert.True(t, errors.IsNotFound(err))
+	admin, err := NewUserService(db).Create(ctx, "admin@example.com", "admin-secret", models.RoleAdmin, "Admin", "User")
+	assert.NoError(t, err)
+	_, err = invites.Reissue(ctx, admin.ID)
+	assert.Error(t, err, "users who were never invited keep their password")
+
+	// The expired link is replaced by a fresh one
+	reissued, err := invites.Reissue(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, reissued.InvitationSent)
+	assert.Equal(t, "zarina@example.com", reissued.Email)
+	assert.WithinDuration(t, time.Now().Add(defaultAccountInviteTTL), reissued.ExpiresAt, time.Minute)
+
+	var email models.Notification
+	assert.NoError(t, db.First(&email, "user_id = ?", user.ID).Error)
+	link := regexp.MustCompile(`https://example\.com/account/invites/(\S+)`).FindStringSubmatch(email.Message)
+	if !assert.Len(t, link, 2) {
+		return
+	}
+	assert.NotEqual(t, expired, link[0])
+	_, err = invites.Get(ctx, link[1])
+	assert.NoError(t, err)
+
+	// Sending another retires the previous link
+	_, err = invites.Reissue(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Error(t, invites.Accept(ctx, link[1], dto.AcceptAccountInviteRequest{Password: "my-own-secret"}))
+
+	var open int64
+	db.Model(&models.AccountInvite{}).Where("user_id = ? AND expires_at > ?", user.ID, time.Now()).Count(&open)
+	assert.EqualValues(t, 1, open)
+
+	// Once the password is chosen there is nothing left to reissue
+	db.Model(&models.AccountInvite{}).Where("user_id = ?", user.ID).Update("used_at", time.Now())
+	_, err = invites.Reissue(ctx, user.ID)
+	assert.Error(t, err)
+}
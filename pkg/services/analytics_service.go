@@ -170,6 +170,8 @@ func (s *AnalyticsService) GetStudentProgress(ctx context.Context, studentID uui
 		report.LatestGrades = append(report.LatestGrades, dto.GradeInfo{
 			ID:        g.ID,
 			Score:     float64(g.Value),
+			Label:     g.Label,
+			GPAPoints: g.GPAPoints,
 			Type:      string(g.Type),
 			Notes:     g.Notes,
 			CreatedAt: g.CreatedAt,
@@ -3,27 +3,34 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ApplicationService handles application operations
 type ApplicationService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	invites *AccountInviteService
 }
 
-// NewApplicationService creates a new application service
-func NewApplicationService(db *gorm.DB) *ApplicationService {
-	return &ApplicationService{db: db}
+// NewApplicationService creates a new application service. invites issues
+// the password links of accounts created on enrollment; without it
+// enrollment cannot create accounts.
+func NewApplicationService(db *gorm.DB, invites *AccountInviteService) *ApplicationService {
+	return &ApplicationService{db: db, invites: invites}
 }
 
 // Create creates a new application
 func (s *ApplicationService) Create(ctx context.Context, req dto.CreateApplicationRequest) (*dto.ApplicationResponse, error) {
 	application := models.Application{
+		ID:                uuid.New(),
 		FirstName:         req.FirstName,
 		LastName:          req.LastName,
 		Email:             req.Email,
@@ -39,11 +46,18 @@ func (s *ApplicationService) Create(ctx context.Context, req dto.CreateApplicati
 		Metadata:          req.Metadata,
 	}
 
+	warnings, err := prerequisiteWarnings(s.db, req.CourseID, req.Email, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := s.db.Create(&application).Error; err != nil {
 		return nil, fmt.Errorf("failed to create application: %w", err)
 	}
 
-	return s.toResponse(&application), nil
+	response := s.toResponse(&application)
+	response.Warnings = warnings
+	return response, nil
 }
 
 // GetByID retrieves an application by ID
@@ -213,8 +227,10 @@ func (s *ApplicationService) Review(ctx context.Context, id string, reviewerID u
 	return s.toResponse(&application), nil
 }
 
-// Enroll enrolls an applicant as a student
-func (s *ApplicationService) Enroll(ctx context.Context, id string, req dto.EnrollApplicationRequest) (*dto.ApplicationResponse, error) {
+// Enroll enrolls an approved applicant as a student of a group. The student,
+// their parent, the first bill and their account are created in one
+// transaction; the account invitation is emailed once it commits.
+func (s *ApplicationService) Enroll(ctx context.Context, id string, req dto.EnrollApplicationRequest) (*dto.EnrollmentResponse, error) {
 	var application models.Application
 	if err := s.db.First(&application, "id = ?", id).Error; err != nil {
 		return nil, fmt.Errorf("application not found")
@@ -224,32 +240,247 @@ func (s *ApplicationService) Enroll(ctx context.Context, id string, req dto.Enro
 		return nil, fmt.Errorf("application must be approved before enrollment")
 	}
 
-	// Create student from application
-	student := models.Student{
-		Name:  application.FirstName + " " + application.LastName,
-		Email: application.Email,
-		Phone: application.Phone,
+	billing := req.Billing
+	if billing == "" {
+		billing = "invoice"
+	}
+	dueDays := req.DueDays
+	if dueDays == 0 {
+		dueDays = 30
+	}
+	createAccount := application.Email != ""
+	if req.CreateAccount != nil {
+		if *req.CreateAccount && application.Email == "" {
+			return nil, errors.Validation("The applicant has no email address to create an account for")
+		}
+		createAccount = *req.CreateAccount
+	}
+	if createAccount && s.invites == nil {
+		return nil, errors.Internal("account invites are not configured", nil)
 	}
 
-	if req.GroupID != nil {
-		student.GroupID = *req.GroupID
+	var parentReq dto.CreateParentRequest
+	var relation models.ParentRelation
+	if req.CreateParent {
+		var err error
+		if parentReq, relation, err = parentFromMetadata(application.Metadata); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.db.Create(&student).Error; err != nil {
-		return nil, fmt.Errorf("failed to create student: %w", err)
+	// The account gets a random password nobody knows; the user chooses
+	// their own through the invite link
+	var password, inviteLink string
+	if createAccount {
+		var err error
+		if password, err = generateSecureToken(32); err != nil {
+			return nil, errors.Internal("failed to generate password", err)
+		}
 	}
 
-	// Update application
-	now := time.Now()
-	application.Status = models.ApplicationEnrolled
-	application.EnrolledAs = &student.ID
-	application.EnrolledAt = &now
+	response := &dto.EnrollmentResponse{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Re-read the application under a row lock so two enrollments of
+		// the same application cannot both pass the status check
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, "id = ?", application.ID).Error; err != nil {
+			return errors.DatabaseError("locking application", err)
+		}
+		if application.Status != models.ApplicationApproved {
+			return errors.Conflict("Application has already been enrolled or is no longer approved")
+		}
 
-	if err := s.db.Save(&application).Error; err != nil {
-		return nil, fmt.Errorf("failed to update application: %w", err)
+		// Creating through the student service allocates the seat under a group lock
+		student, err := NewStudentService(tx).Create(ctx, req.GroupID.String(), dto.CreateStudentRequest{
+			Name:    application.FirstName,
+			Surname: application.LastName,
+			Phone:   application.Phone,
+			Email:   application.Email,
+			HoldID:  req.HoldID,
+		})
+		if err != nil {
+			return err
+		}
+		if application.LeadID != nil {
+			if err := tx.Model(&models.Student{}).Where("id = ?", student.ID).Update("lead_id", *application.LeadID).Error; err != nil {
+				return errors.DatabaseError("updating student", err)
+			}
+			student.LeadID = application.LeadID
+			// Keep the lead pointing at the student it became
+			if err := tx.Model(&models.Lead{}).Where("id = ?", *application.LeadID).Update("student_id", student.ID).Error; err != nil {
+				return errors.DatabaseError("updating lead", err)
+			}
+		}
+		response.Student = student
+		response.Warnings = student.Warnings
+
+		var group models.Group
+		if err := tx.Preload("Course").First(&group, "id = ?", req.GroupID).Error; err != nil {
+			return errors.DatabaseError("finding group", err)
+		}
+
+		if req.CreateParent {
+			parentID, err := enrollParent(ctx, tx, parentReq, relation, student.ID)
+			if err != nil {
+				return err
+			}
+			response.ParentID = &parentID
+		}
+
+		if fee := group.Course.MonthlyFee; fee > 0 {
+			description := fmt.Sprintf("Tuition for %s (%s)", group.Course.Title, group.Name)
+			switch billing {
+			case "invoice":
+				invoice, err := NewInvoiceService(tx).Create(ctx, dto.CreateInvoiceRequest{
+					StudentID:   student.ID,
+					CourseID:    &group.CourseID,
+					GroupID:     &group.ID,
+					SubTotal:    fee,
+					DueDate:     time.Now().AddDate(0, 0, dueDays).Format("2006-01-02"),
+					Description: description,
+				})
+				if err != nil {
+					return err
+				}
+				response.InvoiceID = &invoice.ID
+			case "recurring":
+				startDate := time.Now()
+				if group.StartDate.After(startDate) {
+					startDate = group.StartDate
+				}
+				recurring, err := NewRecurringInvoiceService(tx).CreateRecurringInvoice(ctx, dto.CreateRecurringInvoiceRequest{
+					StudentID:   student.ID,
+					GroupID:     &group.ID,
+					CourseID:    &group.CourseID,
+					Frequency:   models.FrequencyMonthly,
+					DayOfMonth:  startDate.Day(),
+					BaseAmount:  fee,
+					Description: description,
+					StartDate:   startDate,
+					AutoSend:    true,
+					DueDays:     dueDays,
+				})
+				if err != nil {
+					return errors.DatabaseError("creating recurring invoice", err)
+				}
+				response.RecurringInvoiceID = &recurring.ID
+			}
+		}
+
+		if createAccount {
+			user, err := NewUserService(tx).Create(ctx, application.Email, password, models.RoleStudent, application.FirstName, application.LastName)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+				"student_id": student.ID,
+				"phone":      application.Phone,
+			}).Error; err != nil {
+				return errors.DatabaseError("linking user to student", err)
+			}
+			if inviteLink, _, err = s.invites.issue(tx, user.ID); err != nil {
+				return err
+			}
+			response.UserID = &user.ID
+		}
+
+		now := time.Now()
+		application.Status = models.ApplicationEnrolled
+		application.EnrolledAs = &student.ID
+		application.EnrolledAt = &now
+		if err := tx.Save(&application).Error; err != nil {
+			return errors.DatabaseError("updating application", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.toResponse(&application), nil
+	if response.UserID != nil {
+		_, err := NewNotificationService(s.db).SendNotification(ctx, dto.SendNotificationRequest{
+			Type:      models.NotificationEmail,
+			Recipient: application.Email,
+			UserID:    response.UserID,
+			StudentID: &response.Student.ID,
+			Subject:   "Welcome! Your student account is ready",
+			Message: fmt.Sprintf("Hello %s,\n\nYou are now enrolled. Choose the password of your account here: %s\n\nThe link can be used once and expires in %d hours.",
+				application.FirstName, inviteLink, int(s.invites.inviteTTL.Hours())),
+		})
+		response.InvitationSent = err == nil
+	}
+
+	response.Application = s.toResponse(&application)
+	return response, nil
+}
+
+// parentFromMetadata reads the parent an applicant listed in metadata["parent"]
+// as first_name, last_name, email, phone and relation
+func parentFromMetadata(metadata map[string]interface{}) (dto.CreateParentRequest, models.ParentRelation, error) {
+	raw, ok := metadata["parent"].(map[string]interface{})
+	if !ok {
+		return dto.CreateParentRequest{}, "", errors.Validation("The application has no parent details in metadata.parent")
+	}
+	field := func(key string) string {
+		value, _ := raw[key].(string)
+		return strings.TrimSpace(value)
+	}
+
+	req := dto.CreateParentRequest{
+		FirstName:            field("first_name"),
+		LastName:             field("last_name"),
+		Email:                field("email"),
+		Phone:                field("phone"),
+		ReceiveNotifications: true,
+	}
+	if req.FirstName == "" || req.LastName == "" || req.Phone == "" {
+		return req, "", errors.Validation("metadata.parent needs first_name, last_name and phone")
+	}
+
+	relation := models.ParentRelation(field("relation"))
+	switch relation {
+	case models.RelationFather, models.RelationMother, models.RelationGuardian, models.RelationOther:
+	case "":
+		relation = models.RelationGuardian
+	default:
+		return req, "", errors.Validation("metadata.parent.relation must be father, mother, guardian or other")
+	}
+	return req, relation, nil
+}
+
+// enrollParent links a newly enrolled student to their parent, reusing a
+// parent already on file with the same email or phone
+func enrollParent(ctx context.Context, tx *gorm.DB, req dto.CreateParentRequest, relation models.ParentRelation, studentID uuid.UUID) (uuid.UUID, error) {
+	var parent models.Parent
+	query := tx.Where("phone = ?", req.Phone)
+	if req.Email != "" {
+		query = tx.Where("email = ? OR phone = ?", req.Email, req.Phone)
+	}
+	if err := query.Limit(1).Find(&parent).Error; err != nil {
+		return uuid.Nil, errors.DatabaseError("finding parent", err)
+	}
+
+	parents := NewParentService(tx)
+	parentID := parent.ID
+	if parentID == uuid.Nil {
+		created, err := parents.CreateParent(ctx, req)
+		if err != nil {
+			return uuid.Nil, errors.DatabaseError("creating parent", err)
+		}
+		parentID = created.ID
+	}
+
+	if err := parents.LinkStudent(ctx, dto.LinkParentStudentRequest{
+		ParentID:         parentID,
+		StudentID:        studentID,
+		Relation:         relation,
+		IsPrimary:        true,
+		CanPickup:        true,
+		ReceivesGrades:   true,
+		ReceivesInvoices: true,
+	}); err != nil {
+		return uuid.Nil, errors.DatabaseError("linking parent", err)
+	}
+	return parentID, nil
 }
 
 // Delete deletes an application
@@ -286,6 +517,7 @@ func (s *ApplicationService) toResponse(a *models.Application) *dto.ApplicationR
 		EnrolledAs:        a.EnrolledAs,
 		EnrolledAt:        a.EnrolledAt,
 		Metadata:          a.Metadata,
+		LeadID:            a.LeadID,
 		CreatedAt:         a.CreatedAt,
 		UpdatedAt:         a.UpdatedAt,
 	}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestApplicationService_Enroll(t *testing.T) {
+	db := setupTestDB()
+	applications := NewApplicationService(db, NewAccountInviteService(db, "https://example.com/account/invites", 0))
+	ctx := context.Background()
+
+	course := models.Course{Title: "Go Beginner", MonthlyFee: 120}
+	db.Create(&course)
+	group := models.Group{Name: "GO-1", CourseID: course.ID, Capacity: 1, StartDate: time.Now().AddDate(0, 0, 14)}
+	db.Create(&group)
+
+	apply := func(email, phone string, metadata map[string]interface{}) *dto.ApplicationResponse {
+		application, err := applications.Create(ctx, dto.CreateApplicationRequest{FirstName: "Zarina", LastName: "Karimova",
+			Email: email, Phone: phone, CourseID: course.ID, Metadata: metadata})
+		assert.NoError(t, err)
+		db.Model(&models.Application{}).Where("id = ?", application.ID).Update("status", models.ApplicationApproved)
+		return application
+	}
+
+	existing := models.Parent{ID: uuid.New(), FirstName: "Dilnoza", LastName: "Karimova", Email: "dilnoza@example.com", Phone: "992900000200", IsActive: true}
+	db.Create(&existing)
+	first := apply("zarina@example.com", "992900000111", map[string]interface{}{
+		"parent": map[string]interface{}{"first_name": "Dilnoza", "last_name": "Karimova", "phone": "992900000200", "relation": "mother"},
+	})
+
+	t.Run("without parent details in metadata", func(t *testing.T) {
+		_, err := applications.Enroll(ctx, apply("other@example.com", "992900000112", nil).ID.String(),
+			dto.EnrollApplicationRequest{GroupID: group.ID, CreateParent: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("creates the student, parent link, schedule and account", func(t *testing.T) {
+		enrollment, err := applications.Enroll(ctx, first.ID.String(), dto.EnrollApplicationRequest{
+			GroupID: group.ID, CreateParent: true, Billing: "recurring"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.ApplicationEnrolled, enrollment.Application.Status)
+		assert.Equal(t, "Zarina", enrollment.Student.Name)
+		assert.Equal(t, "Karimova", enrollment.Student.Surname)
+		assert.Equal(t, group.ID, enrollment.Student.GroupID)
+
+		// The parent on file is linked rather than duplicated
+		assert.Equal(t, existing.ID, *enrollment.ParentID)
+		var parents int64
+		db.Model(&models.Parent{}).Count(&parents)
+		assert.EqualValues(t, 1, parents)
+		var link models.ParentStudent
+		assert.NoError(t, db.First(&link, "student_id = ?", enrollment.Student.ID).Error)
+		assert.Equal(t, models.RelationMother, link.Relation)
+
+		var recurring models.RecurringInvoice
+		assert.NoError(t, db.First(&recurring, "id = ?", *enrollment.RecurringInvoiceID).Error)
+		assert.Equal(t, 120.0, recurring.BaseAmount)
+		assert.Equal(t, models.FrequencyMonthly, recurring.Frequency)
+
+		var user models.User
+		assert.NoError(t, db.First(&user, "id = ?", *enrollment.UserID).Error)
+		assert.Equal(t, models.RoleStudent, user.Role)
+		assert.Equal(t, enrollment.Student.ID, *user.StudentID)
+		assert.True(t, enrollment.InvitationSent)
+		var invitation models.Notification
+		assert.NoError(t, db.First(&invitation, "user_id = ?", user.ID).Error)
+		assert.Equal(t, "zarina@example.com", invitation.Recipient)
+
+		// The email carries a single-use link, never a password
+		assert.NotContains(t, strings.ToLower(invitation.Message), "password:")
+		inviteLink := regexp.MustCompile(`https://example\.com/account/invites/(\S+)`).FindStringSubmatch(invitation.Message)
+		if assert.Len(t, inviteLink, 2) {
+			users := NewUserService(db)
+			_, err = users.ValidatePassword(ctx, "zarina@example.com", "my-own-secret")
+			assert.Error(t, err)
+
+			invite, err := applications.invites.Get(ctx, inviteLink[1])
+			assert.NoError(t, err)
+			assert.Equal(t, "zarina@example.com", invite.Email)
+			assert.NoError(t, applications.invites.Accept(ctx, inviteLink[1], dto.AcceptAccountInviteRequest{Password: "my-own-secret"}))
+			_, err = users.ValidatePassword(ctx, "zarina@example.com", "my-own-secret")
+			assert.NoError(t, err)
+			assert.Error(t, applications.invites.Accept(ctx, inviteLink[1], dto.AcceptAccountInviteRequest{Password: "someone-else"}), "the link is single-use")
+		}
+	})
+
+	t.Run("a concurrent enrollment is caught under the lock", func(t *testing.T) {
+		raced := apply("raced@example.com", "992900000115", nil)
+		open := models.Group{Name: "GO-3", CourseID: course.ID, Capacity: 10}
+		db.Create(&open)
+
+		// Another request enrolls the application right after the status check
+		racing := true
+		assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:race_enroll", func(tx *gorm.DB) {
+			if racing && tx.Statement.Table == "applications" {
+				racing = false
+				tx.Session(&gorm.Session{NewDB: true}).Exec("UPDATE applications SET status = ? WHERE id = ?", models.ApplicationEnrolled, raced.ID)
+			}
+		}))
+		defer db.Callback().Query().Remove("test:race_enroll")
+
+		_, err := applications.Enroll(ctx, raced.ID.String(), dto.EnrollApplicationRequest{GroupID: open.ID, Billing: "none"})
+		if assert.Error(t, err) {
+			assert.Equal(t, errors.ErrCodeConflict, err.(*errors.AppError).Code)
+		}
+		var students int64
+		db.Model(&models.Student{}).Where("email = ?", "raced@example.com").Count(&students)
+		assert.EqualValues(t, 0, students)
+	})
+
+	t.Run("a failed step rolls the student back", func(t *testing.T) {
+		db.Create(&models.User{ID: uuid.New(), Email: "taken@example.com", Password: "x", Role: models.RoleStudent})
+		open := models.Group{Name: "GO-2", CourseID: course.ID, Capacity: 10}
+		db.Create(&open)
+		_, err := applications.Enroll(ctx, apply("taken@example.com", "992900000114", nil).ID.String(),
+			dto.EnrollApplicationRequest{GroupID: open.ID, Billing: "none"})
+		assert.Error(t, err)
+		var students int64
+		db.Model(&models.Student{}).Where("email = ?", "taken@example.com").Count(&students)
+		assert.EqualValues(t, 0, students)
+	})
+
+	t.Run("a full group is refused", func(t *testing.T) {
+		second := apply("karim@example.com", "992900000113", nil)
+		_, err := applications.Enroll(ctx, second.ID.String(), dto.EnrollApplicationRequest{GroupID: group.ID, Billing: "none"})
+		if assert.Error(t, err) {
+			assert.Equal(t, errors.ErrCodeCapacityExceeded, err.(*errors.AppError).Code)
+		}
+
+		var application models.Application
+		db.First(&application, "id = ?", second.ID)
+		assert.Equal(t, models.ApplicationApproved, application.Status)
+		var users int64
+		db.Model(&models.User{}).Where("email = ?", "karim@example.com").Count(&users)
+		assert.EqualValues(t, 0, users)
+	})
+}
@@ -3,49 +3,68 @@ package services
 import (
 	"context"
 	"fmt"
+	"mime/multipart"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
 )
 
+// maxSubmissionFiles bounds the files uploaded with a single attempt
+const maxSubmissionFiles = 10
+
 // AssignmentService handles assignment operations
 type AssignmentService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	documents *DocumentService
 }
 
 // NewAssignmentService creates a new assignment service
 func NewAssignmentService(db *gorm.DB) *AssignmentService {
-	return &AssignmentService{db: db}
+	return &AssignmentService{
+		db:        db,
+		documents: NewDocumentService(db),
+	}
 }
 
 // CreateAssignment creates a new assignment
 func (s *AssignmentService) CreateAssignment(ctx context.Context, req dto.CreateAssignmentRequest) (*dto.AssignmentResponse, error) {
 	assignment := models.Assignment{
-		ID:            uuid.New(),
-		GroupID:       req.GroupID,
-		CourseID:      req.CourseID,
-		TeacherID:     req.TeacherID,
-		Title:         req.Title,
-		Description:   req.Description,
-		Type:          req.Type,
-		Status:        models.AssignmentDraft,
-		AssignedDate:  req.AssignedDate,
-		DueDate:       req.DueDate,
-		MaxPoints:     req.MaxPoints,
-		PassingPoints: req.PassingPoints,
-		WeightPercent: req.WeightPercent,
-		AllowLate:     req.AllowLate,
-		LatePenalty:   req.LatePenalty,
-		Instructions:  req.Instructions,
-		Resources:     req.Resources,
+		ID:             uuid.New(),
+		GroupID:        req.GroupID,
+		CourseID:       req.CourseID,
+		TeacherID:      req.TeacherID,
+		Title:          req.Title,
+		Description:    req.Description,
+		Type:           req.Type,
+		Status:         models.AssignmentDraft,
+		AssignedDate:   req.AssignedDate,
+		DueDate:        req.DueDate,
+		MaxPoints:      req.MaxPoints,
+		PassingPoints:  req.PassingPoints,
+		WeightPercent:  req.WeightPercent,
+		AllowLate:      req.AllowLate,
+		LatePenalty:    req.LatePenalty,
+		MaxLatePenalty: 100,
+		LateWindowDays: 7,
+		MaxAttempts:    req.MaxAttempts,
+		Instructions:   req.Instructions,
+		Resources:      req.Resources,
 	}
 
 	if req.MaxPoints == 0 {
 		assignment.MaxPoints = 100
 	}
+	if req.MaxLatePenalty != nil {
+		assignment.MaxLatePenalty = *req.MaxLatePenalty
+	}
+	if req.LateWindowDays != nil {
+		assignment.LateWindowDays = *req.LateWindowDays
+	}
 
 	if err := s.db.Create(&assignment).Error; err != nil {
 		return nil, fmt.Errorf("failed to create assignment: %w", err)
@@ -70,10 +89,11 @@ func (s *AssignmentService) UpdateAssignment(ctx context.Context, id uuid.UUID,
 	if req.Type != nil {
 		assignment.Type = *req.Type
 	}
+	now := time.Now()
+	closing := req.Status != nil && *req.Status == models.AssignmentClosed && assignment.Status != models.AssignmentClosed
 	if req.Status != nil {
 		assignment.Status = *req.Status
 		if *req.Status == models.AssignmentClosed {
-			now := time.Now()
 			assignment.ClosedDate = &now
 		}
 	}
@@ -95,6 +115,15 @@ func (s *AssignmentService) UpdateAssignment(ctx context.Context, id uuid.UUID,
 	if req.LatePenalty != nil {
 		assignment.LatePenalty = *req.LatePenalty
 	}
+	if req.MaxLatePenalty != nil {
+		assignment.MaxLatePenalty = *req.MaxLatePenalty
+	}
+	if req.LateWindowDays != nil {
+		assignment.LateWindowDays = *req.LateWindowDays
+	}
+	if req.MaxAttempts != nil {
+		assignment.MaxAttempts = *req.MaxAttempts
+	}
 	if req.Instructions != nil {
 		assignment.Instructions = *req.Instructions
 	}
@@ -102,7 +131,18 @@ func (s *AssignmentService) UpdateAssignment(ctx context.Context, id uuid.UUID,
 		assignment.Resources = *req.Resources
 	}
 
-	if err := s.db.Save(&assignment).Error; err != nil {
+	// Closing by hand records the missing submissions just like the closing job
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&assignment).Error; err != nil {
+			return err
+		}
+		if closing {
+			_, err := s.recordMissingSubmissions(tx, &assignment, now)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to update assignment: %w", err)
 	}
 
@@ -125,9 +165,27 @@ func (s *AssignmentService) GetAssignment(ctx context.Context, id uuid.UUID) (*d
 	var submissions []models.AssignmentSubmission
 	s.db.Where("assignment_id = ?", id).Find(&submissions)
 
-	stats.TotalSubmitted = len(submissions)
+	// Each attempt is a separate submission; count students and only the
+	// attempt whose grade counts
+	submitted := make(map[uuid.UUID]bool)
+	late := make(map[uuid.UUID]bool)
+	hasFinal := make(map[uuid.UUID]bool)
 	for _, sub := range submissions {
-		if sub.Status == models.SubmissionGraded {
+		if sub.Status == models.SubmissionPending {
+			continue // Zero-score record for a missing submission
+		}
+		submitted[sub.StudentID] = true
+		if sub.IsLate {
+			late[sub.StudentID] = true
+		}
+		if sub.IsFinal {
+			hasFinal[sub.StudentID] = true
+		}
+	}
+	stats.TotalSubmitted = len(submitted)
+	stats.LateSubmissions = len(late)
+	for _, sub := range submissions {
+		if sub.Status == models.SubmissionGraded && (sub.IsFinal || !hasFinal[sub.StudentID]) {
 			stats.TotalGraded++
 			if sub.Points != nil {
 				stats.AverageScore += *sub.Points
@@ -139,9 +197,6 @@ func (s *AssignmentService) GetAssignment(ctx context.Context, id uuid.UUID) (*d
 				}
 			}
 		}
-		if sub.IsLate {
-			stats.LateSubmissions++
-		}
 	}
 
 	if stats.TotalGraded > 0 {
@@ -176,16 +231,36 @@ func (s *AssignmentService) GetAssignmentsByGroup(ctx context.Context, groupID u
 	return responses, nil
 }
 
-// SubmitAssignment handles student submission
-func (s *AssignmentService) SubmitAssignment(ctx context.Context, assignmentID, studentID uuid.UUID, req dto.SubmitAssignmentRequest) (*dto.SubmissionResponse, error) {
+// SubmitAssignment records a new attempt for a student. Earlier attempts are
+// kept; uploaded files are stored as assignment documents and linked to the
+// attempt. Attempts beyond the assignment's maximum are rejected, as is work
+// handed in after the due date when late work is not allowed or after the
+// late window has ended. Accepted late attempts are flagged with the days late.
+func (s *AssignmentService) SubmitAssignment(ctx context.Context, assignmentID, studentID uuid.UUID, req dto.SubmitAssignmentRequest, files []*multipart.FileHeader, uploaderID *uuid.UUID) (*dto.SubmissionResponse, error) {
 	var assignment models.Assignment
 	if err := s.db.First(&assignment, "id = ?", assignmentID).Error; err != nil {
-		return nil, fmt.Errorf("assignment not found")
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Assignment", assignmentID.String())
+		}
+		return nil, errors.DatabaseError("finding assignment", err)
+	}
+	if len(files) > maxSubmissionFiles {
+		return nil, errors.Validation(fmt.Sprintf("A submission can have at most %d files", maxSubmissionFiles))
 	}
 
-	// Check if submission exists
-	var submission models.AssignmentSubmission
-	err := s.db.Where("assignment_id = ? AND student_id = ?", assignmentID, studentID).First(&submission).Error
+	if assignment.Status == models.AssignmentClosed {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Assignment is closed")
+	}
+
+	var attempts int64
+	if err := s.db.Model(&models.AssignmentSubmission{}).
+		Where("assignment_id = ? AND student_id = ? AND status <> ?", assignmentID, studentID, models.SubmissionPending).
+		Count(&attempts).Error; err != nil {
+		return nil, errors.DatabaseError("counting submission attempts", err)
+	}
+	if assignment.MaxAttempts > 0 && int(attempts) >= assignment.MaxAttempts {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Maximum of %d attempts reached", assignment.MaxAttempts))
+	}
 
 	now := time.Now()
 	isLate := now.After(assignment.DueDate)
@@ -193,68 +268,115 @@ func (s *AssignmentService) SubmitAssignment(ctx context.Context, assignmentID,
 
 	if isLate {
 		if !assignment.AllowLate {
-			return nil, fmt.Errorf("late submissions are not allowed")
+			return nil, errors.New(errors.ErrCodeInvalidOperation, "Late submissions are not allowed")
 		}
-		duration := now.Sub(assignment.DueDate)
-		daysLate = int(duration.Hours() / 24)
-		if daysLate == 0 {
-			daysLate = 1 // At least 1 day late if passed due date
+		if now.After(assignment.SubmissionDeadline()) {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, "The late submission window has ended").
+				WithDetail("submissions_end", assignment.SubmissionDeadline())
 		}
+		daysLate = daysLateAt(assignment.DueDate, now)
 	}
 
-	if err == gorm.ErrRecordNotFound {
-		// Create new submission
-		submission = models.AssignmentSubmission{
-			ID:            uuid.New(),
-			AssignmentID:  assignmentID,
-			StudentID:     studentID,
-			Status:        models.SubmissionSubmitted,
-			SubmittedAt:   &now,
-			Content:       req.Content,
-			Attachments:   req.Attachments,
-			IsLate:        isLate,
-			DaysLate:      daysLate,
-			AttemptNumber: 1,
-		}
-		if err := s.db.Create(&submission).Error; err != nil {
-			return nil, err
+	submission := models.AssignmentSubmission{
+		ID:            uuid.New(),
+		AssignmentID:  assignmentID,
+		StudentID:     studentID,
+		Status:        models.SubmissionSubmitted,
+		SubmittedAt:   &now,
+		Content:       req.Content,
+		Attachments:   req.Attachments,
+		IsLate:        isLate,
+		DaysLate:      daysLate,
+		AttemptNumber: int(attempts) + 1,
+	}
+
+	// Store the files first so an invalid upload rejects the whole attempt
+	for _, file := range files {
+		doc, err := s.documents.Upload(ctx, dto.UploadDocumentRequest{
+			Name:      file.Filename,
+			Type:      models.DocumentTypeAssignment,
+			StudentID: &studentID,
+			GroupID:   &assignment.GroupID,
+			CourseID:  &assignment.CourseID,
+		}, file, uploaderID)
+		if err != nil {
+			s.removeFiles(ctx, submission.Files)
+			return nil, errors.Validation(fmt.Sprintf("Could not store %s: %v", file.Filename, err))
 		}
-	} else {
-		// Update existing (resubmission)
-		submission.Status = models.SubmissionSubmitted
-		submission.SubmittedAt = &now
-		submission.Content = req.Content
-		submission.Attachments = req.Attachments
-		submission.IsLate = isLate
-		submission.DaysLate = daysLate
-		submission.AttemptNumber++
-
-		if err := s.db.Save(&submission).Error; err != nil {
-			return nil, err
+		submission.Files = append(submission.Files, models.SubmissionAttachment{
+			ID:           uuid.New(),
+			SubmissionID: submission.ID,
+			DocumentID:   doc.ID,
+			FileName:     file.Filename,
+			FileSize:     file.Size,
+			MimeType:     file.Header.Get("Content-Type"),
+		})
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// A real attempt replaces the zero-score record of a reopened assignment
+		if err := tx.Where("assignment_id = ? AND student_id = ? AND status = ?", assignmentID, studentID, models.SubmissionPending).
+			Delete(&models.AssignmentSubmission{}).Error; err != nil {
+			return err
 		}
+		return tx.Create(&submission).Error
+	})
+	if err != nil {
+		s.removeFiles(ctx, submission.Files)
+		return nil, errors.DatabaseError("creating submission", err)
 	}
 
 	return s.toSubmissionResponse(&submission), nil
 }
 
-// GradeSubmission grades a student submission
+// GetSubmissions lists every attempt on an assignment, optionally for one student
+func (s *AssignmentService) GetSubmissions(ctx context.Context, assignmentID uuid.UUID, studentID *uuid.UUID) ([]dto.SubmissionResponse, error) {
+	var assignment models.Assignment
+	if err := s.db.First(&assignment, "id = ?", assignmentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Assignment", assignmentID.String())
+		}
+		return nil, errors.DatabaseError("finding assignment", err)
+	}
+
+	query := s.db.Preload("Student").Preload("Files").Where("assignment_id = ?", assignmentID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+
+	var submissions []models.AssignmentSubmission
+	if err := query.Order("student_id ASC, attempt_number ASC").Find(&submissions).Error; err != nil {
+		return nil, errors.DatabaseError("fetching submissions", err)
+	}
+
+	responses := make([]dto.SubmissionResponse, len(submissions))
+	for i := range submissions {
+		responses[i] = *s.toSubmissionResponse(&submissions[i])
+	}
+	return responses, nil
+}
+
+// GradeSubmission grades one attempt of a student's submission. The graded
+// attempt becomes the final one that counts towards the student's results.
+// Late attempts lose the assignment's per-day penalty, up to its cap.
 func (s *AssignmentService) GradeSubmission(ctx context.Context, submissionID uuid.UUID, graderID uuid.UUID, req dto.GradeSubmissionRequest) (*dto.SubmissionResponse, error) {
 	var submission models.AssignmentSubmission
-	if err := s.db.Preload("Assignment").First(&submission, "id = ?", submissionID).Error; err != nil {
-		return nil, fmt.Errorf("submission not found")
+	if err := s.db.Preload("Assignment").Preload("Files").First(&submission, "id = ?", submissionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Submission", submissionID.String())
+		}
+		return nil, errors.DatabaseError("finding submission", err)
 	}
 
 	now := time.Now()
 	points := req.Points
+	if points < 0 || points > submission.Assignment.MaxPoints {
+		return nil, errors.Validation(fmt.Sprintf("Points must be between 0 and %g", submission.Assignment.MaxPoints))
+	}
 
-	// Apply late penalty if applicable
 	penalty := 0.0
-	if submission.IsLate && submission.Assignment.LatePenalty > 0 {
-		penalty = (submission.Assignment.LatePenalty / 100) * submission.Assignment.MaxPoints * float64(submission.DaysLate)
-		// Cap penalty at max points? Or allow negative? Usually cap at 0 score.
-		if points-penalty < 0 {
-			penalty = points // Max penalty is the points earned
-		}
+	if submission.IsLate {
+		penalty = latePenalty(submission.Assignment, submission.DaysLate, points)
 	}
 
 	finalPoints := points - penalty
@@ -265,9 +387,18 @@ func (s *AssignmentService) GradeSubmission(ctx context.Context, submissionID uu
 	submission.GradedAt = &now
 	submission.GradedBy = &graderID
 	submission.PenaltyApplied = penalty
+	submission.IsFinal = true
 
-	if err := s.db.Save(&submission).Error; err != nil {
-		return nil, err
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AssignmentSubmission{}).
+			Where("assignment_id = ? AND student_id = ? AND id <> ?", submission.AssignmentID, submission.StudentID, submission.ID).
+			Update("is_final", false).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Assignment", "Files").Save(&submission).Error
+	})
+	if err != nil {
+		return nil, errors.DatabaseError("grading submission", err)
 	}
 
 	// Also create/update a Grade record for the gradebook
@@ -277,29 +408,153 @@ func (s *AssignmentService) GradeSubmission(ctx context.Context, submissionID uu
 	return s.toSubmissionResponse(&submission), nil
 }
 
+// CloseDueAssignments closes published assignments whose submission deadline
+// has passed and records a zero-score pending submission for every student in
+// the group who never submitted
+func (s *AssignmentService) CloseDueAssignments(ctx context.Context, now time.Time) (*dto.CloseAssignmentsResponse, error) {
+	var assignments []models.Assignment
+	if err := s.db.Where("status = ? AND due_date <= ?", models.AssignmentPublished, now).Find(&assignments).Error; err != nil {
+		return nil, errors.DatabaseError("fetching due assignments", err)
+	}
+
+	resp := &dto.CloseAssignmentsResponse{ClosedAssignmentIDs: make([]uuid.UUID, 0)}
+	for i := range assignments {
+		assignment := &assignments[i]
+		if now.Before(assignment.SubmissionDeadline()) {
+			continue // Still inside the late window
+		}
+
+		missing := 0
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Assignment{}).Where("id = ?", assignment.ID).Updates(map[string]interface{}{
+				"status":      models.AssignmentClosed,
+				"closed_date": now,
+			}).Error; err != nil {
+				return err
+			}
+			var err error
+			missing, err = s.recordMissingSubmissions(tx, assignment, now)
+			return err
+		})
+		if err != nil {
+			return nil, errors.DatabaseError("closing assignment", err)
+		}
+
+		resp.TotalClosed++
+		resp.MissingSubmissions += missing
+		resp.ClosedAssignmentIDs = append(resp.ClosedAssignmentIDs, assignment.ID)
+	}
+
+	return resp, nil
+}
+
+// recordMissingSubmissions creates a zero-score pending submission for each
+// student of the assignment's group without any attempt
+func (s *AssignmentService) recordMissingSubmissions(tx *gorm.DB, assignment *models.Assignment, now time.Time) (int, error) {
+	var students []models.Student
+	if err := tx.Where("group_id = ?", assignment.GroupID).Find(&students).Error; err != nil {
+		return 0, err
+	}
+
+	var submitted []uuid.UUID
+	if err := tx.Model(&models.AssignmentSubmission{}).Where("assignment_id = ?", assignment.ID).
+		Distinct().Pluck("student_id", &submitted).Error; err != nil {
+		return 0, err
+	}
+	hasSubmission := make(map[uuid.UUID]bool, len(submitted))
+	for _, id := range submitted {
+		hasSubmission[id] = true
+	}
+
+	created := 0
+	for _, student := range students {
+		if hasSubmission[student.ID] {
+			continue
+		}
+		zero := 0.0
+		record := models.AssignmentSubmission{
+			ID:           uuid.New(),
+			AssignmentID: assignment.ID,
+			StudentID:    student.ID,
+			Status:       models.SubmissionPending,
+			Points:       &zero,
+			Feedback:     "Not submitted before the assignment closed",
+			IsFinal:      true,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// daysLateAt counts started days between the due date and the submission,
+// so anything past the due date is at least one day late
+func daysLateAt(dueDate, submittedAt time.Time) int {
+	late := submittedAt.Sub(dueDate)
+	if late <= 0 {
+		return 0
+	}
+	days := int(late / (24 * time.Hour))
+	if late%(24*time.Hour) > 0 {
+		days++
+	}
+	return days
+}
+
+// latePenalty returns the points deducted for work handed in daysLate days
+// after the due date. The deduction is a percentage of the maximum points per
+// day, capped by the assignment's maximum late penalty and the points earned.
+func latePenalty(assignment *models.Assignment, daysLate int, points float64) float64 {
+	if daysLate <= 0 || assignment.LatePenalty <= 0 {
+		return 0
+	}
+	percent := assignment.LatePenalty * float64(daysLate)
+	if percent > assignment.MaxLatePenalty {
+		percent = assignment.MaxLatePenalty
+	}
+	penalty := assignment.MaxPoints * percent / 100
+	if penalty > points {
+		penalty = points
+	}
+	return penalty
+}
+
+// removeFiles deletes documents stored for an attempt that was not recorded
+func (s *AssignmentService) removeFiles(ctx context.Context, files []models.SubmissionAttachment) {
+	for _, file := range files {
+		s.documents.Delete(ctx, file.DocumentID.String())
+	}
+}
+
 // toResponse converts model to DTO
 func (s *AssignmentService) toResponse(a *models.Assignment) *dto.AssignmentResponse {
 	resp := &dto.AssignmentResponse{
-		ID:            a.ID,
-		GroupID:       a.GroupID,
-		CourseID:      a.CourseID,
-		TeacherID:     a.TeacherID,
-		Title:         a.Title,
-		Description:   a.Description,
-		Type:          a.Type,
-		Status:        a.Status,
-		AssignedDate:  a.AssignedDate,
-		DueDate:       a.DueDate,
-		ClosedDate:    a.ClosedDate,
-		MaxPoints:     a.MaxPoints,
-		PassingPoints: a.PassingPoints,
-		WeightPercent: a.WeightPercent,
-		AllowLate:     a.AllowLate,
-		LatePenalty:   a.LatePenalty,
-		Instructions:  a.Instructions,
-		Resources:     a.Resources,
-		CreatedAt:     a.CreatedAt,
-		UpdatedAt:     a.UpdatedAt,
+		ID:             a.ID,
+		GroupID:        a.GroupID,
+		CourseID:       a.CourseID,
+		TeacherID:      a.TeacherID,
+		Title:          a.Title,
+		Description:    a.Description,
+		Type:           a.Type,
+		Status:         a.Status,
+		AssignedDate:   a.AssignedDate,
+		DueDate:        a.DueDate,
+		ClosedDate:     a.ClosedDate,
+		MaxPoints:      a.MaxPoints,
+		PassingPoints:  a.PassingPoints,
+		WeightPercent:  a.WeightPercent,
+		AllowLate:      a.AllowLate,
+		LatePenalty:    a.LatePenalty,
+		MaxLatePenalty: a.MaxLatePenalty,
+		LateWindowDays: a.LateWindowDays,
+		SubmissionsEnd: a.SubmissionDeadline(),
+		MaxAttempts:    a.MaxAttempts,
+		Instructions:   a.Instructions,
+		Resources:      a.Resources,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
 	}
 
 	if a.Group != nil {
@@ -317,7 +572,7 @@ func (s *AssignmentService) toResponse(a *models.Assignment) *dto.AssignmentResp
 
 // toSubmissionResponse converts submission model to DTO
 func (s *AssignmentService) toSubmissionResponse(sub *models.AssignmentSubmission) *dto.SubmissionResponse {
-	return &dto.SubmissionResponse{
+	resp := &dto.SubmissionResponse{
 		ID:             sub.ID,
 		AssignmentID:   sub.AssignmentID,
 		StudentID:      sub.StudentID,
@@ -332,7 +587,42 @@ func (s *AssignmentService) toSubmissionResponse(sub *models.AssignmentSubmissio
 		DaysLate:       sub.DaysLate,
 		PenaltyApplied: sub.PenaltyApplied,
 		AttemptNumber:  sub.AttemptNumber,
+		IsFinal:        sub.IsFinal,
+		Files:          make([]dto.SubmissionFileResponse, len(sub.Files)),
 		CreatedAt:      sub.CreatedAt,
 		UpdatedAt:      sub.UpdatedAt,
 	}
+	for i, file := range sub.Files {
+		resp.Files[i] = dto.SubmissionFileResponse{
+			DocumentID:  file.DocumentID,
+			FileName:    file.FileName,
+			FileSize:    file.FileSize,
+			MimeType:    file.MimeType,
+			DownloadURL: s.documents.DownloadURL(file.DocumentID),
+		}
+	}
+	if sub.Student != nil {
+		resp.Student = &dto.StudentSimple{ID: sub.Student.ID, Name: sub.Student.Name, Surname: sub.Student.Surname}
+	}
+	return resp
+}
+
+// BackfillAssignmentLatePolicy gives assignments stored before the late
+// policy columns existed the default cap of 100% and a 7 day late window.
+// The columns have no defaults, so AutoMigrate adds them as NULL, which
+// would read as no late window at all. It runs after AutoMigrate.
+func BackfillAssignmentLatePolicy(db *gorm.DB) error {
+	result := db.Unscoped().Model(&models.Assignment{}).Where("max_late_penalty IS NULL").Update("max_late_penalty", 100)
+	if result.Error != nil {
+		return errors.DatabaseError("backfilling assignment late penalty cap", result.Error)
+	}
+	filled := result.RowsAffected
+	result = db.Unscoped().Model(&models.Assignment{}).Where("late_window_days IS NULL").Update("late_window_days", 7)
+	if result.Error != nil {
+		return errors.DatabaseError("backfilling assignment late window", result.Error)
+	}
+	if filled += result.RowsAffected; filled > 0 {
+		logger.Warnf("backfilled %d missing assignment late policy values", filled)
+	}
+	return nil
 }
@@ -40,6 +40,18 @@ func TestAssignmentService_CreateAssignment(t *testing.T) {
 	assert.Equal(t, req.Title, resp.Title)
 	assert.Equal(t, req.GroupID, resp.GroupID)
 	assert.Equal(t, models.AssignmentDraft, resp.Status)
+	assert.Equal(t, 100.0, resp.MaxLatePenalty)
+	assert.Equal(t, 7, resp.LateWindowDays)
+
+	// Zero is a real late policy: no late deduction and no late window
+	zeroPenalty, zeroDays := 0.0, 0
+	req.MaxLatePenalty, req.LateWindowDays = &zeroPenalty, &zeroDays
+	resp, err = service.CreateAssignment(context.Background(), req)
+	assert.NoError(t, err)
+	var stored models.Assignment
+	assert.NoError(t, db.First(&stored, "id = ?", resp.ID).Error)
+	assert.Equal(t, 0.0, stored.MaxLatePenalty)
+	assert.Equal(t, 0, stored.LateWindowDays)
 }
 
 func TestAssignmentService_GetAssignment(t *testing.T) {
@@ -95,7 +107,7 @@ func TestAssignmentService_SubmitAssignment(t *testing.T) {
 		Content: "My submission content",
 	}
 
-	resp, err := service.SubmitAssignment(context.Background(), assignmentID, studentID, req)
+	resp, err := service.SubmitAssignment(context.Background(), assignmentID, studentID, req, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -103,3 +115,161 @@ func TestAssignmentService_SubmitAssignment(t *testing.T) {
 	assert.Equal(t, "My submission content", resp.Content)
 	assert.False(t, resp.IsLate)
 }
+
+func TestAssignmentService_ResubmissionAttempts(t *testing.T) {
+	db := setupTestDB()
+	service := NewAssignmentService(db)
+	ctx := context.Background()
+
+	studentID := uuid.New()
+	assignment := models.Assignment{
+		ID:           uuid.New(),
+		GroupID:      uuid.New(),
+		CourseID:     uuid.New(),
+		TeacherID:    uuid.New(),
+		Title:        "Homework 2",
+		Type:         models.AssignmentTypeHomework,
+		Status:       models.AssignmentPublished,
+		AssignedDate: time.Now(),
+		DueDate:      time.Now().Add(24 * time.Hour),
+		MaxPoints:    100,
+		AllowLate:    true,
+		MaxAttempts:  2,
+	}
+	db.Create(&assignment)
+
+	first, err := service.SubmitAssignment(ctx, assignment.ID, studentID, dto.SubmitAssignmentRequest{Content: "draft"}, nil, nil)
+	assert.NoError(t, err)
+	second, err := service.SubmitAssignment(ctx, assignment.ID, studentID, dto.SubmitAssignmentRequest{Content: "final"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.AttemptNumber)
+
+	// The assignment allows two attempts
+	_, err = service.SubmitAssignment(ctx, assignment.ID, studentID, dto.SubmitAssignmentRequest{Content: "third"}, nil, nil)
+	assert.Error(t, err)
+
+	// Earlier drafts are kept
+	attempts, err := service.GetSubmissions(ctx, assignment.ID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 2)
+	assert.Equal(t, "draft", attempts[0].Content)
+
+	// Grading picks the attempt that counts
+	_, err = service.GradeSubmission(ctx, second.ID, uuid.New(), dto.GradeSubmissionRequest{Points: 70})
+	assert.NoError(t, err)
+	graded, err := service.GradeSubmission(ctx, first.ID, uuid.New(), dto.GradeSubmissionRequest{Points: 90})
+	assert.NoError(t, err)
+	assert.True(t, graded.IsFinal)
+
+	var secondAttempt models.AssignmentSubmission
+	db.First(&secondAttempt, "id = ?", second.ID)
+	assert.False(t, secondAttempt.IsFinal)
+
+	resp, err := service.GetAssignment(ctx, assignment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.SubmissionStats.TotalSubmitted)
+	assert.Equal(t, 1, resp.SubmissionStats.TotalGraded)
+	assert.Equal(t, 90.0, resp.SubmissionStats.AverageScore)
+}
+
+func TestAssignmentService_LatePenaltyAndClosing(t *testing.T) {
+	db := setupTestDB()
+	service := NewAssignmentService(db)
+	ctx := context.Background()
+
+	groupID := uuid.New()
+	submitter := models.Student{GroupID: groupID, Name: "Ali", Surname: "Valiev", Phone: "992900000011"}
+	db.Create(&submitter)
+	absentee := models.Student{GroupID: groupID, Name: "Vali", Surname: "Aliev", Phone: "992900000012"}
+	db.Create(&absentee)
+
+	newAssignment := func(due time.Time) models.Assignment {
+		assignment := models.Assignment{
+			ID:             uuid.New(),
+			GroupID:        groupID,
+			CourseID:       uuid.New(),
+			TeacherID:      uuid.New(),
+			Title:          "Essay",
+			Type:           models.AssignmentTypeHomework,
+			Status:         models.AssignmentPublished,
+			AssignedDate:   due.AddDate(0, 0, -7),
+			DueDate:        due,
+			MaxPoints:      100,
+			AllowLate:      true,
+			LatePenalty:    10,
+			MaxLatePenalty: 25,
+			LateWindowDays: 7,
+		}
+		db.Create(&assignment)
+		return assignment
+	}
+
+	// Two and a half days late counts as three days; 30% is capped at 25%
+	open := newAssignment(time.Now().Add(-60 * time.Hour))
+	late, err := service.SubmitAssignment(ctx, open.ID, submitter.ID, dto.SubmitAssignmentRequest{Content: "late essay"}, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, late.IsLate)
+	assert.Equal(t, 3, late.DaysLate)
+
+	graded, err := service.GradeSubmission(ctx, late.ID, uuid.New(), dto.GradeSubmissionRequest{Points: 90})
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, graded.PenaltyApplied)
+	assert.Equal(t, 65.0, *graded.Points)
+
+	// Past the late window nothing is accepted any more
+	expired := newAssignment(time.Now().AddDate(0, 0, -10))
+	_, err = service.SubmitAssignment(ctx, expired.ID, submitter.ID, dto.SubmitAssignmentRequest{Content: "too late"}, nil, nil)
+	assert.Error(t, err)
+	db.Create(&models.AssignmentSubmission{ID: uuid.New(), AssignmentID: expired.ID, StudentID: submitter.ID, Status: models.SubmissionSubmitted})
+
+	resp, err := service.CloseDueAssignments(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.TotalClosed)
+	assert.Equal(t, []uuid.UUID{expired.ID}, resp.ClosedAssignmentIDs)
+	assert.Equal(t, 1, resp.MissingSubmissions)
+
+	var missing models.AssignmentSubmission
+	assert.NoError(t, db.First(&missing, "assignment_id = ? AND student_id = ?", expired.ID, absentee.ID).Error)
+	assert.Equal(t, models.SubmissionPending, missing.Status)
+	assert.Equal(t, 0.0, *missing.Points)
+
+	var closed models.Assignment
+	db.First(&closed, "id = ?", expired.ID)
+	assert.Equal(t, models.AssignmentClosed, closed.Status)
+	assert.NotNil(t, closed.ClosedDate)
+
+	_, err = service.SubmitAssignment(ctx, expired.ID, absentee.ID, dto.SubmitAssignmentRequest{Content: "after close"}, nil, nil)
+	assert.Error(t, err)
+
+	// Running again does not close anything twice
+	resp, err = service.CloseDueAssignments(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.TotalClosed)
+}
+
+func TestBackfillAssignmentLatePolicy(t *testing.T) {
+	db := setupTestDB()
+
+	// An assignment stored before the late policy columns existed
+	assert.NoError(t, db.Migrator().DropColumn(&models.Assignment{}, "max_late_penalty"))
+	assert.NoError(t, db.Migrator().DropColumn(&models.Assignment{}, "late_window_days"))
+	legacy := uuid.New()
+	assert.NoError(t, db.Exec("INSERT INTO assignments (id, group_id, course_id, teacher_id, title, type, status, assigned_date, due_date, allow_late) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		legacy, uuid.New(), uuid.New(), uuid.New(), "Essay", models.AssignmentTypeHomework, models.AssignmentPublished, time.Now(), time.Now(), true).Error)
+	assert.NoError(t, db.AutoMigrate(&models.Assignment{}))
+
+	// An explicit zero policy is left alone
+	zero := models.Assignment{ID: uuid.New(), GroupID: uuid.New(), CourseID: uuid.New(), TeacherID: uuid.New(), Title: "Strict",
+		Type: models.AssignmentTypeHomework, AssignedDate: time.Now(), DueDate: time.Now()}
+	assert.NoError(t, db.Create(&zero).Error)
+
+	assert.NoError(t, BackfillAssignmentLatePolicy(db))
+	var stored models.Assignment
+	db.First(&stored, "id = ?", legacy)
+	assert.Equal(t, 100.0, stored.MaxLatePenalty)
+	assert.Equal(t, 7, stored.LateWindowDays)
+	var strict models.Assignment
+	db.First(&strict, "id = ?", zero.ID)
+	assert.Equal(t, 0.0, strict.MaxLatePenalty)
+	assert.Equal(t, 0, strict.LateWindowDays)
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// alertTemplate is the default wording of an alert type. An active notification
+// template with the same name overrides it.
+type alertTemplate struct {
+	name    string
+	subject string
+	body    string
+}
+
+// defaultAlertTemplates may use {{student_name}}, {{group_name}}, {{date}},
+// {{streak}}, {{attendance_rate}}, {{window_days}} and {{threshold}}
+var defaultAlertTemplates = map[models.AttendanceAlertType]alertTemplate{
+	models.AlertAbsence: {
+		name:    "attendance_absence",
+		subject: "{{student_name}} was absent on {{date}}",
+		body:    "{{student_name}} was marked absent from {{group_name}} on {{date}}.",
+	},
+	models.AlertLate: {
+		name:    "attendance_late",
+		subject: "{{student_name}} was late on {{date}}",
+		body:    "{{student_name}} arrived late to {{group_name}} on {{date}}.",
+	},
+	models.AlertAbsenceStreak: {
+		name:    "attendance_absence_streak",
+		subject: "{{student_name}} missed {{streak}} lessons in a row",
+		body:    "{{student_name}} ({{group_name}}) has been absent from {{streak}} consecutive lessons, most recently on {{date}}.",
+	},
+	models.AlertLowAttendance: {
+		name:    "attendance_low_attendance",
+		subject: "{{student_name}} attendance at {{attendance_rate}}%",
+		body:    "{{student_name}} ({{group_name}}) attended {{attendance_rate}}% of lessons in the last {{window_days}} days, below the {{threshold}}% threshold.",
+	},
+}
+
+// AttendanceAlertService alerts parents about absences and late arrivals and
+// escalates absence streaks and low attendance to teachers and admins
+type AttendanceAlertService struct {
+	db            *gorm.DB
+	notifications *NotificationService
+}
+
+// NewAttendanceAlertService creates a new attendance alert service
+func NewAttendanceAlertService(db *gorm.DB) *AttendanceAlertService {
+	return &AttendanceAlertService{
+		db:            db,
+		notifications: NewNotificationService(db),
+	}
+}
+
+// Process runs the alert rules for a saved attendance record and returns the
+// alerts raised. Parents are alerted once per date; escalations are raised
+// when a threshold is crossed.
+func (s *AttendanceAlertService) Process(ctx context.Context, attendance *models.Attendance) ([]models.AttendanceAlert, error) {
+	if attendance.Status != models.StatusAbsent && attendance.Status != models.StatusLate {
+		return nil, nil
+	}
+
+	settings, err := loadAttendanceAlertSettings(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var student models.Student
+	if err := s.db.First(&student, "id = ?", attendance.StudentID).Error; err != nil {
+		return nil, errors.DatabaseError("finding student", err)
+	}
+	var group models.Group
+	if err := s.db.First(&group, "id = ?", attendance.GroupID).Error; err != nil {
+		return nil, errors.DatabaseError("finding group", err)
+	}
+
+	vars := map[string]string{
+		"student_name": student.Name + " " + student.Surname,
+		"group_name":   group.Name,
+		"date":         attendance.Date.Format("2006-01-02"),
+	}
+
+	alerts := make([]models.AttendanceAlert, 0)
+	raise := func(alert *models.AttendanceAlert, err error) error {
+		if alert != nil {
+			alerts = append(alerts, *alert)
+		}
+		return err
+	}
+
+	alertType := models.AlertAbsence
+	if attendance.Status == models.StatusLate {
+		alertType = models.AlertLate
+	}
+	if settings.ParentAlertsEnabled && (alertType == models.AlertAbsence || settings.AlertOnLate) {
+		if err := raise(s.alertParents(ctx, attendance, &student, alertType, vars)); err != nil {
+			return alerts, err
+		}
+	}
+
+	if attendance.Status != models.StatusAbsent {
+		return alerts, nil
+	}
+
+	if settings.ConsecutiveAbsences > 0 {
+		if err := raise(s.checkAbsenceStreak(ctx, attendance, &group, settings, vars)); err != nil {
+			return alerts, err
+		}
+	}
+	if settings.LowAttendancePercent > 0 {
+		if err := raise(s.checkLowAttendance(ctx, attendance, &group, settings, vars)); err != nil {
+			return alerts, err
+		}
+	}
+	return alerts, nil
+}
+
+// GetByStudent lists the alerts logged against a student
+func (s *AttendanceAlertService) GetByStudent(ctx context.Context, studentID uuid.UUID) ([]dto.AttendanceAlertResponse, error) {
+	var alerts []models.AttendanceAlert
+	if err := s.db.Preload("Student").
+		Where("student_id = ?", studentID).
+		Order("date DESC, created_at DESC").
+		Find(&alerts).Error; err != nil {
+		return nil, errors.DatabaseError("fetching attendance alerts", err)
+	}
+	return s.toResponses(alerts), nil
+}
+
+// GetByGroup lists the alerts raised in a group, optionally only escalations
+func (s *AttendanceAlertService) GetByGroup(ctx context.Context, groupID uuid.UUID, escalatedOnly bool) ([]dto.AttendanceAlertResponse, error) {
+	query := s.db.Preload("Student").Where("group_id = ?", groupID)
+	if escalatedOnly {
+		query = query.Where("escalated = ?", true)
+	}
+
+	var alerts []models.AttendanceAlert
+	if err := query.Order("date DESC, created_at DESC").Find(&alerts).Error; err != nil {
+		return nil, errors.DatabaseError("fetching attendance alerts", err)
+	}
+	return s.toResponses(alerts), nil
+}
+
+// GetSettings returns the alert thresholds
+func (s *AttendanceAlertService) GetSettings(ctx context.Context) (*dto.AttendanceAlertSettingsResponse, error) {
+	settings, err := loadAttendanceAlertSettings(s.db)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSettingsResponse(settings), nil
+}
+
+// UpdateSettings changes the alert thresholds
+func (s *AttendanceAlertService) UpdateSettings(ctx context.Context, req dto.UpdateAttendanceAlertSettingsRequest) (*dto.AttendanceAlertSettingsResponse, error) {
+	settings, err := loadAttendanceAlertSettings(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ParentAlertsEnabled != nil {
+		settings.ParentAlertsEnabled = *req.ParentAlertsEnabled
+	}
+	if req.AlertOnLate != nil {
+		settings.AlertOnLate = *req.AlertOnLate
+	}
+	if req.ConsecutiveAbsences != nil {
+		settings.ConsecutiveAbsences = *req.ConsecutiveAbsences
+	}
+	if req.LowAttendancePercent != nil {
+		settings.LowAttendancePercent = *req.LowAttendancePercent
+	}
+	if req.LowAttendanceWindowDays != nil {
+		settings.LowAttendanceWindowDays = *req.LowAttendanceWindowDays
+	}
+	if req.LowAttendanceMinRecords != nil {
+		settings.LowAttendanceMinRecords = *req.LowAttendanceMinRecords
+	}
+	if req.EscalateToTeacher != nil {
+		settings.EscalateToTeacher = *req.EscalateToTeacher
+	}
+	if req.EscalateToAdmins != nil {
+		settings.EscalateToAdmins = *req.EscalateToAdmins
+	}
+
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+		err = s.db.Create(settings).Error
+	} else {
+		err = s.db.Save(settings).Error
+	}
+	if err != nil {
+		return nil, errors.DatabaseError("saving attendance alert settings", err)
+	}
+	return s.toSettingsResponse(settings), nil
+}
+
+// alertParents notifies the student's parents about an absence or late
+// arrival, once per student and date
+func (s *AttendanceAlertService) alertParents(ctx context.Context, attendance *models.Attendance, student *models.Student, alertType models.AttendanceAlertType, vars map[string]string) (*models.AttendanceAlert, error) {
+	var count int64
+	if err := s.db.Model(&models.AttendanceAlert{}).
+		Where("student_id = ? AND date = ? AND type IN ?", attendance.StudentID, attendance.Date,
+			[]models.AttendanceAlertType{models.AlertAbsence, models.AlertLate}).
+		Count(&count).Error; err != nil {
+		return nil, errors.DatabaseError("checking attendance alerts", err)
+	}
+	if count > 0 {
+		return nil, nil
+	}
+
+	parents, err := linkedParentsWithPreference(s.db, student.ID, "")
+	if err != nil {
+		return nil, errors.DatabaseError("finding parents", err)
+	}
+	contacts := make([]alertContact, 0, len(parents))
+	for _, parent := range parents {
+		contacts = append(contacts, alertContact{email: parent.Email, phone: parent.Phone})
+	}
+
+	return s.raise(ctx, attendance, alertType, false, contacts, vars)
+}
+
+// checkAbsenceStreak escalates when the student's run of consecutive absences
+// in the group reaches the threshold, once per student and date. Excused
+// absences neither count nor break the run; longer runs are not re-escalated.
+func (s *AttendanceAlertService) checkAbsenceStreak(ctx context.Context, attendance *models.Attendance, group *models.Group, settings *models.AttendanceAlertSettings, vars map[string]string) (*models.AttendanceAlert, error) {
+	var recent []models.Attendance
+	if err := s.db.Where("student_id = ? AND group_id = ? AND date <= ? AND status <> ?",
+		attendance.StudentID, attendance.GroupID, attendance.Date, models.StatusExcused).
+		Order("date DESC").
+		Limit(settings.ConsecutiveAbsences + 1).
+		Find(&recent).Error; err != nil {
+		return nil, errors.DatabaseError("fetching attendance", err)
+	}
+
+	streak := 0
+	for _, record := range recent {
+		if record.Status != models.StatusAbsent {
+			break
+		}
+		streak++
+	}
+	if streak != settings.ConsecutiveAbsences {
+		return nil, nil
+	}
+
+	// Re-marking the day must not escalate the same streak again
+	var count int64
+	if err := s.db.Model(&models.AttendanceAlert{}).
+		Where("student_id = ? AND date = ? AND type = ?", attendance.StudentID, attendance.Date, models.AlertAbsenceStreak).
+		Count(&count).Error; err != nil {
+		return nil, errors.DatabaseError("checking attendance alerts", err)
+	}
+	if count > 0 {
+		return nil, nil
+	}
+
+	vars["streak"] = fmt.Sprintf("%d", streak)
+	return s.escalate(ctx, attendance, group, models.AlertAbsenceStreak, settings, vars)
+}
+
+// checkLowAttendance escalates when the student's attendance rate over the
+// window falls below the threshold, at most once per window. Late arrivals
+// count as attended and excused absences are left out.
+func (s *AttendanceAlertService) checkLowAttendance(ctx context.Context, attendance *models.Attendance, group *models.Group, settings *models.AttendanceAlertSettings, vars map[string]string) (*models.AttendanceAlert, error) {
+	windowStart := attendance.Date.AddDate(0, 0, -settings.LowAttendanceWindowDays+1)
+
+	var records []models.Attendance
+	if err := s.db.Where("student_id = ? AND group_id = ? AND date >= ? AND date <= ? AND status <> ?",
+		attendance.StudentID, attendance.GroupID, windowStart, attendance.Date, models.StatusExcused).
+		Find(&records).Error; err != nil {
+		return nil, errors.DatabaseError("fetching attendance", err)
+	}
+	if len(records) == 0 || len(records) < settings.LowAttendanceMinRecords {
+		return nil, nil
+	}
+
+	attended := 0
+	for _, record := range records {
+		if record.Status == models.StatusPresent || record.Status == models.StatusLate {
+			attended++
+		}
+	}
+	rate := float64(attended) / float64(len(records)) * 100
+	if rate >= settings.LowAttendancePercent {
+		return nil, nil
+	}
+
+	var count int64
+	if err := s.db.Model(&models.AttendanceAlert{}).
+		Where("student_id = ? AND group_id = ? AND type = ? AND date >= ?",
+			attendance.StudentID, attendance.GroupID, models.AlertLowAttendance, windowStart).
+		Count(&count).Error; err != nil {
+		return nil, errors.DatabaseError("checking attendance alerts", err)
+	}
+	if count > 0 {
+		return nil, nil
+	}
+
+	vars["attendance_rate"] = fmt.Sprintf("%.0f", rate)
+	vars["window_days"] = fmt.Sprintf("%d", settings.LowAttendanceWindowDays)
+	vars["threshold"] = fmt.Sprintf("%.0f", settings.LowAttendancePercent)
+	return s.escalate(ctx, attendance, group, models.AlertLowAttendance, settings, vars)
+}
+
+// escalate sends an alert to the group's teacher and the active admins
+func (s *AttendanceAlertService) escalate(ctx context.Context, attendance *models.Attendance, group *models.Group, alertType models.AttendanceAlertType, settings *models.AttendanceAlertSettings, vars map[string]string) (*models.AttendanceAlert, error) {
+	contacts := make([]alertContact, 0)
+	if settings.EscalateToTeacher {
+		var teacher models.Teacher
+		if err := s.db.First(&teacher, "id = ?", group.TeacherID).Error; err == nil {
+			contacts = append(contacts, alertContact{email: teacher.Email, phone: teacher.Phone})
+		}
+	}
+	if settings.EscalateToAdmins {
+		var admins []models.User
+		if err := s.db.Where("role = ? AND is_active = ?", models.RoleAdmin, true).Find(&admins).Error; err != nil {
+			return nil, errors.DatabaseError("finding admins", err)
+		}
+		for _, admin := range admins {
+			contacts = append(contacts, alertContact{email: admin.Email, phone: admin.Phone})
+		}
+	}
+
+	return s.raise(ctx, attendance, alertType, true, contacts, vars)
+}
+
+// alertContact is a person an alert is delivered to
+type alertContact struct {
+	email string
+	phone string
+}
+
+// raise renders the alert, delivers it to each contact and logs it against the student
+func (s *AttendanceAlertService) raise(ctx context.Context, attendance *models.Attendance, alertType models.AttendanceAlertType, escalated bool, contacts []alertContact, vars map[string]string) (*models.AttendanceAlert, error) {
+	subject, message := s.render(alertType, vars)
+
+	alert := models.AttendanceAlert{
+		ID:           uuid.New(),
+		StudentID:    attendance.StudentID,
+		GroupID:      attendance.GroupID,
+		AttendanceID: &attendance.ID,
+		Date:         attendance.Date,
+		Type:         alertType,
+		Escalated:    escalated,
+		Subject:      subject,
+		Message:      message,
+		Recipients:   make([]string, 0, len(contacts)),
+	}
+
+	seen := make(map[string]bool)
+	for _, contact := range contacts {
+		key := contact.email + "|" + contact.phone
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		recipient, ok := s.notifications.notifyContact(ctx, &attendance.StudentID, contact.email, contact.phone, subject, message)
+		if recipient == "" {
+			continue
+		}
+		alert.Recipients = append(alert.Recipients, recipient)
+		if ok {
+			alert.NotificationsSent++
+		}
+	}
+
+	if err := s.db.Create(&alert).Error; err != nil {
+		return nil, errors.DatabaseError("logging attendance alert", err)
+	}
+	return &alert, nil
+}
+
+// render fills in the alert's subject and message, preferring an active
+// notification template named after the alert type
+func (s *AttendanceAlertService) render(alertType models.AttendanceAlertType, vars map[string]string) (string, string) {
+	tmpl := defaultAlertTemplates[alertType]
+	subject, body := tmpl.subject, tmpl.body
+
+	var custom models.NotificationTemplate
+	if err := s.db.Where("name = ? AND is_active = ?", tmpl.name, true).First(&custom).Error; err == nil {
+		if custom.Subject != "" {
+			subject = custom.Subject
+		}
+		body = custom.Body
+	}
+
+	pairs := make([]string, 0, len(vars)*2)
+	for key, value := range vars {
+		pairs = append(pairs, "{{"+key+"}}", value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	return replacer.Replace(subject), replacer.Replace(body)
+}
+
+func (s *AttendanceAlertService) toResponses(alerts []models.AttendanceAlert) []dto.AttendanceAlertResponse {
+	responses := make([]dto.AttendanceAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = dto.AttendanceAlertResponse{
+			ID:                alert.ID,
+			StudentID:         alert.StudentID,
+			GroupID:           alert.GroupID,
+			AttendanceID:      alert.AttendanceID,
+			Date:              alert.Date.Format("2006-01-02"),
+			Type:              alert.Type,
+			Escalated:         alert.Escalated,
+			Subject:           alert.Subject,
+			Message:           alert.Message,
+			Recipients:        alert.Recipients,
+			NotificationsSent: alert.NotificationsSent,
+			CreatedAt:         alert.CreatedAt,
+		}
+		if alert.Student != nil {
+			responses[i].StudentName = alert.Student.Name + " " + alert.Student.Surname
+		}
+	}
+	return responses
+}
+
+func (s *AttendanceAlertService) toSettingsResponse(settings *models.AttendanceAlertSettings) *dto.AttendanceAlertSettingsResponse {
+	return &dto.AttendanceAlertSettingsResponse{
+		ParentAlertsEnabled:     settings.ParentAlertsEnabled,
+		AlertOnLate:             settings.AlertOnLate,
+		ConsecutiveAbsences:     settings.ConsecutiveAbsences,
+		LowAttendancePercent:    settings.LowAttendancePercent,
+		LowAttendanceWindowDays: settings.LowAttendanceWindowDays,
+		LowAttendanceMinRecords: settings.LowAttendanceMinRecords,
+		EscalateToTeacher:       settings.EscalateToTeacher,
+		EscalateToAdmins:        settings.EscalateToAdmins,
+		UpdatedAt:               settings.UpdatedAt,
+	}
+}
+
+// loadAttendanceAlertSettings returns the stored alert settings, or the
+// defaults if none have been saved yet
+func loadAttendanceAlertSettings(db *gorm.DB) (*models.AttendanceAlertSettings, error) {
+	var settings models.AttendanceAlertSettings
+	if err := db.First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			defaults := models.DefaultAttendanceAlertSettings()
+			return &defaults, nil
+		}
+		return nil, errors.DatabaseError("loading attendance alert settings", err)
+	}
+	return &settings, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttendanceAlertService_ParentAlertsAndStreak(t *testing.T) {
+	db := setupTestDB()
+	attendance := NewAttendanceService(db)
+	alerts := NewAttendanceAlertService(db)
+	ctx := context.Background()
+
+	teacher := models.Teacher{Name: "Olim", Surname: "Karimov", Phone: "992900000009", Email: "teacher@example.com"}
+	db.Create(&teacher)
+	timetable := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed,Fri"}
+	db.Create(&timetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, TeacherID: teacher.ID, Capacity: 10}
+	db.Create(&group)
+	student := models.Student{GroupID: group.ID, Name: "Ali", Surname: "Valiev", Phone: "992900000001"}
+	db.Create(&student)
+	parent := models.Parent{ID: uuid.New(), FirstName: "Parent", LastName: "One", Email: "parent@example.com", Phone: "992900000002", IsActive: true, ReceiveNotifications: true}
+	db.Create(&parent)
+	db.Create(&models.ParentStudent{ID: uuid.New(), ParentID: parent.ID, StudentID: student.ID, Relation: models.RelationMother})
+
+	mark := func(date, status string) {
+		_, err := attendance.MarkAttendance(ctx, group.ID.String(), dto.CreateAttendanceRequest{
+			StudentID: student.ID, Date: date, Status: status,
+		})
+		assert.NoError(t, err)
+	}
+
+	// 2026-03-02, 04 and 06 are Monday, Wednesday and Friday
+	mark("2026-03-02", "absent")
+	mark("2026-03-02", "late") // same day: parents are not alerted twice
+	mark("2026-03-02", "absent")
+	mark("2026-03-04", "absent")
+
+	logged, err := alerts.GetByStudent(ctx, student.ID)
+	assert.NoError(t, err)
+	assert.Len(t, logged, 2)
+	assert.Equal(t, []string{"parent@example.com"}, logged[0].Recipients)
+	assert.False(t, logged[0].Escalated)
+
+	// Third absence in a row escalates to the teacher
+	mark("2026-03-06", "absent")
+	escalated, err := alerts.GetByGroup(ctx, group.ID, true)
+	assert.NoError(t, err)
+	assert.Len(t, escalated, 1)
+	assert.Equal(t, models.AlertAbsenceStreak, escalated[0].Type)
+	assert.Contains(t, escalated[0].Recipients, "teacher@example.com")
+	assert.Contains(t, escalated[0].Message, "3 consecutive lessons")
+
+	// Re-marking the absence does not escalate the streak again
+	mark("2026-03-06", "absent")
+	var record models.Attendance
+	assert.NoError(t, db.Where("student_id = ? AND date = ?", student.ID, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)).First(&record).Error)
+	raised, err := alerts.Process(ctx, &record)
+	assert.NoError(t, err)
+	assert.Empty(t, raised)
+	escalated, err = alerts.GetByGroup(ctx, group.ID, true)
+	assert.NoError(t, err)
+	assert.Len(t, escalated, 1)
+}
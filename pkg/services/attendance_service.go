@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
 )
@@ -17,15 +18,20 @@ type AttendanceService interface {
 	BatchMarkAttendance(ctx context.Context, groupID string, req dto.BatchAttendanceRequest) ([]dto.AttendanceResponse, error)
 	GetGroupAttendance(ctx context.Context, groupID string, date string) ([]dto.AttendanceResponse, error)
 	GetStudentAttendance(ctx context.Context, studentID string, groupID string) ([]dto.AttendanceResponse, error)
+	GetAttendanceSheet(ctx context.Context, groupID string, date string, sessionID *uuid.UUID) (*dto.AttendanceSheetResponse, error)
 }
 
 type attendanceService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	alerts *AttendanceAlertService
 }
 
 // NewAttendanceService creates a new attendance service
 func NewAttendanceService(db *gorm.DB) AttendanceService {
-	return &attendanceService{db: db}
+	return &attendanceService{
+		db:     db,
+		alerts: NewAttendanceAlertService(db),
+	}
 }
 
 func (s *attendanceService) MarkAttendance(ctx context.Context, groupID string, req dto.CreateAttendanceRequest) (*dto.AttendanceResponse, error) {
@@ -52,21 +58,33 @@ func (s *attendanceService) MarkAttendance(ctx context.Context, groupID string,
 		return nil, errors.Validation("Invalid date format (YYYY-MM-DD)")
 	}
 
+	// Attendance is recorded against a lesson; reject dates without one
+	session, err := sessionForAttendance(s.db, group.ID, date, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := markSessionHeld(s.db, session); err != nil {
+		return nil, err
+	}
+
 	// Check if attendance already exists
 	var existing models.Attendance
-	if err := s.db.Where("student_id = ? AND group_id = ? AND date = ?", req.StudentID, groupID, date).First(&existing).Error; err == nil {
+	if err := findSessionAttendance(s.db, req.StudentID, session).First(&existing).Error; err == nil {
 		// Update existing
+		existing.SessionID = &session.ID
 		existing.Status = models.AttendanceStatus(req.Status)
 		existing.Notes = req.Notes
 		if err := s.db.Save(&existing).Error; err != nil {
 			return nil, errors.DatabaseError("updating attendance", err)
 		}
+		raiseAttendanceAlerts(ctx, s.alerts, &existing)
 		return s.toResponse(&existing), nil
 	}
 
 	attendance := models.Attendance{
 		StudentID: req.StudentID,
 		GroupID:   uuid.MustParse(groupID),
+		SessionID: &session.ID,
 		Date:      date,
 		Status:    models.AttendanceStatus(req.Status),
 		Notes:     req.Notes,
@@ -75,6 +93,7 @@ func (s *attendanceService) MarkAttendance(ctx context.Context, groupID string,
 	if err := s.db.Create(&attendance).Error; err != nil {
 		return nil, errors.DatabaseError("creating attendance", err)
 	}
+	raiseAttendanceAlerts(ctx, s.alerts, &attendance)
 
 	// Preload student for response
 	attendance.Student = student
@@ -98,9 +117,20 @@ func (s *attendanceService) BatchMarkAttendance(ctx context.Context, groupID str
 	}
 
 	var responses []dto.AttendanceResponse
+	var saved []models.Attendance
+
+	// Attendance is recorded against a lesson; reject dates without one
+	session, err := sessionForAttendance(s.db, group.ID, date, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Use transaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := markSessionHeld(tx, session); err != nil {
+			return err
+		}
+
 		for _, item := range req.Attendances {
 			// Validate student belongs to group
 			var student models.Student
@@ -110,8 +140,9 @@ func (s *attendanceService) BatchMarkAttendance(ctx context.Context, groupID str
 
 			var attendance models.Attendance
 			// Check existing
-			if err := tx.Where("student_id = ? AND group_id = ? AND date = ?", item.StudentID, groupID, date).First(&attendance).Error; err == nil {
+			if err := findSessionAttendance(tx, item.StudentID, session).First(&attendance).Error; err == nil {
 				// Update
+				attendance.SessionID = &session.ID
 				attendance.Status = models.AttendanceStatus(item.Status)
 				attendance.Notes = item.Notes
 				if err := tx.Save(&attendance).Error; err != nil {
@@ -122,6 +153,7 @@ func (s *attendanceService) BatchMarkAttendance(ctx context.Context, groupID str
 				attendance = models.Attendance{
 					StudentID: item.StudentID,
 					GroupID:   uuid.MustParse(groupID),
+					SessionID: &session.ID,
 					Date:      date,
 					Status:    models.AttendanceStatus(item.Status),
 					Notes:     item.Notes,
@@ -133,14 +165,23 @@ func (s *attendanceService) BatchMarkAttendance(ctx context.Context, groupID str
 
 			attendance.Student = student
 			responses = append(responses, *s.toResponse(&attendance))
+			saved = append(saved, attendance)
 		}
 		return nil
 	})
 
 	if err != nil {
+		if _, ok := err.(*errors.AppError); ok {
+			return nil, err
+		}
 		return nil, errors.DatabaseError("processing batch attendance", err)
 	}
 
+	// Alerts go out only once the whole batch is committed
+	for i := range saved {
+		raiseAttendanceAlerts(ctx, s.alerts, &saved[i])
+	}
+
 	return responses, nil
 }
 
@@ -189,11 +230,113 @@ func (s *attendanceService) GetStudentAttendance(ctx context.Context, studentID
 	return responses, nil
 }
 
+// GetAttendanceSheet returns the roster of a group's lesson on a date: the
+// enrolled students with their marks so far, and prospects booked for a trial
+// lesson as guests
+func (s *attendanceService) GetAttendanceSheet(ctx context.Context, groupID string, dateStr string, sessionID *uuid.UUID) (*dto.AttendanceSheetResponse, error) {
+	gid, err := uuid.Parse(groupID)
+	if err != nil {
+		return nil, errors.BadRequest("Invalid group ID")
+	}
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, errors.Validation("Invalid date format (YYYY-MM-DD)")
+	}
+
+	session, err := sessionForAttendance(s.db, gid, date, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var students []models.Student
+	if err := s.db.Where("group_id = ?", gid).Order("surname ASC, name ASC").Find(&students).Error; err != nil {
+		return nil, errors.DatabaseError("fetching group students", err)
+	}
+	var attendances []models.Attendance
+	if err := s.db.Where("group_id = ? AND (session_id = ? OR (session_id IS NULL AND date = ?))", gid, session.ID, session.Date).
+		Find(&attendances).Error; err != nil {
+		return nil, errors.DatabaseError("fetching lesson attendance", err)
+	}
+	var trials []models.TrialLesson
+	if err := s.db.Where("session_id = ? AND status <> ?", session.ID, models.TrialCancelled).
+		Order("prospect_name ASC").Find(&trials).Error; err != nil {
+		return nil, errors.DatabaseError("fetching trial guests", err)
+	}
+
+	marked := make(map[uuid.UUID]*models.Attendance, len(attendances))
+	for i := range attendances {
+		marked[attendances[i].StudentID] = &attendances[i]
+	}
+
+	sheet := &dto.AttendanceSheetResponse{
+		GroupID:   gid,
+		SessionID: session.ID,
+		TeacherID: session.TeacherID,
+		Date:      session.Date.Format("2006-01-02"),
+		StartTime: session.StartTime,
+		EndTime:   session.EndTime,
+		Room:      session.Room,
+		Students:  make([]dto.AttendanceSheetEntry, len(students)),
+		Guests:    make([]dto.AttendanceSheetGuest, len(trials)),
+	}
+	for i, st := range students {
+		entry := dto.AttendanceSheetEntry{
+			Student: dto.StudentSimple{ID: st.ID, Name: st.Name, Surname: st.Surname, Phone: st.Phone},
+		}
+		if a, ok := marked[st.ID]; ok {
+			entry.AttendanceID = &a.ID
+			entry.Status = string(a.Status)
+			entry.Notes = a.Notes
+		}
+		sheet.Students[i] = entry
+	}
+	for i, t := range trials {
+		sheet.Guests[i] = dto.AttendanceSheetGuest{
+			TrialID: t.ID,
+			Name:    t.ProspectName,
+			Phone:   t.ProspectPhone,
+			Status:  t.Status,
+			Outcome: t.Outcome,
+		}
+	}
+
+	return sheet, nil
+}
+
+// raiseAttendanceAlerts runs the alert pipeline for a saved record. Alert
+// failures are logged rather than failing the attendance mark.
+func raiseAttendanceAlerts(ctx context.Context, alerts *AttendanceAlertService, attendance *models.Attendance) {
+	if _, err := alerts.Process(ctx, attendance); err != nil {
+		logger.Error("failed to process attendance alerts", err)
+	}
+}
+
+// findSessionAttendance scopes a query to a student's record for a lesson,
+// including records made before attendance was tied to lessons
+func findSessionAttendance(db *gorm.DB, studentID uuid.UUID, session *models.ClassSession) *gorm.DB {
+	return db.Where("student_id = ? AND group_id = ? AND (session_id = ? OR (session_id IS NULL AND date = ?))",
+		studentID, session.GroupID, session.ID, session.Date)
+}
+
+// markSessionHeld marks a scheduled lesson as held once attendance is taken
+func markSessionHeld(db *gorm.DB, session *models.ClassSession) error {
+	if session.Status != models.SessionScheduled {
+		return nil
+	}
+	if err := db.Model(&models.ClassSession{}).Where("id = ?", session.ID).
+		Update("status", models.SessionHeld).Error; err != nil {
+		return errors.DatabaseError("updating class session status", err)
+	}
+	session.Status = models.SessionHeld
+	return nil
+}
+
 func (s *attendanceService) toResponse(a *models.Attendance) *dto.AttendanceResponse {
 	return &dto.AttendanceResponse{
 		ID:        a.ID,
 		StudentID: a.StudentID,
 		GroupID:   a.GroupID,
+		SessionID: a.SessionID,
 		Date:      a.Date.Format("2006-01-02"),
 		Status:    string(a.Status),
 		Notes:     a.Notes,
@@ -13,12 +13,16 @@ import (
 
 // BulkService handles bulk operations
 type BulkService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	alerts *AttendanceAlertService
 }
 
 // NewBulkService creates a new bulk service
 func NewBulkService(db *gorm.DB) *BulkService {
-	return &BulkService{db: db}
+	return &BulkService{
+		db:     db,
+		alerts: NewAttendanceAlertService(db),
+	}
 }
 
 // BulkCreateStudents creates multiple students at once using batch insert for performance
@@ -50,6 +54,11 @@ func (s *BulkService) BulkCreateStudents(ctx context.Context, req dto.BulkCreate
 
 	// Use transaction with batch insert for better performance
 	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// The group must have a seat for every student; it stays locked until they are created
+		if _, err := allocateSeats(tx, req.GroupID, len(students), nil); err != nil {
+			return err
+		}
+
 		// CreateInBatches for optimal performance with large datasets
 		// Batch size of 100 balances memory usage and insert speed
 		if err := tx.CreateInBatches(students, 100).Error; err != nil {
@@ -102,54 +111,71 @@ func (s *BulkService) BulkMarkAttendance(ctx context.Context, req dto.BulkAttend
 		return nil, fmt.Errorf("group not found: %w", err)
 	}
 
+	// Attendance is recorded against a lesson; resolve it once for the batch
+	session, err := sessionForAttendance(s.db, group.ID, date, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := markSessionHeld(s.db, session); err != nil {
+		return nil, err
+	}
+
 	// Process each attendance
 	for i, item := range req.Attendances {
-		// Check if attendance already exists for this student on this date
+		fail := func(err error) {
+			resp.TotalFailed++
+			resp.Failed = append(resp.Failed, dto.BulkFailedItem{
+				Index: i,
+				Error: err.Error(),
+				Data:  item,
+			})
+		}
+
+		var student models.Student
+		if err := s.db.First(&student, "id = ? AND group_id = ?", item.StudentID, group.ID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				fail(fmt.Errorf("student not found in this group"))
+			} else {
+				fail(err)
+			}
+			continue
+		}
+
+		// Check if attendance already exists for this student in this lesson
 		var existing models.Attendance
-		result := s.db.Where("student_id = ? AND date = ?", item.StudentID, date).First(&existing)
+		result := findSessionAttendance(s.db, item.StudentID, session).First(&existing)
 
 		if result.Error == nil {
 			// Update existing
+			existing.SessionID = &session.ID
 			existing.Status = models.AttendanceStatus(item.Status)
 			existing.Notes = item.Notes
 			if err := s.db.Save(&existing).Error; err != nil {
-				resp.TotalFailed++
-				resp.Failed = append(resp.Failed, dto.BulkFailedItem{
-					Index: i,
-					Error: err.Error(),
-					Data:  item,
-				})
+				fail(err)
 			} else {
 				resp.TotalMarked++
+				raiseAttendanceAlerts(ctx, s.alerts, &existing)
 			}
 		} else if result.Error == gorm.ErrRecordNotFound {
 			// Create new
 			attendance := models.Attendance{
 				ID:        uuid.New(),
 				StudentID: item.StudentID,
-				GroupID:   req.GroupID,
+				GroupID:   group.ID,
+				SessionID: &session.ID,
 				Date:      date,
 				Status:    models.AttendanceStatus(item.Status),
 				Notes:     item.Notes,
 			}
 			if err := s.db.Create(&attendance).Error; err != nil {
-				resp.TotalFailed++
-				resp.Failed = append(resp.Failed, dto.BulkFailedItem{
-					Index: i,
-					Error: err.Error(),
-					Data:  item,
-				})
+				fail(err)
 			} else {
 				resp.TotalMarked++
+				raiseAttendanceAlerts(ctx, s.alerts, &attendance)
 			}
 		} else {
 			// DB error
-			resp.TotalFailed++
-			resp.Failed = append(resp.Failed, dto.BulkFailedItem{
-				Index: i,
-				Error: result.Error.Error(),
-				Data:  item,
-			})
+			fail(result.Error)
 		}
 	}
 
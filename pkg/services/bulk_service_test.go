@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkService_BulkMarkAttendance(t *testing.T) {
+	db := setupTestDB()
+	bulk := NewBulkService(db)
+	ctx := context.Background()
+
+	timetable := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed"}
+	db.Create(&timetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	morning := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&morning)
+	other := models.Group{Name: "GO-2", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&other)
+
+	zarina := models.Student{ID: uuid.New(), Name: "Zarina", Surname: "Karimova", Phone: "992900000301", GroupID: morning.ID}
+	db.Create(&zarina)
+	karim := models.Student{ID: uuid.New(), Name: "Karim", Surname: "Saidov", Phone: "992900000302", GroupID: other.ID}
+	db.Create(&karim)
+
+	// 2026-03-02 is a Monday; Karim was already marked in his own group
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	theirs := models.Attendance{ID: uuid.New(), StudentID: karim.ID, GroupID: other.ID, Date: monday, Status: models.StatusPresent}
+	db.Create(&theirs)
+
+	req := dto.BulkAttendanceRequest{GroupID: morning.ID, Date: "2026-03-02", Attendances: []dto.StudentAttendanceItem{
+		{StudentID: zarina.ID, Status: "late"},
+		{StudentID: karim.ID, Status: "absent"},
+	}}
+	resp, err := bulk.BulkMarkAttendance(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.TotalMarked)
+	if assert.Equal(t, 1, resp.TotalFailed) {
+		assert.Equal(t, 1, resp.Failed[0].Index)
+	}
+
+	// The other group's record is left alone
+	db.First(&theirs, "id = ?", theirs.ID)
+	assert.Equal(t, models.StatusPresent, theirs.Status)
+	assert.Nil(t, theirs.SessionID)
+
+	var session models.ClassSession
+	assert.NoError(t, db.First(&session, "group_id = ? AND date = ?", morning.ID, monday).Error)
+	assert.Equal(t, models.SessionHeld, session.Status)
+	var marked models.Attendance
+	assert.NoError(t, db.First(&marked, "student_id = ?", zarina.ID).Error)
+	if assert.NotNil(t, marked.SessionID) {
+		assert.Equal(t, session.ID, *marked.SessionID)
+	}
+
+	// Marking again updates the lesson's record
+	req.Attendances = req.Attendances[:1]
+	req.Attendances[0].Status = "present"
+	_, err = bulk.BulkMarkAttendance(ctx, req)
+	assert.NoError(t, err)
+	var records []models.Attendance
+	db.Find(&records, "student_id = ?", zarina.ID)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, models.StatusPresent, records[0].Status)
+	}
+
+	// Tuesday has no lesson
+	req.Date = "2026-03-03"
+	_, err = bulk.BulkMarkAttendance(ctx, req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,515 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Feeds need zone rules even on hosts without them
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Days around today that a feed covers; recurring events are published as
+// rules and are not cut at the window
+const (
+	calendarFeedPastDays   = 30
+	calendarFeedFutureDays = 180
+)
+
+// CalendarFeedService publishes teacher, group, student and parent
+// calendars as iCalendar subscription feeds behind secret tokens
+type CalendarFeedService struct {
+	db  *gorm.DB
+	loc *time.Location
+}
+
+// NewCalendarFeedService creates a calendar feed service. Lesson times are
+// wall clock times of the school, so feeds are written in timezone.
+func NewCalendarFeedService(db *gorm.DB, timezone string) *CalendarFeedService {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		logger.Warnf("unknown calendar feed time zone %q, using UTC: %v", timezone, err)
+		loc = time.UTC
+	}
+	return &CalendarFeedService{db: db, loc: loc}
+}
+
+// Create publishes a new feed for a teacher, group, student or parent
+func (s *CalendarFeedService) Create(ctx context.Context, req dto.CreateCalendarFeedRequest, createdBy uuid.UUID) (*dto.CalendarFeedResponse, error) {
+	name, err := s.ownerName(req.OwnerType, req.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != "" {
+		name = req.Name
+	}
+	token, err := generateSecureToken(30)
+	if err != nil {
+		return nil, errors.Internal("generating feed token", err)
+	}
+
+	feed := models.CalendarFeed{
+		ID:        uuid.New(),
+		Token:     token,
+		OwnerType: req.OwnerType,
+		OwnerID:   req.OwnerID,
+		Name:      name,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.Create(&feed).Error; err != nil {
+		return nil, errors.DatabaseError("creating calendar feed", err)
+	}
+	return toCalendarFeedResponse(&feed), nil
+}
+
+// List returns feeds, newest first; revoked feeds only when asked for
+func (s *CalendarFeedService) List(ctx context.Context, filter dto.CalendarFeedFilter) ([]dto.CalendarFeedResponse, error) {
+	query := s.db.Order("created_at DESC")
+	if filter.OwnerType != nil {
+		query = query.Where("owner_type = ?", *filter.OwnerType)
+	}
+	if filter.OwnerID != nil {
+		query = query.Where("owner_id = ?", *filter.OwnerID)
+	}
+	if !filter.IncludeRevoked {
+		query = query.Where("revoked_at IS NULL")
+	}
+	var feeds []models.CalendarFeed
+	if err := query.Find(&feeds).Error; err != nil {
+		return nil, errors.DatabaseError("finding calendar feeds", err)
+	}
+
+	responses := make([]dto.CalendarFeedResponse, len(feeds))
+	for i := range feeds {
+		responses[i] = *toCalendarFeedResponse(&feeds[i])
+	}
+	return responses, nil
+}
+
+// Revoke stops a feed's URL from working. Revoking twice is harmless.
+func (s *CalendarFeedService) Revoke(ctx context.Context, id uuid.UUID) (*dto.CalendarFeedResponse, error) {
+	var feed models.CalendarFeed
+	if err := s.db.First(&feed, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Calendar feed", id.String())
+		}
+		return nil, errors.DatabaseError("finding calendar feed", err)
+	}
+	if !feed.IsRevoked() {
+		now := time.Now()
+		feed.RevokedAt = &now
+		if err := s.db.Model(&feed).Update("revoked_at", now).Error; err != nil {
+			return nil, errors.DatabaseError("revoking calendar feed", err)
+		}
+	}
+	return toCalendarFeedResponse(&feed), nil
+}
+
+// Render writes the iCalendar object of the feed with the given token:
+// lessons from timetables and class sessions, exams, assignment due dates
+// and calendar events. Every entry keeps the same UID across renders so
+// clients update it in place.
+func (s *CalendarFeedService) Render(ctx context.Context, token string) ([]byte, error) {
+	var feed models.CalendarFeed
+	if err := s.db.Where("token = ? AND revoked_at IS NULL", token).First(&feed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("Calendar feed")
+		}
+		return nil, errors.DatabaseError("finding calendar feed", err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.loc)
+	from, to := today.AddDate(0, 0, -calendarFeedPastDays), today.AddDate(0, 0, calendarFeedFutureDays)
+	groupIDs, teacherID, err := s.scopeOf(&feed, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newICSWriter(s.loc)
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", icsProductID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", feed.Name)
+	w.line("X-WR-TIMEZONE", s.loc.String())
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w.line("X-PUBLISHED-TTL", "PT1H")
+	w.timezone(from.AddDate(-1, 0, 0), to)
+
+	if err := s.writeLessons(w, groupIDs, teacherID, from, to, now); err != nil {
+		return nil, err
+	}
+	if err := s.writeExams(w, groupIDs, from, to, now); err != nil {
+		return nil, err
+	}
+	if err := s.writeAssignments(w, groupIDs, teacherID, from, to, now); err != nil {
+		return nil, err
+	}
+	if err := s.writeEvents(w, groupIDs, teacherID, from, to, now); err != nil {
+		return nil, err
+	}
+	if err := s.writeDaysOff(w, from, to, now); err != nil {
+		return nil, err
+	}
+	w.line("END", "VCALENDAR")
+
+	if err := s.db.Model(&feed).UpdateColumn("last_accessed_at", now).Error; err != nil {
+		logger.Warnf("failed to record access to calendar feed %s: %v", feed.ID, err)
+	}
+	return w.bytes(), nil
+}
+
+// ownerName checks that a feed owner exists and returns a calendar name
+func (s *CalendarFeedService) ownerName(ownerType models.CalendarFeedOwner, ownerID uuid.UUID) (string, error) {
+	var (
+		record interface{}
+		label  string
+		name   func() string
+	)
+	switch ownerType {
+	case models.FeedOwnerTeacher:
+		var teacher models.Teacher
+		record, label, name = &teacher, "Teacher", func() string { return teacher.Name + " " + teacher.Surname }
+	case models.FeedOwnerGroup:
+		var group models.Group
+		record, label, name = &group, "Group", func() string { return group.Name }
+	case models.FeedOwnerStudent:
+		var student models.Student
+		record, label, name = &student, "Student", func() string { return student.Name + " " + student.Surname }
+	case models.FeedOwnerParent:
+		var parent models.Parent
+		record, label, name = &parent, "Parent", func() string { return parent.FirstName + " " + parent.LastName }
+	default:
+		return "", errors.Validation(fmt.Sprintf("Invalid owner_type %q", ownerType))
+	}
+
+	if err := s.db.First(record, "id = ?", ownerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", errors.NotFoundWithID(label, ownerID.String())
+		}
+		return "", errors.DatabaseError("finding feed owner", err)
+	}
+	return name(), nil
+}
+
+// scopeOf returns the groups whose schedule a feed shows and, for a
+// teacher's feed, the teacher. Parents see the groups of their children.
+func (s *CalendarFeedService) scopeOf(feed *models.CalendarFeed, from, to time.Time) ([]uuid.UUID, *uuid.UUID, error) {
+	var groupIDs []uuid.UUID
+	var err error
+	switch feed.OwnerType {
+	case models.FeedOwnerTeacher:
+		teacherID := feed.OwnerID
+		err = s.db.Model(&models.Group{}).
+			Where("teacher_id = ? OR id IN (?)", teacherID,
+				s.db.Model(&models.ClassSession{}).Select("group_id").
+					Where("teacher_id = ? AND date >= ? AND date <= ?", teacherID, dateOnly(from), dateOnly(to))).
+			Pluck("id", &groupIDs).Error
+		if err != nil {
+			return nil, nil, errors.DatabaseError("finding teacher groups", err)
+		}
+		return groupIDs, &teacherID, nil
+	case models.FeedOwnerGroup:
+		groupIDs = []uuid.UUID{feed.OwnerID}
+	case models.FeedOwnerStudent:
+		err = s.db.Model(&models.Student{}).Where("id = ?", feed.OwnerID).Pluck("group_id", &groupIDs).Error
+	case models.FeedOwnerParent:
+		err = s.db.Model(&models.Student{}).
+			Where("id IN (?)", s.db.Model(&models.ParentStudent{}).Select("student_id").Where("parent_id = ?", feed.OwnerID)).
+			Distinct().Pluck("group_id", &groupIDs).Error
+	}
+	if err != nil {
+		return nil, nil, errors.DatabaseError("finding feed groups", err)
+	}
+
+	known := groupIDs[:0]
+	for _, id := range groupIDs {
+		if id != uuid.Nil {
+			known = append(known, id)
+		}
+	}
+	return known, nil, nil
+}
+
+// writeLessons writes timetable lessons. A lesson's UID is its group and
+// planned start, so a generated session replaces the slot it came from.
+func (s *CalendarFeedService) writeLessons(w *icsWriter, groupIDs []uuid.UUID, teacherID *uuid.UUID, from, to, now time.Time) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+	var groups []models.Group
+	if err := s.db.Preload("Timetable.Slots").Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
+		return errors.DatabaseError("finding groups", err)
+	}
+	lessons, err := lessonOccurrences(s.db, groups, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, lesson := range lessons {
+		if teacherID != nil && lesson.TeacherID != *teacherID {
+			continue
+		}
+		start, err := s.lessonTime(lesson.Date, lesson.StartTime)
+		if err != nil {
+			continue
+		}
+		end, err := s.lessonTime(lesson.Date, lesson.EndTime)
+		if err != nil {
+			continue
+		}
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("lesson-%s-%s@%s", lesson.GroupID, start.Format("20060102T1504"), icsUIDDomain))
+		w.stamp("DTSTAMP", now)
+		w.time("DTSTART", start)
+		w.time("DTEND", end)
+		w.text("SUMMARY", lesson.GroupName)
+		w.text("LOCATION", lesson.Room)
+		w.line("CATEGORIES", "LESSON")
+		w.line("STATUS", "CONFIRMED")
+		w.line("END", "VEVENT")
+	}
+	return nil
+}
+
+// lessonTime combines a lesson date with an "HH:MM" time of the school
+func (s *CalendarFeedService) lessonTime(date time.Time, clock string) (time.Time, error) {
+	minutes, err := models.ParseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), minutes/60, minutes%60, 0, 0, s.loc), nil
+}
+
+// writeExams writes the exams of the groups. Cancelled exams stay in the
+// feed as cancelled so clients drop them.
+func (s *CalendarFeedService) writeExams(w *icsWriter, groupIDs []uuid.UUID, from, to, now time.Time) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+	var exams []models.Exam
+	if err := s.db.Select("id", "title", "description", "type", "status", "group_id", "start_time", "end_time", "location", "updated_at").
+		Where("group_id IN ? AND end_time > ? AND start_time < ?", groupIDs, from, to).
+		Order("start_time").Find(&exams).Error; err != nil {
+		return errors.DatabaseError("finding exams", err)
+	}
+
+	for _, exam := range exams {
+		status := "CONFIRMED"
+		if exam.Status == models.ExamStatusCancelled {
+			status = "CANCELLED"
+		}
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("exam-%s@%s", exam.ID, icsUIDDomain))
+		w.stamp("DTSTAMP", now)
+		w.time("DTSTART", exam.StartTime)
+		w.time("DTEND", exam.EndTime)
+		w.text("SUMMARY", "Exam: "+exam.Title)
+		w.text("DESCRIPTION", exam.Description)
+		w.text("LOCATION", exam.Location)
+		w.line("CATEGORIES", "EXAM,"+strings.ToUpper(string(exam.Type)))
+		w.line("STATUS", status)
+		w.stamp("LAST-MODIFIED", exam.UpdatedAt)
+		w.line("END", "VEVENT")
+	}
+	return nil
+}
+
+// writeAssignments writes the due dates of published assignments as
+// moments in time
+func (s *CalendarFeedService) writeAssignments(w *icsWriter, groupIDs []uuid.UUID, teacherID *uuid.UUID, from, to, now time.Time) error {
+	query := s.db.Select("id", "title", "description", "group_id", "teacher_id", "status", "due_date", "updated_at").
+		Where("status IN ? AND due_date >= ? AND due_date < ?",
+			[]models.AssignmentStatus{models.AssignmentPublished, models.AssignmentClosed}, from, to)
+	switch {
+	case teacherID != nil && len(groupIDs) > 0:
+		query = query.Where("group_id IN ? OR teacher_id = ?", groupIDs, *teacherID)
+	case teacherID != nil:
+		query = query.Where("teacher_id = ?", *teacherID)
+	case len(groupIDs) > 0:
+		query = query.Where("group_id IN ?", groupIDs)
+	default:
+		return nil
+	}
+	var assignments []models.Assignment
+	if err := query.Order("due_date").Find(&assignments).Error; err != nil {
+		return errors.DatabaseError("finding assignments", err)
+	}
+
+	for _, assignment := range assignments {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("assignment-%s@%s", assignment.ID, icsUIDDomain))
+		w.stamp("DTSTAMP", now)
+		w.time("DTSTART", assignment.DueDate)
+		w.text("SUMMARY", "Due: "+assignment.Title)
+		w.text("DESCRIPTION", assignment.Description)
+		w.line("CATEGORIES", "ASSIGNMENT")
+		w.line("TRANSP", "TRANSPARENT")
+		w.stamp("LAST-MODIFIED", assignment.UpdatedAt)
+		w.line("END", "VEVENT")
+	}
+	return nil
+}
+
+// writeEvents writes calendar events of the groups or teacher and school
+// wide holidays. Recurring events are written once with their rule, and
+// their exception rows share the series UID with a RECURRENCE-ID.
+func (s *CalendarFeedService) writeEvents(w *icsWriter, groupIDs []uuid.UUID, teacherID *uuid.UUID, from, to, now time.Time) error {
+	relevant := func(db *gorm.DB) *gorm.DB {
+		cond := s.db.Where("type = ? AND group_id IS NULL AND teacher_id IS NULL", models.EventTypeHoliday)
+		if len(groupIDs) > 0 {
+			cond = cond.Or("group_id IN ?", groupIDs)
+		}
+		if teacherID != nil {
+			cond = cond.Or("teacher_id = ?", *teacherID)
+		}
+		return db.Where(cond)
+	}
+
+	var series []models.Event
+	if err := s.db.Scopes(relevant, onlySeries).Where("start_time < ?", to).
+		Order("start_time").Find(&series).Error; err != nil {
+		return errors.DatabaseError("finding recurring events", err)
+	}
+	written := make(map[uuid.UUID]bool, len(series))
+	for i := range series {
+		event := &series[i]
+		rule, err := models.ParseRecurrence(event.RecurrenceRule)
+		if err != nil {
+			logger.Warnf("event %s has an invalid recurrence rule: %v", event.ID, err)
+			if event.EndTime.After(from) {
+				s.writeEvent(w, event, event.ID, nil, now)
+			}
+			continue
+		}
+		if len(rule.Between(event.StartTime, from.Add(-event.EndTime.Sub(event.StartTime)), to)) == 0 {
+			continue
+		}
+		s.writeEvent(w, event, event.ID, rule, now)
+		written[event.ID] = true
+	}
+
+	var events []models.Event
+	if err := s.db.Scopes(relevant, notSeries).Where("end_time > ? AND start_time < ?", from, to).
+		Order("start_time").Find(&events).Error; err != nil {
+		return errors.DatabaseError("finding events", err)
+	}
+	for i := range events {
+		event := &events[i]
+		if event.ParentEventID != nil && event.RecurrenceID != nil && written[*event.ParentEventID] {
+			s.writeEvent(w, event, *event.ParentEventID, nil, now)
+			continue
+		}
+		s.writeEvent(w, event, event.ID, nil, now)
+	}
+	return nil
+}
+
+// writeDaysOff writes holidays and term breaks as all-day events. Their
+// dates are calendar dates, so they are not converted to school time.
+func (s *CalendarFeedService) writeDaysOff(w *icsWriter, from, to, now time.Time) error {
+	var holidays []models.Holiday
+	if err := s.db.Where("end_date >= ? AND start_date < ?", dateOnly(from), to).
+		Order("start_date").Find(&holidays).Error; err != nil {
+		return errors.DatabaseError("finding holidays", err)
+	}
+	var breaks []models.TermBreak
+	if err := s.db.Where("end_date >= ? AND start_date < ?", dateOnly(from), to).
+		Order("start_date").Find(&breaks).Error; err != nil {
+		return errors.DatabaseError("finding term breaks", err)
+	}
+
+	write := func(uid, name string, start, end time.Time, modified time.Time) {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("%s@%s", uid, icsUIDDomain))
+		w.stamp("DTSTAMP", now)
+		w.line("DTSTART;VALUE=DATE", start.Format(icsDayLayout))
+		w.line("DTEND;VALUE=DATE", end.AddDate(0, 0, 1).Format(icsDayLayout))
+		w.text("SUMMARY", name)
+		w.line("CATEGORIES", "HOLIDAY")
+		w.line("TRANSP", "TRANSPARENT")
+		if !modified.IsZero() {
+			w.stamp("LAST-MODIFIED", modified)
+		}
+		w.line("END", "VEVENT")
+	}
+	for _, holiday := range holidays {
+		write("holiday-"+holiday.ID.String(), holiday.Name, holiday.StartDate, holiday.EndDate, holiday.UpdatedAt)
+	}
+	for _, termBreak := range breaks {
+		write("term-break-"+termBreak.ID.String(), termBreak.Name, termBreak.StartDate, termBreak.EndDate, time.Time{})
+	}
+	return nil
+}
+
+// writeEvent writes an event under the UID of uidEventID, with the series'
+// rule or, for an exception row of a written series, its RECURRENCE-ID
+func (s *CalendarFeedService) writeEvent(w *icsWriter, event *models.Event, uidEventID uuid.UUID, rule *models.Recurrence, now time.Time) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", fmt.Sprintf("event-%s@%s", uidEventID, icsUIDDomain))
+	w.stamp("DTSTAMP", now)
+	if event.AllDay {
+		w.date("DTSTART", event.StartTime)
+		w.date("DTEND", s.dayAfter(event.EndTime))
+	} else {
+		w.time("DTSTART", event.StartTime)
+		w.time("DTEND", event.EndTime)
+	}
+	if rule != nil {
+		for _, line := range strings.Split(rule.String(), "\n") {
+			w.raw(line)
+		}
+	}
+	if uidEventID != event.ID {
+		if event.AllDay {
+			w.date("RECURRENCE-ID", *event.RecurrenceID)
+		} else {
+			w.time("RECURRENCE-ID", *event.RecurrenceID)
+		}
+	}
+	w.text("SUMMARY", event.Title)
+	w.text("DESCRIPTION", event.Description)
+	w.text("LOCATION", event.Location)
+	w.line("CATEGORIES", strings.ToUpper(string(event.Type)))
+	if event.Type == models.EventTypeHoliday || event.Type == models.EventTypeDeadline {
+		w.line("TRANSP", "TRANSPARENT")
+	}
+	w.stamp("LAST-MODIFIED", event.UpdatedAt)
+	w.line("END", "VEVENT")
+}
+
+// dayAfter returns the exclusive end date of an all-day event ending at end.
+// An end at local midnight already is one.
+func (s *CalendarFeedService) dayAfter(end time.Time) time.Time {
+	local := end.In(s.loc)
+	if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 {
+		return local
+	}
+	return local.AddDate(0, 0, 1)
+}
+
+func toCalendarFeedResponse(feed *models.CalendarFeed) *dto.CalendarFeedResponse {
+	response := &dto.CalendarFeedResponse{
+		ID:             feed.ID,
+		OwnerType:      feed.OwnerType,
+		OwnerID:        feed.OwnerID,
+		Name:           feed.Name,
+		CreatedBy:      feed.CreatedBy,
+		RevokedAt:      feed.RevokedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+		CreatedAt:      feed.CreatedAt,
+	}
+	if !feed.IsRevoked() {
+		response.URL = "/calendar/feeds/" + feed.Token + ".ics"
+	}
+	return response
+}
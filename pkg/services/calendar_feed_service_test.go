@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarFeedService_Render(t *testing.T) {
+	db := setupTestDB()
+	feeds := NewCalendarFeedService(db, "Asia/Dushanbe")
+	calendar := NewCalendarService(db)
+	ctx := context.Background()
+	creator := uuid.New()
+
+	aziz := models.Teacher{Name: "Aziz", Surname: "Rahimov", Phone: "992900000071"}
+	db.Create(&aziz)
+	other := models.Teacher{Name: "Olim", Surname: "Karimov", Phone: "992900000072"}
+	db.Create(&other)
+
+	daily := models.Timetable{Classroom: "Room 1", StartTime: "09:00", EndTime: "10:30", Days: "Mon,Tue,Wed,Thu,Fri,Sat,Sun"}
+	db.Create(&daily)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	group := models.Group{Name: "GO-1", TeacherID: aziz.ID, TimetableID: daily.ID, Capacity: 10}
+	db.Create(&group)
+	otherGroup := models.Group{Name: "JS-1", TeacherID: other.ID, Capacity: 10}
+	db.Create(&otherGroup)
+
+	student := models.Student{GroupID: group.ID, Name: "Farid", Surname: "Saidov", Phone: "992900000073"}
+	db.Create(&student)
+	parent := models.Parent{ID: uuid.New(), FirstName: "Nodir", LastName: "Saidov", Phone: "992900000074", Email: "nodir@example.com"}
+	db.Create(&parent)
+	db.Create(&models.ParentStudent{ID: uuid.New(), ParentID: parent.ID, StudentID: student.ID, Relation: models.RelationFather})
+
+	now := time.Now()
+	day := func(offset, hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+offset, hour, 0, 0, 0, time.UTC)
+	}
+	exam := models.Exam{ID: uuid.New(), Title: "Midterm", Type: models.ExamTypeMidterm, Status: models.ExamStatusScheduled,
+		GroupID: group.ID, StartTime: day(3, 10), EndTime: day(3, 12), Duration: 120, TotalMarks: 100, PassingMarks: 50, CreatedBy: creator}
+	db.Omit("Metadata").Create(&exam)
+	db.Create(&models.Assignment{ID: uuid.New(), GroupID: group.ID, TeacherID: aziz.ID, Title: "Homework; part 1",
+		Type: models.AssignmentTypeHomework, Status: models.AssignmentPublished, AssignedDate: day(0, 8), DueDate: day(5, 18)})
+	db.Create(&models.Assignment{ID: uuid.New(), GroupID: group.ID, TeacherID: aziz.ID, Title: "Draft",
+		Type: models.AssignmentTypeHomework, Status: models.AssignmentDraft, AssignedDate: day(0, 8), DueDate: day(5, 18)})
+
+	series, err := calendar.CreateEvent(ctx, dto.CreateEventRequest{Title: "Club", Type: models.EventTypeOther, GroupID: &group.ID,
+		StartTime: day(1, 14), EndTime: day(1, 15), RecurrenceRule: "FREQ=WEEKLY;COUNT=4"}, creator)
+	assert.NoError(t, err)
+	title, second := "Club (outdoors)", day(8, 14)
+	_, err = calendar.Update(ctx, series.ID.String(), dto.UpdateEventRequest{Title: &title, Scope: dto.EventScopeThis, Occurrence: &second})
+	assert.NoError(t, err)
+	_, err = calendar.CreateEvent(ctx, dto.CreateEventRequest{Title: "Independence Day", Type: models.EventTypeHoliday,
+		StartTime: day(10, 0), EndTime: day(10, 23), AllDay: true}, creator)
+	assert.NoError(t, err)
+	_, err = calendar.CreateEvent(ctx, dto.CreateEventRequest{Title: "JS meetup", Type: models.EventTypeOther, GroupID: &otherGroup.ID,
+		StartTime: day(2, 14), EndTime: day(2, 15)}, creator)
+	assert.NoError(t, err)
+
+	_, err = feeds.Create(ctx, dto.CreateCalendarFeedRequest{OwnerType: models.FeedOwnerStudent, OwnerID: uuid.New()}, creator)
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeNotFound, err.(*errors.AppError).Code)
+	}
+
+	parentFeed, err := feeds.Create(ctx, dto.CreateCalendarFeedRequest{OwnerType: models.FeedOwnerParent, OwnerID: parent.ID}, creator)
+	assert.NoError(t, err)
+	assert.Equal(t, "Nodir Saidov", parentFeed.Name)
+	token := strings.TrimSuffix(strings.TrimPrefix(parentFeed.URL, "/calendar/feeds/"), ".ics")
+
+	body, err := feeds.Render(ctx, token)
+	assert.NoError(t, err)
+	ics := string(body)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.Contains(t, ics, "BEGIN:VTIMEZONE\r\nTZID:Asia/Dushanbe\r\n")
+	assert.Contains(t, ics, "TZOFFSETTO:+0500")
+
+	// Lessons are keyed by group and planned start in school time
+	tomorrow := day(1, 9)
+	assert.Contains(t, ics, "UID:lesson-"+group.ID.String()+"-"+tomorrow.Format("20060102")+"T0900@crm-service")
+	assert.Contains(t, ics, "DTSTART;TZID=Asia/Dushanbe:"+tomorrow.Format("20060102")+"T090000")
+	assert.Contains(t, ics, "UID:exam-"+exam.ID.String()+"@crm-service")
+	assert.Contains(t, ics, `SUMMARY:Due: Homework\; part 1`)
+	assert.NotContains(t, ics, "Draft")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:"+day(10, 0).Format("20060102"))
+	assert.NotContains(t, ics, "JS meetup")
+
+	// The series is published once with its rule, and the moved occurrence
+	// shares its UID
+	seriesUID := "UID:event-" + series.ID.String() + "@crm-service"
+	assert.Equal(t, 2, strings.Count(ics, seriesUID))
+	assert.Contains(t, ics, "RRULE:FREQ=WEEKLY;COUNT=4")
+	assert.Contains(t, ics, "RECURRENCE-ID;TZID=Asia/Dushanbe:"+second.In(feeds.loc).Format("20060102T150405"))
+
+	// UIDs stay the same between renders
+	uids := regexp.MustCompile(`UID:\S+`)
+	again, err := feeds.Render(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, uids.FindAllString(ics, -1), uids.FindAllString(string(again), -1))
+
+	// A teacher only sees the groups they teach
+	teacherFeed, err := feeds.Create(ctx, dto.CreateCalendarFeedRequest{OwnerType: models.FeedOwnerTeacher, OwnerID: other.ID}, creator)
+	assert.NoError(t, err)
+	body, err = feeds.Render(ctx, strings.TrimSuffix(strings.TrimPrefix(teacherFeed.URL, "/calendar/feeds/"), ".ics"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "JS meetup")
+	assert.Contains(t, string(body), "Independence Day")
+	assert.NotContains(t, string(body), "UID:lesson-")
+
+	revoked, err := feeds.Revoke(ctx, parentFeed.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, revoked.URL)
+	_, err = feeds.Render(ctx, token)
+	assert.True(t, errors.IsNotFound(err))
+
+	listed, err := feeds.List(ctx, dto.CalendarFeedFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// iCalendar (RFC 5545) output
+const (
+	icsLineLimit   = 75 // Octets per line before folding
+	icsProductID   = "-//SoftClub//CRM Service//EN"
+	icsUIDDomain   = "crm-service"
+	icsLocalLayout = "20060102T150405"
+	icsDayLayout   = "20060102"
+)
+
+// icsEscaper escapes TEXT property values
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icsUnescaper reads escaped TEXT property values
+var icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// icsWriter builds an iCalendar object. Times are written as local times in
+// loc with a TZID, or in UTC when loc is UTC.
+type icsWriter struct {
+	b   strings.Builder
+	loc *time.Location
+}
+
+func newICSWriter(loc *time.Location) *icsWriter {
+	return &icsWriter{loc: loc}
+}
+
+// raw writes a content line, folding it at 75 octets without splitting
+// UTF-8 characters
+func (w *icsWriter) raw(line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsLineLimit - 1 // The leading space counts
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+func (w *icsWriter) line(name, value string) {
+	w.raw(name + ":" + value)
+}
+
+// text writes a TEXT property, leaving out empty values
+func (w *icsWriter) text(name, value string) {
+	if value != "" {
+		w.line(name, icsEscaper.Replace(value))
+	}
+}
+
+// time writes a DATE-TIME property
+func (w *icsWriter) time(name string, t time.Time) {
+	if w.loc == time.UTC {
+		w.line(name, t.UTC().Format(icsLocalLayout)+"Z")
+		return
+	}
+	w.line(name+";TZID="+w.loc.String(), t.In(w.loc).Format(icsLocalLayout))
+}
+
+// date writes a DATE property for the day t falls on locally
+func (w *icsWriter) date(name string, t time.Time) {
+	w.line(name+";VALUE=DATE", t.In(w.loc).Format(icsDayLayout))
+}
+
+// stamp writes a DATE-TIME property that must be in UTC, e.g. DTSTAMP
+func (w *icsWriter) stamp(name string, t time.Time) {
+	w.line(name, t.UTC().Format(icsLocalLayout)+"Z")
+}
+
+func (w *icsWriter) bytes() []byte {
+	return []byte(w.b.String())
+}
+
+// zoneTransition is a change of UTC offset in a time zone
+type zoneTransition struct {
+	at         time.Time
+	fromOffset int
+	toOffset   int
+	name       string
+	dst        bool
+}
+
+// zoneTransitions finds the offset changes of loc between from and to
+func zoneTransitions(loc *time.Location, from, to time.Time) []zoneTransition {
+	offset := func(t time.Time) int {
+		_, seconds := t.In(loc).Zone()
+		return seconds
+	}
+	var transitions []zoneTransition
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		if offset(day) == offset(next) {
+			continue
+		}
+		// Zone rules change at most once a day; narrow it to the second
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if offset(mid) == offset(lo) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		local := hi.In(loc)
+		name, _ := local.Zone()
+		transitions = append(transitions, zoneTransition{
+			at: hi, fromOffset: offset(lo), toOffset: offset(hi), name: name, dst: local.IsDST(),
+		})
+	}
+	return transitions
+}
+
+// formatUTCOffset formats an offset in seconds as in TZOFFSETTO, e.g. "+0500"
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds/60%60)
+}
+
+// timezone writes a VTIMEZONE for the writer's location with the offset
+// changes between from and to. The offset in force at from applies to
+// earlier times.
+func (w *icsWriter) timezone(from, to time.Time) {
+	if w.loc == time.UTC {
+		return
+	}
+	observance := func(dst bool, start time.Time, fromOffset, toOffset int, name string) {
+		kind := "STANDARD"
+		if dst {
+			kind = "DAYLIGHT"
+		}
+		w.line("BEGIN", kind)
+		w.line("DTSTART", start.Format(icsLocalLayout))
+		w.line("TZOFFSETFROM", formatUTCOffset(fromOffset))
+		w.line("TZOFFSETTO", formatUTCOffset(toOffset))
+		w.text("TZNAME", name)
+		w.line("END", kind)
+	}
+
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", w.loc.String())
+	initial := from.In(w.loc)
+	name, offset := initial.Zone()
+	observance(initial.IsDST(), time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), offset, offset, name)
+	for _, tr := range zoneTransitions(w.loc, from, to) {
+		// DTSTART is the wall clock time just before the change
+		start := tr.at.UTC().Add(time.Duration(tr.fromOffset) * time.Second)
+		observance(tr.dst, start, tr.fromOffset, tr.toOffset, tr.name)
+	}
+	w.line("END", "VTIMEZONE")
+}
+
+// icsEvent is a VEVENT read from an iCalendar file
+type icsEvent struct {
+	UID       string
+	Summary   string
+	Cancelled bool
+	Start     time.Time
+	End       time.Time // Exclusive; zero when the event has no DTEND
+	AllDay    bool
+	Rule      *models.Recurrence
+}
+
+// parseICSEvents reads the events of an iCalendar object. Components nested
+// in events, such as alarms, are ignored.
+func parseICSEvents(data string) ([]icsEvent, error) {
+	// Unfold continuation lines
+	data = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(data)
+	if !strings.Contains(strings.ToUpper(data), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar file")
+	}
+
+	var (
+		events []icsEvent
+		event  *icsEvent
+		rule   []string
+		depth  int // Components open inside the current event
+	)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && event == nil:
+			event, rule, depth = &icsEvent{}, nil, 0
+		case event == nil:
+		case name == "BEGIN":
+			depth++
+		case name == "END" && depth > 0:
+			depth--
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if event.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", event.Summary)
+			}
+			if len(rule) > 0 {
+				r, err := models.ParseRecurrence(strings.Join(rule, "\n"))
+				if err != nil {
+					return nil, fmt.Errorf("event %q: %v", event.Summary, err)
+				}
+				event.Rule = r
+			}
+			events = append(events, *event)
+			event = nil
+		case depth > 0:
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = icsUnescaper.Replace(value)
+		case name == "STATUS":
+			event.Cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "DTSTART" || name == "DTEND":
+			t, allDay, err := models.ParseICSTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", name, value)
+			}
+			if name == "DTSTART" {
+				event.Start, event.AllDay = t, allDay
+			} else {
+				event.End = t
+			}
+		case name == "RRULE" || name == "EXDATE":
+			rule = append(rule, line)
+		}
+	}
+	if event != nil {
+		return nil, fmt.Errorf("event %q is not closed", event.Summary)
+	}
+	return events, nil
+}
+
+// splitICSLine splits a content line into its upper-cased name, parameters
+// and value. Colons inside quoted parameter values do not end the name.
+func splitICSLine(line string) (name, params, value string, ok bool) {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			name, value = line[:i], line[i+1:]
+			if j := strings.IndexByte(name, ';'); j >= 0 {
+				name, params = name[:j], name[j+1:]
+			}
+			return strings.ToUpper(name), params, value, name != ""
+		}
+	}
+	return "", "", "", false
+}
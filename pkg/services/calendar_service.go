@@ -3,11 +3,15 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CalendarService handles calendar and event operations
@@ -22,7 +26,12 @@ func NewCalendarService(db *gorm.DB) *CalendarService {
 
 // CreateEvent creates a new event
 func (s *CalendarService) CreateEvent(ctx context.Context, req dto.CreateEventRequest, creatorID uuid.UUID) (*dto.EventResponse, error) {
+	if req.EndTime.Before(req.StartTime) {
+		return nil, errors.Validation("End time must not be before start time")
+	}
+
 	event := models.Event{
+		ID:             uuid.New(),
 		Title:          req.Title,
 		Description:    req.Description,
 		Type:           req.Type,
@@ -39,6 +48,16 @@ func (s *CalendarService) CreateEvent(ctx context.Context, req dto.CreateEventRe
 		Metadata:       req.Metadata,
 		CreatedBy:      creatorID,
 	}
+	if err := setRecurrence(&event, req.RecurrenceRule, req.IsRecurring); err != nil {
+		return nil, err
+	}
+	if err := s.assignRoom(&event, req.RoomID); err != nil {
+		return nil, err
+	}
+
+	if err := ensureEventIsFree(s.db, &event); err != nil {
+		return nil, err
+	}
 
 	if err := s.db.Create(&event).Error; err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
@@ -59,29 +78,44 @@ func (s *CalendarService) GetByID(ctx context.Context, id string) (*dto.EventRes
 	return s.toResponse(&event), nil
 }
 
-// GetEvents retrieves events within a date range
+// GetEvents retrieves events starting within a date range. Recurring events
+// are expanded into their occurrences, with exception rows in place of the
+// occurrences they replace.
 func (s *CalendarService) GetEvents(ctx context.Context, req dto.CalendarRequest) ([]dto.EventResponse, error) {
-	var events []models.Event
-
-	query := s.db.Model(&models.Event{}).
-		Where("start_time >= ? AND start_time <= ?", req.StartDate, req.EndDate)
-
-	if req.GroupID != nil {
-		query = query.Where("group_id = ?", req.GroupID)
-	}
-	if req.CourseID != nil {
-		query = query.Where("course_id = ?", req.CourseID)
-	}
-	if req.TeacherID != nil {
-		query = query.Where("teacher_id = ?", req.TeacherID)
+	filter := func(query *gorm.DB) *gorm.DB {
+		query = query.Model(&models.Event{})
+		if req.GroupID != nil {
+			query = query.Where("group_id = ?", req.GroupID)
+		}
+		if req.CourseID != nil {
+			query = query.Where("course_id = ?", req.CourseID)
+		}
+		if req.TeacherID != nil {
+			query = query.Where("teacher_id = ?", req.TeacherID)
+		}
+		if req.Type != nil {
+			query = query.Where("type = ?", req.Type)
+		}
+		return query
 	}
-	if req.Type != nil {
-		query = query.Where("type = ?", req.Type)
+
+	var events []models.Event
+	if err := filter(s.db).Scopes(notSeries).
+		Where("start_time >= ? AND start_time <= ?", req.StartDate, req.EndDate).
+		Find(&events).Error; err != nil {
+		return nil, err
 	}
 
-	if err := query.Order("start_time ASC").Find(&events).Error; err != nil {
+	var series []models.Event
+	if err := filter(s.db).Scopes(onlySeries).Where("start_time <= ?", req.EndDate).Find(&series).Error; err != nil {
+		return nil, err
+	}
+	occurrences, err := expandSeries(s.db, series, *req.StartDate, *req.EndDate)
+	if err != nil {
 		return nil, err
 	}
+	events = append(events, occurrences...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartTime.Before(events[j].StartTime) })
 
 	responses := make([]dto.EventResponse, len(events))
 	for i, event := range events {
@@ -91,71 +125,281 @@ func (s *CalendarService) GetEvents(ctx context.Context, req dto.CalendarRequest
 	return responses, nil
 }
 
-// Update updates an event
+// GetLessons lists lessons on real dates between two dates, optionally for one
+// group or teacher. A teacher's lessons include groups they cover as a
+// substitute.
+func (s *CalendarService) GetLessons(ctx context.Context, req dto.LessonCalendarRequest) ([]dto.LessonOccurrence, error) {
+	from, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.Validation("Invalid start_date format (YYYY-MM-DD)")
+	}
+	to, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.Validation("Invalid end_date format (YYYY-MM-DD)")
+	}
+	if to.Before(from) {
+		return nil, errors.Validation("end_date must not be before start_date")
+	}
+	if to.Sub(from).Hours()/24 >= maxSessionGenerationDays {
+		return nil, errors.Validation(fmt.Sprintf("date range cannot exceed %d days", maxSessionGenerationDays))
+	}
+
+	query := s.db.Preload("Timetable.Slots")
+	if req.GroupID != nil {
+		query = query.Where("id = ?", *req.GroupID)
+	}
+	if req.TeacherID != nil {
+		query = query.Where("teacher_id = ? OR id IN (?)", *req.TeacherID,
+			s.db.Model(&models.ClassSession{}).Select("group_id").
+				Where("teacher_id = ? AND date >= ? AND date <= ?", *req.TeacherID, from, to))
+	}
+	var groups []models.Group
+	if err := query.Find(&groups).Error; err != nil {
+		return nil, errors.DatabaseError("finding groups", err)
+	}
+
+	lessons, err := lessonOccurrences(s.db, groups, from, to)
+	if err != nil || req.TeacherID == nil {
+		return lessons, err
+	}
+	taught := make([]dto.LessonOccurrence, 0, len(lessons))
+	for _, lesson := range lessons {
+		if lesson.TeacherID == *req.TeacherID {
+			taught = append(taught, lesson)
+		}
+	}
+	return taught, nil
+}
+
+// Update updates an event. For a recurring event, scope "this" changes the
+// occurrence starting at req.Occurrence through an exception row, and
+// "following" ends the series there and continues it as a new series with
+// the changes; the default changes the whole series.
 func (s *CalendarService) Update(ctx context.Context, id string, req dto.UpdateEventRequest) (*dto.EventResponse, error) {
 	var event models.Event
 	if err := s.db.First(&event, "id = ?", id).Error; err != nil {
 		return nil, fmt.Errorf("event not found")
 	}
 
-	updates := make(map[string]interface{})
+	if event.IsSeries() && (req.Scope == dto.EventScopeThis || req.Scope == dto.EventScopeFollowing) {
+		rule, start, err := occurrenceOfSeries(&event, req.Occurrence)
+		if err != nil {
+			return nil, err
+		}
+		if req.Scope == dto.EventScopeThis {
+			return s.updateOccurrence(&event, start, req)
+		}
+		if !start.Equal(event.StartTime) {
+			return s.updateFollowing(&event, rule, start, req)
+		}
+	}
+
+	return s.updateEvent(&event, req)
+}
+
+// updateEvent applies the changes to a stored event, a whole series or a
+// single occurrence's exception row, and saves it
+func (s *CalendarService) updateEvent(event *models.Event, req dto.UpdateEventRequest) (*dto.EventResponse, error) {
+	if err := s.applyUpdate(event, req); err != nil {
+		return nil, err
+	}
+	if err := ensureEventIsFree(s.db, event, event.ID); err != nil {
+		return nil, err
+	}
+	if err := s.db.Omit(clause.Associations).Save(event).Error; err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+
+	return s.toResponse(event), nil
+}
+
+// updateOccurrence changes one occurrence of a series, creating its
+// exception row on the first change
+func (s *CalendarService) updateOccurrence(series *models.Event, start time.Time, req dto.UpdateEventRequest) (*dto.EventResponse, error) {
+	if req.RecurrenceRule != nil {
+		return nil, errors.Validation("A single occurrence cannot have its own recurrence_rule")
+	}
+	exceptions, err := seriesExceptions(s.db, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, exception := range exceptions[series.ID] {
+		if exception.RecurrenceID.Equal(start) && !exception.IsSeries() {
+			var existing models.Event
+			if err := s.db.First(&existing, "id = ?", exception.ID).Error; err != nil {
+				return nil, err
+			}
+			return s.updateEvent(&existing, req)
+		}
+	}
+
+	exception := occurrenceOf(series, start)
+	exception.ID = uuid.New()
+	exception.IsRecurring, exception.RecurrenceRule = false, ""
+	exception.ParentEventID = &series.ID
+	exception.CreatedAt, exception.UpdatedAt = time.Time{}, time.Time{}
+	if err := s.applyUpdate(&exception, req); err != nil {
+		return nil, err
+	}
+	if err := ensureEventIsFree(s.db, &exception); err != nil {
+		return nil, err
+	}
+	if err := s.db.Omit(clause.Associations).Create(&exception).Error; err != nil {
+		return nil, fmt.Errorf("failed to create event exception: %w", err)
+	}
+	return s.toResponse(&exception), nil
+}
+
+// updateFollowing ends a series before start and continues it from there as
+// a new series with the changes. Exceptions from start on are dropped.
+func (s *CalendarService) updateFollowing(series *models.Event, rule *models.Recurrence, start time.Time, req dto.UpdateEventRequest) (*dto.EventResponse, error) {
+	next := occurrenceOf(series, start)
+	next.ID = uuid.New()
+	next.ParentEventID = &series.ID
+	next.CreatedAt, next.UpdatedAt = time.Time{}, time.Time{}
+	nextRule := *rule
+	if rule.Count > 0 {
+		rule.Count = rule.CountBefore(series.StartTime, start)
+		nextRule.Count -= rule.Count
+	} else {
+		until := start.Add(-time.Second)
+		rule.Until = &until
+	}
+	next.RecurrenceRule = nextRule.String()
+	if err := s.applyUpdate(&next, req); err != nil {
+		return nil, err
+	}
+	if err := ensureEventIsFree(s.db, &next); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(series).Update("recurrence_rule", rule.String()).Error; err != nil {
+			return err
+		}
+		if err := deleteExceptions(tx, series.ID, start, time.Time{}); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(&next).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to split recurring event: %w", err)
+	}
+	return s.toResponse(&next), nil
+}
+
+// applyUpdate applies the requested changes to an event and validates it
+func (s *CalendarService) applyUpdate(event *models.Event, req dto.UpdateEventRequest) error {
 	if req.Title != nil {
-		updates["title"] = *req.Title
+		event.Title = *req.Title
 	}
 	if req.Description != nil {
-		updates["description"] = *req.Description
+		event.Description = *req.Description
 	}
 	if req.Type != nil {
-		updates["type"] = *req.Type
+		event.Type = *req.Type
 	}
 	if req.StartTime != nil {
-		updates["start_time"] = *req.StartTime
+		event.StartTime = *req.StartTime
 	}
 	if req.EndTime != nil {
-		updates["end_time"] = *req.EndTime
+		event.EndTime = *req.EndTime
 	}
 	if req.AllDay != nil {
-		updates["all_day"] = *req.AllDay
+		event.AllDay = *req.AllDay
 	}
 	if req.Location != nil {
-		updates["location"] = *req.Location
+		event.Location = *req.Location
 	}
 	if req.GroupID != nil {
-		updates["group_id"] = *req.GroupID
+		event.GroupID = req.GroupID
 	}
 	if req.CourseID != nil {
-		updates["course_id"] = *req.CourseID
+		event.CourseID = req.CourseID
 	}
 	if req.TeacherID != nil {
-		updates["teacher_id"] = *req.TeacherID
+		event.TeacherID = req.TeacherID
 	}
 	if req.RecurrenceRule != nil {
-		updates["recurrence_rule"] = *req.RecurrenceRule
+		if err := setRecurrence(event, *req.RecurrenceRule, *req.RecurrenceRule != ""); err != nil {
+			return err
+		}
 	}
 	if req.Color != nil {
-		updates["color"] = *req.Color
+		event.Color = *req.Color
 	}
 	if req.Metadata != nil {
-		updates["metadata"] = req.Metadata
+		event.Metadata = req.Metadata
 	}
 
-	if err := s.db.Model(&event).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("failed to update event: %w", err)
+	if req.Location != nil || req.RoomID != nil {
+		if err := s.assignRoom(event, req.RoomID); err != nil {
+			return err
+		}
 	}
-
-	return s.toResponse(&event), nil
+	if event.EndTime.Before(event.StartTime) {
+		return errors.Validation("End time must not be before start time")
+	}
+	return nil
 }
 
-// Delete deletes an event
-func (s *CalendarService) Delete(ctx context.Context, id string) error {
-	result := s.db.Delete(&models.Event{}, "id = ?", id)
-	if result.Error != nil {
-		return result.Error
+// Delete deletes an event. For a recurring event, scope "this" removes the
+// occurrence starting at req.Occurrence with an EXDATE and "following" ends
+// the series before it. Deleting an exception row removes its occurrence;
+// deleting a series removes its exceptions too.
+func (s *CalendarService) Delete(ctx context.Context, id string, req dto.EventScopeRequest) error {
+	var event models.Event
+	if err := s.db.First(&event, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("event not found")
+		}
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("event not found")
+
+	if event.IsSeries() && (req.Scope == dto.EventScopeThis || req.Scope == dto.EventScopeFollowing) {
+		rule, start, err := occurrenceOfSeries(&event, req.Occurrence)
+		if err != nil {
+			return err
+		}
+		if req.Scope == dto.EventScopeThis || !start.Equal(event.StartTime) {
+			if req.Scope == dto.EventScopeThis {
+				rule.Exclude(start)
+			} else {
+				until := start.Add(-time.Second)
+				rule.Count, rule.Until = 0, &until
+			}
+			return s.db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Model(&event).Update("recurrence_rule", rule.String()).Error; err != nil {
+					return err
+				}
+				if req.Scope == dto.EventScopeThis {
+					return deleteExceptions(tx, event.ID, start, start)
+				}
+				return deleteExceptions(tx, event.ID, start, time.Time{})
+			})
+		}
 	}
-	return nil
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if !event.IsSeries() && event.ParentEventID != nil && event.RecurrenceID != nil {
+			// The replaced occurrence must not come back
+			var parent models.Event
+			if err := tx.First(&parent, "id = ?", *event.ParentEventID).Error; err == nil && parent.IsSeries() {
+				if rule, err := models.ParseRecurrence(parent.RecurrenceRule); err == nil {
+					rule.Exclude(*event.RecurrenceID)
+					if err := tx.Model(&parent).Update("recurrence_rule", rule.String()).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if event.IsSeries() {
+			if err := tx.Where("parent_event_id = ? AND recurrence_id IS NOT NULL", event.ID).Delete(&models.Event{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&event).Error
+	})
 }
 
 // toResponse converts an event model to response DTO
@@ -169,12 +413,14 @@ func (s *CalendarService) toResponse(e *models.Event) *dto.EventResponse {
 		EndTime:        e.EndTime,
 		AllDay:         e.AllDay,
 		Location:       e.Location,
+		RoomID:         e.RoomID,
 		GroupID:        e.GroupID,
 		CourseID:       e.CourseID,
 		TeacherID:      e.TeacherID,
 		IsRecurring:    e.IsRecurring,
 		RecurrenceRule: e.RecurrenceRule,
 		ParentEventID:  e.ParentEventID,
+		RecurrenceID:   e.RecurrenceID,
 		Color:          e.Color,
 		Metadata:       e.Metadata,
 		CreatedBy:      e.CreatedBy,
@@ -182,3 +428,77 @@ func (s *CalendarService) toResponse(e *models.Event) *dto.EventResponse {
 		UpdatedAt:      e.UpdatedAt,
 	}
 }
+
+// assignRoom links the event to the room it names, by ID or by a location
+// matching a room, and checks the room seats the event's group
+func (s *CalendarService) assignRoom(event *models.Event, roomID *uuid.UUID) error {
+	room, err := resolveRoom(s.db, roomID, event.Location)
+	if err != nil {
+		return err
+	}
+	event.RoomID = nil
+	if room == nil {
+		return nil
+	}
+	event.RoomID = &room.ID
+	event.Location = room.Name
+	if event.GroupID == nil {
+		return nil
+	}
+	headcount, err := groupHeadcount(s.db, *event.GroupID)
+	if err != nil {
+		return err
+	}
+	return ensureRoomFits(room, headcount)
+}
+
+// setRecurrence validates and stores an event's recurrence rule in a
+// normalized form; an empty rule makes the event happen once
+func setRecurrence(event *models.Event, value string, recurring bool) error {
+	event.IsRecurring, event.RecurrenceRule = false, ""
+	if value == "" && !recurring {
+		return nil
+	}
+	rule, err := models.ParseRecurrence(value)
+	if err != nil {
+		return errors.Validation("Invalid recurrence_rule: " + err.Error())
+	}
+	event.IsRecurring, event.RecurrenceRule = true, rule.String()
+	return nil
+}
+
+// occurrenceOfSeries parses a series' rule and checks the given start is one
+// of its occurrences
+func occurrenceOfSeries(series *models.Event, occurrence *time.Time) (*models.Recurrence, time.Time, error) {
+	if occurrence == nil {
+		return nil, time.Time{}, errors.Validation("occurrence is required to change part of a recurring event")
+	}
+	rule, err := models.ParseRecurrence(series.RecurrenceRule)
+	if err != nil {
+		return nil, time.Time{}, errors.Validation("Invalid recurrence_rule: " + err.Error())
+	}
+	if !rule.Occurs(series.StartTime, *occurrence) {
+		return nil, time.Time{}, errors.Validation(fmt.Sprintf("%s is not an occurrence of this event", occurrence.Format(time.RFC3339)))
+	}
+	return rule, *occurrence, nil
+}
+
+// deleteExceptions deletes a series' exception rows, and series split off
+// it, replacing occurrences that start between from and to inclusive; a zero
+// to means no end
+func deleteExceptions(tx *gorm.DB, seriesID uuid.UUID, from, to time.Time) error {
+	exceptions, err := seriesExceptions(tx, seriesID)
+	if err != nil {
+		return err
+	}
+	var ids []uuid.UUID
+	for _, exception := range exceptions[seriesID] {
+		if !exception.RecurrenceID.Before(from) && (to.IsZero() || !exception.RecurrenceID.After(to)) {
+			ids = append(ids, exception.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Where("id IN ?", ids).Delete(&models.Event{}).Error
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarService_RecurringEvents(t *testing.T) {
+	db := setupTestDB()
+	calendar := NewCalendarService(db)
+	ctx := context.Background()
+
+	teacher := models.Teacher{Name: "Aziz", Surname: "Rahimov", Phone: "992900000061"}
+	db.Create(&teacher)
+	creator := uuid.New()
+	at := func(day, hour int) time.Time { return time.Date(2026, 11, day, hour, 0, 0, 0, time.UTC) }
+	titles := func() []string {
+		from, to := at(1, 0), at(30, 0)
+		events, err := calendar.GetEvents(ctx, dto.CalendarRequest{StartDate: &from, EndDate: &to})
+		assert.NoError(t, err)
+		titles := make([]string, len(events))
+		for i, event := range events {
+			titles[i] = event.StartTime.Format("02 15:04 ") + event.Title
+		}
+		return titles
+	}
+
+	_, err := calendar.CreateEvent(ctx, dto.CreateEventRequest{Title: "Sync", Type: models.EventTypeMeeting,
+		StartTime: at(2, 10), EndTime: at(2, 11), RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO,XX"}, creator)
+	assert.Error(t, err)
+
+	// Mondays and Wednesdays, six times, skipping 4 November; 2026-11-02 is a Monday
+	series, err := calendar.CreateEvent(ctx, dto.CreateEventRequest{
+		Title: "Sync", Type: models.EventTypeMeeting, StartTime: at(2, 10), EndTime: at(2, 11), TeacherID: &teacher.ID,
+		RecurrenceRule: "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6\nEXDATE:20261104T100000Z",
+	}, creator)
+	assert.NoError(t, err)
+	assert.True(t, series.IsRecurring)
+	assert.Equal(t, []string{"02 10:00 Sync", "09 10:00 Sync", "11 10:00 Sync", "16 10:00 Sync", "18 10:00 Sync"}, titles())
+
+	// The teacher is busy at every occurrence, not only the first
+	_, err = calendar.CreateEvent(ctx, dto.CreateEventRequest{Title: "Parent call", Type: models.EventTypeMeeting,
+		StartTime: at(11, 10), EndTime: at(11, 11), TeacherID: &teacher.ID}, creator)
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeScheduleConflict, err.(*errors.AppError).Code)
+	}
+
+	// Moving one occurrence keeps the rest of the series
+	title, start, end := "Sync (moved)", at(9, 12), at(9, 13)
+	nov9 := at(9, 10)
+	moved, err := calendar.Update(ctx, series.ID.String(), dto.UpdateEventRequest{
+		Title: &title, StartTime: &start, EndTime: &end, Scope: dto.EventScopeThis, Occurrence: &nov9,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, series.ID, *moved.ParentEventID)
+	assert.True(t, nov9.Equal(*moved.RecurrenceID))
+	assert.Equal(t, []string{"02 10:00 Sync", "09 12:00 Sync (moved)", "11 10:00 Sync", "16 10:00 Sync", "18 10:00 Sync"}, titles())
+
+	// A time that is not an occurrence is rejected
+	wrong := at(10, 10)
+	_, err = calendar.Update(ctx, series.ID.String(), dto.UpdateEventRequest{Title: &title, Scope: dto.EventScopeThis, Occurrence: &wrong})
+	assert.Error(t, err)
+
+	// Renaming from 16 November on splits the series, keeping its count
+	renamed, nov16 := "Weekly review", at(16, 10)
+	following, err := calendar.Update(ctx, series.ID.String(), dto.UpdateEventRequest{
+		Title: &renamed, Scope: dto.EventScopeFollowing, Occurrence: &nov16,
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, following.RecurrenceRule, "COUNT=2")
+	assert.Equal(t, []string{"02 10:00 Sync", "09 12:00 Sync (moved)", "11 10:00 Sync", "16 10:00 Weekly review", "18 10:00 Weekly review"}, titles())
+
+	nov18 := at(18, 10)
+	assert.NoError(t, calendar.Delete(ctx, following.ID.String(), dto.EventScopeRequest{Scope: dto.EventScopeThis, Occurrence: &nov18}))
+	assert.NoError(t, calendar.Delete(ctx, moved.ID.String(), dto.EventScopeRequest{}))
+	assert.Equal(t, []string{"02 10:00 Sync", "11 10:00 Sync", "16 10:00 Weekly review"}, titles())
+}
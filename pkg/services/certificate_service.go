@@ -0,0 +1,589 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// verificationAlphabet omits characters that are easy to misread (0/O, 1/I)
+const verificationAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// CertificateService issues, revokes and verifies completion certificates
+type CertificateService struct {
+	db        *gorm.DB
+	documents *DocumentService
+}
+
+// NewCertificateService creates a new certificate service
+func NewCertificateService(db *gorm.DB) *CertificateService {
+	return &CertificateService{
+		db:        db,
+		documents: NewDocumentService(db),
+	}
+}
+
+// CreateTemplate creates a certificate layout
+func (s *CertificateService) CreateTemplate(ctx context.Context, req dto.CreateCertificateTemplateRequest) (*dto.CertificateTemplateResponse, error) {
+	template := models.CertificateTemplate{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Title:          req.Title,
+		Body:           req.Body,
+		Orientation:    req.Orientation,
+		SignatoryName:  req.SignatoryName,
+		SignatoryTitle: req.SignatoryTitle,
+		IsDefault:      req.IsDefault,
+		IsActive:       true,
+	}
+	if template.Orientation == "" {
+		template.Orientation = "L"
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.CertificateTemplate{}).Where("LOWER(name) = ?", strings.ToLower(req.Name)).Count(&count).Error; err != nil {
+			return errors.DatabaseError("checking certificate template name", err)
+		}
+		if count > 0 {
+			return errors.DuplicateEntry("Certificate template", "name")
+		}
+		if template.IsDefault {
+			if err := tx.Model(&models.CertificateTemplate{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return errors.DatabaseError("clearing default certificate template", err)
+			}
+		}
+		if err := tx.Create(&template).Error; err != nil {
+			return errors.DatabaseError("creating certificate template", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toTemplateResponse(&template), nil
+}
+
+// UpdateTemplate updates a certificate layout. Certificates already issued keep their PDF.
+func (s *CertificateService) UpdateTemplate(ctx context.Context, id uuid.UUID, req dto.UpdateCertificateTemplateRequest) (*dto.CertificateTemplateResponse, error) {
+	var template models.CertificateTemplate
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&template, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("Certificate template", id.String())
+			}
+			return errors.DatabaseError("finding certificate template", err)
+		}
+
+		if req.Name != nil && !strings.EqualFold(*req.Name, template.Name) {
+			var count int64
+			if err := tx.Model(&models.CertificateTemplate{}).Where("LOWER(name) = ? AND id != ?", strings.ToLower(*req.Name), id).Count(&count).Error; err != nil {
+				return errors.DatabaseError("checking certificate template name", err)
+			}
+			if count > 0 {
+				return errors.DuplicateEntry("Certificate template", "name")
+			}
+			template.Name = *req.Name
+		}
+		if req.Title != nil {
+			template.Title = *req.Title
+		}
+		if req.Body != nil {
+			template.Body = *req.Body
+		}
+		if req.Orientation != nil {
+			template.Orientation = *req.Orientation
+		}
+		if req.SignatoryName != nil {
+			template.SignatoryName = *req.SignatoryName
+		}
+		if req.SignatoryTitle != nil {
+			template.SignatoryTitle = *req.SignatoryTitle
+		}
+		if req.IsActive != nil {
+			template.IsActive = *req.IsActive
+		}
+		if req.IsDefault != nil {
+			if *req.IsDefault && !template.IsDefault {
+				if err := tx.Model(&models.CertificateTemplate{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+					return errors.DatabaseError("clearing default certificate template", err)
+				}
+			}
+			template.IsDefault = *req.IsDefault
+		}
+
+		if err := tx.Save(&template).Error; err != nil {
+			return errors.DatabaseError("updating certificate template", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toTemplateResponse(&template), nil
+}
+
+// GetTemplates lists certificate layouts
+func (s *CertificateService) GetTemplates(ctx context.Context) ([]dto.CertificateTemplateResponse, error) {
+	var templates []models.CertificateTemplate
+	if err := s.db.Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, errors.DatabaseError("fetching certificate templates", err)
+	}
+
+	responses := make([]dto.CertificateTemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = *s.toTemplateResponse(&templates[i])
+	}
+	return responses, nil
+}
+
+// Issue issues a certificate to a single student. Staff issuing by hand are
+// not held to the group eligibility rules, but a student can hold only one
+// active certificate per course.
+func (s *CertificateService) Issue(ctx context.Context, req dto.IssueCertificateRequest, issuerID *uuid.UUID) (*dto.CertificateResponse, error) {
+	var student models.Student
+	if err := s.db.First(&student, "id = ?", req.StudentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Student", req.StudentID.String())
+		}
+		return nil, errors.DatabaseError("finding student", err)
+	}
+	var course models.Course
+	if err := s.db.First(&course, "id = ?", req.CourseID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Course", req.CourseID.String())
+		}
+		return nil, errors.DatabaseError("finding course", err)
+	}
+
+	held, err := s.hasActiveCertificate(student.ID, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		return nil, errors.Conflict("Student already holds a certificate for this course")
+	}
+
+	template, err := s.resolveTemplate(req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("student_id = ? AND course_id = ?", student.ID, course.ID)
+	if req.GroupID != nil {
+		query = query.Where("group_id = ?", *req.GroupID)
+	}
+	var card models.ReportCard
+	cardPtr := &card
+	if err := query.Order("generated_at DESC").First(&card).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.DatabaseError("finding report card", err)
+		}
+		cardPtr = nil
+	}
+
+	cert, _, err := s.issue(ctx, &student, &course, req.GroupID, template, cardPtr, req.CompletionDate, issuerID)
+	return cert, err
+}
+
+// IssueForGroup issues certificates to every eligible student in a group.
+// Students are eligible when their report card meets the minimum attendance
+// and, unless disabled, has a passing final grade.
+func (s *CertificateService) IssueForGroup(ctx context.Context, groupID uuid.UUID, req dto.IssueGroupCertificatesRequest, issuerID *uuid.UUID) (*dto.IssueGroupCertificatesResponse, error) {
+	var group models.Group
+	if err := s.db.Preload("Course").Preload("Students").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+	if group.Course == nil {
+		return nil, errors.NotFoundWithID("Course", group.CourseID.String())
+	}
+
+	template, err := s.resolveTemplate(req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	requirePass := true
+	if req.RequirePassingGrade != nil {
+		requirePass = *req.RequirePassingGrade
+	}
+
+	result := &dto.IssueGroupCertificatesResponse{
+		Issued:  make([]dto.CertificateResponse, 0),
+		Skipped: make([]dto.SkippedCertificate, 0),
+	}
+	// The group is issued all at once or not at all; files rendered for a
+	// batch that rolls back are removed
+	var generated []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		documents := *s.documents
+		documents.db = tx
+		issuer := &CertificateService{db: tx, documents: &documents}
+		for i := range group.Students {
+			student := &group.Students[i]
+			skip := func(reason string) {
+				result.Skipped = append(result.Skipped, dto.SkippedCertificate{
+					StudentID:   student.ID,
+					StudentName: student.Name + " " + student.Surname,
+					Reason:      reason,
+				})
+			}
+
+			held, err := issuer.hasActiveCertificate(student.ID, group.CourseID)
+			if err != nil {
+				return err
+			}
+			if held {
+				skip("already holds a certificate for this course")
+				continue
+			}
+
+			query := tx.Where("student_id = ? AND group_id = ?", student.ID, group.ID)
+			if req.Term != "" {
+				query = query.Where("term = ?", req.Term)
+			}
+			var card models.ReportCard
+			if err := query.Order("generated_at DESC").First(&card).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					skip("no report card")
+					continue
+				}
+				return errors.DatabaseError("finding report card", err)
+			}
+
+			if reason := certificateIneligibility(&card, req.MinAttendance, requirePass); reason != "" {
+				skip(reason)
+				continue
+			}
+
+			cert, path, err := issuer.issue(ctx, student, group.Course, &group.ID, template, &card, req.CompletionDate, issuerID)
+			if path != "" {
+				generated = append(generated, path)
+			}
+			if err != nil {
+				return err
+			}
+			result.Issued = append(result.Issued, *cert)
+		}
+		return nil
+	})
+	if err != nil {
+		for _, path := range generated {
+			os.Remove(path)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// certificateIneligibility returns why a report card does not qualify for a
+// certificate, or an empty string when it does
+func certificateIneligibility(card *models.ReportCard, minAttendance float64, requirePass bool) string {
+	if card.AttendanceRate < minAttendance {
+		return fmt.Sprintf("attendance %.1f%% is below the required %.1f%%", card.AttendanceRate, minAttendance)
+	}
+	if requirePass && !card.Passed {
+		return fmt.Sprintf("final grade %s is not a passing grade", card.FinalGrade)
+	}
+	return ""
+}
+
+// GetByID retrieves a certificate
+func (s *CertificateService) GetByID(ctx context.Context, id uuid.UUID) (*dto.CertificateResponse, error) {
+	var cert models.Certificate
+	if err := s.db.Preload("Student").Preload("Course").First(&cert, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Certificate", id.String())
+		}
+		return nil, errors.DatabaseError("finding certificate", err)
+	}
+	return s.toResponse(&cert), nil
+}
+
+// GetByStudent lists a student's certificates
+func (s *CertificateService) GetByStudent(ctx context.Context, studentID uuid.UUID) ([]dto.CertificateResponse, error) {
+	var certs []models.Certificate
+	if err := s.db.Preload("Student").Preload("Course").
+		Where("student_id = ?", studentID).
+		Order("issued_at DESC").
+		Find(&certs).Error; err != nil {
+		return nil, errors.DatabaseError("fetching certificates", err)
+	}
+
+	responses := make([]dto.CertificateResponse, len(certs))
+	for i := range certs {
+		responses[i] = *s.toResponse(&certs[i])
+	}
+	return responses, nil
+}
+
+// Revoke revokes a certificate; it remains verifiable but is reported as invalid
+func (s *CertificateService) Revoke(ctx context.Context, id uuid.UUID, req dto.RevokeCertificateRequest, revokerID *uuid.UUID) (*dto.CertificateResponse, error) {
+	var cert models.Certificate
+	if err := s.db.Preload("Student").Preload("Course").First(&cert, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Certificate", id.String())
+		}
+		return nil, errors.DatabaseError("finding certificate", err)
+	}
+	if cert.Status == models.CertificateRevoked {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Certificate is already revoked")
+	}
+
+	now := time.Now()
+	cert.Status = models.CertificateRevoked
+	cert.RevokedAt = &now
+	cert.RevokedBy = revokerID
+	cert.RevocationReason = req.Reason
+
+	if err := s.db.Model(&cert).Updates(map[string]interface{}{
+		"status":            cert.Status,
+		"revoked_at":        cert.RevokedAt,
+		"revoked_by":        cert.RevokedBy,
+		"revocation_reason": cert.RevocationReason,
+	}).Error; err != nil {
+		return nil, errors.DatabaseError("revoking certificate", err)
+	}
+
+	return s.toResponse(&cert), nil
+}
+
+// Verify looks up a certificate by its public verification code
+func (s *CertificateService) Verify(ctx context.Context, code string) (*dto.CertificateVerificationResponse, error) {
+	var cert models.Certificate
+	if err := s.db.Preload("Student").Preload("Course").
+		First(&cert, "verification_code = ?", strings.ToUpper(strings.TrimSpace(code))).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("Certificate")
+		}
+		return nil, errors.DatabaseError("verifying certificate", err)
+	}
+
+	resp := &dto.CertificateVerificationResponse{
+		Valid:            cert.Status == models.CertificateIssued,
+		Status:           cert.Status,
+		Number:           cert.Number,
+		CompletionDate:   cert.CompletionDate,
+		IssuedAt:         cert.IssuedAt,
+		RevokedAt:        cert.RevokedAt,
+		RevocationReason: cert.RevocationReason,
+	}
+	if cert.Student != nil {
+		resp.HolderName = cert.Student.Name + " " + cert.Student.Surname
+	}
+	if cert.Course != nil {
+		resp.CourseName = cert.Course.Title
+	}
+	return resp, nil
+}
+
+// issue numbers, renders and stores a certificate and returns the path of
+// its rendered file, also when saving the certificate itself fails
+func (s *CertificateService) issue(ctx context.Context, student *models.Student, course *models.Course, groupID *uuid.UUID, template *models.CertificateTemplate, card *models.ReportCard, completionDate *time.Time, issuerID *uuid.UUID) (*dto.CertificateResponse, string, error) {
+	number, err := nextCertificateNumber(s.db)
+	if err != nil {
+		return nil, "", errors.DatabaseError("generating certificate number", err)
+	}
+	code, err := generateVerificationCode()
+	if err != nil {
+		return nil, "", errors.Internal("failed to generate verification code", err)
+	}
+
+	now := time.Now()
+	cert := models.Certificate{
+		ID:               uuid.New(),
+		Number:           number,
+		VerificationCode: code,
+		StudentID:        student.ID,
+		CourseID:         course.ID,
+		GroupID:          groupID,
+		CompletionDate:   now,
+		Status:           models.CertificateIssued,
+		IssuedAt:         now,
+		IssuedBy:         issuerID,
+		Student:          student,
+		Course:           course,
+	}
+	if template.ID != uuid.Nil {
+		cert.TemplateID = &template.ID
+	}
+	if card != nil {
+		cert.ReportCardID = &card.ID
+		cert.FinalGrade = card.FinalGrade
+		cert.FinalPercentage = card.FinalPercentage
+	}
+	if completionDate != nil {
+		cert.CompletionDate = *completionDate
+	}
+
+	studentName := student.Name + " " + student.Surname
+	content, err := renderCertificatePDF(template, &cert, studentName, course.Title, s.verificationURL(code))
+	if err != nil {
+		return nil, "", errors.Internal("failed to render certificate", err)
+	}
+
+	document := models.Document{
+		Name:      fmt.Sprintf("Certificate %s - %s", number, studentName),
+		Type:      models.DocumentTypeCertificate,
+		FileName:  fmt.Sprintf("certificate-%s.pdf", number),
+		MimeType:  "application/pdf",
+		StudentID: &student.ID,
+		CourseID:  &course.ID,
+		GroupID:   groupID,
+		Metadata:  map[string]interface{}{"certificate_number": number},
+	}
+	document.UploadedBy = issuerID
+	if err := s.documents.SaveGenerated(ctx, &document, content); err != nil {
+		return nil, "", errors.Internal("failed to store certificate", err)
+	}
+	cert.DocumentID = &document.ID
+
+	if err := s.db.Omit(clause.Associations).Create(&cert).Error; err != nil {
+		return nil, document.FilePath, errors.DatabaseError("creating certificate", err)
+	}
+
+	return s.toResponse(&cert), document.FilePath, nil
+}
+
+// hasActiveCertificate reports whether a student already holds an unrevoked
+// certificate for a course
+func (s *CertificateService) hasActiveCertificate(studentID, courseID uuid.UUID) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.Certificate{}).
+		Where("student_id = ? AND course_id = ? AND status = ?", studentID, courseID, models.CertificateIssued).
+		Count(&count).Error; err != nil {
+		return false, errors.DatabaseError("checking existing certificates", err)
+	}
+	return count > 0, nil
+}
+
+// resolveTemplate picks the requested template, then the default template,
+// then the built-in layout
+func (s *CertificateService) resolveTemplate(id *uuid.UUID) (*models.CertificateTemplate, error) {
+	var template models.CertificateTemplate
+	if id != nil {
+		if err := s.db.First(&template, "id = ? AND is_active = ?", *id, true).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NotFoundWithID("Certificate template", id.String())
+			}
+			return nil, errors.DatabaseError("finding certificate template", err)
+		}
+		return &template, nil
+	}
+
+	err := s.db.Where("is_default = ? AND is_active = ?", true, true).First(&template).Error
+	if err == gorm.ErrRecordNotFound {
+		return models.DefaultCertificateTemplate(), nil
+	}
+	if err != nil {
+		return nil, errors.DatabaseError("finding default certificate template", err)
+	}
+	return &template, nil
+}
+
+// nextCertificateNumber atomically allocates the next number in the current
+// year's sequence, e.g. CERT-2026-000042
+func nextCertificateNumber(db *gorm.DB) (string, error) {
+	year := time.Now().Format("2006")
+
+	var number string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		counter := models.CertificateCounter{Year: year, Counter: 1}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "year"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"counter":    gorm.Expr("certificate_counters.counter + 1"),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&counter).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("year = ?", year).First(&counter).Error; err != nil {
+			return err
+		}
+		number = fmt.Sprintf("CERT-%s-%06d", year, counter.Counter)
+		return nil
+	})
+	return number, err
+}
+
+// generateVerificationCode returns a random code formatted as XXXX-XXXX-XXXX
+func generateVerificationCode() (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, v := range raw {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(verificationAlphabet[int(v)%len(verificationAlphabet)])
+	}
+	return b.String(), nil
+}
+
+func (s *CertificateService) verificationURL(code string) string {
+	return fmt.Sprintf("%s/verify/%s", s.documents.baseURL, code)
+}
+
+func (s *CertificateService) toTemplateResponse(t *models.CertificateTemplate) *dto.CertificateTemplateResponse {
+	return &dto.CertificateTemplateResponse{
+		ID:             t.ID,
+		Name:           t.Name,
+		Title:          t.Title,
+		Body:           t.Body,
+		Orientation:    t.Orientation,
+		SignatoryName:  t.SignatoryName,
+		SignatoryTitle: t.SignatoryTitle,
+		IsDefault:      t.IsDefault,
+		IsActive:       t.IsActive,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func (s *CertificateService) toResponse(c *models.Certificate) *dto.CertificateResponse {
+	resp := &dto.CertificateResponse{
+		ID:               c.ID,
+		Number:           c.Number,
+		VerificationCode: c.VerificationCode,
+		VerificationURL:  s.verificationURL(c.VerificationCode),
+		StudentID:        c.StudentID,
+		CourseID:         c.CourseID,
+		GroupID:          c.GroupID,
+		TemplateID:       c.TemplateID,
+		FinalGrade:       c.FinalGrade,
+		FinalPercentage:  c.FinalPercentage,
+		CompletionDate:   c.CompletionDate,
+		Status:           c.Status,
+		IssuedAt:         c.IssuedAt,
+		DocumentID:       c.DocumentID,
+		RevokedAt:        c.RevokedAt,
+		RevocationReason: c.RevocationReason,
+	}
+	if c.Student != nil {
+		resp.StudentName = c.Student.Name + " " + c.Student.Surname
+	}
+	if c.Course != nil {
+		resp.CourseName = c.Course.Title
+	}
+	return resp
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNextCertificateNumber(t *testing.T) {
+	db := setupTestDB()
+	year := time.Now().Format("2006")
+
+	first, err := nextCertificateNumber(db)
+	assert.NoError(t, err)
+	second, err := nextCertificateNumber(db)
+	assert.NoError(t, err)
+
+	assert.Equal(t, fmt.Sprintf("CERT-%s-000001", year), first)
+	assert.Equal(t, fmt.Sprintf("CERT-%s-000002", year), second)
+}
+
+func TestCertificateService_IssueForGroupIsAllOrNothing(t *testing.T) {
+	db := setupTestDB()
+	certificates := NewCertificateService(db)
+	certificates.documents.uploadPath = t.TempDir()
+	ctx := context.Background()
+
+	course := models.Course{Title: "Go", Duration: 3}
+	db.Create(&course)
+	group := models.Group{Name: "GO-1", CourseID: course.ID, Capacity: 10}
+	db.Create(&group)
+	for i, name := range []string{"Zarina", "Karim"} {
+		student := models.Student{ID: uuid.New(), Name: name, Surname: "Karimov", Phone: fmt.Sprintf("99290000050%d", i), GroupID: group.ID}
+		db.Create(&student)
+		db.Create(&models.ReportCard{ID: uuid.New(), StudentID: student.ID, GroupID: group.ID, CourseID: course.ID, Term: "2026 Spring",
+			AttendanceRate: 95, FinalPercentage: 90, FinalGrade: "A", Passed: true, GeneratedAt: time.Now()})
+	}
+
+	// The second certificate fails to save
+	created := 0
+	assert.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_second_certificate", func(tx *gorm.DB) {
+		if tx.Statement.Table == "certificates" {
+			if created++; created == 2 {
+				tx.AddError(fmt.Errorf("disk full"))
+			}
+		}
+	}))
+	_, err := certificates.IssueForGroup(ctx, group.ID, dto.IssueGroupCertificatesRequest{}, nil)
+	assert.Error(t, err)
+	db.Callback().Create().Remove("test:fail_second_certificate")
+
+	var count int64
+	db.Model(&models.Certificate{}).Count(&count)
+	assert.EqualValues(t, 0, count)
+	db.Model(&models.Document{}).Count(&count)
+	assert.EqualValues(t, 0, count)
+	files, _ := filepath.Glob(filepath.Join(certificates.documents.uploadPath, "*", "*"))
+	assert.Empty(t, files, "rendered files are removed with the rollback")
+
+	resp, err := certificates.IssueForGroup(ctx, group.ID, dto.IssueGroupCertificatesRequest{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Issued, 2)
+	for _, cert := range resp.Issued {
+		var document models.Document
+		if assert.NoError(t, db.First(&document, "id = ?", *cert.DocumentID).Error) {
+			_, err := os.Stat(document.FilePath)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestGenerateVerificationCode(t *testing.T) {
+	code, err := generateVerificationCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, 14)
+	assert.Equal(t, 2, strings.Count(code, "-"))
+	assert.NotContains(t, code, "0")
+	assert.NotContains(t, code, "O")
+}
+
+func TestCertificateIneligibility(t *testing.T) {
+	card := &models.ReportCard{AttendanceRate: 70, Passed: true, FinalGrade: "B"}
+	assert.Contains(t, certificateIneligibility(card, 75, true), "attendance")
+	assert.Empty(t, certificateIneligibility(card, 60, true))
+
+	card.Passed = false
+	assert.Contains(t, certificateIneligibility(card, 60, true), "not a passing grade")
+	assert.Empty(t, certificateIneligibility(card, 60, false))
+}
+
+func TestRenderCertificatePDF(t *testing.T) {
+	cert := &models.Certificate{Number: "CERT-2026-000001", CompletionDate: time.Now()}
+	content, err := renderCertificatePDF(models.DefaultCertificateTemplate(), cert, "Jane Doe", "Go Basics", "http://localhost:8080/verify/ABCD-EFGH-JKLM")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "%PDF"))
+}
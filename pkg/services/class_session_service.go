@@ -0,0 +1,845 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// maxSessionGenerationDays bounds a single generation request
+const maxSessionGenerationDays = 366
+
+// ClassSessionService handles individual lessons materialized from timetables
+type ClassSessionService struct {
+	db            *gorm.DB
+	notifications *NotificationService
+}
+
+// NewClassSessionService creates a new class session service
+func NewClassSessionService(db *gorm.DB) *ClassSessionService {
+	return &ClassSessionService{
+		db:            db,
+		notifications: NewNotificationService(db),
+	}
+}
+
+// Generate materializes a group's lessons for a date range from its timetable
+// slots. Slots that already have a lesson are left untouched, so it is safe
+// to re-run; holidays and term breaks are skipped.
+func (s *ClassSessionService) Generate(ctx context.Context, groupID uuid.UUID, req dto.GenerateClassSessionsRequest) (*dto.GenerateClassSessionsResponse, error) {
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return nil, errors.Validation("Invalid from date format (YYYY-MM-DD)")
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		return nil, errors.Validation("Invalid to date format (YYYY-MM-DD)")
+	}
+	if to.Before(from) {
+		return nil, errors.Validation("to date must not be before from date")
+	}
+	if to.Sub(from).Hours()/24 >= maxSessionGenerationDays {
+		return nil, errors.Validation(fmt.Sprintf("date range cannot exceed %d days", maxSessionGenerationDays))
+	}
+
+	group, err := loadGroupWithTimetable(s.db, groupID)
+	if err != nil {
+		return nil, err
+	}
+	off, err := daysOff(s.db, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.GenerateClassSessionsResponse{Sessions: make([]dto.ClassSessionResponse, 0)}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			slots := group.Timetable.SlotsOn(date.Weekday())
+			if _, ok := off[date]; ok {
+				result.SkippedDaysOff += len(slots)
+				continue
+			}
+			for _, slot := range slots {
+				session, created, err := materializeSession(tx, group, &slot, date)
+				if err != nil {
+					return err
+				}
+				if created {
+					result.Created++
+				} else {
+					result.Existing++
+				}
+				result.Sessions = append(result.Sessions, *s.toResponse(session))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetByGroup lists a group's lessons, optionally within a date range and status
+func (s *ClassSessionService) GetByGroup(ctx context.Context, groupID uuid.UUID, from, to, status string) ([]dto.ClassSessionResponse, error) {
+	query := s.db.Preload("Group").Where("group_id = ?", groupID)
+	if from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, errors.Validation("Invalid from date format (YYYY-MM-DD)")
+		}
+		query = query.Where("date >= ?", date)
+	}
+	if to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, errors.Validation("Invalid to date format (YYYY-MM-DD)")
+		}
+		query = query.Where("date <= ?", date)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var sessions []models.ClassSession
+	if err := query.Order("date ASC, start_time ASC").Find(&sessions).Error; err != nil {
+		return nil, errors.DatabaseError("fetching class sessions", err)
+	}
+
+	responses := make([]dto.ClassSessionResponse, len(sessions))
+	for i := range sessions {
+		responses[i] = *s.toResponse(&sessions[i])
+	}
+	return responses, nil
+}
+
+// GetByID retrieves a lesson
+func (s *ClassSessionService) GetByID(ctx context.Context, id uuid.UUID) (*dto.ClassSessionResponse, error) {
+	session, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(session), nil
+}
+
+// Update updates a lesson's topic, notes, teacher, room or marks it as held
+func (s *ClassSessionService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateClassSessionRequest) (*dto.ClassSessionResponse, error) {
+	session, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != nil && !session.AcceptsAttendance() {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot change the status of a %s session", session.Status))
+	}
+
+	if req.Topic != nil {
+		session.Topic = *req.Topic
+	}
+	if req.Notes != nil {
+		session.Notes = *req.Notes
+	}
+	if req.Room != nil {
+		session.Room = *req.Room
+	}
+	if req.TeacherID != nil {
+		var teacher models.Teacher
+		if err := s.db.First(&teacher, "id = ?", *req.TeacherID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NotFoundWithID("Teacher", req.TeacherID.String())
+			}
+			return nil, errors.DatabaseError("finding teacher", err)
+		}
+		session.TeacherID = *req.TeacherID
+	}
+	if req.Status != nil {
+		session.Status = *req.Status
+	}
+
+	if (req.Room != nil || req.TeacherID != nil) && session.AcceptsAttendance() {
+		booking := lessonBooking(session.ID, session.GroupID, session.TeacherID, session.Date, session.StartTime, session.EndTime, session.Room)
+		if err := ensureNoScheduleConflicts(s.db, booking, session.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.db.Omit("Group").Save(session).Error; err != nil {
+		return nil, errors.DatabaseError("updating class session", err)
+	}
+	return s.toResponse(session), nil
+}
+
+// Cancel cancels a scheduled lesson, optionally granting make-up credits, and
+// notifies the group's students and their parents
+func (s *ClassSessionService) Cancel(ctx context.Context, id uuid.UUID, req dto.CancelClassSessionRequest) (*dto.SessionChangeResponse, error) {
+	session, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SessionChangeResponse{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		credits, err := cancelSession(tx, session, req)
+		result.MakeUpCredits = credits
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Sessions = []dto.ClassSessionResponse{*s.toResponse(session)}
+
+	if notifyRequested(req.Notify) {
+		result.NotificationsSent = s.notifyGroup(ctx, session.GroupID,
+			"Lesson cancelled",
+			fmt.Sprintf("The %s has been cancelled. Reason: %s", describeSession(session), req.Reason))
+	}
+	return result, nil
+}
+
+// CancelRange cancels every scheduled lesson of a group within a date range,
+// including timetabled lessons that have not been generated yet
+func (s *ClassSessionService) CancelRange(ctx context.Context, groupID uuid.UUID, req dto.CancelClassSessionsRequest) (*dto.SessionChangeResponse, error) {
+	from, to, err := parseSessionRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SessionChangeResponse{}
+	var cancelled []models.ClassSession
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		sessions, err := scheduledSessionsInRange(tx, groupID, from, to)
+		if err != nil {
+			return err
+		}
+		for i := range sessions {
+			credits, err := cancelSession(tx, &sessions[i], req.CancelClassSessionRequest)
+			if err != nil {
+				return err
+			}
+			result.MakeUpCredits += credits
+		}
+		cancelled = sessions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Sessions = make([]dto.ClassSessionResponse, len(cancelled))
+	lines := make([]string, len(cancelled))
+	for i := range cancelled {
+		result.Sessions[i] = *s.toResponse(&cancelled[i])
+		lines[i] = "- " + describeSession(&cancelled[i])
+	}
+
+	if notifyRequested(req.Notify) {
+		result.NotificationsSent = s.notifyGroup(ctx, groupID,
+			"Lessons cancelled",
+			fmt.Sprintf("The following lessons have been cancelled. Reason: %s\n%s", req.Reason, strings.Join(lines, "\n")))
+	}
+	return result, nil
+}
+
+// Reschedule moves a scheduled lesson to a new date and time. The slot is
+// checked for room, teacher and group conflicts; the original lesson is kept
+// as rescheduled and linked to its replacement.
+func (s *ClassSessionService) Reschedule(ctx context.Context, id uuid.UUID, req dto.RescheduleClassSessionRequest) (*dto.SessionChangeResponse, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.Validation("Invalid date format (YYYY-MM-DD)")
+	}
+	if _, _, err := parseTimeRange(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	session, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	room := session.Room
+	if req.Room != "" {
+		room = req.Room
+	}
+	slot := sessionSlot{
+		GroupID:   session.GroupID,
+		TeacherID: session.TeacherID,
+		Date:      date,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Room:      room,
+	}
+
+	var moved []models.ClassSession
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		moved, err = moveSessions(tx, []models.ClassSession{*session}, []sessionSlot{slot}, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SessionChangeResponse{Sessions: []dto.ClassSessionResponse{*s.toResponse(&moved[0])}}
+	if notifyRequested(req.Notify) {
+		result.NotificationsSent = s.notifyGroup(ctx, session.GroupID,
+			"Lesson rescheduled",
+			rescheduleMessage(req.Reason, []string{fmt.Sprintf("- %s -> %s", describeSession(session), describeSlot(&moved[0]))}))
+	}
+	return result, nil
+}
+
+// RescheduleRange moves every scheduled lesson of a group within a date range
+// by a number of days and/or to new times or a new room. All new slots are
+// checked before any lesson is moved.
+func (s *ClassSessionService) RescheduleRange(ctx context.Context, groupID uuid.UUID, req dto.RescheduleClassSessionsRequest) (*dto.SessionChangeResponse, error) {
+	from, to, err := parseSessionRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if (req.StartTime == "") != (req.EndTime == "") {
+		return nil, errors.Validation("start_time and end_time must be given together")
+	}
+	if req.StartTime != "" {
+		if _, _, err := parseTimeRange(req.StartTime, req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	if req.ShiftDays == 0 && req.StartTime == "" && req.Room == "" {
+		return nil, errors.Validation("Nothing to change: provide shift_days, new times or a room")
+	}
+
+	var originals, moved []models.ClassSession
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		sessions, err := scheduledSessionsInRange(tx, groupID, from, to)
+		if err != nil {
+			return err
+		}
+
+		slots := make([]sessionSlot, len(sessions))
+		for i, session := range sessions {
+			slots[i] = sessionSlot{
+				GroupID:   session.GroupID,
+				TeacherID: session.TeacherID,
+				Date:      session.Date.AddDate(0, 0, req.ShiftDays),
+				StartTime: session.StartTime,
+				EndTime:   session.EndTime,
+				Room:      session.Room,
+			}
+			if req.StartTime != "" {
+				slots[i].StartTime, slots[i].EndTime = req.StartTime, req.EndTime
+			}
+			if req.Room != "" {
+				slots[i].Room = req.Room
+			}
+		}
+
+		originals = sessions
+		moved, err = moveSessions(tx, sessions, slots, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SessionChangeResponse{Sessions: make([]dto.ClassSessionResponse, len(moved))}
+	lines := make([]string, len(moved))
+	for i := range moved {
+		result.Sessions[i] = *s.toResponse(&moved[i])
+		lines[i] = fmt.Sprintf("- %s -> %s", describeSession(&originals[i]), describeSlot(&moved[i]))
+	}
+
+	if notifyRequested(req.Notify) {
+		result.NotificationsSent = s.notifyGroup(ctx, groupID, "Lessons rescheduled", rescheduleMessage(req.Reason, lines))
+	}
+	return result, nil
+}
+
+// GetStudentMakeUpCredits lists a student's make-up credits, expiring any
+// that have passed their expiry date
+func (s *ClassSessionService) GetStudentMakeUpCredits(ctx context.Context, studentID uuid.UUID) ([]dto.MakeUpCreditResponse, error) {
+	now := time.Now()
+	if err := s.db.Model(&models.MakeUpCredit{}).
+		Where("student_id = ? AND status = ? AND expires_at IS NOT NULL AND expires_at < ?", studentID, models.MakeUpCreditAvailable, now).
+		Update("status", models.MakeUpCreditExpired).Error; err != nil {
+		return nil, errors.DatabaseError("expiring make-up credits", err)
+	}
+
+	var credits []models.MakeUpCredit
+	if err := s.db.Preload("Session").
+		Where("student_id = ?", studentID).
+		Order("created_at DESC").
+		Find(&credits).Error; err != nil {
+		return nil, errors.DatabaseError("fetching make-up credits", err)
+	}
+
+	responses := make([]dto.MakeUpCreditResponse, len(credits))
+	for i := range credits {
+		responses[i] = *s.toCreditResponse(&credits[i])
+	}
+	return responses, nil
+}
+
+// RedeemMakeUpCredit uses an available make-up credit for a lesson the student attends instead
+func (s *ClassSessionService) RedeemMakeUpCredit(ctx context.Context, creditID uuid.UUID, req dto.RedeemMakeUpCreditRequest) (*dto.MakeUpCreditResponse, error) {
+	var credit models.MakeUpCredit
+	if err := s.db.Preload("Session").First(&credit, "id = ?", creditID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Make-up credit", creditID.String())
+		}
+		return nil, errors.DatabaseError("finding make-up credit", err)
+	}
+
+	now := time.Now()
+	if credit.Status == models.MakeUpCreditAvailable && credit.ExpiresAt != nil && credit.ExpiresAt.Before(now) {
+		credit.Status = models.MakeUpCreditExpired
+		s.db.Model(&credit).Update("status", credit.Status)
+	}
+	if credit.Status != models.MakeUpCreditAvailable {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Make-up credit is %s", credit.Status))
+	}
+
+	session, err := s.find(s.db, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.AcceptsAttendance() {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot redeem a make-up credit for a %s session", session.Status))
+	}
+
+	// The student must be free: only their own group's bookings can clash
+	attendance := lessonBooking(session.ID, uuid.Nil, uuid.Nil, session.Date, session.StartTime, session.EndTime, "")
+	attendance.StudentIDs = []uuid.UUID{credit.StudentID}
+	if err := ensureNoScheduleConflicts(s.db, attendance, session.ID); err != nil {
+		return nil, err
+	}
+
+	credit.Status = models.MakeUpCreditUsed
+	credit.UsedSessionID = &session.ID
+	credit.UsedAt = &now
+	if err := s.db.Omit("Session").Save(&credit).Error; err != nil {
+		return nil, errors.DatabaseError("redeeming make-up credit", err)
+	}
+	return s.toCreditResponse(&credit), nil
+}
+
+// notifyGroup tells a group's students and their parents about a schedule
+// change and returns how many notifications were delivered
+func (s *ClassSessionService) notifyGroup(ctx context.Context, groupID uuid.UUID, subject, message string) int {
+	var students []models.Student
+	if err := s.db.Where("group_id = ?", groupID).Find(&students).Error; err != nil {
+		return 0
+	}
+
+	sent := 0
+	notifiedParents := make(map[uuid.UUID]bool)
+	for _, student := range students {
+		if _, ok := s.notifications.notifyContact(ctx, &student.ID, student.Email, student.Phone, subject, message); ok {
+			sent++
+		}
+
+		parents, err := linkedParentsWithPreference(s.db, student.ID, "")
+		if err != nil {
+			continue
+		}
+		for _, parent := range parents {
+			if notifiedParents[parent.ID] {
+				continue
+			}
+			notifiedParents[parent.ID] = true
+			if _, ok := s.notifications.notifyContact(ctx, &student.ID, parent.Email, parent.Phone, subject, message); ok {
+				sent++
+			}
+		}
+	}
+	return sent
+}
+
+func (s *ClassSessionService) find(db *gorm.DB, id uuid.UUID) (*models.ClassSession, error) {
+	var session models.ClassSession
+	if err := db.Preload("Group").First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Class session", id.String())
+		}
+		return nil, errors.DatabaseError("finding class session", err)
+	}
+	return &session, nil
+}
+
+func (s *ClassSessionService) toResponse(cs *models.ClassSession) *dto.ClassSessionResponse {
+	resp := &dto.ClassSessionResponse{
+		ID:          cs.ID,
+		GroupID:     cs.GroupID,
+		TimetableID: cs.TimetableID,
+		TeacherID:   cs.TeacherID,
+		Date:        cs.Date.Format("2006-01-02"),
+		StartTime:   cs.StartTime,
+		EndTime:     cs.EndTime,
+		Room:        cs.Room,
+		Topic:       cs.Topic,
+		Status:      cs.Status,
+		Notes:       cs.Notes,
+
+		CancellationReason: cs.CancellationReason,
+		CancelledAt:        cs.CancelledAt,
+		RescheduledFromID:  cs.RescheduledFromID,
+		RescheduledToID:    cs.RescheduledToID,
+
+		CreatedAt: cs.CreatedAt,
+		UpdatedAt: cs.UpdatedAt,
+	}
+	if cs.Group != nil {
+		resp.GroupName = cs.Group.Name
+	}
+	return resp
+}
+
+func (s *ClassSessionService) toCreditResponse(credit *models.MakeUpCredit) *dto.MakeUpCreditResponse {
+	resp := &dto.MakeUpCreditResponse{
+		ID:            credit.ID,
+		StudentID:     credit.StudentID,
+		GroupID:       credit.GroupID,
+		SessionID:     credit.SessionID,
+		Reason:        credit.Reason,
+		Status:        credit.Status,
+		ExpiresAt:     credit.ExpiresAt,
+		UsedSessionID: credit.UsedSessionID,
+		UsedAt:        credit.UsedAt,
+		CreatedAt:     credit.CreatedAt,
+	}
+	if credit.Session != nil {
+		resp.SessionDate = credit.Session.Date.Format("2006-01-02")
+	}
+	return resp
+}
+
+// sessionSlot is the place and time a lesson is booked into
+type sessionSlot struct {
+	GroupID   uuid.UUID
+	TeacherID uuid.UUID
+	Date      time.Time
+	StartTime string
+	EndTime   string
+	Room      string
+}
+
+// parseSessionRange parses and bounds an inclusive date range
+func parseSessionRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Validation("Invalid from date format (YYYY-MM-DD)")
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Validation("Invalid to date format (YYYY-MM-DD)")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.Validation("to date must not be before from date")
+	}
+	if to.Sub(from).Hours()/24 >= maxSessionGenerationDays {
+		return time.Time{}, time.Time{}, errors.Validation(fmt.Sprintf("date range cannot exceed %d days", maxSessionGenerationDays))
+	}
+	return from, to, nil
+}
+
+// scheduledSessionsInRange returns a group's scheduled lessons within a date
+// range, generating timetabled lessons that do not exist yet outside holidays
+// and term breaks
+func scheduledSessionsInRange(tx *gorm.DB, groupID uuid.UUID, from, to time.Time) ([]models.ClassSession, error) {
+	var group models.Group
+	if err := tx.Preload("Timetable.Slots").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+
+	if group.Timetable != nil {
+		off, err := daysOff(tx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if _, ok := off[date]; ok {
+				continue
+			}
+			for _, slot := range group.Timetable.SlotsOn(date.Weekday()) {
+				if _, _, err := materializeSession(tx, &group, &slot, date); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var sessions []models.ClassSession
+	if err := tx.Where("group_id = ? AND date >= ? AND date <= ? AND status = ?", groupID, from, to, models.SessionScheduled).
+		Order("date ASC, start_time ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, errors.DatabaseError("finding class sessions", err)
+	}
+	if len(sessions) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Group has no scheduled lessons in this date range")
+	}
+	for i := range sessions {
+		sessions[i].Group = &group
+	}
+	return sessions, nil
+}
+
+// cancelSession marks a scheduled lesson as cancelled and, if requested,
+// grants each student of the group a make-up credit. It returns the number of
+// credits created.
+func cancelSession(tx *gorm.DB, session *models.ClassSession, req dto.CancelClassSessionRequest) (int, error) {
+	if session.Status != models.SessionScheduled {
+		return 0, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot cancel a %s session", session.Status))
+	}
+
+	now := time.Now()
+	session.Status = models.SessionCancelled
+	session.CancellationReason = req.Reason
+	session.CancelledAt = &now
+	if err := tx.Omit("Group").Save(session).Error; err != nil {
+		return 0, errors.DatabaseError("cancelling class session", err)
+	}
+
+	if !req.CreateMakeUpCredit {
+		return 0, nil
+	}
+
+	var students []models.Student
+	if err := tx.Where("group_id = ?", session.GroupID).Find(&students).Error; err != nil {
+		return 0, errors.DatabaseError("finding group students", err)
+	}
+	var expiresAt *time.Time
+	if req.MakeUpCreditValidDays > 0 {
+		expiry := now.AddDate(0, 0, req.MakeUpCreditValidDays)
+		expiresAt = &expiry
+	}
+	for _, student := range students {
+		credit := models.MakeUpCredit{
+			ID:        uuid.New(),
+			StudentID: student.ID,
+			GroupID:   session.GroupID,
+			SessionID: session.ID,
+			Reason:    req.Reason,
+			Status:    models.MakeUpCreditAvailable,
+			ExpiresAt: expiresAt,
+		}
+		if err := tx.Create(&credit).Error; err != nil {
+			return 0, errors.DatabaseError("creating make-up credit", err)
+		}
+	}
+	return len(students), nil
+}
+
+// moveSessions reschedules scheduled lessons into new slots. Every slot is
+// checked for conflicts first; the originals are then marked as rescheduled
+// (freeing their slots) before the replacement lessons are created.
+func moveSessions(tx *gorm.DB, sessions []models.ClassSession, slots []sessionSlot, reason string) ([]models.ClassSession, error) {
+	ids := make([]uuid.UUID, len(sessions))
+	for i, session := range sessions {
+		if session.Status != models.SessionScheduled {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot reschedule a %s session", session.Status))
+		}
+		ids[i] = session.ID
+	}
+
+	conflicts := make([]dto.ScheduleConflict, 0)
+	for _, slot := range slots {
+		booking := lessonBooking(uuid.Nil, slot.GroupID, slot.TeacherID, slot.Date, slot.StartTime, slot.EndTime, slot.Room)
+		found, err := findScheduleConflicts(tx, booking, ids...)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, found...)
+	}
+	if err := scheduleConflictError(bookingLesson, conflicts); err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		if err := tx.Model(&models.ClassSession{}).Where("id = ?", sessions[i].ID).
+			Updates(map[string]interface{}{
+				"status":              models.SessionRescheduled,
+				"cancellation_reason": reason,
+			}).Error; err != nil {
+			return nil, errors.DatabaseError("rescheduling class session", err)
+		}
+	}
+
+	moved := make([]models.ClassSession, len(sessions))
+	for i, slot := range slots {
+		original := sessions[i]
+		moved[i] = models.ClassSession{
+			ID:                uuid.New(),
+			GroupID:           original.GroupID,
+			TimetableID:       original.TimetableID,
+			TeacherID:         original.TeacherID,
+			Date:              slot.Date,
+			StartTime:         slot.StartTime,
+			EndTime:           slot.EndTime,
+			Room:              slot.Room,
+			Topic:             original.Topic,
+			Status:            models.SessionScheduled,
+			Notes:             original.Notes,
+			RescheduledFromID: &original.ID,
+		}
+		if err := tx.Create(&moved[i]).Error; err != nil {
+			return nil, errors.DatabaseError("creating rescheduled class session", err)
+		}
+		if err := tx.Model(&models.ClassSession{}).Where("id = ?", original.ID).
+			Update("rescheduled_to_id", moved[i].ID).Error; err != nil {
+			return nil, errors.DatabaseError("linking rescheduled class session", err)
+		}
+		moved[i].Group = original.Group
+	}
+	return moved, nil
+}
+
+// notifyRequested reports whether a schedule change should be announced;
+// notifications are sent unless explicitly disabled
+func notifyRequested(notify *bool) bool {
+	return notify == nil || *notify
+}
+
+// describeSession renders a lesson for notification messages
+func describeSession(session *models.ClassSession) string {
+	name := "lesson"
+	if session.Group != nil {
+		name = session.Group.Name + " lesson"
+	}
+	return fmt.Sprintf("%s on %s at %s-%s", name, session.Date.Format("2006-01-02"), session.StartTime, session.EndTime)
+}
+
+// describeSlot renders the new date, time and room of a moved lesson
+func describeSlot(session *models.ClassSession) string {
+	text := fmt.Sprintf("%s at %s-%s", session.Date.Format("2006-01-02"), session.StartTime, session.EndTime)
+	if session.Room != "" {
+		text += ", room " + session.Room
+	}
+	return text
+}
+
+func rescheduleMessage(reason string, lines []string) string {
+	message := "The following lessons have been moved:\n" + strings.Join(lines, "\n")
+	if reason != "" {
+		message += "\nReason: " + reason
+	}
+	return message
+}
+
+// loadGroupWithTimetable loads a group and requires it to have a timetable
+func loadGroupWithTimetable(db *gorm.DB, groupID uuid.UUID) (*models.Group, error) {
+	var group models.Group
+	if err := db.Preload("Timetable.Slots").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+	if group.Timetable == nil {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Group has no timetable")
+	}
+	return &group, nil
+}
+
+// materializeSession returns the group's lesson for a timetable slot on a
+// date, creating it if it does not exist yet
+func materializeSession(tx *gorm.DB, group *models.Group, slot *models.TimetableSlot, date time.Time) (*models.ClassSession, bool, error) {
+	var session models.ClassSession
+	err := tx.Where("group_id = ? AND date = ? AND start_time = ?", group.ID, date, slot.StartTime()).First(&session).Error
+	if err == nil {
+		session.Group = group
+		return &session, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, errors.DatabaseError("finding class session", err)
+	}
+
+	session = models.ClassSession{
+		ID:          uuid.New(),
+		GroupID:     group.ID,
+		TimetableID: &group.Timetable.ID,
+		TeacherID:   group.TeacherID,
+		Date:        date,
+		StartTime:   slot.StartTime(),
+		EndTime:     slot.EndTime(),
+		Room:        group.Timetable.SlotClassroom(slot),
+		Status:      models.SessionScheduled,
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return nil, false, errors.DatabaseError("creating class session", err)
+	}
+	session.Group = group
+	return &session, true, nil
+}
+
+// sessionForAttendance resolves the lesson attendance on a date is recorded
+// against. A timetabled lesson that has not been generated yet is created on
+// demand; dates without a lesson, and cancelled or moved lessons, are rejected.
+func sessionForAttendance(tx *gorm.DB, groupID uuid.UUID, date time.Time, sessionID *uuid.UUID) (*models.ClassSession, error) {
+	if sessionID != nil {
+		var session models.ClassSession
+		if err := tx.First(&session, "id = ? AND group_id = ?", *sessionID, groupID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New(errors.ErrCodeNotFound, "Class session not found in this group")
+			}
+			return nil, errors.DatabaseError("finding class session", err)
+		}
+		if !session.Date.Equal(date) {
+			return nil, errors.Validation("Date does not match the class session date")
+		}
+		if !session.AcceptsAttendance() {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot record attendance for a %s session", session.Status))
+		}
+		return &session, nil
+	}
+
+	var sessions []models.ClassSession
+	if err := tx.Where("group_id = ? AND date = ?", groupID, date).Find(&sessions).Error; err != nil {
+		return nil, errors.DatabaseError("finding class sessions", err)
+	}
+
+	open := make([]models.ClassSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.AcceptsAttendance() {
+			open = append(open, session)
+		}
+	}
+	switch {
+	case len(open) == 1:
+		return &open[0], nil
+	case len(open) > 1:
+		return nil, errors.Validation("Group has several lessons on this date; session_id is required")
+	case len(sessions) > 0:
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("The lesson on %s is %s", date.Format("2006-01-02"), sessions[0].Status))
+	}
+
+	group, err := loadGroupWithTimetable(tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	off, err := daysOff(tx, date, date)
+	if err != nil {
+		return nil, err
+	}
+	if name, ok := off[dateOnly(date)]; ok {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("There are no lessons on %s (%s)", date.Format("2006-01-02"), name))
+	}
+	slots := group.Timetable.SlotsOn(date.Weekday())
+	switch {
+	case len(slots) == 0:
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("No lesson is scheduled for this group on %s", date.Format("2006-01-02")))
+	case len(slots) > 1:
+		return nil, errors.Validation("Group has several lessons on this date; session_id is required")
+	}
+	session, _, err := materializeSession(tx, group, &slots[0], date)
+	return session, err
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassSessionService_Generate(t *testing.T) {
+	db := setupTestDB()
+	service := NewClassSessionService(db)
+
+	timetable := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed,Fri"}
+	db.Create(&timetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&group)
+
+	// 2026-03-02 is a Monday; the week has three lessons
+	req := dto.GenerateClassSessionsRequest{From: "2026-03-02", To: "2026-03-08"}
+	resp, err := service.Generate(context.Background(), group.ID, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.Created)
+	assert.Equal(t, "Room 101", resp.Sessions[0].Room)
+
+	// Re-running keeps the existing lessons
+	resp, err = service.Generate(context.Background(), group.ID, req)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Created)
+	assert.Equal(t, 3, resp.Existing)
+
+	// Tuesday has no lesson
+	tuesday := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	_, err = sessionForAttendance(db, group.ID, tuesday, nil)
+	assert.Error(t, err)
+
+	// A cancelled lesson does not accept attendance
+	wednesday := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	db.Model(&models.ClassSession{}).Where("group_id = ? AND date = ?", group.ID, wednesday).Update("status", models.SessionCancelled)
+	_, err = sessionForAttendance(db, group.ID, wednesday, nil)
+	assert.Error(t, err)
+
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	session, err := sessionForAttendance(db, group.ID, monday, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "09:00", session.StartTime)
+}
+
+func TestClassSessionService_CancelAndReschedule(t *testing.T) {
+	db := setupTestDB()
+	service := NewClassSessionService(db)
+	ctx := context.Background()
+
+	timetable := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed"}
+	db.Create(&timetable)
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&group)
+	db.Create(&models.Student{GroupID: group.ID, Name: "Ali", Surname: "Valiev", Phone: "992900000001"})
+
+	// Another group uses Room 102 on Tuesdays at 14:00
+	otherTimetable := models.Timetable{Classroom: "Room 102", StartTime: "14:00", EndTime: "16:00", Days: "Tue"}
+	db.Create(&otherTimetable)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	db.Create(&models.Group{Name: "JS-1", TimetableID: otherTimetable.ID, Capacity: 10})
+
+	// 2026-03-02 is a Monday
+	resp, err := service.Generate(ctx, group.ID, dto.GenerateClassSessionsRequest{From: "2026-03-02", To: "2026-03-04"})
+	assert.NoError(t, err)
+	monday, wednesday := resp.Sessions[0], resp.Sessions[1]
+
+	notify := false
+	cancelled, err := service.Cancel(ctx, monday.ID, dto.CancelClassSessionRequest{
+		Reason: "Teacher is ill", CreateMakeUpCredit: true, MakeUpCreditValidDays: 30, Notify: &notify,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionCancelled, cancelled.Sessions[0].Status)
+	assert.Equal(t, 1, cancelled.MakeUpCredits)
+
+	// A cancelled lesson cannot be cancelled again
+	_, err = service.Cancel(ctx, monday.ID, dto.CancelClassSessionRequest{Reason: "again", Notify: &notify})
+	assert.Error(t, err)
+
+	// The other group's timetabled lesson occupies Room 102 on Tuesday
+	_, err = service.Reschedule(ctx, wednesday.ID, dto.RescheduleClassSessionRequest{
+		Date: "2026-03-03", StartTime: "15:00", EndTime: "17:00", Room: "Room 102", Notify: &notify,
+	})
+	assert.Error(t, err)
+
+	moved, err := service.Reschedule(ctx, wednesday.ID, dto.RescheduleClassSessionRequest{
+		Date: "2026-03-03", StartTime: "09:00", EndTime: "11:00", Notify: &notify,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-03-03", moved.Sessions[0].Date)
+	assert.Equal(t, wednesday.ID, *moved.Sessions[0].RescheduledFromID)
+
+	original, err := service.GetByID(ctx, wednesday.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionRescheduled, original.Status)
+
+	// Shifting Mondays onto Wednesdays reuses the slots the moved lessons free up
+	shifted, err := service.RescheduleRange(ctx, group.ID, dto.RescheduleClassSessionsRequest{
+		From: "2026-03-09", To: "2026-03-18", ShiftDays: 2, Notify: &notify,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, shifted.Sessions, 4)
+
+	// Shifting by a week would double up with the timetabled lessons that follow
+	_, err = service.RescheduleRange(ctx, group.ID, dto.RescheduleClassSessionsRequest{
+		From: "2026-03-23", To: "2026-03-23", ShiftDays: 7, Notify: &notify,
+	})
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/logger"
@@ -17,7 +19,7 @@ type CourseService interface {
 	Update(ctx context.Context, id string, req dto.UpdateCourseRequest) (*dto.CourseResponse, error)
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*dto.CourseResponse, error)
-	GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
+	GetAll(ctx context.Context, req dto.PaginationRequest, filter dto.CourseFilter) (*dto.PaginatedResponse, error)
 }
 
 type courseService struct {
@@ -33,16 +35,25 @@ func (s *courseService) Create(ctx context.Context, req dto.CreateCourseRequest)
 	logger.WithContext(map[string]interface{}{"title": req.Title}).Info().Msg("fetching all courses")
 
 	course := models.Course{
-		Title:      req.Title,
-		MonthlyFee: req.MonthlyFee,
-		Duration:   req.Duration,
+		Title:       req.Title,
+		MonthlyFee:  req.MonthlyFee,
+		Duration:    req.Duration,
+		Level:       req.Level,
+		Category:    req.Category,
+		Description: req.Description,
 	}
 
-	if err := s.db.Create(&course).Error; err != nil {
-		return nil, errors.DatabaseError("creating course", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&course).Error; err != nil {
+			return errors.DatabaseError("creating course", err)
+		}
+		return s.setPrerequisites(tx, course.ID, req.PrerequisiteIDs)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.toResponse(&course), nil
+	return s.GetByID(ctx, course.ID.String())
 }
 
 func (s *courseService) Update(ctx context.Context, id string, req dto.UpdateCourseRequest) (*dto.CourseResponse, error) {
@@ -57,22 +68,34 @@ func (s *courseService) Update(ctx context.Context, id string, req dto.UpdateCou
 	course.Title = req.Title
 	course.MonthlyFee = req.MonthlyFee
 	course.Duration = req.Duration
+	course.Level = req.Level
+	course.Category = req.Category
+	course.Description = req.Description
 
-	if err := s.db.Save(&course).Error; err != nil {
-		// The instruction provided for this line results in syntactically incorrect Go code.
-		// The original line was: return nil, errors.DatabaseError("updating course", err)
-		// The instruction was: return nil, errors.DatabaseErr.Info().Msg("updating course"), err)
-		// This attempts to call .Info().Msg() on an undefined 'errors.DatabaseErr' and
-		// incorrectly places 'err' as a third return value.
-		// Assuming the intent was to log before returning the error,
-		// but without a clear instruction on how to integrate it correctly with the error return,
-		// and to avoid breaking the syntax, this line is kept as is.
-		// If 'errors.DatabaseErr' is meant to be a logger, it needs to be defined,
-		// and the return statement would need to be restructured.
-		return nil, errors.DatabaseError("updating course", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&course).Error; err != nil {
+			// The instruction provided for this line results in syntactically incorrect Go code.
+			// The original line was: return nil, errors.DatabaseError("updating course", err)
+			// The instruction was: return nil, errors.DatabaseErr.Info().Msg("updating course"), err)
+			// This attempts to call .Info().Msg() on an undefined 'errors.DatabaseErr' and
+			// incorrectly places 'err' as a third return value.
+			// Assuming the intent was to log before returning the error,
+			// but without a clear instruction on how to integrate it correctly with the error return,
+			// and to avoid breaking the syntax, this line is kept as is.
+			// If 'errors.DatabaseErr' is meant to be a logger, it needs to be defined,
+			// and the return statement would need to be restructured.
+			return errors.DatabaseError("updating course", err)
+		}
+		if req.PrerequisiteIDs != nil {
+			return s.setPrerequisites(tx, course.ID, *req.PrerequisiteIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.toResponse(&course), nil
+	return s.GetByID(ctx, id)
 }
 
 func (s *courseService) Delete(ctx context.Context, id string) error {
@@ -94,12 +117,15 @@ func (s *courseService) Delete(ctx context.Context, id string) error {
 	if result.RowsAffected == 0 {
 		return errors.NotFoundWithID("Course", id)
 	}
+	if err := s.db.Where("course_id = ? OR prerequisite_id = ?", id, id).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+		return errors.DatabaseError("deleting course prerequisites", err)
+	}
 	return nil
 }
 
 func (s *courseService) GetByID(ctx context.Context, id string) (*dto.CourseResponse, error) {
 	var course models.Course
-	if err := s.db.Preload("Groups").First(&course, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Groups").Preload("Prerequisites.Prerequisite").First(&course, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundWithID("Course", id)
 		}
@@ -109,7 +135,7 @@ func (s *courseService) GetByID(ctx context.Context, id string) (*dto.CourseResp
 	return s.toResponse(&course), nil
 }
 
-func (s *courseService) GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+func (s *courseService) GetAll(ctx context.Context, req dto.PaginationRequest, filter dto.CourseFilter) (*dto.PaginatedResponse, error) {
 	var courses []models.Course
 	var total int64
 
@@ -119,6 +145,12 @@ func (s *courseService) GetAll(ctx context.Context, req dto.PaginationRequest) (
 		search := "%" + strings.ToLower(req.Search) + "%"
 		query = query.Where("LOWER(title) LIKE ?", search)
 	}
+	if filter.Level != "" {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.Category != "" {
+		query = query.Where("LOWER(category) = ?", strings.ToLower(filter.Category))
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, errors.DatabaseError("counting courses", err)
@@ -128,6 +160,7 @@ func (s *courseService) GetAll(ctx context.Context, req dto.PaginationRequest) (
 		Offset(req.GetOffset()).
 		Limit(req.GetLimit()).
 		Preload("Groups").
+		Preload("Prerequisites.Prerequisite").
 		Find(&courses).Error; err != nil {
 		return nil, errors.DatabaseError("listing courses", err)
 	}
@@ -155,13 +188,185 @@ func (s *courseService) toResponse(c *models.Course) *dto.CourseResponse {
 		}
 	}
 
+	prerequisites := make([]dto.CourseSimple, 0, len(c.Prerequisites))
+	for _, p := range c.Prerequisites {
+		if p.Prerequisite != nil {
+			prerequisites = append(prerequisites, toCourseSimple(p.Prerequisite))
+		}
+	}
+
 	return &dto.CourseResponse{
+		ID:             c.ID,
+		Title:          c.Title,
+		MonthlyFee:     c.MonthlyFee,
+		Duration:       c.Duration,
+		Level:          c.Level,
+		Category:       c.Category,
+		Description:    c.Description,
+		GradingScaleID: c.GradingScaleID,
+		Prerequisites:  prerequisites,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+		Groups:         groups,
+	}
+}
+
+// setPrerequisites replaces the prerequisites of a course. They must exist
+// and must not lead back to the course.
+func (s *courseService) setPrerequisites(tx *gorm.DB, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	unique := make([]uuid.UUID, 0, len(prerequisiteIDs))
+	seen := make(map[uuid.UUID]bool, len(prerequisiteIDs))
+	for _, id := range prerequisiteIDs {
+		if id == courseID {
+			return errors.Validation("A course cannot be its own prerequisite")
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > 0 {
+		var count int64
+		if err := tx.Model(&models.Course{}).Where("id IN ?", unique).Count(&count).Error; err != nil {
+			return errors.DatabaseError("checking prerequisites", err)
+		}
+		if int(count) != len(unique) {
+			return errors.NotFound("Prerequisite course")
+		}
+		if err := ensureNoPrerequisiteCycle(tx, courseID, unique); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("course_id = ?", courseID).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+		return errors.DatabaseError("clearing prerequisites", err)
+	}
+	for _, id := range unique {
+		if err := tx.Create(&models.CoursePrerequisite{CourseID: courseID, PrerequisiteID: id}).Error; err != nil {
+			return errors.DatabaseError("adding prerequisite", err)
+		}
+	}
+	return nil
+}
+
+// ensureNoPrerequisiteCycle checks that none of the new prerequisites of a
+// course requires the course itself, directly or through other courses
+func ensureNoPrerequisiteCycle(tx *gorm.DB, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	var edges []models.CoursePrerequisite
+	if err := tx.Where("course_id <> ?", courseID).Find(&edges).Error; err != nil {
+		return errors.DatabaseError("loading course graph", err)
+	}
+	requires := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		requires[e.CourseID] = append(requires[e.CourseID], e.PrerequisiteID)
+	}
+
+	// Depth-first search from each new prerequisite, remembering the path
+	var path []uuid.UUID
+	visited := make(map[uuid.UUID]bool)
+	var leadsBack func(id uuid.UUID) bool
+	leadsBack = func(id uuid.UUID) bool {
+		path = append(path, id)
+		if id == courseID {
+			return true
+		}
+		if !visited[id] {
+			visited[id] = true
+			for _, next := range requires[id] {
+				if leadsBack(next) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	for _, id := range prerequisiteIDs {
+		path = []uuid.UUID{courseID}
+		if !leadsBack(id) {
+			continue
+		}
+		var courses []models.Course
+		tx.Select("id", "title").Where("id IN ?", path).Find(&courses)
+		titles := make(map[uuid.UUID]string, len(courses))
+		for _, c := range courses {
+			titles[c.ID] = c.Title
+		}
+		names := make([]string, len(path))
+		for i, id := range path {
+			names[i] = titles[id]
+		}
+		return errors.New(errors.ErrCodeInvalidOperation, "Prerequisites would form a cycle").
+			WithDetail("cycle", strings.Join(names, " → "))
+	}
+	return nil
+}
+
+func toCourseSimple(c *models.Course) dto.CourseSimple {
+	return dto.CourseSimple{
 		ID:         c.ID,
 		Title:      c.Title,
 		MonthlyFee: c.MonthlyFee,
 		Duration:   c.Duration,
-		CreatedAt:  c.CreatedAt,
-		UpdatedAt:  c.UpdatedAt,
-		Groups:     groups,
+		Level:      c.Level,
+	}
+}
+
+// prerequisiteWarnings lists the prerequisites of a course a person has not
+// completed. People are matched to their student records by email or phone;
+// a course counts as completed with an issued certificate or a passed report
+// card for it. Missing prerequisites only warn, they do not block.
+func prerequisiteWarnings(db *gorm.DB, courseID uuid.UUID, email, phone string) ([]string, error) {
+	var prerequisites []models.Course
+	if err := db.Joins("JOIN course_prerequisites ON course_prerequisites.prerequisite_id = courses.id").
+		Where("course_prerequisites.course_id = ?", courseID).
+		Order("courses.title").Find(&prerequisites).Error; err != nil {
+		return nil, errors.DatabaseError("finding course prerequisites", err)
+	}
+	if len(prerequisites) == 0 {
+		return nil, nil
+	}
+
+	var studentIDs []uuid.UUID
+	if email != "" || phone != "" {
+		query := db.Model(&models.Student{})
+		switch {
+		case email != "" && phone != "":
+			query = query.Where("LOWER(email) = ? OR phone = ?", strings.ToLower(email), phone)
+		case email != "":
+			query = query.Where("LOWER(email) = ?", strings.ToLower(email))
+		default:
+			query = query.Where("phone = ?", phone)
+		}
+		if err := query.Pluck("id", &studentIDs).Error; err != nil {
+			return nil, errors.DatabaseError("finding student records", err)
+		}
+	}
+
+	completed := make(map[uuid.UUID]bool)
+	if len(studentIDs) > 0 {
+		var certified, passed []uuid.UUID
+		if err := db.Model(&models.Certificate{}).
+			Where("student_id IN ? AND status = ?", studentIDs, models.CertificateIssued).
+			Pluck("course_id", &certified).Error; err != nil {
+			return nil, errors.DatabaseError("finding certificates", err)
+		}
+		if err := db.Model(&models.ReportCard{}).
+			Where("student_id IN ? AND passed = ?", studentIDs, true).
+			Pluck("course_id", &passed).Error; err != nil {
+			return nil, errors.DatabaseError("finding report cards", err)
+		}
+		for _, id := range append(certified, passed...) {
+			completed[id] = true
+		}
+	}
+
+	var warnings []string
+	for _, p := range prerequisites {
+		if !completed[p.ID] {
+			warnings = append(warnings, fmt.Sprintf("Prerequisite %q has not been completed", p.Title))
+		}
 	}
+	return warnings, nil
 }
@@ -61,17 +61,26 @@ func NewDocumentService(db *gorm.DB) *DocumentService {
 	}
 }
 
-// Upload uploads a new document
-func (s *DocumentService) Upload(ctx context.Context, req dto.UploadDocumentRequest, file *multipart.FileHeader, uploaderID uuid.UUID) (*dto.DocumentResponse, error) {
+// ValidateUpload checks an uploaded file against the size and type limits
+func (s *DocumentService) ValidateUpload(file *multipart.FileHeader) error {
 	// Security: Validate file size
 	if file.Size > maxFileSize {
-		return nil, fmt.Errorf("file size exceeds maximum allowed (%d MB)", maxFileSize/(1024*1024))
+		return fmt.Errorf("file size exceeds maximum allowed (%d MB)", maxFileSize/(1024*1024))
 	}
 
 	// Security: Validate MIME type
 	mimeType := file.Header.Get("Content-Type")
 	if !allowedMimeTypes[mimeType] {
-		return nil, fmt.Errorf("file type not allowed: %s", mimeType)
+		return fmt.Errorf("file type not allowed: %s", mimeType)
+	}
+	return nil
+}
+
+// Upload uploads a new document. uploaderID is nil for files sent by
+// applicants and students without a user account.
+func (s *DocumentService) Upload(ctx context.Context, req dto.UploadDocumentRequest, file *multipart.FileHeader, uploaderID *uuid.UUID) (*dto.DocumentResponse, error) {
+	if err := s.ValidateUpload(file); err != nil {
+		return nil, err
 	}
 
 	// Open uploaded file
@@ -108,22 +117,24 @@ func (s *DocumentService) Upload(ctx context.Context, req dto.UploadDocumentRequ
 
 	// Create document record
 	document := models.Document{
-		Name:        req.Name,
-		Description: req.Description,
-		Type:        req.Type,
-		Status:      models.DocumentStatusPending,
-		FileName:    file.Filename,
-		FilePath:    filePath,
-		FileSize:    file.Size,
-		MimeType:    file.Header.Get("Content-Type"),
-		StudentID:   req.StudentID,
-		TeacherID:   req.TeacherID,
-		CourseID:    req.CourseID,
-		GroupID:     req.GroupID,
-		UploadedBy:  uploaderID,
-		UploadedAt:  time.Now(),
-		Tags:        req.Tags,
-		Metadata:    req.Metadata,
+		ID:            uuid.New(),
+		Name:          req.Name,
+		Description:   req.Description,
+		Type:          req.Type,
+		Status:        models.DocumentStatusPending,
+		FileName:      file.Filename,
+		FilePath:      filePath,
+		FileSize:      file.Size,
+		MimeType:      file.Header.Get("Content-Type"),
+		StudentID:     req.StudentID,
+		TeacherID:     req.TeacherID,
+		CourseID:      req.CourseID,
+		GroupID:       req.GroupID,
+		ApplicationID: req.ApplicationID,
+		UploadedBy:    uploaderID,
+		UploadedAt:    time.Now(),
+		Tags:          req.Tags,
+		Metadata:      req.Metadata,
 	}
 
 	if err := s.db.Create(&document).Error; err != nil {
@@ -135,6 +146,42 @@ func (s *DocumentService) Upload(ctx context.Context, req dto.UploadDocumentRequ
 	return s.toResponse(&document), nil
 }
 
+// SaveGenerated stores a server-generated file (report card, transcript,
+// certificate) and creates an approved document record for it
+func (s *DocumentService) SaveGenerated(ctx context.Context, document *models.Document, content []byte) error {
+	typeDir := filepath.Join(s.uploadPath, string(document.Type))
+	if err := os.MkdirAll(typeDir, 0755); err != nil {
+		return fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	if document.ID == uuid.Nil {
+		document.ID = uuid.New()
+	}
+	filePath := filepath.Join(typeDir, document.ID.String()+filepath.Ext(document.FileName))
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+
+	now := time.Now()
+	document.FilePath = filePath
+	document.FileSize = int64(len(content))
+	document.Status = models.DocumentStatusApproved
+	document.UploadedAt = now
+	document.ApprovedAt = &now
+
+	if err := s.db.WithContext(ctx).Create(document).Error; err != nil {
+		os.Remove(filePath)
+		return fmt.Errorf("failed to create document record: %w", err)
+	}
+
+	return nil
+}
+
+// DownloadURL returns the download link for a document
+func (s *DocumentService) DownloadURL(id uuid.UUID) string {
+	return fmt.Sprintf("%s/api/v1/documents/%s/download", s.baseURL, id)
+}
+
 // GetByID retrieves a document by ID
 func (s *DocumentService) GetByID(ctx context.Context, id string) (*dto.DocumentResponse, error) {
 	var document models.Document
@@ -331,30 +378,31 @@ func (s *DocumentService) GetFilePath(ctx context.Context, id string) (string, e
 
 // toResponse converts a document model to response DTO
 func (s *DocumentService) toResponse(d *models.Document) *dto.DocumentResponse {
-	downloadURL := fmt.Sprintf("%s/api/v1/documents/%s/download", s.baseURL, d.ID)
+	downloadURL := s.DownloadURL(d.ID)
 
 	return &dto.DocumentResponse{
-		ID:          d.ID,
-		Name:        d.Name,
-		Description: d.Description,
-		Type:        d.Type,
-		Status:      d.Status,
-		FileName:    d.FileName,
-		FilePath:    d.FilePath,
-		FileSize:    d.FileSize,
-		MimeType:    d.MimeType,
-		StudentID:   d.StudentID,
-		TeacherID:   d.TeacherID,
-		CourseID:    d.CourseID,
-		GroupID:     d.GroupID,
-		UploadedBy:  d.UploadedBy,
-		UploadedAt:  d.UploadedAt,
-		ApprovedBy:  d.ApprovedBy,
-		ApprovedAt:  d.ApprovedAt,
-		Tags:        d.Tags,
-		Metadata:    d.Metadata,
-		CreatedAt:   d.CreatedAt,
-		UpdatedAt:   d.UpdatedAt,
-		DownloadURL: downloadURL,
+		ID:            d.ID,
+		Name:          d.Name,
+		Description:   d.Description,
+		Type:          d.Type,
+		Status:        d.Status,
+		FileName:      d.FileName,
+		FilePath:      d.FilePath,
+		FileSize:      d.FileSize,
+		MimeType:      d.MimeType,
+		StudentID:     d.StudentID,
+		TeacherID:     d.TeacherID,
+		CourseID:      d.CourseID,
+		GroupID:       d.GroupID,
+		ApplicationID: d.ApplicationID,
+		UploadedBy:    d.UploadedBy,
+		UploadedAt:    d.UploadedAt,
+		ApprovedBy:    d.ApprovedBy,
+		ApprovedAt:    d.ApprovedAt,
+		Tags:          d.Tags,
+		Metadata:      d.Metadata,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+		DownloadURL:   downloadURL,
 	}
 }
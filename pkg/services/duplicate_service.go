@@ -0,0 +1,691 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDuplicateMinScore = 0.7
+	defaultDuplicateLimit    = 100
+
+	// Names whose parts are at least this similar count as the same name
+	duplicateNameThreshold = 0.85
+
+	// Local numbers of localPhoneDigits digits are assumed to be in defaultPhoneCountryCode
+	defaultPhoneCountryCode = "992"
+	localPhoneDigits        = 9
+)
+
+// DuplicateService finds records that describe the same person and merges them
+type DuplicateService struct {
+	db *gorm.DB
+}
+
+// NewDuplicateService creates a new duplicate service
+func NewDuplicateService(db *gorm.DB) *DuplicateService {
+	return &DuplicateService{db: db}
+}
+
+// mergeReference is a column pointing at a student or parent that a merge
+// re-points to the surviving record
+type mergeReference struct {
+	table  string
+	column string
+	where  string   // Extra condition, e.g. the entity type of custom field values
+	unique []string // Columns unique together with column; rows that would clash stay with the merged record
+}
+
+var studentMergeReferences = []mergeReference{
+	{table: "attendances", column: "student_id"},
+	{table: "grades", column: "student_id"},
+	{table: "invoices", column: "student_id"},
+	{table: "recurring_invoices", column: "student_id"},
+	{table: "payments", column: "student_id"},
+	{table: "scholarships", column: "student_id"},
+	{table: "documents", column: "student_id"},
+	{table: "parent_students", column: "student_id", unique: []string{"parent_id"}},
+	{table: "assignment_submissions", column: "student_id"},
+	{table: "exam_results", column: "student_id", unique: []string{"exam_id"}},
+	{table: "report_cards", column: "student_id", unique: []string{"group_id", "term"}},
+	{table: "certificates", column: "student_id"},
+	{table: "attendance_alerts", column: "student_id", unique: []string{"date", "type"}},
+	{table: "make_up_credits", column: "student_id"},
+	{table: "student_transfers", column: "student_id"},
+	{table: "notifications", column: "student_id"},
+	{table: "users", column: "student_id"},
+	{table: "leads", column: "student_id"},
+	{table: "waitlists", column: "student_id"},
+	{table: "seat_holds", column: "student_id"},
+	{table: "applications", column: "enrolled_as"},
+	{table: "custom_field_values", column: "entity_id", where: "field_id IN (SELECT id FROM custom_fields WHERE entity_type = 'student')"},
+}
+
+var parentMergeReferences = []mergeReference{
+	{table: "parent_students", column: "parent_id", unique: []string{"student_id"}},
+	{table: "custom_field_values", column: "entity_id", where: "field_id IN (SELECT id FROM custom_fields WHERE entity_type = 'parent')"},
+}
+
+// duplicateRecord is a person-like record prepared for comparison
+type duplicateRecord struct {
+	dto.DuplicateRecord
+	first, last string // Normalized name parts
+}
+
+// FindCandidates pairs up students, parents, applications and waitlist
+// prospects that probably describe the same person. Records are compared
+// when they share a normalized phone, an email or name initials, and scored
+// on matching contacts and fuzzy name similarity. Parents are only compared
+// with parents, since a child often shares a parent's contacts.
+func (s *DuplicateService) FindCandidates(ctx context.Context, filter dto.DuplicateFilter) ([]dto.DuplicateCandidate, error) {
+	minScore := filter.MinScore
+	if minScore == 0 {
+		minScore = defaultDuplicateMinScore
+	}
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultDuplicateLimit
+	}
+
+	records, err := s.loadRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[string][]int)
+	for i, r := range records {
+		if r.Phone != "" {
+			blocks["p:"+r.Phone] = append(blocks["p:"+r.Phone], i)
+		}
+		if r.Email != "" {
+			blocks["e:"+r.Email] = append(blocks["e:"+r.Email], i)
+		}
+		if key := nameBlockKey(r.first, r.last); key != "" {
+			blocks["n:"+key] = append(blocks["n:"+key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	candidates := make([]dto.DuplicateCandidate, 0)
+	for _, block := range blocks {
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				pair := [2]int{block[x], block[y]}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+
+				left, right := &records[pair[0]], &records[pair[1]]
+				if !duplicateComparable(left.Type, right.Type) {
+					continue
+				}
+				if filter.Type != "" && left.Type != filter.Type && right.Type != filter.Type {
+					continue
+				}
+				score, reasons := scoreDuplicate(left, right)
+				if score < minScore {
+					continue
+				}
+				candidates = append(candidates, dto.DuplicateCandidate{
+					Left:      left.DuplicateRecord,
+					Right:     right.DuplicateRecord,
+					Score:     score,
+					Reasons:   reasons,
+					Mergeable: left.Type == right.Type && (left.Type == string(models.MergeStudent) || left.Type == string(models.MergeParent)),
+				})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Left.Name < candidates[j].Left.Name
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// loadRecords loads every student, parent, open application and waitlist
+// prospect with normalized contacts
+func (s *DuplicateService) loadRecords() ([]duplicateRecord, error) {
+	var records []duplicateRecord
+	add := func(kind string, id uuid.UUID, first, last, email, phone string) {
+		first, last = normalizeName(first), normalizeName(last)
+		if last == "" {
+			first, last = splitNormalizedName(first)
+		}
+		records = append(records, duplicateRecord{
+			DuplicateRecord: dto.DuplicateRecord{
+				Type:  kind,
+				ID:    id,
+				Name:  strings.TrimSpace(strings.Join(strings.Fields(first+" "+last), " ")),
+				Email: normalizeEmail(email),
+				Phone: normalizePhone(phone),
+			},
+			first: first,
+			last:  last,
+		})
+	}
+
+	var students []models.Student
+	if err := s.db.Select("id", "name", "surname", "email", "phone").Find(&students).Error; err != nil {
+		return nil, errors.DatabaseError("loading students", err)
+	}
+	for _, st := range students {
+		add("student", st.ID, st.Name, st.Surname, st.Email, st.Phone)
+	}
+
+	var parents []models.Parent
+	if err := s.db.Select("id", "first_name", "last_name", "email", "phone").Find(&parents).Error; err != nil {
+		return nil, errors.DatabaseError("loading parents", err)
+	}
+	for _, p := range parents {
+		add("parent", p.ID, p.FirstName, p.LastName, p.Email, p.Phone)
+	}
+
+	var applications []models.Application
+	if err := s.db.Select("id", "first_name", "last_name", "email", "phone").
+		Where("enrolled_as IS NULL AND status <> ?", models.ApplicationRejected).Find(&applications).Error; err != nil {
+		return nil, errors.DatabaseError("loading applications", err)
+	}
+	for _, a := range applications {
+		add("application", a.ID, a.FirstName, a.LastName, a.Email, a.Phone)
+	}
+
+	var prospects []models.Waitlist
+	if err := s.db.Select("id", "prospect_name", "prospect_email", "prospect_phone").
+		Where("student_id IS NULL AND status IN ?", []models.WaitlistStatus{models.WaitlistPending, models.WaitlistNotified}).
+		Find(&prospects).Error; err != nil {
+		return nil, errors.DatabaseError("loading waitlist prospects", err)
+	}
+	for _, w := range prospects {
+		add("waitlist", w.ID, w.ProspectName, "", w.ProspectEmail, w.ProspectPhone)
+	}
+
+	return records, nil
+}
+
+// duplicateComparable reports whether two kinds of record can describe the
+// same person: parents only match parents
+func duplicateComparable(a, b string) bool {
+	return (a == "parent") == (b == "parent")
+}
+
+// scoreDuplicate scores how likely two records are the same person. A
+// matching name is worth up to 0.5 and each matching contact 0.3, so
+// contacts shared by siblings do not reach the default threshold on their own.
+func scoreDuplicate(a, b *duplicateRecord) (float64, []string) {
+	var score float64
+	reasons := make([]string, 0, 3)
+
+	if a.Email != "" && a.Email == b.Email {
+		score += 0.3
+		reasons = append(reasons, "same_email")
+	}
+	if a.Phone != "" && a.Phone == b.Phone {
+		score += 0.3
+		reasons = append(reasons, "same_phone")
+	}
+	if similarity := nameSimilarity(a, b); similarity >= duplicateNameThreshold {
+		score += 0.5 * similarity
+		reasons = append(reasons, "similar_name")
+	}
+
+	return math.Round(math.Min(score, 1)*100) / 100, reasons
+}
+
+// nameSimilarity compares first and last names separately, also swapped, and
+// returns the weaker part's Jaro-Winkler similarity of the better ordering
+func nameSimilarity(a, b *duplicateRecord) float64 {
+	if a.first == "" || b.first == "" {
+		return 0
+	}
+	if a.last == "" || b.last == "" {
+		return jaroWinkler(strings.TrimSpace(a.first+" "+a.last), strings.TrimSpace(b.first+" "+b.last))
+	}
+	direct := math.Min(jaroWinkler(a.first, b.first), jaroWinkler(a.last, b.last))
+	swapped := math.Min(jaroWinkler(a.first, b.last), jaroWinkler(a.last, b.first))
+	return math.Max(direct, swapped)
+}
+
+// nameBlockKey groups names by the initials of their parts, in either order
+func nameBlockKey(first, last string) string {
+	if first == "" || last == "" {
+		return ""
+	}
+	initials := []string{string([]rune(first)[0]), string([]rune(last)[0])}
+	sort.Strings(initials)
+	return initials[0] + initials[1]
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings, from 0 to 1
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	window := int(math.Max(float64(len(s1)), float64(len(s2))))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		lo, hi := i-window, i+window+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(s2) {
+			hi = len(s2)
+		}
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(s1) && prefix < len(s2) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// normalizeName lower-cases a name and keeps only its letters and spaces
+func normalizeName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r), r == '-':
+			return ' '
+		default:
+			return -1
+		}
+	}, name)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// splitNormalizedName splits a full normalized name into its first word and the rest
+func splitNormalizedName(name string) (string, string) {
+	first, rest, _ := strings.Cut(name, " ")
+	return first, rest
+}
+
+// normalizeEmail lower-cases and trims an email address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizePhone formats a phone number as E.164. Numbers written with 00
+// are international; bare local numbers get the default country code.
+func normalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+
+	switch {
+	case digits == "":
+		return ""
+	case strings.HasPrefix(phone, "+"):
+		return "+" + digits
+	case strings.HasPrefix(digits, "00"):
+		return "+" + digits[2:]
+	case len(digits) == localPhoneDigits:
+		return "+" + defaultPhoneCountryCode + digits
+	case len(digits) == localPhoneDigits+1 && digits[0] == '0':
+		return "+" + defaultPhoneCountryCode + digits[1:]
+	default:
+		return "+" + digits
+	}
+}
+
+// Merge folds a duplicate student or parent into the record that survives.
+// Everything pointing at the duplicate is re-pointed to the survivor, blank
+// survivor contacts are filled in from the duplicate, and the duplicate is
+// deleted. The merge is audited and can be undone.
+func (s *DuplicateService) Merge(ctx context.Context, req dto.MergeRecordsRequest, userID *uuid.UUID) (*dto.RecordMergeResponse, error) {
+	if req.SurvivorID == req.MergedID {
+		return nil, errors.Validation("A record cannot be merged into itself")
+	}
+
+	merge := models.RecordMerge{
+		ID:         uuid.New(),
+		EntityType: req.EntityType,
+		SurvivorID: req.SurvivorID,
+		MergedID:   req.MergedID,
+		Score:      req.Score,
+		MergedBy:   userID,
+		MergedAt:   time.Now(),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var snapshot interface{}
+		var references []mergeReference
+
+		switch req.EntityType {
+		case models.MergeStudent:
+			survivor, merged, err := loadMergeStudents(tx, req.SurvivorID, req.MergedID)
+			if err != nil {
+				return err
+			}
+			merge.Filled, err = fillBlanks(tx, &models.Student{}, survivor.ID, map[string][2]string{
+				"email": {survivor.Email, merged.Email},
+				"phone": {survivor.Phone, merged.Phone},
+			})
+			if err != nil {
+				return err
+			}
+			snapshot, references = merged, studentMergeReferences
+		case models.MergeParent:
+			survivor, merged, err := loadMergeParents(tx, req.SurvivorID, req.MergedID)
+			if err != nil {
+				return err
+			}
+			// The email stays with the deleted duplicate; it is unique
+			alternate := merged.AlternatePhone
+			if normalizePhone(merged.Phone) != normalizePhone(survivor.Phone) {
+				alternate = merged.Phone
+			}
+			merge.Filled, err = fillBlanks(tx, &models.Parent{}, survivor.ID, map[string][2]string{
+				"alternate_phone": {survivor.AlternatePhone, alternate},
+				"address":         {survivor.Address, merged.Address},
+				"city":            {survivor.City, merged.City},
+				"country":         {survivor.Country, merged.Country},
+				"occupation":      {survivor.Occupation, merged.Occupation},
+				"workplace":       {survivor.Workplace, merged.Workplace},
+			})
+			if err != nil {
+				return err
+			}
+			snapshot, references = merged, parentMergeReferences
+		default:
+			return errors.Validation("entity_type must be student or parent")
+		}
+
+		moved, err := repointReferences(tx, references, req.MergedID, req.SurvivorID)
+		if err != nil {
+			return err
+		}
+		merge.Moved = moved
+
+		if err := deleteMergedRecord(tx, req.EntityType, req.MergedID); err != nil {
+			return err
+		}
+		if err := tx.Create(&merge).Error; err != nil {
+			return errors.DatabaseError("recording merge", err)
+		}
+		return auditMerge(tx, &merge, snapshot, s.toResponse(&merge))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(&merge), nil
+}
+
+// UndoMerge restores the duplicate of a merge and moves its rows back
+func (s *DuplicateService) UndoMerge(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*dto.RecordMergeResponse, error) {
+	var merge models.RecordMerge
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&merge, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("Merge", id.String())
+			}
+			return errors.DatabaseError("finding merge", err)
+		}
+		if merge.UndoneAt != nil {
+			return errors.New(errors.ErrCodeInvalidOperation, "This merge has already been undone")
+		}
+
+		model, err := mergeModel(merge.EntityType)
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(model).Where("id = ?", merge.MergedID).Update("deleted_at", nil).Error; err != nil {
+			return errors.DatabaseError("restoring merged record", err)
+		}
+
+		for key, ids := range merge.Moved {
+			table, column, _ := strings.Cut(key, ".")
+			if err := tx.Table(table).Where("id IN ? AND "+column+" = ?", ids, merge.SurvivorID).
+				Update(column, merge.MergedID).Error; err != nil {
+				return errors.DatabaseError("moving "+table+" back", err)
+			}
+		}
+		if len(merge.Filled) > 0 {
+			if err := tx.Model(model).Where("id = ?", merge.SurvivorID).Updates(merge.Filled).Error; err != nil {
+				return errors.DatabaseError("restoring survivor", err)
+			}
+		}
+
+		now := time.Now()
+		merge.UndoneAt = &now
+		merge.UndoneBy = userID
+		if err := tx.Save(&merge).Error; err != nil {
+			return errors.DatabaseError("recording undo", err)
+		}
+		return auditMerge(tx, &merge, s.toResponse(&merge), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(&merge), nil
+}
+
+// loadMergeStudents loads the survivor and duplicate of a student merge
+func loadMergeStudents(tx *gorm.DB, survivorID, mergedID uuid.UUID) (*models.Student, *models.Student, error) {
+	var survivor, merged models.Student
+	if err := tx.First(&survivor, "id = ?", survivorID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.NotFoundWithID("Student", survivorID.String())
+		}
+		return nil, nil, errors.DatabaseError("finding student", err)
+	}
+	if err := tx.First(&merged, "id = ?", mergedID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.NotFoundWithID("Student", mergedID.String())
+		}
+		return nil, nil, errors.DatabaseError("finding student", err)
+	}
+	return &survivor, &merged, nil
+}
+
+// loadMergeParents loads the survivor and duplicate of a parent merge
+func loadMergeParents(tx *gorm.DB, survivorID, mergedID uuid.UUID) (*models.Parent, *models.Parent, error) {
+	var survivor, merged models.Parent
+	if err := tx.First(&survivor, "id = ?", survivorID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.NotFoundWithID("Parent", survivorID.String())
+		}
+		return nil, nil, errors.DatabaseError("finding parent", err)
+	}
+	if err := tx.First(&merged, "id = ?", mergedID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.NotFoundWithID("Parent", mergedID.String())
+		}
+		return nil, nil, errors.DatabaseError("finding parent", err)
+	}
+	return &survivor, &merged, nil
+}
+
+// fillBlanks copies the duplicate's value into each blank survivor column,
+// given as column: {survivor value, duplicate value}. It returns the filled
+// columns with their previous values.
+func fillBlanks(tx *gorm.DB, model interface{}, survivorID uuid.UUID, columns map[string][2]string) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	previous := make(map[string]interface{})
+	for column, values := range columns {
+		if strings.TrimSpace(values[0]) == "" && strings.TrimSpace(values[1]) != "" {
+			updates[column] = values[1]
+			previous[column] = values[0]
+		}
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	if err := tx.Model(model).Where("id = ?", survivorID).Updates(updates).Error; err != nil {
+		return nil, errors.DatabaseError("filling in survivor", err)
+	}
+	return previous, nil
+}
+
+// repointReferences re-points every reference to the duplicate at the
+// survivor and returns the moved row IDs per "table.column". Tables that do
+// not exist in this database are skipped.
+func repointReferences(tx *gorm.DB, references []mergeReference, mergedID, survivorID uuid.UUID) (map[string][]uuid.UUID, error) {
+	moved := make(map[string][]uuid.UUID)
+	for _, ref := range references {
+		if !tx.Migrator().HasTable(ref.table) {
+			continue
+		}
+
+		query := tx.Table(ref.table).Where(ref.column+" = ?", mergedID)
+		if ref.where != "" {
+			query = query.Where(ref.where)
+		}
+		if len(ref.unique) > 0 {
+			clash := "SELECT 1 FROM " + ref.table + " AS kept WHERE kept." + ref.column + " = ?"
+			for _, column := range ref.unique {
+				clash += " AND kept." + column + " = " + ref.table + "." + column
+			}
+			query = query.Where("NOT EXISTS ("+clash+")", survivorID)
+		}
+
+		var ids []uuid.UUID
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return nil, errors.DatabaseError("finding "+ref.table, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := tx.Table(ref.table).Where("id IN ?", ids).Update(ref.column, survivorID).Error; err != nil {
+			return nil, errors.DatabaseError("re-pointing "+ref.table, err)
+		}
+		key := ref.table + "." + ref.column
+		moved[key] = append(moved[key], ids...)
+	}
+	return moved, nil
+}
+
+// deleteMergedRecord soft-deletes the duplicate of a merge
+func deleteMergedRecord(tx *gorm.DB, entity models.MergeEntity, id uuid.UUID) error {
+	model, err := mergeModel(entity)
+	if err != nil {
+		return err
+	}
+	if err := tx.Delete(model, "id = ?", id).Error; err != nil {
+		return errors.DatabaseError("deleting merged record", err)
+	}
+	return nil
+}
+
+// mergeModel returns the model of a merged entity type
+func mergeModel(entity models.MergeEntity) (interface{}, error) {
+	switch entity {
+	case models.MergeStudent:
+		return &models.Student{}, nil
+	case models.MergeParent:
+		return &models.Parent{}, nil
+	}
+	return nil, errors.Validation("entity_type must be student or parent")
+}
+
+// auditMerge records a merge, or its undo, in the audit log
+func auditMerge(tx *gorm.DB, merge *models.RecordMerge, oldValue, newValue interface{}) error {
+	userID := merge.MergedBy
+	if merge.UndoneAt != nil {
+		userID = merge.UndoneBy
+	}
+
+	log := models.AuditLog{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Action:     models.AuditActionUpdate,
+		Resource:   string(merge.EntityType) + "_merge",
+		ResourceID: &merge.ID,
+		Success:    true,
+	}
+	if oldValue != nil {
+		if data, err := json.Marshal(oldValue); err == nil {
+			log.OldValue = string(data)
+		}
+	}
+	if newValue != nil {
+		if data, err := json.Marshal(newValue); err == nil {
+			log.NewValue = string(data)
+		}
+	}
+	if err := tx.Create(&log).Error; err != nil {
+		return errors.DatabaseError("auditing merge", err)
+	}
+	return nil
+}
+
+// toResponse converts a merge to its DTO
+func (s *DuplicateService) toResponse(m *models.RecordMerge) *dto.RecordMergeResponse {
+	moved := make(map[string]int, len(m.Moved))
+	for key, ids := range m.Moved {
+		moved[key] = len(ids)
+	}
+	return &dto.RecordMergeResponse{
+		ID:         m.ID,
+		EntityType: m.EntityType,
+		SurvivorID: m.SurvivorID,
+		MergedID:   m.MergedID,
+		Score:      m.Score,
+		Moved:      moved,
+		Filled:     m.Filled,
+		MergedBy:   m.MergedBy,
+		MergedAt:   m.MergedAt,
+		UndoneBy:   m.UndoneBy,
+		UndoneAt:   m.UndoneAt,
+	}
+}
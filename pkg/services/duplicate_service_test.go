@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	assert.Equal(t, "+992900000071", normalizePhone("90 000 00 71"))
+	assert.Equal(t, "+992900000071", normalizePhone("0900-000-071"))
+	assert.Equal(t, "+992900000071", normalizePhone("992900000071"))
+	assert.Equal(t, "+992900000071", normalizePhone("+992 (90) 000-00-71"))
+	assert.Equal(t, "+79001234567", normalizePhone("0079001234567"))
+	assert.Equal(t, "", normalizePhone(" - "))
+}
+
+func TestDuplicateService_FindMergeAndUndo(t *testing.T) {
+	db := setupTestDB()
+	duplicates := NewDuplicateService(db)
+	ctx := context.Background()
+
+	group := models.Group{Name: "GO-1", Capacity: 10}
+	db.Create(&group)
+	survivor := models.Student{GroupID: group.ID, Name: "Rustam", Surname: "Karimov", Phone: "992900000071"}
+	merged := models.Student{GroupID: group.ID, Name: "Rustam", Surname: "Karimova", Phone: "900000071", Email: "rustam@example.com"}
+	sibling := models.Student{GroupID: group.ID, Name: "Nilufar", Surname: "Karimova", Phone: "992900000071"}
+	db.Create(&survivor)
+	db.Create(&merged)
+	db.Create(&sibling)
+	db.Create(&models.Waitlist{ID: uuid.New(), GroupID: group.ID, ProspectName: "Karimov Rustam", ProspectPhone: "+992 90 000 00 71", Status: models.WaitlistPending, RequestedAt: time.Now()})
+
+	candidates, err := duplicates.FindCandidates(ctx, dto.DuplicateFilter{})
+	assert.NoError(t, err)
+	var studentPair, prospectPair bool
+	for _, c := range candidates {
+		ids := map[uuid.UUID]bool{c.Left.ID: true, c.Right.ID: true}
+		assert.False(t, ids[sibling.ID], "siblings sharing a phone are not duplicates")
+		if ids[survivor.ID] && ids[merged.ID] {
+			studentPair = true
+			assert.True(t, c.Mergeable)
+			assert.Contains(t, c.Reasons, "same_phone")
+		}
+		if ids[survivor.ID] && (c.Left.Type == "waitlist" || c.Right.Type == "waitlist") {
+			prospectPair = true
+			assert.False(t, c.Mergeable)
+		}
+	}
+	assert.True(t, studentPair)
+	assert.True(t, prospectPair)
+
+	// Both students are linked to the same parent; the clashing link stays behind
+	parent := models.Parent{ID: uuid.New(), FirstName: "Aziz", LastName: "Karimov", Phone: "992900000070"}
+	db.Create(&parent)
+	db.Create(&models.ParentStudent{ID: uuid.New(), ParentID: parent.ID, StudentID: survivor.ID, Relation: models.RelationFather})
+	db.Create(&models.ParentStudent{ID: uuid.New(), ParentID: parent.ID, StudentID: merged.ID, Relation: models.RelationFather})
+	attendance := models.Attendance{StudentID: merged.ID, GroupID: group.ID, Date: time.Now(), Status: models.StatusPresent}
+	db.Create(&attendance)
+
+	merge, err := duplicates.Merge(ctx, dto.MergeRecordsRequest{EntityType: models.MergeStudent, SurvivorID: survivor.ID, MergedID: merged.ID}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, merge.Moved["attendances.student_id"])
+	assert.Zero(t, merge.Moved["parent_students.student_id"])
+	assert.Equal(t, "", merge.Filled["email"])
+
+	var reloaded models.Student
+	db.First(&reloaded, "id = ?", survivor.ID)
+	assert.Equal(t, "rustam@example.com", reloaded.Email)
+	var moved models.Attendance
+	db.First(&moved, "id = ?", attendance.ID)
+	assert.Equal(t, survivor.ID, moved.StudentID)
+	var remaining int64
+	db.Model(&models.Student{}).Where("id = ?", merged.ID).Count(&remaining)
+	assert.Zero(t, remaining)
+	var audits int64
+	db.Model(&models.AuditLog{}).Where("resource = ?", "student_merge").Count(&audits)
+	assert.Equal(t, int64(1), audits)
+
+	undone, err := duplicates.UndoMerge(ctx, merge.ID, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, undone.UndoneAt)
+
+	var restored models.Student
+	assert.NoError(t, db.First(&restored, "id = ?", merged.ID).Error)
+	var movedBack models.Attendance
+	db.First(&movedBack, "id = ?", attendance.ID)
+	assert.Equal(t, merged.ID, movedBack.StudentID)
+	var reverted models.Student
+	db.First(&reverted, "id = ?", survivor.ID)
+	assert.Equal(t, "", reverted.Email)
+
+	_, err = duplicates.UndoMerge(ctx, merge.ID, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// recurringEventHorizon is how far ahead recurring events are expanded when
+// there is no end date, e.g. for upcoming bookings
+const recurringEventHorizon = 180 * 24 * time.Hour
+
+// onlySeries narrows a query to events that repeat by a recurrence rule
+func onlySeries(db *gorm.DB) *gorm.DB {
+	return db.Where("is_recurring = ? AND COALESCE(recurrence_rule, '') <> ''", true)
+}
+
+// notSeries narrows a query to events that happen once, exception rows included
+func notSeries(db *gorm.DB) *gorm.DB {
+	return db.Where("(is_recurring = ? OR COALESCE(recurrence_rule, '') = '')", false)
+}
+
+// occurrenceOf copies a series as its occurrence starting at start
+func occurrenceOf(series *models.Event, start time.Time) models.Event {
+	occurrence := *series
+	occurrence.EndTime = start.Add(series.EndTime.Sub(series.StartTime))
+	occurrence.StartTime = start
+	occurrence.RecurrenceID = &start
+	return occurrence
+}
+
+// seriesExceptions loads the exception rows of recurring series by series
+func seriesExceptions(db *gorm.DB, seriesIDs ...uuid.UUID) (map[uuid.UUID][]models.Event, error) {
+	exceptions := make(map[uuid.UUID][]models.Event)
+	if len(seriesIDs) == 0 {
+		return exceptions, nil
+	}
+	var rows []models.Event
+	if err := db.Select("id", "parent_event_id", "recurrence_id", "is_recurring", "recurrence_rule").
+		Where("parent_event_id IN ? AND recurrence_id IS NOT NULL", seriesIDs).
+		Find(&rows).Error; err != nil {
+		return nil, errors.DatabaseError("finding event exceptions", err)
+	}
+	for _, row := range rows {
+		exceptions[*row.ParentEventID] = append(exceptions[*row.ParentEventID], row)
+	}
+	return exceptions, nil
+}
+
+// expandSeries returns the occurrences of recurring series starting between
+// from and to inclusive. EXDATEs and occurrences replaced by exception rows
+// are left out; a series with an unreadable rule counts as a single event.
+func expandSeries(db *gorm.DB, series []models.Event, from, to time.Time) ([]models.Event, error) {
+	ids := make([]uuid.UUID, len(series))
+	for i := range series {
+		ids[i] = series[i].ID
+	}
+	exceptions, err := seriesExceptions(db, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []models.Event
+	for i := range series {
+		event := &series[i]
+		rule, err := models.ParseRecurrence(event.RecurrenceRule)
+		if err != nil {
+			logger.Warnf("event %s has an invalid recurrence rule: %v", event.ID, err)
+			if !event.StartTime.Before(from) && !event.StartTime.After(to) {
+				occurrences = append(occurrences, *event)
+			}
+			continue
+		}
+		replaced := make(map[int64]bool, len(exceptions[event.ID]))
+		for _, exception := range exceptions[event.ID] {
+			replaced[exception.RecurrenceID.Unix()] = true
+		}
+		for _, start := range rule.Between(event.StartTime, from, to) {
+			if !replaced[start.Unix()] {
+				occurrences = append(occurrences, occurrenceOf(event, start))
+			}
+		}
+	}
+	return occurrences, nil
+}
+
+// eventsBetween finds the events overlapping [from, to) with recurring
+// series replaced by their occurrences. A zero to finds all later single
+// events and expands series up to the horizon. scope adds filters and column
+// selections to both queries; selected columns must include id, start_time,
+// end_time, is_recurring and recurrence_rule.
+func eventsBetween(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, from, to time.Time) ([]models.Event, error) {
+	query := scope(db.Model(&models.Event{})).Scopes(notSeries).Where("end_time > ?", from)
+	if !to.IsZero() {
+		query = query.Where("start_time < ?", to)
+	}
+	var events []models.Event
+	if err := query.Find(&events).Error; err != nil {
+		return nil, errors.DatabaseError("finding events", err)
+	}
+
+	if to.IsZero() {
+		to = from.Add(recurringEventHorizon)
+	}
+	var series []models.Event
+	if err := scope(db.Model(&models.Event{})).Scopes(onlySeries).Where("start_time < ?", to).
+		Find(&series).Error; err != nil {
+		return nil, errors.DatabaseError("finding recurring events", err)
+	}
+	// Occurrences that started up to maxBookingDays earlier may still run
+	occurrences, err := expandSeries(db, series, from.AddDate(0, 0, -maxBookingDays), to)
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.EndTime.After(from) && occurrence.StartTime.Before(to) {
+			events = append(events, occurrence)
+		}
+	}
+	return events, nil
+}
+
+// ensureEventIsFree checks an event against other bookings. A recurring
+// event is checked on each occurrence within the horizon, ignoring its own
+// exception rows; an exception row ignores the series it belongs to.
+func ensureEventIsFree(db *gorm.DB, event *models.Event, exclude ...uuid.UUID) error {
+	if !eventOccupiesSchedule(event.Type) {
+		return nil
+	}
+	booking := eventBooking(event)
+	if event.ParentEventID != nil && event.RecurrenceID != nil {
+		exclude = append(exclude, *event.ParentEventID)
+	}
+
+	if event.IsSeries() {
+		from := time.Now()
+		if event.StartTime.After(from) {
+			from = event.StartTime
+		}
+		occurrences, err := expandSeries(db, []models.Event{*event}, from, from.Add(recurringEventHorizon))
+		if err != nil {
+			return err
+		}
+		booking.Spans = nil
+		for i := range occurrences {
+			booking.Spans = append(booking.Spans, eventBooking(&occurrences[i]).Spans...)
+		}
+		if len(booking.Spans) == 0 {
+			return nil
+		}
+
+		exceptions, err := seriesExceptions(db, event.ID)
+		if err != nil {
+			return err
+		}
+		for _, exception := range exceptions[event.ID] {
+			exclude = append(exclude, exception.ID)
+		}
+	}
+	return ensureNoScheduleConflicts(db, booking, exclude...)
+}
@@ -7,23 +7,38 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ExamService handles exam operations
 type ExamService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	notifications *NotificationService
 }
 
 // NewExamService creates a new exam service
 func NewExamService(db *gorm.DB) *ExamService {
-	return &ExamService{db: db}
+	return &ExamService{
+		db:            db,
+		notifications: NewNotificationService(db),
+	}
 }
 
 // Create creates a new exam
 func (s *ExamService) Create(ctx context.Context, req dto.CreateExamRequest, creatorID uuid.UUID) (*dto.ExamResponse, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return nil, errors.Validation("End time must be after start time")
+	}
+	if req.PassingMarks > req.TotalMarks {
+		return nil, errors.Validation("Passing marks cannot exceed total marks")
+	}
+
 	exam := models.Exam{
+		ID:           uuid.New(),
 		Title:        req.Title,
 		Description:  req.Description,
 		Type:         req.Type,
@@ -40,6 +55,14 @@ func (s *ExamService) Create(ctx context.Context, req dto.CreateExamRequest, cre
 		Metadata:     req.Metadata,
 		CreatedBy:    creatorID,
 	}
+	exam.Status = exam.StatusAt(time.Now())
+
+	if err := s.assignRoom(&exam, req.RoomID); err != nil {
+		return nil, err
+	}
+	if err := ensureNoScheduleConflicts(s.db, examBooking(&exam)); err != nil {
+		return nil, err
+	}
 
 	if err := s.db.Create(&exam).Error; err != nil {
 		return nil, fmt.Errorf("failed to create exam: %w", err)
@@ -50,18 +73,15 @@ func (s *ExamService) Create(ctx context.Context, req dto.CreateExamRequest, cre
 
 // GetByID retrieves an exam by ID
 func (s *ExamService) GetByID(ctx context.Context, id string) (*dto.ExamResponse, error) {
-	var exam models.Exam
-	if err := s.db.First(&exam, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("exam not found")
-		}
+	exam, err := s.find(id)
+	if err != nil {
 		return nil, err
 	}
-	return s.toResponse(&exam), nil
+	return s.toResponse(exam), nil
 }
 
-// GetAll retrieves all exams with pagination
-func (s *ExamService) GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+// GetAll retrieves all exams with pagination, optionally within a term
+func (s *ExamService) GetAll(ctx context.Context, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error) {
 	var exams []models.Exam
 	var total int64
 
@@ -71,6 +91,9 @@ func (s *ExamService) GetAll(ctx context.Context, req dto.PaginationRequest) (*d
 		search := "%" + req.Search + "%"
 		query = query.Where("title ILIKE ?", search)
 	}
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, err
@@ -121,12 +144,15 @@ func (s *ExamService) GetByCourse(ctx context.Context, courseID string, req dto.
 	}, nil
 }
 
-// GetByGroup retrieves exams for a specific group
-func (s *ExamService) GetByGroup(ctx context.Context, groupID string, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+// GetByGroup retrieves exams for a specific group, optionally within a term
+func (s *ExamService) GetByGroup(ctx context.Context, groupID string, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error) {
 	var exams []models.Exam
 	var total int64
 
 	query := s.db.Model(&models.Exam{}).Where("group_id = ?", groupID)
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, err
@@ -149,11 +175,64 @@ func (s *ExamService) GetByGroup(ctx context.Context, groupID string, req dto.Pa
 	}, nil
 }
 
-// Update updates an exam
+// Update updates an exam. Status changes must follow the exam lifecycle;
+// publishing goes through Publish, and marks are locked once published.
 func (s *ExamService) Update(ctx context.Context, id string, req dto.UpdateExamRequest) (*dto.ExamResponse, error) {
-	var exam models.Exam
-	if err := s.db.First(&exam, "id = ?", id).Error; err != nil {
-		return nil, fmt.Errorf("exam not found")
+	exam, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != nil && *req.Status != exam.Status {
+		if *req.Status == models.ExamStatusPublished {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, "Use the publish endpoint to publish exam results")
+		}
+		if !exam.Status.CanTransitionTo(*req.Status) {
+			return nil, errors.New(errors.ErrCodeInvalidOperation,
+				fmt.Sprintf("Exam cannot move from %s to %s", exam.Status, *req.Status))
+		}
+	}
+	if exam.Status == models.ExamStatusPublished && (req.TotalMarks != nil || req.PassingMarks != nil) {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Marks cannot change after results are published")
+	}
+
+	start, end := exam.StartTime, exam.EndTime
+	if req.StartTime != nil {
+		start = *req.StartTime
+	}
+	if req.EndTime != nil {
+		end = *req.EndTime
+	}
+	if !end.After(start) {
+		return nil, errors.Validation("End time must be after start time")
+	}
+	total, passing := exam.TotalMarks, exam.PassingMarks
+	if req.TotalMarks != nil {
+		total = *req.TotalMarks
+	}
+	if req.PassingMarks != nil {
+		passing = *req.PassingMarks
+	}
+	if passing > total {
+		return nil, errors.Validation("Passing marks cannot exceed total marks")
+	}
+
+	moved := *exam
+	if req.Location != nil || req.RoomID != nil {
+		if req.Location != nil {
+			moved.Location = *req.Location
+		}
+		if err := s.assignRoom(&moved, req.RoomID); err != nil {
+			return nil, err
+		}
+	}
+	if req.StartTime != nil || req.EndTime != nil || req.Location != nil || req.RoomID != nil {
+		moved.StartTime, moved.EndTime = start, end
+		if moved.Status != models.ExamStatusCancelled {
+			if err := ensureNoScheduleConflicts(s.db, examBooking(&moved), exam.ID); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	updates := make(map[string]interface{})
@@ -171,6 +250,7 @@ func (s *ExamService) Update(ctx context.Context, id string, req dto.UpdateExamR
 	}
 	if req.StartTime != nil {
 		updates["start_time"] = *req.StartTime
+		updates["term_id"] = models.TermAt(s.db, *req.StartTime)
 	}
 	if req.EndTime != nil {
 		updates["end_time"] = *req.EndTime
@@ -184,8 +264,9 @@ func (s *ExamService) Update(ctx context.Context, id string, req dto.UpdateExamR
 	if req.PassingMarks != nil {
 		updates["passing_marks"] = *req.PassingMarks
 	}
-	if req.Location != nil {
-		updates["location"] = *req.Location
+	if req.Location != nil || req.RoomID != nil {
+		updates["location"] = moved.Location
+		updates["room_id"] = moved.RoomID
 	}
 	if req.Instructions != nil {
 		updates["instructions"] = *req.Instructions
@@ -194,44 +275,182 @@ func (s *ExamService) Update(ctx context.Context, id string, req dto.UpdateExamR
 		updates["metadata"] = req.Metadata
 	}
 
-	if err := s.db.Model(&exam).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("failed to update exam: %w", err)
+	if err := s.db.Model(exam).Updates(updates).Error; err != nil {
+		return nil, errors.DatabaseError("updating exam", err)
+	}
+	if err := s.syncStatus(exam, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return s.toResponse(&exam), nil
+	return s.toResponse(exam), nil
 }
 
-// SubmitResult submits an exam result
+// SubmitResult records a student's result, replacing any earlier result for
+// the same student. The student must belong to the exam's group.
 func (s *ExamService) SubmitResult(ctx context.Context, examID string, graderID uuid.UUID, req dto.SubmitExamResultRequest) (*dto.ExamResultResponse, error) {
-	var exam models.Exam
-	if err := s.db.First(&exam, "id = ?", examID).Error; err != nil {
-		return nil, fmt.Errorf("exam not found")
+	exam, err := s.find(examID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResultsOpen(exam); err != nil {
+		return nil, err
+	}
+
+	var members int64
+	if err := s.db.Model(&models.Student{}).Where("id = ? AND group_id = ?", req.StudentID, exam.GroupID).Count(&members).Error; err != nil {
+		return nil, errors.DatabaseError("checking group membership", err)
+	}
+	if members == 0 {
+		return nil, errors.Validation("Student is not a member of the exam's group").
+			WithDetail("student_id", req.StudentID)
+	}
+
+	scale, err := resolveGradingScale(s.db, exam.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.saveResult(exam, scale, graderID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResultResponse(result), nil
+}
+
+// BulkSubmitResults records results for several students of the exam's group.
+// Each entry is saved on its own; entries for students outside the group,
+// repeated students and invalid marks are reported as failures.
+func (s *ExamService) BulkSubmitResults(ctx context.Context, examID string, graderID uuid.UUID, req dto.BulkExamResultsRequest) (*dto.BulkExamResultsResponse, error) {
+	exam, err := s.find(examID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResultsOpen(exam); err != nil {
+		return nil, err
+	}
+
+	var memberIDs []uuid.UUID
+	if err := s.db.Model(&models.Student{}).Where("group_id = ?", exam.GroupID).Pluck("id", &memberIDs).Error; err != nil {
+		return nil, errors.DatabaseError("fetching group students", err)
+	}
+	members := make(map[uuid.UUID]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+
+	scale, err := resolveGradingScale(s.db, exam.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.BulkExamResultsResponse{
+		TotalRequested: len(req.Results),
+		Saved:          make([]dto.ExamResultResponse, 0, len(req.Results)),
+		Failed:         make([]dto.BulkFailedItem, 0),
+	}
+	fail := func(i int, item dto.SubmitExamResultRequest, message string) {
+		resp.TotalFailed++
+		resp.Failed = append(resp.Failed, dto.BulkFailedItem{Index: i, Error: message, Data: item})
 	}
 
-	// Calculate percentage and grade
-	percentage := (req.MarksObtained / float64(exam.TotalMarks)) * 100
-	passed := req.MarksObtained >= float64(exam.PassingMarks) && !req.Absent
+	seen := make(map[uuid.UUID]bool, len(req.Results))
+	for i, item := range req.Results {
+		if !members[item.StudentID] {
+			fail(i, item, "student is not a member of the exam's group")
+			continue
+		}
+		if seen[item.StudentID] {
+			fail(i, item, "duplicate entry for student")
+			continue
+		}
+		seen[item.StudentID] = true
+
+		result, err := s.saveResult(exam, scale, graderID, item)
+		if err != nil {
+			fail(i, item, err.Error())
+			continue
+		}
+		resp.TotalSaved++
+		resp.Saved = append(resp.Saved, *s.toResultResponse(result))
+	}
 
-	result := models.ExamResult{
-		ExamID:        exam.ID,
-		StudentID:     req.StudentID,
-		MarksObtained: req.MarksObtained,
-		Percentage:    percentage,
-		Passed:        passed,
-		Remarks:       req.Remarks,
-		Absent:        req.Absent,
-		GradedBy:      graderID,
+	return resp, nil
+}
+
+// Publish releases a completed exam's results to students and locks them
+// against further changes
+func (s *ExamService) Publish(ctx context.Context, examID string, publisherID uuid.UUID) (*dto.PublishExamResponse, error) {
+	exam, err := s.find(examID)
+	if err != nil {
+		return nil, err
+	}
+	if !exam.Status.CanTransitionTo(models.ExamStatusPublished) {
+		return nil, errors.New(errors.ErrCodeInvalidOperation,
+			fmt.Sprintf("Only completed exams can be published; exam is %s", exam.Status))
 	}
 
 	now := time.Now()
-	result.GradedAt = &now
-	result.CalculateGrade()
+	if err := s.db.Model(&models.Exam{}).Where("id = ?", exam.ID).Updates(map[string]interface{}{
+		"status":       models.ExamStatusPublished,
+		"published_at": now,
+		"published_by": publisherID,
+	}).Error; err != nil {
+		return nil, errors.DatabaseError("publishing exam", err)
+	}
+	exam.Status = models.ExamStatusPublished
+	exam.PublishedAt = &now
+	exam.PublishedBy = &publisherID
+
+	var results []models.ExamResult
+	if err := s.db.Preload("Student").Where("exam_id = ?", exam.ID).Find(&results).Error; err != nil {
+		return nil, errors.DatabaseError("fetching exam results", err)
+	}
+
+	resp := &dto.PublishExamResponse{
+		Exam:             *s.toResponse(exam),
+		ResultsPublished: len(results),
+	}
+	subject := fmt.Sprintf("Exam results published: %s", exam.Title)
+	for _, result := range results {
+		student := result.Student
+		var message string
+		if result.Absent {
+			message = fmt.Sprintf("Dear %s, the results of %s are published. You were marked absent.", student.Name, exam.Title)
+		} else {
+			outcome := "passed"
+			if !result.Passed {
+				outcome = "did not pass"
+			}
+			message = fmt.Sprintf("Dear %s, the results of %s are published. You scored %g of %d (%.1f%%, grade %s) and %s.",
+				student.Name, exam.Title, result.MarksObtained, exam.TotalMarks, result.Percentage, result.Grade, outcome)
+		}
+		if _, ok := s.notifications.notifyContact(ctx, &student.ID, student.Email, student.Phone, subject, message); ok {
+			resp.NotificationsSent++
+		}
+	}
+
+	return resp, nil
+}
+
+// AdvanceStatuses moves scheduled and running exams along their lifecycle
+// according to their start and end times. It returns the number of exams
+// whose status changed.
+func (s *ExamService) AdvanceStatuses(ctx context.Context, now time.Time) (int64, error) {
+	started := s.db.Model(&models.Exam{}).
+		Where("status = ? AND start_time <= ? AND end_time > ?", models.ExamStatusScheduled, now, now).
+		Update("status", models.ExamStatusInProgress)
+	if started.Error != nil {
+		return 0, errors.DatabaseError("starting exams", started.Error)
+	}
 
-	if err := s.db.Create(&result).Error; err != nil {
-		return nil, fmt.Errorf("failed to submit result: %w", err)
+	finished := s.db.Model(&models.Exam{}).
+		Where("status IN ? AND end_time <= ?", []models.ExamStatus{models.ExamStatusScheduled, models.ExamStatusInProgress}, now).
+		Update("status", models.ExamStatusCompleted)
+	if finished.Error != nil {
+		return started.RowsAffected, errors.DatabaseError("completing exams", finished.Error)
 	}
 
-	return s.toResultResponse(&result), nil
+	return started.RowsAffected + finished.RowsAffected, nil
 }
 
 // GetResults retrieves results for an exam
@@ -321,6 +540,111 @@ func (s *ExamService) GetStatistics(ctx context.Context, examID string) (*dto.Ex
 	return &stats, nil
 }
 
+// find loads an exam and brings its status up to date with its schedule
+func (s *ExamService) find(id string) (*models.Exam, error) {
+	var exam models.Exam
+	if err := s.db.First(&exam, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Exam", id)
+		}
+		return nil, errors.DatabaseError("finding exam", err)
+	}
+	if err := s.syncStatus(&exam, time.Now()); err != nil {
+		return nil, err
+	}
+	return &exam, nil
+}
+
+// assignRoom links the exam to the room it names, by ID or by a location
+// matching a room, and checks the room seats the exam's group
+func (s *ExamService) assignRoom(exam *models.Exam, roomID *uuid.UUID) error {
+	room, err := resolveRoom(s.db, roomID, exam.Location)
+	if err != nil {
+		return err
+	}
+	exam.RoomID = nil
+	if room == nil {
+		return nil
+	}
+	exam.RoomID = &room.ID
+	exam.Location = room.Name
+	headcount, err := groupHeadcount(s.db, exam.GroupID)
+	if err != nil {
+		return err
+	}
+	return ensureRoomFits(room, headcount)
+}
+
+// syncStatus stores the status the exam's schedule calls for, if it changed
+func (s *ExamService) syncStatus(exam *models.Exam, now time.Time) error {
+	status := exam.StatusAt(now)
+	if status == exam.Status {
+		return nil
+	}
+	if err := s.db.Model(&models.Exam{}).Where("id = ?", exam.ID).Update("status", status).Error; err != nil {
+		return errors.DatabaseError("updating exam status", err)
+	}
+	exam.Status = status
+	return nil
+}
+
+// checkResultsOpen rejects result entry for exams that have not started,
+// were cancelled or whose results are already published
+func checkResultsOpen(exam *models.Exam) error {
+	switch exam.Status {
+	case models.ExamStatusScheduled:
+		return errors.New(errors.ErrCodeInvalidOperation, "Results cannot be entered before the exam starts")
+	case models.ExamStatusCancelled:
+		return errors.New(errors.ErrCodeInvalidOperation, "Exam is cancelled")
+	case models.ExamStatusPublished:
+		return errors.New(errors.ErrCodeInvalidOperation, "Exam results are published and locked")
+	}
+	return nil
+}
+
+// saveResult validates the marks and creates or replaces the student's result
+func (s *ExamService) saveResult(exam *models.Exam, scale *models.GradingScaleVersion, graderID uuid.UUID, req dto.SubmitExamResultRequest) (*models.ExamResult, error) {
+	if exam.TotalMarks <= 0 {
+		return nil, errors.Validation("Exam has no total marks set")
+	}
+	marks := req.MarksObtained
+	if req.Absent {
+		marks = 0
+	}
+	if marks < 0 || marks > float64(exam.TotalMarks) {
+		return nil, errors.Validation(fmt.Sprintf("Marks must be between 0 and %d", exam.TotalMarks))
+	}
+
+	percentage := (marks / float64(exam.TotalMarks)) * 100
+	passed := marks >= float64(exam.PassingMarks) && !req.Absent
+	if exam.PassingMarks <= 0 {
+		passed = scale.Passed(percentage) && !req.Absent
+	}
+
+	var result models.ExamResult
+	err := s.db.Where("exam_id = ? AND student_id = ?", exam.ID, req.StudentID).First(&result).Error
+	if err == gorm.ErrRecordNotFound {
+		result = models.ExamResult{ID: uuid.New(), ExamID: exam.ID, StudentID: req.StudentID}
+	} else if err != nil {
+		return nil, errors.DatabaseError("finding exam result", err)
+	}
+
+	now := time.Now()
+	result.MarksObtained = marks
+	result.Percentage = percentage
+	result.Passed = passed
+	result.Remarks = req.Remarks
+	result.Absent = req.Absent
+	result.GradedBy = graderID
+	result.GradedAt = &now
+	result.ApplyGradingScale(scale)
+
+	if err := s.db.Omit(clause.Associations).Save(&result).Error; err != nil {
+		return nil, errors.DatabaseError("saving exam result", err)
+	}
+	return &result, nil
+}
+
 // Delete deletes an exam
 func (s *ExamService) Delete(ctx context.Context, id string) error {
 	result := s.db.Delete(&models.Exam{}, "id = ?", id)
@@ -349,9 +673,12 @@ func (s *ExamService) toResponse(e *models.Exam) *dto.ExamResponse {
 		TotalMarks:   e.TotalMarks,
 		PassingMarks: e.PassingMarks,
 		Location:     e.Location,
+		RoomID:       e.RoomID,
+		TermID:       e.TermID,
 		Instructions: e.Instructions,
 		Metadata:     e.Metadata,
 		CreatedBy:    e.CreatedBy,
+		PublishedAt:  e.PublishedAt,
 		CreatedAt:    e.CreatedAt,
 		UpdatedAt:    e.UpdatedAt,
 	}
@@ -360,18 +687,52 @@ func (s *ExamService) toResponse(e *models.Exam) *dto.ExamResponse {
 // toResultResponse converts an exam result model to response DTO
 func (s *ExamService) toResultResponse(r *models.ExamResult) *dto.ExamResultResponse {
 	return &dto.ExamResultResponse{
-		ID:            r.ID,
-		ExamID:        r.ExamID,
-		StudentID:     r.StudentID,
-		MarksObtained: r.MarksObtained,
-		Percentage:    r.Percentage,
-		Grade:         r.Grade,
-		Passed:        r.Passed,
-		Remarks:       r.Remarks,
-		Absent:        r.Absent,
-		GradedBy:      r.GradedBy,
-		GradedAt:      r.GradedAt,
-		CreatedAt:     r.CreatedAt,
-		UpdatedAt:     r.UpdatedAt,
+		ID:                    r.ID,
+		ExamID:                r.ExamID,
+		StudentID:             r.StudentID,
+		MarksObtained:         r.MarksObtained,
+		Percentage:            r.Percentage,
+		Grade:                 r.Grade,
+		GradePoints:           r.GradePoints,
+		Passed:                r.Passed,
+		Remarks:               r.Remarks,
+		Absent:                r.Absent,
+		GradedBy:              r.GradedBy,
+		GradedAt:              r.GradedAt,
+		GradingScaleVersionID: r.GradingScaleVersionID,
+		CreatedAt:             r.CreatedAt,
+		UpdatedAt:             r.UpdatedAt,
+	}
+}
+
+// DeduplicateExamResults keeps the most recently updated result of every
+// exam and student and soft-deletes the rest, so the unique index on exam
+// results can be built over data stored before it existed. It runs before
+// AutoMigrate and does nothing on a fresh database.
+func DeduplicateExamResults(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.ExamResult{}) {
+		return nil
 	}
+
+	var results []models.ExamResult
+	if err := db.Select("id", "exam_id", "student_id").
+		Order("exam_id, student_id, updated_at DESC, created_at DESC").
+		Find(&results).Error; err != nil {
+		return errors.DatabaseError("finding exam results", err)
+	}
+
+	stale := make([]uuid.UUID, 0)
+	for i := 1; i < len(results); i++ {
+		if results[i].ExamID == results[i-1].ExamID && results[i].StudentID == results[i-1].StudentID {
+			stale = append(stale, results[i].ID)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	if err := db.Where("id IN ?", stale).Delete(&models.ExamResult{}).Error; err != nil {
+		return errors.DatabaseError("removing duplicate exam results", err)
+	}
+	logger.Warnf("removed %d duplicate exam results", len(stale))
+	return nil
 }
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamService_ResultsLifecycle(t *testing.T) {
+	db := setupTestDB()
+	service := NewExamService(db)
+	ctx := context.Background()
+
+	course := models.Course{Title: "Go", Duration: 3}
+	db.Create(&course)
+	group := models.Group{CourseID: course.ID, Name: "G1"}
+	db.Create(&group)
+	ali := models.Student{GroupID: group.ID, Name: "Ali", Surname: "Valiev", Phone: "992900000021"}
+	db.Create(&ali)
+	vali := models.Student{GroupID: group.ID, Name: "Vali", Surname: "Aliev", Phone: "992900000022"}
+	db.Create(&vali)
+	outsider := models.Student{GroupID: uuid.New(), Name: "Sami", Surname: "Karimov", Phone: "992900000023"}
+	db.Create(&outsider)
+
+	// Metadata is a jsonb column, so the exam is stored without it
+	start := time.Now().Add(time.Hour)
+	exam := models.Exam{
+		ID:           uuid.New(),
+		Title:        "Midterm",
+		Type:         models.ExamTypeMidterm,
+		Status:       models.ExamStatusScheduled,
+		CourseID:     course.ID,
+		GroupID:      group.ID,
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Hour),
+		Duration:     120,
+		TotalMarks:   50,
+		PassingMarks: 30,
+		CreatedBy:    uuid.New(),
+	}
+	db.Omit("Metadata").Create(&exam)
+
+	graderID := uuid.New()
+	_, err := service.SubmitResult(ctx, exam.ID.String(), graderID, dto.SubmitExamResultRequest{StudentID: ali.ID, MarksObtained: 40})
+	assert.Error(t, err, "results before the exam starts are rejected")
+
+	// The exam runs its course
+	changed, err := service.AdvanceStatuses(ctx, start.Add(3*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), changed)
+
+	_, err = service.SubmitResult(ctx, exam.ID.String(), graderID, dto.SubmitExamResultRequest{StudentID: ali.ID, MarksObtained: 60})
+	assert.Error(t, err, "marks above the total are rejected")
+
+	_, err = service.SubmitResult(ctx, exam.ID.String(), graderID, dto.SubmitExamResultRequest{StudentID: ali.ID, MarksObtained: 20})
+	assert.NoError(t, err)
+	result, err := service.SubmitResult(ctx, exam.ID.String(), graderID, dto.SubmitExamResultRequest{StudentID: ali.ID, MarksObtained: 40})
+	assert.NoError(t, err)
+	assert.Equal(t, 80.0, result.Percentage)
+	assert.True(t, result.Passed)
+
+	bulk, err := service.BulkSubmitResults(ctx, exam.ID.String(), graderID, dto.BulkExamResultsRequest{Results: []dto.SubmitExamResultRequest{
+		{StudentID: vali.ID, MarksObtained: 25},
+		{StudentID: outsider.ID, MarksObtained: 45},
+		{StudentID: vali.ID, MarksObtained: 35},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, bulk.TotalSaved)
+	assert.Equal(t, 2, bulk.TotalFailed)
+
+	// Resubmitting replaced Ali's result instead of adding another one
+	results, err := service.GetResults(ctx, exam.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	published, err := service.Publish(ctx, exam.ID.String(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExamStatusPublished, published.Exam.Status)
+	assert.Equal(t, 2, published.ResultsPublished)
+
+	_, err = service.SubmitResult(ctx, exam.ID.String(), graderID, dto.SubmitExamResultRequest{StudentID: vali.ID, MarksObtained: 50})
+	assert.Error(t, err, "published results are locked")
+}
+
+func TestDeduplicateExamResults(t *testing.T) {
+	db := setupTestDB()
+
+	// Results stored before the unique index existed
+	assert.NoError(t, db.Migrator().DropIndex(&models.ExamResult{}, "idx_exam_result_student"))
+	examID, studentID, otherID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	older := models.ExamResult{ID: uuid.New(), ExamID: examID, StudentID: studentID, MarksObtained: 10, UpdatedAt: now.Add(-time.Hour)}
+	latest := models.ExamResult{ID: uuid.New(), ExamID: examID, StudentID: studentID, MarksObtained: 30, UpdatedAt: now}
+	other := models.ExamResult{ID: uuid.New(), ExamID: examID, StudentID: otherID, MarksObtained: 20, UpdatedAt: now.Add(-time.Hour)}
+	for _, result := range []*models.ExamResult{&older, &latest, &other} {
+		updatedAt := result.UpdatedAt
+		assert.NoError(t, db.Create(result).Error)
+		db.Model(result).UpdateColumn("updated_at", updatedAt)
+	}
+
+	assert.NoError(t, DeduplicateExamResults(db))
+	assert.NoError(t, db.AutoMigrate(&models.ExamResult{}), "the unique index builds")
+
+	var kept []models.ExamResult
+	db.Order("student_id").Find(&kept)
+	assert.Len(t, kept, 2)
+	var remaining models.ExamResult
+	db.First(&remaining, "exam_id = ? AND student_id = ?", examID, studentID)
+	assert.Equal(t, latest.ID, remaining.ID)
+	var removed int64
+	db.Unscoped().Model(&models.ExamResult{}).Where("id = ? AND deleted_at IS NOT NULL", older.ID).Count(&removed)
+	assert.Equal(t, int64(1), removed)
+
+	assert.NoError(t, DeduplicateExamResults(db), "nothing left to remove")
+}
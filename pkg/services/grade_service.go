@@ -16,8 +16,8 @@ type GradeService interface {
 	Create(ctx context.Context, groupID string, req dto.CreateGradeRequest) (*dto.GradeResponse, error)
 	Update(ctx context.Context, id string, req dto.UpdateGradeRequest) (*dto.GradeResponse, error)
 	Delete(ctx context.Context, id string) error
-	GetStudentGrades(ctx context.Context, studentID string, groupID string) ([]dto.GradeResponse, error)
-	GetGroupGrades(ctx context.Context, groupID string, courseID string) ([]dto.GradeResponse, error)
+	GetStudentGrades(ctx context.Context, studentID string, groupID string, termID string) ([]dto.GradeResponse, error)
+	GetGroupGrades(ctx context.Context, groupID string, courseID string, termID string) ([]dto.GradeResponse, error)
 }
 
 type gradeService struct {
@@ -63,6 +63,12 @@ func (s *gradeService) Create(ctx context.Context, groupID string, req dto.Creat
 		Notes:     req.Notes,
 	}
 
+	scale, err := resolveGradingScale(s.db, group.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	grade.ApplyGradingScale(scale)
+
 	if err := s.db.Create(&grade).Error; err != nil {
 		return nil, errors.DatabaseError("creating grade", err)
 	}
@@ -90,8 +96,17 @@ func (s *gradeService) Update(ctx context.Context, id string, req dto.UpdateGrad
 	grade.Value = req.Value
 	grade.Type = req.Type
 	grade.Date = date
+	grade.TermID = models.TermAt(s.db, date)
 	grade.Notes = req.Notes
 
+	// Re-derive the label with the version the grade was originally graded
+	// with, so editing a value never silently moves it to a newer scale
+	scale, err := gradingScaleVersionByID(s.db, grade.GradingScaleVersionID)
+	if err != nil {
+		return nil, err
+	}
+	grade.ApplyGradingScale(scale)
+
 	if err := s.db.Save(&grade).Error; err != nil {
 		return nil, errors.DatabaseError("updating grade", err)
 	}
@@ -110,13 +125,16 @@ func (s *gradeService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *gradeService) GetStudentGrades(ctx context.Context, studentID string, groupID string) ([]dto.GradeResponse, error) {
+func (s *gradeService) GetStudentGrades(ctx context.Context, studentID string, groupID string, termID string) ([]dto.GradeResponse, error) {
 	var grades []models.Grade
 
 	query := s.db.Where("student_id = ?", studentID)
 	if groupID != "" {
 		query = query.Where("group_id = ?", groupID)
 	}
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Order("date desc").Preload("Student").Find(&grades).Error; err != nil {
 		return nil, errors.DatabaseError("fetching student grades", err)
@@ -130,13 +148,16 @@ func (s *gradeService) GetStudentGrades(ctx context.Context, studentID string, g
 	return responses, nil
 }
 
-func (s *gradeService) GetGroupGrades(ctx context.Context, groupID string, courseID string) ([]dto.GradeResponse, error) {
+func (s *gradeService) GetGroupGrades(ctx context.Context, groupID string, courseID string, termID string) ([]dto.GradeResponse, error) {
 	var grades []models.Grade
 
 	query := s.db.Where("group_id = ?", groupID)
 	if courseID != "" {
 		query = query.Where("course_id = ?", courseID)
 	}
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Order("date desc").Preload("Student").Find(&grades).Error; err != nil {
 		return nil, errors.DatabaseError("fetching group grades", err)
@@ -152,16 +173,21 @@ func (s *gradeService) GetGroupGrades(ctx context.Context, groupID string, cours
 
 func (s *gradeService) toResponse(g *models.Grade) *dto.GradeResponse {
 	return &dto.GradeResponse{
-		ID:        g.ID,
-		StudentID: g.StudentID,
-		GroupID:   g.GroupID,
-		CourseID:  g.CourseID,
-		Value:     g.Value,
-		Type:      g.Type,
-		Date:      g.Date.Format("2006-01-02"),
-		Notes:     g.Notes,
-		CreatedAt: g.CreatedAt,
-		UpdatedAt: g.UpdatedAt,
+		ID:                    g.ID,
+		StudentID:             g.StudentID,
+		GroupID:               g.GroupID,
+		CourseID:              g.CourseID,
+		Value:                 g.Value,
+		Type:                  g.Type,
+		Date:                  g.Date.Format("2006-01-02"),
+		Notes:                 g.Notes,
+		Label:                 g.Label,
+		GPAPoints:             g.GPAPoints,
+		Passed:                g.Passed,
+		GradingScaleVersionID: g.GradingScaleVersionID,
+		TermID:                g.TermID,
+		CreatedAt:             g.CreatedAt,
+		UpdatedAt:             g.UpdatedAt,
 		Student: dto.StudentSimple{
 			ID:      g.Student.ID,
 			Name:    g.Student.Name,
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// GradingScaleService handles grading scale operations
+type GradingScaleService struct {
+	db *gorm.DB
+}
+
+// NewGradingScaleService creates a new grading scale service
+func NewGradingScaleService(db *gorm.DB) *GradingScaleService {
+	return &GradingScaleService{db: db}
+}
+
+// Create creates a grading scale together with its first version
+func (s *GradingScaleService) Create(ctx context.Context, req dto.CreateGradingScaleRequest, creatorID *uuid.UUID) (*dto.GradingScaleResponse, error) {
+	bands, err := buildGradingBands(req.Type, req.Bands)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := models.GradingScale{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Description:    req.Description,
+		Type:           req.Type,
+		IsDefault:      req.IsDefault,
+		IsActive:       true,
+		CurrentVersion: 1,
+	}
+	version := models.GradingScaleVersion{
+		ID:             uuid.New(),
+		GradingScaleID: scale.ID,
+		Version:        1,
+		Bands:          bands,
+		PassThreshold:  req.PassThreshold,
+		CreatedBy:      creatorID,
+	}
+	scale.CurrentVersionID = &version.ID
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.GradingScale{}).Where("LOWER(name) = ?", strings.ToLower(req.Name)).Count(&count).Error; err != nil {
+			return errors.DatabaseError("checking grading scale name", err)
+		}
+		if count > 0 {
+			return errors.DuplicateEntry("Grading scale", "name")
+		}
+
+		if scale.IsDefault {
+			if err := tx.Model(&models.GradingScale{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return errors.DatabaseError("clearing default grading scale", err)
+			}
+		}
+		if err := tx.Create(&scale).Error; err != nil {
+			return errors.DatabaseError("creating grading scale", err)
+		}
+		if err := tx.Create(&version).Error; err != nil {
+			return errors.DatabaseError("creating grading scale version", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scale.Versions = []models.GradingScaleVersion{version}
+	return s.toResponse(&scale), nil
+}
+
+// Update updates a grading scale. Band or threshold changes are stored as a
+// new version; results graded under earlier versions keep their grades.
+func (s *GradingScaleService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateGradingScaleRequest, editorID *uuid.UUID) (*dto.GradingScaleResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var scale models.GradingScale
+		if err := tx.First(&scale, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("Grading scale", id.String())
+			}
+			return errors.DatabaseError("finding grading scale", err)
+		}
+
+		if req.Name != nil && !strings.EqualFold(*req.Name, scale.Name) {
+			var count int64
+			if err := tx.Model(&models.GradingScale{}).
+				Where("LOWER(name) = ? AND id != ?", strings.ToLower(*req.Name), id).
+				Count(&count).Error; err != nil {
+				return errors.DatabaseError("checking grading scale name", err)
+			}
+			if count > 0 {
+				return errors.DuplicateEntry("Grading scale", "name")
+			}
+			scale.Name = *req.Name
+		}
+		if req.Description != nil {
+			scale.Description = *req.Description
+		}
+		if req.IsActive != nil {
+			scale.IsActive = *req.IsActive
+		}
+		if req.IsDefault != nil {
+			if *req.IsDefault && !scale.IsDefault {
+				if err := tx.Model(&models.GradingScale{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+					return errors.DatabaseError("clearing default grading scale", err)
+				}
+			}
+			scale.IsDefault = *req.IsDefault
+		}
+
+		if req.Bands != nil || req.PassThreshold != nil {
+			var current models.GradingScaleVersion
+			if err := loadCurrentVersion(tx, &scale, &current); err != nil {
+				return err
+			}
+
+			next := models.GradingScaleVersion{
+				ID:             uuid.New(),
+				GradingScaleID: scale.ID,
+				Version:        scale.CurrentVersion + 1,
+				Bands:          current.Bands,
+				PassThreshold:  current.PassThreshold,
+				ChangeNotes:    req.ChangeNotes,
+				CreatedBy:      editorID,
+			}
+			if req.Bands != nil {
+				bands, err := buildGradingBands(scale.Type, req.Bands)
+				if err != nil {
+					return err
+				}
+				next.Bands = bands
+			}
+			if req.PassThreshold != nil {
+				next.PassThreshold = *req.PassThreshold
+			}
+
+			if err := tx.Create(&next).Error; err != nil {
+				return errors.DatabaseError("creating grading scale version", err)
+			}
+			scale.CurrentVersion = next.Version
+			scale.CurrentVersionID = &next.ID
+		}
+
+		if err := tx.Save(&scale).Error; err != nil {
+			return errors.DatabaseError("updating grading scale", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// GetByID retrieves a grading scale with its version history
+func (s *GradingScaleService) GetByID(ctx context.Context, id uuid.UUID) (*dto.GradingScaleResponse, error) {
+	var scale models.GradingScale
+	if err := s.db.Preload("Versions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("version DESC")
+	}).First(&scale, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Grading scale", id.String())
+		}
+		return nil, errors.DatabaseError("finding grading scale", err)
+	}
+
+	return s.toResponse(&scale), nil
+}
+
+// GetAll lists grading scales with their current version
+func (s *GradingScaleService) GetAll(ctx context.Context) ([]dto.GradingScaleResponse, error) {
+	var scales []models.GradingScale
+	if err := s.db.Preload("Versions").Order("name ASC").Find(&scales).Error; err != nil {
+		return nil, errors.DatabaseError("listing grading scales", err)
+	}
+
+	responses := make([]dto.GradingScaleResponse, len(scales))
+	for i, scale := range scales {
+		resp := s.toResponse(&scale)
+		resp.Versions = nil
+		responses[i] = *resp
+	}
+
+	return responses, nil
+}
+
+// Delete deletes a grading scale that is not assigned to any course
+func (s *GradingScaleService) Delete(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.Course{}).Where("grading_scale_id = ?", id).Count(&count).Error; err != nil {
+		return errors.DatabaseError("checking grading scale usage", err)
+	}
+	if count > 0 {
+		return errors.New(errors.ErrCodeResourceInUse, "Cannot delete grading scale assigned to courses")
+	}
+
+	result := s.db.Delete(&models.GradingScale{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("deleting grading scale", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundWithID("Grading scale", id.String())
+	}
+	return nil
+}
+
+// AssignToCourse sets (or clears) the grading scale used by a course
+func (s *GradingScaleService) AssignToCourse(ctx context.Context, courseID uuid.UUID, req dto.AssignGradingScaleRequest) error {
+	var course models.Course
+	if err := s.db.First(&course, "id = ?", courseID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFoundWithID("Course", courseID.String())
+		}
+		return errors.DatabaseError("finding course", err)
+	}
+
+	if req.GradingScaleID != nil {
+		var scale models.GradingScale
+		if err := s.db.First(&scale, "id = ?", *req.GradingScaleID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("Grading scale", req.GradingScaleID.String())
+			}
+			return errors.DatabaseError("finding grading scale", err)
+		}
+		if !scale.IsActive {
+			return errors.New(errors.ErrCodeInvalidOperation, "Grading scale is not active")
+		}
+	}
+
+	if err := s.db.Model(&course).Update("grading_scale_id", req.GradingScaleID).Error; err != nil {
+		return errors.DatabaseError("assigning grading scale", err)
+	}
+	return nil
+}
+
+// ResolveForCourse returns the grading scale version that new results for a
+// course should be graded with
+func (s *GradingScaleService) ResolveForCourse(ctx context.Context, courseID uuid.UUID) (*models.GradingScaleVersion, error) {
+	return resolveGradingScale(s.db, courseID)
+}
+
+// resolveGradingScale picks the course's scale, falling back to the default
+// scale and finally to the built-in letter scale
+func resolveGradingScale(db *gorm.DB, courseID uuid.UUID) (*models.GradingScaleVersion, error) {
+	var scale models.GradingScale
+	found := false
+
+	var course models.Course
+	if err := db.Select("id", "grading_scale_id").First(&course, "id = ?", courseID).Error; err == nil && course.GradingScaleID != nil {
+		if err := db.First(&scale, "id = ?", *course.GradingScaleID).Error; err == nil {
+			found = true
+		}
+	}
+	if !found {
+		err := db.Where("is_default = ? AND is_active = ?", true, true).First(&scale).Error
+		if err == gorm.ErrRecordNotFound {
+			return models.DefaultGradingScaleVersion(), nil
+		}
+		if err != nil {
+			return nil, errors.DatabaseError("finding default grading scale", err)
+		}
+	}
+
+	var version models.GradingScaleVersion
+	if err := loadCurrentVersion(db, &scale, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// gradingScaleVersionByID loads a stored version, falling back to the built-in
+// scale for results graded before scales existed
+func gradingScaleVersionByID(db *gorm.DB, id *uuid.UUID) (*models.GradingScaleVersion, error) {
+	if id == nil {
+		return models.DefaultGradingScaleVersion(), nil
+	}
+	var version models.GradingScaleVersion
+	if err := db.First(&version, "id = ?", *id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return models.DefaultGradingScaleVersion(), nil
+		}
+		return nil, errors.DatabaseError("finding grading scale version", err)
+	}
+	return &version, nil
+}
+
+// loadCurrentVersion loads the version a scale currently grades with
+func loadCurrentVersion(db *gorm.DB, scale *models.GradingScale, version *models.GradingScaleVersion) error {
+	query := db.Where("grading_scale_id = ?", scale.ID)
+	if scale.CurrentVersionID != nil {
+		query = db.Where("id = ?", *scale.CurrentVersionID)
+	}
+	if err := query.Order("version DESC").First(version).Error; err != nil {
+		return errors.DatabaseError("finding grading scale version", err)
+	}
+	return nil
+}
+
+// buildGradingBands validates requested bands and returns them ordered from
+// the highest minimum percentage down
+func buildGradingBands(scaleType models.GradingScaleType, reqs []dto.GradingBandRequest) ([]models.GradingBand, error) {
+	if len(reqs) == 0 {
+		return nil, errors.Validation("A grading scale needs at least one band")
+	}
+	if scaleType == models.GradingScalePassFail && len(reqs) != 2 {
+		return nil, errors.Validation("A pass/fail scale must have exactly two bands")
+	}
+
+	labels := make(map[string]bool)
+	minimums := make(map[float64]bool)
+	hasZero := false
+	bands := make([]models.GradingBand, len(reqs))
+
+	for i, r := range reqs {
+		label := strings.TrimSpace(r.Label)
+		if label == "" {
+			return nil, errors.Validation("Band labels cannot be empty")
+		}
+		if labels[strings.ToLower(label)] {
+			return nil, errors.Validation(fmt.Sprintf("Duplicate band label %q", label))
+		}
+		if r.MinPercentage < 0 || r.MinPercentage > 100 {
+			return nil, errors.Validation(fmt.Sprintf("Band %q minimum must be between 0 and 100", label))
+		}
+		if minimums[r.MinPercentage] {
+			return nil, errors.Validation(fmt.Sprintf("More than one band starts at %.2f%%", r.MinPercentage))
+		}
+		if r.MinPercentage == 0 {
+			hasZero = true
+		}
+		labels[strings.ToLower(label)] = true
+		minimums[r.MinPercentage] = true
+		bands[i] = models.GradingBand{Label: label, MinPercentage: r.MinPercentage, GPAPoints: r.GPAPoints}
+	}
+
+	if !hasZero {
+		return nil, errors.Validation("The lowest band must start at 0% so every result can be graded")
+	}
+
+	sort.Slice(bands, func(i, j int) bool {
+		return bands[i].MinPercentage > bands[j].MinPercentage
+	})
+	return bands, nil
+}
+
+func (s *GradingScaleService) toVersionResponse(v *models.GradingScaleVersion) *dto.GradingScaleVersionResponse {
+	return &dto.GradingScaleVersionResponse{
+		ID:            v.ID,
+		Version:       v.Version,
+		Bands:         v.Bands,
+		PassThreshold: v.PassThreshold,
+		ChangeNotes:   v.ChangeNotes,
+		CreatedBy:     v.CreatedBy,
+		CreatedAt:     v.CreatedAt,
+	}
+}
+
+func (s *GradingScaleService) toResponse(g *models.GradingScale) *dto.GradingScaleResponse {
+	resp := &dto.GradingScaleResponse{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Type:        g.Type,
+		IsDefault:   g.IsDefault,
+		IsActive:    g.IsActive,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+
+	resp.Versions = make([]dto.GradingScaleVersionResponse, len(g.Versions))
+	for i := range g.Versions {
+		v := &g.Versions[i]
+		resp.Versions[i] = *s.toVersionResponse(v)
+		if g.CurrentVersionID != nil && v.ID == *g.CurrentVersionID {
+			resp.CurrentVersion = s.toVersionResponse(v)
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradingScaleVersion_Band(t *testing.T) {
+	scale := models.DefaultGradingScaleVersion()
+
+	assert.Equal(t, "A+", scale.Band(95).Label)
+	assert.Equal(t, "B", scale.Band(70).Label)
+	assert.Equal(t, "F", scale.Band(12).Label)
+	assert.True(t, scale.Passed(50))
+	assert.False(t, scale.Passed(49.9))
+}
+
+func TestGradingScaleService_UpdateCreatesVersion(t *testing.T) {
+	db := setupTestDB()
+	service := NewGradingScaleService(db)
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, dto.CreateGradingScaleRequest{
+		Name: "Pass/Fail",
+		Type: models.GradingScalePassFail,
+		Bands: []dto.GradingBandRequest{
+			{Label: "Pass", MinPercentage: 60, GPAPoints: 1},
+			{Label: "Fail", MinPercentage: 0},
+		},
+		PassThreshold: 60,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.CurrentVersion.Version)
+	firstVersionID := created.CurrentVersion.ID
+
+	threshold := 70.0
+	updated, err := service.Update(ctx, created.ID, dto.UpdateGradingScaleRequest{
+		Bands: []dto.GradingBandRequest{
+			{Label: "Pass", MinPercentage: 70, GPAPoints: 1},
+			{Label: "Fail", MinPercentage: 0},
+		},
+		PassThreshold: &threshold,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.CurrentVersion.Version)
+	assert.Len(t, updated.Versions, 2)
+
+	// Results graded with the first version keep its bands
+	old, err := gradingScaleVersionByID(db, &firstVersionID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pass", old.Band(65).Label)
+	assert.Equal(t, "Fail", updated.CurrentVersion.Bands[1].Label)
+
+	_, err = service.Create(ctx, dto.CreateGradingScaleRequest{
+		Name:  "Broken",
+		Type:  models.GradingScaleLetter,
+		Bands: []dto.GradingBandRequest{{Label: "A", MinPercentage: 50}},
+	}, nil)
+	assert.Error(t, err, "a scale without a 0% band must be rejected")
+
+	_, err = service.GetByID(ctx, uuid.New())
+	assert.Error(t, err)
+}
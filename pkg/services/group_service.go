@@ -17,7 +17,7 @@ type GroupService interface {
 	Update(ctx context.Context, id string, req dto.UpdateGroupRequest) (*dto.GroupResponse, error)
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*dto.GroupResponse, error)
-	GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
+	GetAll(ctx context.Context, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error)
 }
 
 type groupService struct {
@@ -46,6 +46,13 @@ func (s *groupService) Create(ctx context.Context, req dto.CreateGroupRequest) (
 		Capacity:    req.Capacity,
 	}
 
+	if err := ensureTeacherActive(s.db, group.TeacherID); err != nil {
+		return nil, err
+	}
+	if err := s.checkSchedule(&group); err != nil {
+		return nil, err
+	}
+
 	if err := s.db.Create(&group).Error; err != nil {
 		return nil, errors.DatabaseError("creating group", err)
 	}
@@ -71,6 +78,12 @@ func (s *groupService) Update(ctx context.Context, id string, req dto.UpdateGrou
 			return nil, err
 		}
 	}
+	rescheduled := req.TeacherID != group.TeacherID || req.TimetableID != group.TimetableID
+	if req.TeacherID != group.TeacherID {
+		if err := ensureTeacherActive(s.db, req.TeacherID); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if capacity reduction is valid (must be >= current student count)
 	if req.Capacity < group.Capacity {
@@ -83,13 +96,26 @@ func (s *groupService) Update(ctx context.Context, id string, req dto.UpdateGrou
 		}
 	}
 
+	previousTimetableID := group.TimetableID
 	group.Name = req.Name
 	group.StartDate = req.StartDate
+	group.TermID = models.TermAt(s.db, req.StartDate)
 	group.CourseID = req.CourseID
 	group.TeacherID = req.TeacherID
 	group.TimetableID = req.TimetableID
 	group.Capacity = req.Capacity
 
+	if rescheduled {
+		if err := s.checkSchedule(&group); err != nil {
+			return nil, err
+		}
+	}
+	if req.TimetableID != previousTimetableID {
+		if err := s.checkRoom(&group); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.db.Save(&group).Error; err != nil {
 		return nil, errors.DatabaseError("updating group", err)
 	}
@@ -121,7 +147,7 @@ func (s *groupService) Delete(ctx context.Context, id string) error {
 
 func (s *groupService) GetByID(ctx context.Context, id string) (*dto.GroupResponse, error) {
 	var group models.Group
-	if err := s.db.Preload("Course").Preload("Teacher").Preload("Timetable").Preload("Students").First(&group, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Course").Preload("Teacher").Preload("Timetable.Slots").Preload("Students").First(&group, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundWithID("Group", id)
 		}
@@ -131,7 +157,7 @@ func (s *groupService) GetByID(ctx context.Context, id string) (*dto.GroupRespon
 	return s.toResponse(&group), nil
 }
 
-func (s *groupService) GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+func (s *groupService) GetAll(ctx context.Context, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error) {
 	var groups []models.Group
 	var total int64
 
@@ -141,6 +167,9 @@ func (s *groupService) GetAll(ctx context.Context, req dto.PaginationRequest) (*
 		search := "%" + strings.ToLower(req.Search) + "%"
 		query = query.Where("LOWER(name) LIKE ?", search)
 	}
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, errors.DatabaseError("counting groups", err)
@@ -151,7 +180,7 @@ func (s *groupService) GetAll(ctx context.Context, req dto.PaginationRequest) (*
 		Limit(req.GetLimit()).
 		Preload("Course").
 		Preload("Teacher").
-		Preload("Timetable").
+		Preload("Timetable.Slots").
 		Find(&groups).Error; err != nil {
 		return nil, errors.DatabaseError("listing groups", err)
 	}
@@ -182,8 +211,40 @@ func (s *groupService) validateForeignKeys(courseID, teacherID, timetableID stri
 	return nil
 }
 
+// checkSchedule rejects a teacher or timetable that would double-book the
+// teacher or the classroom, clash with other bookings of the group, or fall
+// outside the teacher's availability and weekly hours
+func (s *groupService) checkSchedule(group *models.Group) error {
+	var timetable models.Timetable
+	if err := s.db.Preload("Slots").First(&timetable, "id = ?", group.TimetableID).Error; err != nil {
+		return errors.DatabaseError("finding timetable", err)
+	}
+	if err := ensureTeacherCanTake(s.db, group.TeacherID, timetable.Slots, group.ID); err != nil {
+		return err
+	}
+	return ensureNoTimetableConflicts(s.db, &timetable, group, group.ID)
+}
+
+// checkRoom rejects moving the group to a timetable whose room cannot seat
+// its students together with the groups already using it
+func (s *groupService) checkRoom(group *models.Group) error {
+	var timetable models.Timetable
+	if err := s.db.Preload("Slots").First(&timetable, "id = ?", group.TimetableID).Error; err != nil {
+		return errors.DatabaseError("finding timetable", err)
+	}
+	sharing, err := timetableHeadcount(s.db, timetable.ID)
+	if err != nil {
+		return err
+	}
+	own, err := groupHeadcount(s.db, group.ID)
+	if err != nil {
+		return err
+	}
+	return ensureTimetableRoomsFit(s.db, &timetable, sharing+own)
+}
+
 func (s *groupService) loadRelations(group *models.Group) {
-	s.db.Preload("Course").Preload("Teacher").Preload("Timetable").First(group, "id = ?", group.ID)
+	s.db.Preload("Course").Preload("Teacher").Preload("Timetable.Slots").First(group, "id = ?", group.ID)
 }
 
 func (s *groupService) toResponse(g *models.Group) *dto.GroupResponse {
@@ -206,6 +267,7 @@ func (s *groupService) toResponse(g *models.Group) *dto.GroupResponse {
 		TimetableID:  g.TimetableID,
 		Capacity:     g.Capacity,
 		StudentCount: len(g.Students),
+		TermID:       g.TermID,
 		Course: dto.CourseSimple{
 			ID:         g.Course.ID,
 			Title:      g.Course.Title,
@@ -224,6 +286,7 @@ func (s *groupService) toResponse(g *models.Group) *dto.GroupResponse {
 			EndTime:   g.Timetable.EndTime,
 			Days:      g.Timetable.Days,
 			Classroom: g.Timetable.Classroom,
+			Slots:     toSlotResponses(g.Timetable),
 		},
 		Students:  students,
 		CreatedAt: g.CreatedAt,
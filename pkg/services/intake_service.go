@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Form tokens are only accepted between formTokenMinAge and formTokenMaxAge
+// after they were issued; bots tend to submit a form the moment they load it.
+const (
+	formTokenMinAge = 3 * time.Second
+	formTokenMaxAge = 2 * time.Hour
+
+	// MaxIntakeDocuments limits the supporting files of a public application
+	MaxIntakeDocuments = 5
+)
+
+// IntakeService accepts applications from the public website
+type IntakeService struct {
+	db            *gorm.DB
+	secret        []byte
+	statusURL     string
+	documents     *DocumentService
+	notifications *NotificationService
+}
+
+// NewIntakeService creates a new intake service. Form tokens are signed with
+// secret; without one, a random secret is used and tokens do not survive a
+// restart. Status-check links are statusURL followed by the applicant's token.
+func NewIntakeService(db *gorm.DB, secret, statusURL string) *IntakeService {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("failed to generate intake form secret: %v", err))
+		}
+		logger.Warn("INTAKE_FORM_SECRET is not set; form tokens will not survive a restart")
+	}
+
+	return &IntakeService{
+		db:            db,
+		secret:        key,
+		statusURL:     strings.TrimRight(statusURL, "/"),
+		documents:     NewDocumentService(db),
+		notifications: NewNotificationService(db),
+	}
+}
+
+// IssueFormToken returns a signed token to embed in the application form
+func (s *IntakeService) IssueFormToken(ctx context.Context) (*dto.FormTokenResponse, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Internal("failed to generate form token", err)
+	}
+
+	issuedAt := time.Now()
+	payload := strconv.FormatInt(issuedAt.Unix(), 10) + "." + hex.EncodeToString(nonce)
+
+	return &dto.FormTokenResponse{
+		Token:     payload + "." + s.sign(payload),
+		NotBefore: issuedAt.Add(formTokenMinAge),
+		ExpiresAt: issuedAt.Add(formTokenMaxAge),
+	}, nil
+}
+
+// Submit creates a pending application from the public form, stores its
+// supporting files and sends the applicant an acknowledgement with a link
+// to check its status
+func (s *IntakeService) Submit(ctx context.Context, req dto.PublicApplicationRequest, files []*multipart.FileHeader) (*dto.PublicApplicationResponse, error) {
+	if req.Website != "" {
+		logger.WithContext(map[string]interface{}{"email": req.Email}).Warn().Msg("public application rejected by honeypot")
+		return nil, errors.BadRequest("Submission rejected")
+	}
+	if err := s.verifyFormToken(req.FormToken, time.Now()); err != nil {
+		return nil, err
+	}
+
+	dateOfBirth, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		return nil, errors.Validation("date_of_birth must be a date (YYYY-MM-DD)")
+	}
+	var course models.Course
+	if err := s.db.Select("id", "title").First(&course, "id = ?", req.CourseID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Course", req.CourseID)
+		}
+		return nil, errors.DatabaseError("finding course", err)
+	}
+
+	// Check every file before storing any
+	if len(files) > MaxIntakeDocuments {
+		return nil, errors.Validation(fmt.Sprintf("At most %d documents can be attached", MaxIntakeDocuments))
+	}
+	for _, file := range files {
+		if err := s.documents.ValidateUpload(file); err != nil {
+			return nil, errors.Validation(fmt.Sprintf("%s: %v", file.Filename, err))
+		}
+	}
+
+	statusToken, err := generateSecureToken(32)
+	if err != nil {
+		return nil, errors.Internal("failed to generate status token", err)
+	}
+
+	var application *dto.ApplicationResponse
+	var stored []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		application, err = NewApplicationService(tx, nil).Create(ctx, dto.CreateApplicationRequest{
+			FirstName:         req.FirstName,
+			LastName:          req.LastName,
+			Email:             req.Email,
+			Phone:             req.Phone,
+			DateOfBirth:       dateOfBirth,
+			Gender:            req.Gender,
+			Address:           req.Address,
+			CourseID:          course.ID,
+			PreviousEducation: req.PreviousEducation,
+		})
+		if err != nil {
+			return errors.DatabaseError("creating application", err)
+		}
+
+		documents := NewDocumentService(tx)
+		documents.uploadPath = s.documents.uploadPath
+		documentIDs := make([]string, 0, len(files))
+		for _, file := range files {
+			document, err := documents.Upload(ctx, dto.UploadDocumentRequest{
+				Name:          file.Filename,
+				Description:   fmt.Sprintf("Submitted with the application of %s %s", req.FirstName, req.LastName),
+				Type:          models.DocumentTypeOther,
+				CourseID:      &course.ID,
+				ApplicationID: &application.ID,
+			}, file, nil) // Uploaded by the applicant, not a user
+			if err != nil {
+				return errors.Internal("failed to store document", err)
+			}
+			stored = append(stored, document.FilePath)
+			documentIDs = append(documentIDs, document.ID.String())
+		}
+
+		if err := tx.Model(&models.Application{}).Where("id = ?", application.ID).Updates(models.Application{
+			StatusToken: statusToken,
+			Documents:   documentIDs,
+		}).Error; err != nil {
+			return errors.DatabaseError("updating application", err)
+		}
+		application.Documents = documentIDs
+		return nil
+	})
+	if err != nil {
+		for _, path := range stored {
+			os.Remove(path)
+		}
+		return nil, err
+	}
+
+	statusURL := s.statusURL + "/" + statusToken
+	subject := "We received your application"
+	message := fmt.Sprintf("Hello %s,\n\nThank you for applying to %s. We will review your application and get back to you.\n\nYou can check its status at any time: %s",
+		req.FirstName, course.Title, statusURL)
+	s.notifications.notifyContact(ctx, nil, req.Email, req.Phone, subject, message)
+
+	return &dto.PublicApplicationResponse{
+		ApplicationID: application.ID,
+		Status:        application.Status,
+		StatusURL:     statusURL,
+		Documents:     len(application.Documents),
+	}, nil
+}
+
+// GetStatus returns what an applicant may see about their application
+func (s *IntakeService) GetStatus(ctx context.Context, token string) (*dto.PublicApplicationStatusResponse, error) {
+	if token == "" {
+		return nil, errors.NotFound("Application")
+	}
+
+	var application models.Application
+	if err := s.db.Preload("Course").First(&application, "status_token = ?", token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("Application")
+		}
+		return nil, errors.DatabaseError("finding application", err)
+	}
+
+	return &dto.PublicApplicationStatusResponse{
+		FirstName:       application.FirstName,
+		CourseTitle:     application.Course.Title,
+		Status:          application.Status,
+		ApplicationDate: application.ApplicationDate,
+		ReviewedAt:      application.ReviewedAt,
+		EnrolledAt:      application.EnrolledAt,
+		Documents:       len(application.Documents),
+	}, nil
+}
+
+// verifyFormToken checks a form token's signature and age
+func (s *IntakeService) verifyFormToken(token string, now time.Time) error {
+	invalid := errors.BadRequest("The form has expired; reload the page and submit it again")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return invalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload))) {
+		return invalid
+	}
+	issuedAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return invalid
+	}
+
+	age := now.Sub(time.Unix(issuedAt, 0))
+	if age < formTokenMinAge {
+		return errors.BadRequest("The form was submitted too quickly; wait a moment and submit it again")
+	}
+	if age > formTokenMaxAge {
+		return invalid
+	}
+	return nil
+}
+
+// sign returns the HMAC of a form token payload
+func (s *IntakeService) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
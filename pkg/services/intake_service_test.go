@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// intakeFiles builds uploaded file headers from name → content type pairs
+func intakeFiles(t *testing.T, files map[string]string) []*multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, contentType := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="documents"; filename="%s"`, name))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		assert.NoError(t, err)
+		part.Write([]byte("content of " + name))
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, req.ParseMultipartForm(1<<20))
+	return req.MultipartForm.File["documents"]
+}
+
+func TestIntakeService_Submit(t *testing.T) {
+	db := setupTestDB()
+	intake := NewIntakeService(db, "secret", "https://example.com/apply/status/")
+	intake.documents.uploadPath = t.TempDir()
+	ctx := context.Background()
+
+	course := models.Course{Title: "Go Beginner"}
+	db.Create(&course)
+
+	// A token from a minute ago passes the minimum age
+	formToken := func(issuedAt time.Time) string {
+		payload := fmt.Sprintf("%d.%s", issuedAt.Unix(), "0011223344556677")
+		return payload + "." + intake.sign(payload)
+	}
+	req := dto.PublicApplicationRequest{FirstName: "Zarina", LastName: "Karimova", Email: "zarina@example.com", Phone: "992900000111",
+		DateOfBirth: "2008-05-14", CourseID: course.ID.String(), FormToken: formToken(time.Now().Add(-time.Minute))}
+
+	issued, err := intake.IssueFormToken(ctx)
+	assert.NoError(t, err)
+	tooQuick := req
+	tooQuick.FormToken = issued.Token
+	_, err = intake.Submit(ctx, tooQuick, nil)
+	assert.Error(t, err)
+
+	expired := req
+	expired.FormToken = formToken(time.Now().Add(-3 * time.Hour))
+	_, err = intake.Submit(ctx, expired, nil)
+	assert.Error(t, err)
+
+	tampered := req
+	tampered.FormToken = strings.Replace(req.FormToken, "0011", "9911", 1)
+	_, err = intake.Submit(ctx, tampered, nil)
+	assert.Error(t, err)
+
+	spam := req
+	spam.Website = "http://spam.example.com"
+	_, err = intake.Submit(ctx, spam, nil)
+	assert.Error(t, err)
+
+	_, err = intake.Submit(ctx, req, intakeFiles(t, map[string]string{"setup.exe": "application/x-msdownload"}))
+	assert.Error(t, err)
+	var count int64
+	db.Model(&models.Application{}).Count(&count)
+	assert.EqualValues(t, 0, count)
+
+	// A valid submission stores the documents and acknowledges the applicant
+	result, err := intake.Submit(ctx, req, intakeFiles(t, map[string]string{"passport.pdf": "application/pdf", "photo.jpg": "image/jpeg"}))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApplicationPending, result.Status)
+	assert.Equal(t, 2, result.Documents)
+	assert.True(t, strings.HasPrefix(result.StatusURL, "https://example.com/apply/status/"))
+
+	var application models.Application
+	db.First(&application, "id = ?", result.ApplicationID)
+	assert.Len(t, application.Documents, 2)
+	var documents []models.Document
+	db.Find(&documents, "application_id = ?", result.ApplicationID)
+	assert.Len(t, documents, 2)
+
+	var notification models.Notification
+	db.First(&notification, "recipient = ?", "zarina@example.com")
+	assert.Contains(t, notification.Message, result.StatusURL)
+
+	token := strings.TrimPrefix(result.StatusURL, "https://example.com/apply/status/")
+	status, err := intake.GetStatus(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "Go Beginner", status.CourseTitle)
+	assert.Equal(t, 2, status.Documents)
+	_, err = intake.GetStatus(ctx, "unknown")
+	assert.Error(t, err)
+}
+
+func TestIntakeService_SubmitWithForeignKeys(t *testing.T) {
+	// Foreign keys are enforced as in production, so an applicant's upload
+	// must not reference a user
+	db, err := gorm.Open(sqlite.Open("file::memory:?_pragma=foreign_keys(1)"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&models.User{}, &models.Teacher{}, &models.Course{}, &models.Timetable{}, &models.Group{},
+		&models.CoursePrerequisite{}, &models.Student{}, &models.Application{}, &models.Document{}, &models.Notification{}))
+	intake := NewIntakeService(db, "secret", "https://example.com/apply/status/")
+	intake.documents.uploadPath = t.TempDir()
+	ctx := context.Background()
+
+	course := models.Course{Title: "Go Beginner"}
+	db.Create(&course)
+	payload := fmt.Sprintf("%d.%s", time.Now().Add(-time.Minute).Unix(), "0011223344556677")
+	req := dto.PublicApplicationRequest{FirstName: "Zarina", LastName: "Karimova", Email: "zarina@example.com", Phone: "992900000111",
+		DateOfBirth: "2008-05-14", CourseID: course.ID.String(), FormToken: payload + "." + intake.sign(payload)}
+
+	result, err := intake.Submit(ctx, req, intakeFiles(t, map[string]string{"passport.pdf": "application/pdf"}))
+	if assert.NoError(t, err) {
+		var document models.Document
+		db.First(&document, "application_id = ?", result.ApplicationID)
+		assert.Nil(t, document.UploadedBy)
+	}
+}
@@ -19,7 +19,7 @@ type InvoiceService interface {
 	Update(ctx context.Context, id string, req dto.UpdateInvoiceRequest) (*dto.InvoiceResponse, error)
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*dto.InvoiceResponse, error)
-	GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
+	GetAll(ctx context.Context, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error)
 	GetByStudent(ctx context.Context, studentID string, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
 }
 
@@ -172,7 +172,7 @@ func (s *invoiceService) GetByID(ctx context.Context, id string) (*dto.InvoiceRe
 	return s.toResponse(&invoice), nil
 }
 
-func (s *invoiceService) GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+func (s *invoiceService) GetAll(ctx context.Context, req dto.PaginationRequest, termID string) (*dto.PaginatedResponse, error) {
 	var invoices []models.Invoice
 	var total int64
 
@@ -182,6 +182,9 @@ func (s *invoiceService) GetAll(ctx context.Context, req dto.PaginationRequest)
 		search := "%" + strings.ToLower(req.Search) + "%"
 		query = query.Where("LOWER(invoice_number) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
+	if termID != "" {
+		query = query.Where("term_id = ?", termID)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, errors.DatabaseError("counting invoices", err)
@@ -246,6 +249,7 @@ func (s *invoiceService) toResponse(inv *models.Invoice) *dto.InvoiceResponse {
 		StudentID:      inv.StudentID,
 		CourseID:       inv.CourseID,
 		GroupID:        inv.GroupID,
+		TermID:         inv.TermID,
 		SubTotal:       inv.SubTotal,
 		DiscountAmount: inv.DiscountAmount,
 		TaxAmount:      inv.TaxAmount,
@@ -0,0 +1,555 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// LeadService manages prospective student inquiries through the sales
+// pipeline until they become applications or students
+type LeadService struct {
+	db *gorm.DB
+}
+
+// NewLeadService creates a new lead service
+func NewLeadService(db *gorm.DB) *LeadService {
+	return &LeadService{db: db}
+}
+
+// Create records a new lead. Without an owner, the lead is assigned to the
+// staff user who records it.
+func (s *LeadService) Create(ctx context.Context, req dto.CreateLeadRequest, userID uuid.UUID) (*dto.LeadResponse, error) {
+	if req.Email == "" && req.Phone == "" {
+		return nil, errors.Validation("A lead needs an email or a phone number")
+	}
+	if req.CourseID != nil {
+		if err := ensureLeadCourse(s.db, *req.CourseID); err != nil {
+			return nil, err
+		}
+	}
+	ownerID := req.OwnerID
+	if ownerID != nil {
+		if err := ensureLeadOwner(s.db, *ownerID); err != nil {
+			return nil, err
+		}
+	} else if ensureLeadOwner(s.db, userID) == nil {
+		ownerID = &userID
+	}
+
+	lead := models.Lead{
+		ID:             uuid.New(),
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Source:         req.Source,
+		Campaign:       req.Campaign,
+		CourseID:       req.CourseID,
+		OwnerID:        ownerID,
+		Stage:          models.LeadNew,
+		StageChangedAt: time.Now(),
+		NextFollowUpAt: req.NextFollowUpAt,
+		Notes:          req.Notes,
+	}
+	if err := s.db.Create(&lead).Error; err != nil {
+		return nil, errors.DatabaseError("creating lead", err)
+	}
+
+	return s.GetByID(ctx, lead.ID)
+}
+
+// GetByID retrieves a lead
+func (s *LeadService) GetByID(ctx context.Context, id uuid.UUID) (*dto.LeadResponse, error) {
+	var lead models.Lead
+	if err := s.db.Preload("Course").Preload("Owner").First(&lead, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Lead", id.String())
+		}
+		return nil, errors.DatabaseError("finding lead", err)
+	}
+	return toLeadResponse(&lead, time.Now()), nil
+}
+
+// GetAll lists leads, newest first, or by follow-up date when only due
+// follow-ups are requested
+func (s *LeadService) GetAll(ctx context.Context, req dto.PaginationRequest, filter dto.LeadFilter) (*dto.PaginatedResponse, error) {
+	query := s.db.Model(&models.Lead{})
+	if req.Search != "" {
+		search := "%" + strings.ToLower(req.Search) + "%"
+		query = query.Where("LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ? OR LOWER(email) LIKE ? OR phone LIKE ?",
+			search, search, search, search)
+	}
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("owner_id = ?", filter.OwnerID)
+	}
+	if filter.CourseID != "" {
+		query = query.Where("course_id = ?", filter.CourseID)
+	}
+	order := "created_at DESC"
+	if filter.FollowUpDue {
+		query = query.Where("stage NOT IN ? AND next_follow_up_at <= ?", []models.LeadStage{models.LeadWon, models.LeadLost}, time.Now())
+		order = "next_follow_up_at ASC"
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.DatabaseError("counting leads", err)
+	}
+
+	var leads []models.Lead
+	if err := query.Preload("Course").Preload("Owner").Order(order).
+		Offset(req.GetOffset()).Limit(req.GetLimit()).Find(&leads).Error; err != nil {
+		return nil, errors.DatabaseError("listing leads", err)
+	}
+
+	now := time.Now()
+	data := make([]interface{}, len(leads))
+	for i := range leads {
+		data[i] = toLeadResponse(&leads[i], now)
+	}
+
+	return &dto.PaginatedResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: dto.NewPaginationMetadata(req.Page, req.PageSize, total),
+	}, nil
+}
+
+// Update changes a lead's contact, attribution and follow-up details
+func (s *LeadService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateLeadRequest) (*dto.LeadResponse, error) {
+	lead, err := loadLead(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstName != nil {
+		lead.FirstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		lead.LastName = *req.LastName
+	}
+	if req.Email != nil {
+		lead.Email = *req.Email
+	}
+	if req.Phone != nil {
+		lead.Phone = *req.Phone
+	}
+	if lead.Email == "" && lead.Phone == "" {
+		return nil, errors.Validation("A lead needs an email or a phone number")
+	}
+	if req.Source != nil {
+		lead.Source = *req.Source
+	}
+	if req.Campaign != nil {
+		lead.Campaign = *req.Campaign
+	}
+	if req.CourseID != nil {
+		if err := ensureLeadCourse(s.db, *req.CourseID); err != nil {
+			return nil, err
+		}
+		lead.CourseID = req.CourseID
+	}
+	if req.OwnerID != nil {
+		if err := ensureLeadOwner(s.db, *req.OwnerID); err != nil {
+			return nil, err
+		}
+		lead.OwnerID = req.OwnerID
+	}
+	if req.ClearFollowUp {
+		lead.NextFollowUpAt = nil
+	} else if req.NextFollowUpAt != nil {
+		if lead.Stage.IsClosed() {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, "Closed leads have no follow-ups")
+		}
+		lead.NextFollowUpAt = req.NextFollowUpAt
+	}
+	if req.Notes != nil {
+		lead.Notes = *req.Notes
+	}
+
+	if err := s.db.Omit("Course", "Owner").Save(lead).Error; err != nil {
+		return nil, errors.DatabaseError("updating lead", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ChangeStage moves a lead through the pipeline and records the move in its
+// history. Won and lost leads are closed and lose their follow-up; moving
+// them back to an open stage reopens them.
+func (s *LeadService) ChangeStage(ctx context.Context, id uuid.UUID, req dto.ChangeLeadStageRequest, userID uuid.UUID) (*dto.LeadResponse, error) {
+	lead, err := loadLead(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if lead.ConvertedAt != nil {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Converted leads cannot change stage")
+	}
+	if lead.Stage == req.Stage {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Lead is already %s", req.Stage))
+	}
+	reason := strings.TrimSpace(req.LostReason)
+	if req.Stage == models.LeadLost && reason == "" {
+		return nil, errors.Validation("A reason is required when a lead is lost")
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return moveLeadStage(tx, lead, req.Stage, reason, req.Notes, &userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Delete deletes a lead and its history
+func (s *LeadService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Lead{}, "id = ?", id)
+		if result.Error != nil {
+			return errors.DatabaseError("deleting lead", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.NotFoundWithID("Lead", id.String())
+		}
+		if err := tx.Where("lead_id = ?", id).Delete(&models.LeadActivity{}).Error; err != nil {
+			return errors.DatabaseError("deleting lead activities", err)
+		}
+		return nil
+	})
+}
+
+// AddActivity logs a call, message, meeting or note. Reaching a new lead
+// moves it to contacted; a given follow-up date replaces the current one.
+func (s *LeadService) AddActivity(ctx context.Context, id uuid.UUID, req dto.CreateLeadActivityRequest, userID uuid.UUID) (*dto.LeadActivityResponse, error) {
+	lead, err := loadLead(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.NextFollowUpAt != nil && lead.Stage.IsClosed() {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Closed leads have no follow-ups")
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		occurredAt = *req.OccurredAt
+	}
+	activity := models.LeadActivity{
+		ID:         uuid.New(),
+		LeadID:     lead.ID,
+		UserID:     &userID,
+		Type:       req.Type,
+		Summary:    req.Summary,
+		Details:    req.Details,
+		OccurredAt: occurredAt,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&activity).Error; err != nil {
+			return errors.DatabaseError("creating lead activity", err)
+		}
+
+		updates := make(map[string]interface{})
+		if req.Type.IsContact() && (lead.LastContactedAt == nil || occurredAt.After(*lead.LastContactedAt)) {
+			updates["last_contacted_at"] = occurredAt
+		}
+		if req.NextFollowUpAt != nil {
+			updates["next_follow_up_at"] = *req.NextFollowUpAt
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&models.Lead{}).Where("id = ?", lead.ID).Updates(updates).Error; err != nil {
+				return errors.DatabaseError("updating lead", err)
+			}
+		}
+
+		if req.Type.IsContact() && lead.Stage == models.LeadNew {
+			return moveLeadStage(tx, lead, models.LeadContacted, "", "", &userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.Preload("User").First(&activity, "id = ?", activity.ID)
+	return toLeadActivityResponse(&activity), nil
+}
+
+// GetActivities returns a lead's history, most recent first
+func (s *LeadService) GetActivities(ctx context.Context, id uuid.UUID) ([]dto.LeadActivityResponse, error) {
+	if _, err := loadLead(s.db, id); err != nil {
+		return nil, err
+	}
+
+	var activities []models.LeadActivity
+	if err := s.db.Preload("User").Where("lead_id = ?", id).
+		Order("occurred_at DESC, created_at DESC").Find(&activities).Error; err != nil {
+		return nil, errors.DatabaseError("listing lead activities", err)
+	}
+
+	responses := make([]dto.LeadActivityResponse, len(activities))
+	for i := range activities {
+		responses[i] = *toLeadActivityResponse(&activities[i])
+	}
+	return responses, nil
+}
+
+// Convert turns a won lead into an application or a student. The created
+// record keeps the lead's ID, so its source, campaign and owner stay
+// attributable.
+func (s *LeadService) Convert(ctx context.Context, id uuid.UUID, req dto.ConvertLeadRequest, userID uuid.UUID) (*dto.LeadConversionResponse, error) {
+	lead, err := loadLead(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if lead.Stage != models.LeadWon {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Only won leads can be converted")
+	}
+	if lead.ConvertedAt != nil {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "Lead has already been converted")
+	}
+
+	response := &dto.LeadConversionResponse{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var summary string
+
+		switch req.Target {
+		case "application":
+			courseID := lead.CourseID
+			if req.CourseID != nil {
+				courseID = req.CourseID
+			}
+			if courseID == nil {
+				return errors.Validation("A course is required to convert a lead to an application")
+			}
+			if err := ensureLeadCourse(tx, *courseID); err != nil {
+				return err
+			}
+			if req.DateOfBirth == nil {
+				return errors.Validation("date_of_birth is required to convert a lead to an application")
+			}
+			if lead.Email == "" || lead.Phone == "" {
+				return errors.Validation("Applications need both an email and a phone number")
+			}
+
+			application, err := NewApplicationService(tx, nil).Create(ctx, dto.CreateApplicationRequest{
+				FirstName:   lead.FirstName,
+				LastName:    lead.LastName,
+				Email:       lead.Email,
+				Phone:       lead.Phone,
+				DateOfBirth: *req.DateOfBirth,
+				CourseID:    *courseID,
+			})
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Application{}).Where("id = ?", application.ID).Update("lead_id", lead.ID).Error; err != nil {
+				return errors.DatabaseError("attributing application", err)
+			}
+			application.LeadID = &lead.ID
+			lead.ApplicationID = &application.ID
+			response.Application = application
+			summary = "Converted to an application"
+
+		case "student":
+			if req.GroupID == nil {
+				return errors.Validation("group_id is required to convert a lead to a student")
+			}
+			student, err := NewStudentService(tx).Create(ctx, req.GroupID.String(), dto.CreateStudentRequest{
+				Name:    lead.FirstName,
+				Surname: lead.LastName,
+				Phone:   lead.Phone,
+				Email:   lead.Email,
+			})
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Student{}).Where("id = ?", student.ID).Update("lead_id", lead.ID).Error; err != nil {
+				return errors.DatabaseError("attributing student", err)
+			}
+			student.LeadID = &lead.ID
+			lead.StudentID = &student.ID
+			response.Student = student
+			summary = "Converted to a student"
+		}
+
+		lead.ConvertedAt = &now
+		if err := tx.Omit("Course", "Owner").Save(lead).Error; err != nil {
+			return errors.DatabaseError("updating lead", err)
+		}
+		activity := models.LeadActivity{ID: uuid.New(), LeadID: lead.ID, UserID: &userID,
+			Type: models.LeadActivityNote, Summary: summary, OccurredAt: now}
+		if err := tx.Create(&activity).Error; err != nil {
+			return errors.DatabaseError("creating lead activity", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Lead, err = s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// moveLeadStage sets a lead's stage and records the change
+func moveLeadStage(tx *gorm.DB, lead *models.Lead, stage models.LeadStage, reason, notes string, userID *uuid.UUID) error {
+	now := time.Now()
+	from := lead.Stage
+	updates := map[string]interface{}{
+		"stage":            stage,
+		"stage_changed_at": now,
+		"lost_reason":      reason,
+	}
+	if stage.IsClosed() {
+		updates["closed_at"] = now
+		updates["next_follow_up_at"] = nil
+	} else {
+		updates["closed_at"] = nil
+	}
+	if err := tx.Model(&models.Lead{}).Where("id = ?", lead.ID).Updates(updates).Error; err != nil {
+		return errors.DatabaseError("changing lead stage", err)
+	}
+
+	details := notes
+	if reason != "" {
+		details = strings.TrimSpace("Reason: " + reason + "\n" + notes)
+	}
+	activity := models.LeadActivity{
+		ID:         uuid.New(),
+		LeadID:     lead.ID,
+		UserID:     userID,
+		Type:       models.LeadActivityStageChange,
+		Summary:    fmt.Sprintf("Stage changed from %s to %s", from, stage),
+		Details:    details,
+		FromStage:  from,
+		ToStage:    stage,
+		OccurredAt: now,
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return errors.DatabaseError("creating lead activity", err)
+	}
+
+	lead.Stage = stage
+	return nil
+}
+
+// loadLead finds a lead by ID
+func loadLead(db *gorm.DB, id uuid.UUID) (*models.Lead, error) {
+	var lead models.Lead
+	if err := db.First(&lead, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Lead", id.String())
+		}
+		return nil, errors.DatabaseError("finding lead", err)
+	}
+	return &lead, nil
+}
+
+// ensureLeadCourse checks that a lead's course of interest exists
+func ensureLeadCourse(db *gorm.DB, courseID uuid.UUID) error {
+	var count int64
+	if err := db.Model(&models.Course{}).Where("id = ?", courseID).Count(&count).Error; err != nil {
+		return errors.DatabaseError("checking course existence", err)
+	}
+	if count == 0 {
+		return errors.NotFoundWithID("Course", courseID.String())
+	}
+	return nil
+}
+
+// ensureLeadOwner checks that a lead's owner is an active admin or staff user
+func ensureLeadOwner(db *gorm.DB, userID uuid.UUID) error {
+	var user models.User
+	if err := db.Select("id", "role", "is_active").First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFoundWithID("User", userID.String())
+		}
+		return errors.DatabaseError("finding lead owner", err)
+	}
+	if !user.IsActive || (user.Role != models.RoleAdmin && user.Role != models.RoleStaff) {
+		return errors.Validation("Leads can only be owned by active admin or staff users")
+	}
+	return nil
+}
+
+// toLeadResponse converts a lead to its API response
+func toLeadResponse(l *models.Lead, now time.Time) *dto.LeadResponse {
+	response := &dto.LeadResponse{
+		ID:              l.ID,
+		FirstName:       l.FirstName,
+		LastName:        l.LastName,
+		Email:           l.Email,
+		Phone:           l.Phone,
+		Source:          l.Source,
+		Campaign:        l.Campaign,
+		CourseID:        l.CourseID,
+		OwnerID:         l.OwnerID,
+		Stage:           l.Stage,
+		LostReason:      l.LostReason,
+		StageChangedAt:  l.StageChangedAt,
+		ClosedAt:        l.ClosedAt,
+		NextFollowUpAt:  l.NextFollowUpAt,
+		FollowUpOverdue: !l.Stage.IsClosed() && l.NextFollowUpAt != nil && l.NextFollowUpAt.Before(now),
+		LastContactedAt: l.LastContactedAt,
+		ApplicationID:   l.ApplicationID,
+		StudentID:       l.StudentID,
+		ConvertedAt:     l.ConvertedAt,
+		Notes:           l.Notes,
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+	}
+	if l.Course != nil {
+		response.CourseTitle = l.Course.Title
+	}
+	if l.Owner != nil {
+		response.OwnerName = userDisplayName(l.Owner)
+	}
+	return response
+}
+
+// toLeadActivityResponse converts a lead activity to its API response
+func toLeadActivityResponse(a *models.LeadActivity) *dto.LeadActivityResponse {
+	response := &dto.LeadActivityResponse{
+		ID:         a.ID,
+		LeadID:     a.LeadID,
+		UserID:     a.UserID,
+		Type:       a.Type,
+		Summary:    a.Summary,
+		Details:    a.Details,
+		FromStage:  a.FromStage,
+		ToStage:    a.ToStage,
+		OccurredAt: a.OccurredAt,
+	}
+	if a.User != nil {
+		response.UserName = userDisplayName(a.User)
+	}
+	return response
+}
+
+// userDisplayName returns a user's full name, or their email without one
+func userDisplayName(u *models.User) string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Email
+}
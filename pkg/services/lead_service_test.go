@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeadService_Pipeline(t *testing.T) {
+	db := setupTestDB()
+	leads := NewLeadService(db)
+	ctx := context.Background()
+
+	staff := models.User{ID: uuid.New(), Email: "sales@example.com", Password: "x", Role: models.RoleStaff, IsActive: true, FirstName: "Malika", LastName: "Rahimova"}
+	db.Create(&staff)
+	teacher := models.User{ID: uuid.New(), Email: "teacher@example.com", Password: "x", Role: models.RoleTeacher, IsActive: true}
+	db.Create(&teacher)
+	course := models.Course{Title: "Go Beginner"}
+	db.Create(&course)
+
+	_, err := leads.Create(ctx, dto.CreateLeadRequest{FirstName: "Farid", Source: models.LeadSourcePhone}, staff.ID)
+	assert.Error(t, err)
+	_, err = leads.Create(ctx, dto.CreateLeadRequest{FirstName: "Farid", Phone: "992900000101", Source: models.LeadSourcePhone, OwnerID: &teacher.ID}, staff.ID)
+	assert.Error(t, err)
+
+	lead, err := leads.Create(ctx, dto.CreateLeadRequest{FirstName: "Farid", LastName: "Saidov", Phone: "992900000101",
+		Source: models.LeadSourceWebsite, Campaign: "spring-ads", CourseID: &course.ID}, staff.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LeadNew, lead.Stage)
+	assert.Equal(t, "Malika Rahimova", lead.OwnerName)
+	assert.Equal(t, "Go Beginner", lead.CourseTitle)
+
+	// Calling a new lead moves it to contacted and sets its follow-up
+	followUp := time.Now().Add(-time.Hour)
+	activity, err := leads.AddActivity(ctx, lead.ID, dto.CreateLeadActivityRequest{Type: models.LeadActivityCall,
+		Summary: "Asked about evening groups", NextFollowUpAt: &followUp}, staff.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Malika Rahimova", activity.UserName)
+	lead, err = leads.GetByID(ctx, lead.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LeadContacted, lead.Stage)
+	assert.NotNil(t, lead.LastContactedAt)
+	assert.True(t, lead.FollowUpOverdue)
+
+	due, err := leads.GetAll(ctx, dto.PaginationRequest{Page: 1, PageSize: 10}, dto.LeadFilter{FollowUpDue: true})
+	assert.NoError(t, err)
+	assert.Len(t, due.Data, 1)
+
+	_, err = leads.ChangeStage(ctx, lead.ID, dto.ChangeLeadStageRequest{Stage: models.LeadLost}, staff.ID)
+	assert.Error(t, err)
+	_, err = leads.Convert(ctx, lead.ID, dto.ConvertLeadRequest{Target: "student"}, staff.ID)
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeInvalidOperation, err.(*errors.AppError).Code)
+	}
+
+	lead, err = leads.ChangeStage(ctx, lead.ID, dto.ChangeLeadStageRequest{Stage: models.LeadWon}, staff.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, lead.ClosedAt)
+	assert.Nil(t, lead.NextFollowUpAt)
+
+	history, err := leads.GetActivities(ctx, lead.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, models.LeadActivityStageChange, history[0].Type)
+		assert.Equal(t, models.LeadWon, history[0].ToStage)
+	}
+
+	// Converting keeps attribution on the student
+	group := models.Group{Name: "GO-1", CourseID: course.ID, Capacity: 10}
+	db.Create(&group)
+	converted, err := leads.Convert(ctx, lead.ID, dto.ConvertLeadRequest{Target: "student", GroupID: &group.ID}, staff.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, converted.Student) {
+		assert.Equal(t, lead.ID, *converted.Student.LeadID)
+		assert.Equal(t, converted.Student.ID, *converted.Lead.StudentID)
+	}
+	var student models.Student
+	db.First(&student, "lead_id = ?", lead.ID)
+	assert.Equal(t, "Saidov", student.Surname)
+
+	_, err = leads.Convert(ctx, lead.ID, dto.ConvertLeadRequest{Target: "student", GroupID: &group.ID}, staff.ID)
+	assert.Error(t, err)
+	_, err = leads.ChangeStage(ctx, lead.ID, dto.ChangeLeadStageRequest{Stage: models.LeadContacted}, staff.ID)
+	assert.Error(t, err)
+}
+
+func TestLeadService_ConvertToApplication(t *testing.T) {
+	db := setupTestDB()
+	leads := NewLeadService(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	course := models.Course{Title: "Go Beginner"}
+	db.Create(&course)
+	lead, err := leads.Create(ctx, dto.CreateLeadRequest{FirstName: "Zarina", LastName: "Karimova", Email: "zarina@example.com",
+		Phone: "992900000102", Source: models.LeadSourceReferral, CourseID: &course.ID}, userID)
+	assert.NoError(t, err)
+	assert.Nil(t, lead.OwnerID)
+
+	_, err = leads.ChangeStage(ctx, lead.ID, dto.ChangeLeadStageRequest{Stage: models.LeadWon}, userID)
+	assert.NoError(t, err)
+	_, err = leads.Convert(ctx, lead.ID, dto.ConvertLeadRequest{Target: "application"}, userID)
+	assert.Error(t, err)
+
+	birthday := time.Date(2008, 5, 14, 0, 0, 0, 0, time.UTC)
+	converted, err := leads.Convert(ctx, lead.ID, dto.ConvertLeadRequest{Target: "application", DateOfBirth: &birthday}, userID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, converted.Application) {
+		assert.Equal(t, course.ID, converted.Application.CourseID)
+		assert.Equal(t, lead.ID, *converted.Application.LeadID)
+		assert.Equal(t, converted.Application.ID, *converted.Lead.ApplicationID)
+	}
+}
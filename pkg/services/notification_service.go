@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
@@ -217,6 +218,29 @@ func (s *NotificationService) RetryFailed(ctx context.Context, id string) (*dto.
 	return s.toResponse(&notification), nil
 }
 
+// notifyContact emails a contact, falling back to SMS when there is no email
+// address. It returns the recipient used and whether delivery succeeded.
+func (s *NotificationService) notifyContact(ctx context.Context, studentID *uuid.UUID, email, phone, subject, message string) (string, bool) {
+	req := dto.SendNotificationRequest{
+		Type:      models.NotificationEmail,
+		Recipient: email,
+		StudentID: studentID,
+		Subject:   subject,
+		Message:   message,
+	}
+	if email == "" {
+		if phone == "" {
+			return "", false
+		}
+		req.Type = models.NotificationSMS
+		req.Recipient = phone
+	}
+	if _, err := s.SendNotification(ctx, req); err != nil {
+		return req.Recipient, false
+	}
+	return req.Recipient, true
+}
+
 // deliver handles the actual delivery of notifications
 func (s *NotificationService) deliver(ctx context.Context, notification *models.Notification) error {
 	switch notification.Type {
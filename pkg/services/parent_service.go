@@ -182,6 +182,29 @@ func (s *ParentService) GetParentsByStudent(ctx context.Context, studentID uuid.
 	return parents, nil
 }
 
+// linkedParentsWithPreference returns the active parents of a student who opted
+// into a ParentStudent preference column (e.g. "receives_grades") and accept notifications.
+// An empty preference returns every linked parent who accepts notifications.
+func linkedParentsWithPreference(db *gorm.DB, studentID uuid.UUID, preference string) ([]models.Parent, error) {
+	query := db.Preload("Parent").Where("student_id = ?", studentID)
+	if preference != "" {
+		query = query.Where(preference+" = ?", true)
+	}
+
+	var links []models.ParentStudent
+	if err := query.Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	parents := make([]models.Parent, 0, len(links))
+	for _, link := range links {
+		if link.Parent.IsActive && link.Parent.ReceiveNotifications {
+			parents = append(parents, link.Parent)
+		}
+	}
+	return parents, nil
+}
+
 // toResponse converts model to DTO
 func (s *ParentService) toResponse(p *models.Parent) *dto.ParentResponse {
 	resp := &dto.ParentResponse{
@@ -49,17 +49,22 @@ func (s *PortalService) GetStudentDashboard(ctx context.Context, studentID uuid.
 	}
 
 	// Upcoming classes (next 7 days)
+	dashboard.UpcomingClasses = []dto.LessonOccurrence{}
 	if student.GroupID != uuid.Nil {
-		var timetables []models.Timetable
-		s.db.Where("group_id = ?", student.GroupID).Limit(5).Find(&timetables)
-		dashboard.UpcomingClasses = make([]dto.TimetableSimple, len(timetables))
-		for i, tt := range timetables {
-			dashboard.UpcomingClasses[i] = dto.TimetableSimple{
-				ID:        tt.ID,
-				StartTime: tt.StartTime,
-				EndTime:   tt.EndTime,
-				Days:      tt.Days,
-				Classroom: tt.Classroom,
+		var groups []models.Group
+		s.db.Preload("Timetable.Slots").Where("id = ?", student.GroupID).Find(&groups)
+		now := time.Now()
+		lessons, err := lessonOccurrences(s.db, groups, now, now.AddDate(0, 0, 7))
+		if err != nil {
+			return nil, err
+		}
+		for _, lesson := range lessons {
+			if lesson.Date.Equal(dateOnly(now)) && lesson.EndTime <= now.Format("15:04") {
+				continue
+			}
+			dashboard.UpcomingClasses = append(dashboard.UpcomingClasses, lesson)
+			if len(dashboard.UpcomingClasses) == 5 {
+				break
 			}
 		}
 	}
@@ -72,6 +77,8 @@ func (s *PortalService) GetStudentDashboard(ctx context.Context, studentID uuid.
 		dashboard.RecentGrades[i] = dto.GradeInfo{
 			ID:        g.ID,
 			Score:     float64(g.Value),
+			Label:     g.Label,
+			GPAPoints: g.GPAPoints,
 			Type:      string(g.Type),
 			Notes:     g.Notes,
 			CreatedAt: g.CreatedAt,
@@ -154,20 +161,21 @@ func (s *PortalService) GetTeacherDashboard(ctx context.Context, teacherID uuid.
 		dashboard.TotalGroups = int64(len(groupIDs))
 	}
 
-	// Today's classes
-	today := time.Now().Weekday().String()
-	var timetables []models.Timetable
-	if len(groupIDs) > 0 {
-		s.db.Where("group_id IN ? AND day_of_week = ?", groupIDs, today).Find(&timetables)
-		dashboard.TodayClasses = make([]dto.TimetableSimple, len(timetables))
-		for i, tt := range timetables {
-			dashboard.TodayClasses[i] = dto.TimetableSimple{
-				ID:        tt.ID,
-				StartTime: tt.StartTime,
-				EndTime:   tt.EndTime,
-				Days:      tt.Days,
-				Classroom: tt.Classroom,
-			}
+	// Today's classes, including lessons of other groups the teacher covers
+	today := time.Now()
+	dashboard.TodayClasses = []dto.LessonOccurrence{}
+	var groups []models.Group
+	s.db.Preload("Timetable.Slots").
+		Where("teacher_id = ? OR id IN (?)", teacherID,
+			s.db.Model(&models.ClassSession{}).Select("group_id").Where("teacher_id = ? AND date = ?", teacherID, dateOnly(today))).
+		Find(&groups)
+	lessons, err := lessonOccurrences(s.db, groups, today, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, lesson := range lessons {
+		if lesson.TeacherID == teacherID {
+			dashboard.TodayClasses = append(dashboard.TodayClasses, lesson)
 		}
 	}
 
@@ -3,10 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
 )
@@ -23,6 +25,10 @@ func NewRecurringInvoiceService(db *gorm.DB) *RecurringInvoiceService {
 
 // CreateRecurringInvoice creates a new recurring invoice schedule
 func (s *RecurringInvoiceService) CreateRecurringInvoice(ctx context.Context, req dto.CreateRecurringInvoiceRequest) (*dto.RecurringInvoiceResponse, error) {
+	if req.ProRateHolidays && (req.Frequency != models.FrequencyMonthly || req.GroupID == nil) {
+		return nil, errors.Validation("pro_rate_holidays applies to monthly invoices of a group")
+	}
+
 	// Calculate next invoice date
 	nextDate := s.calculateNextDate(req.StartDate, req.Frequency, req.DayOfMonth)
 
@@ -45,6 +51,7 @@ func (s *RecurringInvoiceService) CreateRecurringInvoice(ctx context.Context, re
 		AutoSend:        req.AutoSend,
 		DueDays:         req.DueDays,
 		ReminderDays:    req.ReminderDays,
+		ProRateHolidays: req.ProRateHolidays,
 	}
 
 	if req.Currency == "" {
@@ -92,6 +99,12 @@ func (s *RecurringInvoiceService) UpdateRecurringInvoice(ctx context.Context, id
 	if req.ReminderDays != nil {
 		recurring.ReminderDays = *req.ReminderDays
 	}
+	if req.ProRateHolidays != nil {
+		if *req.ProRateHolidays && (recurring.Frequency != models.FrequencyMonthly || recurring.GroupID == nil) {
+			return nil, errors.Validation("pro_rate_holidays applies to monthly invoices of a group")
+		}
+		recurring.ProRateHolidays = *req.ProRateHolidays
+	}
 
 	if err := s.db.Save(&recurring).Error; err != nil {
 		return nil, fmt.Errorf("failed to update recurring invoice: %w", err)
@@ -134,20 +147,34 @@ func (s *RecurringInvoiceService) GenerateInvoices(ctx context.Context, req dto.
 			continue
 		}
 
+		subTotal, description := rec.BaseAmount, rec.Description
+		if rec.ProRateHolidays {
+			var err error
+			if subTotal, description, err = s.proRate(&rec); err != nil {
+				resp.TotalFailed++
+				resp.Failed = append(resp.Failed, dto.BulkFailedItem{Index: i, Error: err.Error(), Data: rec.ID})
+				continue
+			}
+		}
+		total := math.Max(subTotal-rec.DiscountAmount, 0)
+
 		// Create invoice
 		invoice := models.Invoice{
 			ID:                 uuid.New(),
 			StudentID:          rec.StudentID,
+			GroupID:            rec.GroupID,
+			CourseID:           rec.CourseID,
 			RecurringInvoiceID: &rec.ID,
-			SubTotal:           rec.BaseAmount,
+			TermID:             models.TermAt(s.db, rec.NextInvoiceDate), // The term billed for
+			SubTotal:           subTotal,
 			DiscountAmount:     rec.DiscountAmount,
-			TotalAmount:        rec.BaseAmount - rec.DiscountAmount,
-			BalanceAmount:      rec.BaseAmount - rec.DiscountAmount,
+			TotalAmount:        total,
+			BalanceAmount:      total,
 			Currency:           rec.Currency,
 			Status:             "pending", // Assuming pending status
 			IssueDate:          time.Now(),
 			DueDate:            time.Now().AddDate(0, 0, rec.DueDays),
-			Description:        rec.Description,
+			Description:        description,
 			InvoiceNumber:      fmt.Sprintf("INV-%s-%d", time.Now().Format("20060102"), i), // Simple generation
 		}
 
@@ -178,10 +205,58 @@ func (s *RecurringInvoiceService) GenerateInvoices(ctx context.Context, req dto.
 	return resp, nil
 }
 
-// calculateNextDate calculates the next invoice date based on frequency
+// proRate returns the amount and description of a monthly invoice reduced by
+// the share of the group's timetabled lessons in the billed month that fall
+// on holidays and term breaks
+func (s *RecurringInvoiceService) proRate(rec *models.RecurringInvoice) (float64, string, error) {
+	if rec.GroupID == nil {
+		return rec.BaseAmount, rec.Description, nil
+	}
+	group, err := loadGroupWithTimetable(s.db, *rec.GroupID)
+	if err != nil {
+		return 0, "", err
+	}
+	from := dateOnly(rec.NextInvoiceDate)
+	to := dateOnly(s.calculateNextDate(rec.NextInvoiceDate, rec.Frequency, rec.DayOfMonth)).AddDate(0, 0, -1)
+	off, err := daysOff(s.db, from, to)
+	if err != nil {
+		return 0, "", err
+	}
+
+	planned, lost := 0, 0
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		lessons := len(group.Timetable.SlotsOn(date.Weekday()))
+		planned += lessons
+		if _, ok := off[date]; ok {
+			lost += lessons
+		}
+	}
+	if planned == 0 || lost == 0 {
+		return rec.BaseAmount, rec.Description, nil
+	}
+
+	amount := math.Round(rec.BaseAmount*float64(planned-lost)/float64(planned)*100) / 100
+	note := fmt.Sprintf("%d of %d lessons; %d fall on holidays or breaks", planned-lost, planned, lost)
+	if rec.Description == "" {
+		return amount, note, nil
+	}
+	return amount, rec.Description + " (" + note + ")", nil
+}
+
+// calculateNextDate calculates the next invoice date based on frequency.
+// Semesters follow academic terms: the next date is the start of the next
+// term, or six months later when no term is defined.
 func (s *RecurringInvoiceService) calculateNextDate(currentDate time.Time, frequency models.RecurringFrequency, dayOfMonth int) time.Time {
 	var nextDate time.Time
 
+	if frequency == models.FrequencySemester {
+		var term models.AcademicTerm
+		if err := s.db.Where("start_date > ?", dateOnly(currentDate)).Order("start_date").
+			Limit(1).Find(&term).Error; err == nil && term.ID != uuid.Nil {
+			return term.StartDate
+		}
+	}
+
 	switch frequency {
 	case models.FrequencyWeekly:
 		nextDate = currentDate.AddDate(0, 0, 7)
@@ -253,6 +328,7 @@ func (s *RecurringInvoiceService) toResponse(r *models.RecurringInvoice) *dto.Re
 		AutoSend:        r.AutoSend,
 		DueDays:         r.DueDays,
 		ReminderDays:    r.ReminderDays,
+		ProRateHolidays: r.ProRateHolidays,
 		CreatedAt:       r.CreatedAt,
 		UpdatedAt:       r.UpdatedAt,
 	}
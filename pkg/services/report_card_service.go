@@ -0,0 +1,510 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Default weights of the assignment and exam averages in a final grade
+const (
+	defaultAssignmentWeight = 40.0
+	defaultExamWeight       = 60.0
+)
+
+// ReportCardService generates term report cards and cumulative transcripts
+type ReportCardService struct {
+	db            *gorm.DB
+	documents     *DocumentService
+	notifications *NotificationService
+}
+
+// NewReportCardService creates a new report card service
+func NewReportCardService(db *gorm.DB) *ReportCardService {
+	return &ReportCardService{
+		db:            db,
+		documents:     NewDocumentService(db),
+		notifications: NewNotificationService(db),
+	}
+}
+
+// GenerateForGroup computes, renders and stores a report card for every
+// student in a group, optionally emailing linked parents
+func (s *ReportCardService) GenerateForGroup(ctx context.Context, groupID uuid.UUID, req dto.GenerateReportCardsRequest, generatorID *uuid.UUID) ([]dto.ReportCardResponse, error) {
+	var group models.Group
+	if err := s.db.Preload("Course").Preload("Teacher").Preload("Students").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+
+	assignmentWeight, examWeight := defaultAssignmentWeight, defaultExamWeight
+	if req.AssignmentWeight != nil {
+		assignmentWeight = *req.AssignmentWeight
+	}
+	if req.ExamWeight != nil {
+		examWeight = *req.ExamWeight
+	}
+	if assignmentWeight+examWeight <= 0 {
+		return nil, errors.Validation("assignment and exam weights cannot both be zero")
+	}
+
+	from, to, err := s.reportPeriod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, err := resolveGradingScale(s.db, group.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	teacherName := ""
+	if group.Teacher != nil {
+		teacherName = group.Teacher.Name + " " + group.Teacher.Surname
+	}
+
+	responses := make([]dto.ReportCardResponse, 0, len(group.Students))
+	for i := range group.Students {
+		student := &group.Students[i]
+
+		card, err := s.buildReportCard(student.ID, &group, req.Term, from, to, assignmentWeight, examWeight, scale)
+		if err != nil {
+			return nil, err
+		}
+		if comment, ok := req.Comments[student.ID]; ok {
+			card.TeacherComment = comment
+		}
+		card.GeneratedBy = generatorID
+		card.GeneratedAt = time.Now()
+
+		resp := s.toResponse(card, student, &group)
+		content, err := renderReportCardPDF(resp, teacherName)
+		if err != nil {
+			return nil, errors.Internal("failed to render report card", err)
+		}
+
+		document := models.Document{
+			Name:      fmt.Sprintf("Report card - %s - %s", resp.StudentName, req.Term),
+			Type:      models.DocumentTypeTranscript,
+			FileName:  fmt.Sprintf("report-card-%s.pdf", student.ID),
+			MimeType:  "application/pdf",
+			StudentID: &student.ID,
+			CourseID:  &group.CourseID,
+			GroupID:   &group.ID,
+			Metadata:  map[string]interface{}{"kind": "report_card", "term": req.Term},
+		}
+		document.UploadedBy = generatorID
+		if err := s.documents.SaveGenerated(ctx, &document, content); err != nil {
+			return nil, errors.Internal("failed to store report card", err)
+		}
+		card.DocumentID = &document.ID
+		resp.DocumentID = &document.ID
+
+		if err := s.db.Save(card).Error; err != nil {
+			return nil, errors.DatabaseError("saving report card", err)
+		}
+
+		if req.EmailParents {
+			subject := fmt.Sprintf("Report card for %s (%s)", resp.StudentName, req.Term)
+			message := fmt.Sprintf("The %s report card for %s in %s is ready.\nFinal grade: %s (%.1f%%)\nDownload: %s",
+				req.Term, resp.StudentName, resp.CourseName, resp.FinalGrade, resp.FinalPercentage, s.documents.DownloadURL(document.ID))
+			resp.ParentsNotified = s.emailParents(ctx, student.ID, subject, message)
+		}
+
+		responses = append(responses, *resp)
+	}
+
+	return responses, nil
+}
+
+// GetByGroup returns a group's report cards, optionally filtered by term
+func (s *ReportCardService) GetByGroup(ctx context.Context, groupID uuid.UUID, term string) ([]dto.ReportCardResponse, error) {
+	query := s.db.Preload("Student").Preload("Group").Preload("Course").Where("group_id = ?", groupID)
+	if term != "" {
+		query = query.Where("term = ?", term)
+	}
+
+	var cards []models.ReportCard
+	if err := query.Order("generated_at DESC").Find(&cards).Error; err != nil {
+		return nil, errors.DatabaseError("fetching report cards", err)
+	}
+	return s.toResponses(cards), nil
+}
+
+// GetByStudent returns every report card issued to a student
+func (s *ReportCardService) GetByStudent(ctx context.Context, studentID uuid.UUID) ([]dto.ReportCardResponse, error) {
+	var cards []models.ReportCard
+	if err := s.db.Preload("Student").Preload("Group").Preload("Course").
+		Where("student_id = ?", studentID).
+		Order("generated_at DESC").
+		Find(&cards).Error; err != nil {
+		return nil, errors.DatabaseError("fetching report cards", err)
+	}
+	return s.toResponses(cards), nil
+}
+
+// GetTranscript builds a student's cumulative transcript from their report cards
+func (s *ReportCardService) GetTranscript(ctx context.Context, studentID uuid.UUID) (*dto.TranscriptResponse, error) {
+	var student models.Student
+	if err := s.db.First(&student, "id = ?", studentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Student", studentID.String())
+		}
+		return nil, errors.DatabaseError("finding student", err)
+	}
+	return s.buildTranscript(&student)
+}
+
+// GenerateTranscriptsForGroup renders and stores a transcript for every student
+// in a group who has at least one report card
+func (s *ReportCardService) GenerateTranscriptsForGroup(ctx context.Context, groupID uuid.UUID, req dto.GenerateTranscriptsRequest, generatorID *uuid.UUID) ([]dto.TranscriptResponse, error) {
+	var group models.Group
+	if err := s.db.Preload("Students").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+
+	responses := make([]dto.TranscriptResponse, 0, len(group.Students))
+	for i := range group.Students {
+		student := &group.Students[i]
+
+		transcript, err := s.buildTranscript(student)
+		if err != nil {
+			return nil, err
+		}
+		if transcript.CoursesCompleted == 0 {
+			continue
+		}
+
+		content, err := renderTranscriptPDF(transcript)
+		if err != nil {
+			return nil, errors.Internal("failed to render transcript", err)
+		}
+
+		document := models.Document{
+			Name:      "Academic transcript - " + transcript.StudentName,
+			Type:      models.DocumentTypeTranscript,
+			FileName:  fmt.Sprintf("transcript-%s.pdf", student.ID),
+			MimeType:  "application/pdf",
+			StudentID: &student.ID,
+			Metadata:  map[string]interface{}{"kind": "transcript"},
+		}
+		document.UploadedBy = generatorID
+		if err := s.documents.SaveGenerated(ctx, &document, content); err != nil {
+			return nil, errors.Internal("failed to store transcript", err)
+		}
+		transcript.DocumentID = &document.ID
+
+		if req.EmailParents {
+			subject := "Academic transcript for " + transcript.StudentName
+			message := fmt.Sprintf("The academic transcript for %s is ready.\nCumulative GPA: %.2f\nDownload: %s",
+				transcript.StudentName, transcript.CumulativeGPA, s.documents.DownloadURL(document.ID))
+			transcript.ParentsNotified = s.emailParents(ctx, student.ID, subject, message)
+		}
+
+		responses = append(responses, *transcript)
+	}
+
+	return responses, nil
+}
+
+// reportPeriod resolves the dates a report card covers: the explicit from
+// and to, or else the academic term named by the request. The end is
+// returned exclusive.
+func (s *ReportCardService) reportPeriod(req dto.GenerateReportCardsRequest) (time.Time, time.Time, error) {
+	if req.From != "" || req.To != "" {
+		if req.From == "" || req.To == "" {
+			return time.Time{}, time.Time{}, errors.Validation("from and to must be given together")
+		}
+		from, to, err := parseDateRange(req.From, req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return from, to.AddDate(0, 0, 1), nil
+	}
+
+	var term models.AcademicTerm
+	if err := s.db.Where("name = ?", req.Term).First(&term).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return time.Time{}, time.Time{}, errors.Validation(fmt.Sprintf("No academic term is named %q; give from and to instead", req.Term))
+		}
+		return time.Time{}, time.Time{}, errors.DatabaseError("finding academic term", err)
+	}
+	return models.DateOf(term.StartDate), models.DateOf(term.EndDate).AddDate(0, 0, 1), nil
+}
+
+// buildReportCard computes a student's results in a group between from and
+// to, reusing the existing report card for the same term so regeneration
+// replaces it
+func (s *ReportCardService) buildReportCard(studentID uuid.UUID, group *models.Group, term string, from, to time.Time, assignmentWeight, examWeight float64, scale *models.GradingScaleVersion) (*models.ReportCard, error) {
+	var card models.ReportCard
+	err := s.db.Where("student_id = ? AND group_id = ? AND term = ?", studentID, group.ID, term).First(&card).Error
+	if err == gorm.ErrRecordNotFound {
+		card = models.ReportCard{ID: uuid.New(), StudentID: studentID, GroupID: group.ID, Term: term}
+	} else if err != nil {
+		return nil, errors.DatabaseError("finding report card", err)
+	}
+	card.CourseID = group.CourseID
+
+	// Attendance: late counts as attended, excused absences are not counted
+	var total, attended int64
+	attendance := s.db.Model(&models.Attendance{}).
+		Where("student_id = ? AND group_id = ? AND date >= ? AND date < ?", studentID, group.ID, from, to)
+	if err := attendance.Session(&gorm.Session{}).Where("status != ?", models.StatusExcused).Count(&total).Error; err != nil {
+		return nil, errors.DatabaseError("counting attendance", err)
+	}
+	if err := attendance.Session(&gorm.Session{}).
+		Where("status IN ?", []models.AttendanceStatus{models.StatusPresent, models.StatusLate}).
+		Count(&attended).Error; err != nil {
+		return nil, errors.DatabaseError("counting attendance", err)
+	}
+	if total > 0 {
+		card.AttendanceRate = float64(attended) / float64(total) * 100
+	}
+
+	assignmentAvg, hasAssignments, err := s.assignmentAverage(studentID, group.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	examAvg, hasExams, err := s.examAverage(studentID, group.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	card.AssignmentAverage = assignmentAvg
+	card.ExamAverage = examAvg
+	card.FinalPercentage = finalPercentage(assignmentAvg, hasAssignments, assignmentWeight, examAvg, hasExams, examWeight)
+
+	band := scale.Band(card.FinalPercentage)
+	card.FinalGrade = band.Label
+	card.GPAPoints = band.GPAPoints
+	card.Passed = scale.Passed(card.FinalPercentage)
+	card.GradingScaleVersionID = scale.VersionID()
+
+	return &card, nil
+}
+
+// assignmentAverage averages the student's graded attempt on each published
+// assignment due between from and to: the final attempt chosen by the teacher, or the best one. Assignments past their due date without a graded
+// submission count as zero.
+func (s *ReportCardService) assignmentAverage(studentID, groupID uuid.UUID, from, to time.Time) (float64, bool, error) {
+	var assignments []models.Assignment
+	if err := s.db.Where("group_id = ? AND status != ? AND due_date >= ? AND due_date < ?", groupID, models.AssignmentDraft, from, to).
+		Find(&assignments).Error; err != nil {
+		return 0, false, errors.DatabaseError("fetching assignments", err)
+	}
+
+	now := time.Now()
+	var sum float64
+	count := 0
+	for _, a := range assignments {
+		var submissions []models.AssignmentSubmission
+		if err := s.db.Where("assignment_id = ? AND student_id = ? AND points IS NOT NULL", a.ID, studentID).Find(&submissions).Error; err != nil {
+			return 0, false, errors.DatabaseError("fetching submissions", err)
+		}
+
+		if len(submissions) == 0 {
+			if a.DueDate.Before(now) {
+				count++
+			}
+			continue
+		}
+		best := 0.0
+		for _, sub := range submissions {
+			if sub.IsFinal {
+				best = *sub.Points
+				break
+			}
+			if *sub.Points > best {
+				best = *sub.Points
+			}
+		}
+		if a.MaxPoints > 0 {
+			sum += best / a.MaxPoints * 100
+		}
+		count++
+	}
+
+	if count == 0 {
+		return 0, false, nil
+	}
+	return sum / float64(count), true, nil
+}
+
+// examAverage averages the student's results on the group's exams held
+// between from and to
+func (s *ReportCardService) examAverage(studentID, groupID uuid.UUID, from, to time.Time) (float64, bool, error) {
+	var results []models.ExamResult
+	if err := s.db.Joins("JOIN exams ON exams.id = exam_results.exam_id").
+		Where("exam_results.student_id = ? AND exams.group_id = ? AND exams.status != ? AND exams.deleted_at IS NULL AND exams.start_time >= ? AND exams.start_time < ?",
+			studentID, groupID, models.ExamStatusCancelled, from, to).
+		Find(&results).Error; err != nil {
+		return 0, false, errors.DatabaseError("fetching exam results", err)
+	}
+
+	if len(results) == 0 {
+		return 0, false, nil
+	}
+	var sum float64
+	for _, r := range results {
+		sum += r.Percentage
+	}
+	return sum / float64(len(results)), true, nil
+}
+
+// finalPercentage combines the component averages by weight. A component with
+// no data is left out so it does not drag the final grade down.
+func finalPercentage(assignmentAvg float64, hasAssignments bool, assignmentWeight, examAvg float64, hasExams bool, examWeight float64) float64 {
+	var sum, weights float64
+	if hasAssignments {
+		sum += assignmentAvg * assignmentWeight
+		weights += assignmentWeight
+	}
+	if hasExams {
+		sum += examAvg * examWeight
+		weights += examWeight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return sum / weights
+}
+
+// buildTranscript lists the latest report card for each course a student completed
+func (s *ReportCardService) buildTranscript(student *models.Student) (*dto.TranscriptResponse, error) {
+	var cards []models.ReportCard
+	if err := s.db.Preload("Group").Preload("Course").
+		Where("student_id = ?", student.ID).
+		Order("generated_at ASC").
+		Find(&cards).Error; err != nil {
+		return nil, errors.DatabaseError("fetching report cards", err)
+	}
+
+	order := make([]uuid.UUID, 0)
+	latest := make(map[uuid.UUID]models.ReportCard)
+	for _, card := range cards {
+		if _, seen := latest[card.CourseID]; !seen {
+			order = append(order, card.CourseID)
+		}
+		latest[card.CourseID] = card
+	}
+
+	transcript := &dto.TranscriptResponse{
+		StudentID:   student.ID,
+		StudentName: student.Name + " " + student.Surname,
+		Courses:     make([]dto.TranscriptEntry, 0, len(order)),
+		GeneratedAt: time.Now(),
+	}
+
+	var gpaSum, percentSum float64
+	for _, courseID := range order {
+		card := latest[courseID]
+		entry := dto.TranscriptEntry{
+			CourseID:        card.CourseID,
+			Term:            card.Term,
+			FinalPercentage: card.FinalPercentage,
+			FinalGrade:      card.FinalGrade,
+			GPAPoints:       card.GPAPoints,
+			Passed:          card.Passed,
+		}
+		if card.Course != nil {
+			entry.CourseName = card.Course.Title
+		}
+		if card.Group != nil {
+			entry.GroupName = card.Group.Name
+		}
+		transcript.Courses = append(transcript.Courses, entry)
+
+		gpaSum += card.GPAPoints
+		percentSum += card.FinalPercentage
+		if card.Passed {
+			transcript.CoursesPassed++
+		}
+	}
+
+	transcript.CoursesCompleted = len(transcript.Courses)
+	if transcript.CoursesCompleted > 0 {
+		transcript.CumulativeGPA = gpaSum / float64(transcript.CoursesCompleted)
+		transcript.AveragePercent = percentSum / float64(transcript.CoursesCompleted)
+	}
+
+	return transcript, nil
+}
+
+// emailParents emails the student's parents who receive grades and returns
+// how many notifications were delivered
+func (s *ReportCardService) emailParents(ctx context.Context, studentID uuid.UUID, subject, message string) int {
+	parents, err := linkedParentsWithPreference(s.db, studentID, "receives_grades")
+	if err != nil {
+		return 0
+	}
+
+	sent := 0
+	for _, parent := range parents {
+		if parent.Email == "" {
+			continue
+		}
+		_, err := s.notifications.SendNotification(ctx, dto.SendNotificationRequest{
+			Type:      models.NotificationEmail,
+			Recipient: parent.Email,
+			StudentID: &studentID,
+			Subject:   subject,
+			Message:   message,
+		})
+		if err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+func (s *ReportCardService) toResponses(cards []models.ReportCard) []dto.ReportCardResponse {
+	responses := make([]dto.ReportCardResponse, len(cards))
+	for i := range cards {
+		responses[i] = *s.toResponse(&cards[i], cards[i].Student, cards[i].Group)
+	}
+	return responses
+}
+
+func (s *ReportCardService) toResponse(card *models.ReportCard, student *models.Student, group *models.Group) *dto.ReportCardResponse {
+	resp := &dto.ReportCardResponse{
+		ID:                    card.ID,
+		StudentID:             card.StudentID,
+		GroupID:               card.GroupID,
+		CourseID:              card.CourseID,
+		Term:                  card.Term,
+		AttendanceRate:        card.AttendanceRate,
+		AssignmentAverage:     card.AssignmentAverage,
+		ExamAverage:           card.ExamAverage,
+		FinalPercentage:       card.FinalPercentage,
+		FinalGrade:            card.FinalGrade,
+		GPAPoints:             card.GPAPoints,
+		Passed:                card.Passed,
+		GradingScaleVersionID: card.GradingScaleVersionID,
+		TeacherComment:        card.TeacherComment,
+		DocumentID:            card.DocumentID,
+		GeneratedAt:           card.GeneratedAt,
+	}
+	if student != nil {
+		resp.StudentName = student.Name + " " + student.Surname
+	}
+	if group != nil {
+		resp.GroupName = group.Name
+		if group.Course != nil {
+			resp.CourseName = group.Course.Title
+		}
+	}
+	if resp.CourseName == "" && card.Course != nil {
+		resp.CourseName = card.Course.Title
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalPercentage(t *testing.T) {
+	// Both components weighted 40/60
+	assert.InDelta(t, 76.0, finalPercentage(70, true, 40, 80, true, 60), 0.001)
+	// Missing exams fall back to the assignment average
+	assert.InDelta(t, 70.0, finalPercentage(70, true, 40, 0, false, 60), 0.001)
+	// No data at all
+	assert.Equal(t, 0.0, finalPercentage(0, false, 40, 0, false, 60))
+}
+
+func TestRenderReportCardPDF(t *testing.T) {
+	content, err := renderReportCardPDF(&dto.ReportCardResponse{
+		StudentID:       uuid.New(),
+		StudentName:     "Jane Doe",
+		CourseName:      "Go Basics",
+		GroupName:       "GO-1",
+		Term:            "2026 Spring",
+		FinalPercentage: 87.5,
+		FinalGrade:      "A",
+		GPAPoints:       4,
+		Passed:          true,
+		TeacherComment:  "Great progress.",
+		GeneratedAt:     time.Now(),
+	}, "John Smith")
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(content, []byte("%PDF")))
+}
+
+func TestReportCardService_ScopesResultsToTheTerm(t *testing.T) {
+	db := setupTestDB()
+	service := NewReportCardService(db)
+
+	date := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC) }
+	db.Create(&models.AcademicTerm{ID: uuid.New(), Name: "2026 Spring", StartDate: date(time.March, 1), EndDate: date(time.May, 31)})
+
+	course := models.Course{Title: "Go", Duration: 3}
+	db.Create(&course)
+	group := models.Group{Name: "GO-1", CourseID: course.ID, Capacity: 10}
+	db.Create(&group)
+	student := models.Student{ID: uuid.New(), Name: "Zarina", Surname: "Karimova", Phone: "992900000401", GroupID: group.ID}
+	db.Create(&student)
+
+	// Two lessons and an exam in the term; a missed lesson, a missed
+	// assignment and a failed exam in the winter before it
+	for _, a := range []models.Attendance{
+		{ID: uuid.New(), StudentID: student.ID, GroupID: group.ID, Date: date(time.March, 2), Status: models.StatusPresent},
+		{ID: uuid.New(), StudentID: student.ID, GroupID: group.ID, Date: date(time.May, 31), Status: models.StatusLate},
+		{ID: uuid.New(), StudentID: student.ID, GroupID: group.ID, Date: date(time.February, 2), Status: models.StatusAbsent},
+	} {
+		db.Create(&a)
+	}
+	db.Create(&models.Assignment{ID: uuid.New(), GroupID: group.ID, CourseID: course.ID, Title: "Winter homework",
+		Status: models.AssignmentPublished, DueDate: date(time.February, 10), MaxPoints: 100})
+	for _, exam := range []struct {
+		start   time.Time
+		percent float64
+	}{{date(time.April, 10), 90}, {date(time.January, 20), 10}} {
+		e := models.Exam{ID: uuid.New(), Title: "Exam", Type: models.ExamTypeMidterm, Status: models.ExamStatusCompleted,
+			CourseID: course.ID, GroupID: group.ID, StartTime: exam.start, EndTime: exam.start.Add(time.Hour), Duration: 60, TotalMarks: 100, PassingMarks: 50}
+		db.Omit("Metadata").Create(&e)
+		db.Create(&models.ExamResult{ID: uuid.New(), ExamID: e.ID, StudentID: student.ID, MarksObtained: exam.percent, Percentage: exam.percent})
+	}
+
+	from, to, err := service.reportPeriod(dto.GenerateReportCardsRequest{Term: "2026 Spring"})
+	assert.NoError(t, err)
+	assert.Equal(t, date(time.March, 1), from)
+	assert.Equal(t, date(time.June, 1), to)
+
+	scale, err := resolveGradingScale(db, course.ID)
+	assert.NoError(t, err)
+	card, err := service.buildReportCard(student.ID, &group, "2026 Spring", from, to, 40, 60, scale)
+	assert.NoError(t, err)
+	assert.InDelta(t, 100.0, card.AttendanceRate, 0.001)
+	assert.Equal(t, 0.0, card.AssignmentAverage)
+	assert.InDelta(t, 90.0, card.ExamAverage, 0.001)
+	assert.InDelta(t, 90.0, card.FinalPercentage, 0.001, "the winter assignment does not count")
+
+	// An explicit range overrides the term's dates
+	from, to, err = service.reportPeriod(dto.GenerateReportCardsRequest{Term: "Winter", From: "2026-01-01", To: "2026-02-28"})
+	assert.NoError(t, err)
+	card, err = service.buildReportCard(student.ID, &group, "Winter", from, to, 40, 60, scale)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, card.AttendanceRate)
+	assert.InDelta(t, 10.0*0.6, card.FinalPercentage, 0.001)
+
+	// A label that names no term needs a range
+	_, _, err = service.reportPeriod(dto.GenerateReportCardsRequest{Term: "Winter"})
+	assert.True(t, errors.IsValidation(err))
+	_, _, err = service.reportPeriod(dto.GenerateReportCardsRequest{Term: "Winter", From: "2026-01-01"})
+	assert.True(t, errors.IsValidation(err))
+}
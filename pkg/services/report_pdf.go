@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+)
+
+// pdfDocument wraps fpdf with the layout helpers shared by generated documents
+type pdfDocument struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func newPDFDocument(title, orientation string) *pdfDocument {
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	// Core fonts are cp1252; translate UTF-8 input so Latin accents survive
+	return &pdfDocument{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+}
+
+func (d *pdfDocument) heading(text string) {
+	d.pdf.SetFont("Helvetica", "B", 18)
+	d.pdf.CellFormat(0, 10, d.tr(text), "", 1, "C", false, 0, "")
+	d.pdf.Ln(4)
+}
+
+func (d *pdfDocument) field(label, value string) {
+	d.pdf.SetFont("Helvetica", "B", 11)
+	d.pdf.CellFormat(50, 7, d.tr(label), "", 0, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 11)
+	d.pdf.CellFormat(0, 7, d.tr(value), "", 1, "L", false, 0, "")
+}
+
+func (d *pdfDocument) paragraph(text string) {
+	d.pdf.SetFont("Helvetica", "", 11)
+	d.pdf.MultiCell(0, 6, d.tr(text), "", "L", false)
+}
+
+// table draws a header row followed by data rows; widths are in millimetres
+func (d *pdfDocument) table(widths []float64, header []string, rows [][]string) {
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.SetFillColor(230, 230, 230)
+	for i, h := range header {
+		d.pdf.CellFormat(widths[i], 8, d.tr(h), "1", 0, "C", true, 0, "")
+	}
+	d.pdf.Ln(-1)
+
+	d.pdf.SetFont("Helvetica", "", 10)
+	for _, row := range rows {
+		for i, cell := range row {
+			align := "C"
+			if i == 0 {
+				align = "L"
+			}
+			d.pdf.CellFormat(widths[i], 7, d.tr(cell), "1", 0, align, false, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+}
+
+func (d *pdfDocument) footer(text string) {
+	d.pdf.Ln(8)
+	d.pdf.SetFont("Helvetica", "I", 9)
+	d.pdf.CellFormat(0, 6, d.tr(text), "", 1, "L", false, 0, "")
+}
+
+func (d *pdfDocument) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderReportCardPDF renders a single report card
+func renderReportCardPDF(card *dto.ReportCardResponse, teacherName string) ([]byte, error) {
+	doc := newPDFDocument("Report Card", "P")
+	doc.heading("Report Card")
+
+	doc.field("Student:", card.StudentName)
+	doc.field("Course:", card.CourseName)
+	doc.field("Group:", card.GroupName)
+	doc.field("Term:", card.Term)
+	if teacherName != "" {
+		doc.field("Teacher:", teacherName)
+	}
+	doc.pdf.Ln(4)
+
+	result := "Not passed"
+	if card.Passed {
+		result = "Passed"
+	}
+	doc.table([]float64{90, 80}, []string{"Component", "Result"}, [][]string{
+		{"Attendance rate", fmt.Sprintf("%.1f%%", card.AttendanceRate)},
+		{"Assignment average", fmt.Sprintf("%.1f%%", card.AssignmentAverage)},
+		{"Exam average", fmt.Sprintf("%.1f%%", card.ExamAverage)},
+		{"Final score", fmt.Sprintf("%.1f%%", card.FinalPercentage)},
+		{"Final grade", fmt.Sprintf("%s (%.2f GPA)", card.FinalGrade, card.GPAPoints)},
+		{"Result", result},
+	})
+
+	if card.TeacherComment != "" {
+		doc.pdf.Ln(6)
+		doc.pdf.SetFont("Helvetica", "B", 11)
+		doc.pdf.CellFormat(0, 7, "Teacher comments", "", 1, "L", false, 0, "")
+		doc.paragraph(card.TeacherComment)
+	}
+
+	doc.footer("Generated on " + card.GeneratedAt.Format("2006-01-02"))
+	return doc.bytes()
+}
+
+// renderTranscriptPDF renders a cumulative transcript
+func renderTranscriptPDF(transcript *dto.TranscriptResponse) ([]byte, error) {
+	doc := newPDFDocument("Academic Transcript", "P")
+	doc.heading("Academic Transcript")
+
+	doc.field("Student:", transcript.StudentName)
+	doc.field("Courses completed:", fmt.Sprintf("%d", transcript.CoursesCompleted))
+	doc.field("Courses passed:", fmt.Sprintf("%d", transcript.CoursesPassed))
+	doc.field("Cumulative GPA:", fmt.Sprintf("%.2f", transcript.CumulativeGPA))
+	doc.pdf.Ln(4)
+
+	rows := make([][]string, 0, len(transcript.Courses))
+	for _, c := range transcript.Courses {
+		rows = append(rows, []string{
+			c.CourseName,
+			c.Term,
+			fmt.Sprintf("%.1f%%", c.FinalPercentage),
+			c.FinalGrade,
+			fmt.Sprintf("%.2f", c.GPAPoints),
+		})
+	}
+	doc.table([]float64{60, 35, 25, 25, 25}, []string{"Course", "Term", "Score", "Grade", "GPA"}, rows)
+
+	doc.footer("Generated on " + transcript.GeneratedAt.Format("2006-01-02"))
+	return doc.bytes()
+}
+
+// renderCertificatePDF renders a completion certificate from a template, with
+// a QR code linking to the public verification page
+func renderCertificatePDF(template *models.CertificateTemplate, cert *models.Certificate, studentName, courseName, verifyURL string) ([]byte, error) {
+	doc := newPDFDocument(template.Title, template.Orientation)
+	pageW, pageH := doc.pdf.GetPageSize()
+
+	doc.pdf.SetLineWidth(1)
+	doc.pdf.Rect(10, 10, pageW-20, pageH-20, "D")
+
+	doc.pdf.SetY(35)
+	doc.pdf.SetFont("Helvetica", "B", 28)
+	doc.pdf.CellFormat(0, 14, doc.tr(template.Title), "", 1, "C", false, 0, "")
+	doc.pdf.Ln(6)
+
+	doc.pdf.SetFont("Helvetica", "B", 22)
+	doc.pdf.CellFormat(0, 12, doc.tr(studentName), "", 1, "C", false, 0, "")
+	doc.pdf.Ln(6)
+
+	doc.pdf.SetFont("Helvetica", "", 13)
+	doc.pdf.MultiCell(0, 7, doc.tr(template.RenderBody(cert, studentName, courseName)), "", "C", false)
+
+	if template.SignatoryName != "" {
+		doc.pdf.SetY(pageH - 55)
+		doc.pdf.SetFont("Helvetica", "B", 12)
+		doc.pdf.CellFormat(0, 6, doc.tr(template.SignatoryName), "", 1, "C", false, 0, "")
+		doc.pdf.SetFont("Helvetica", "", 10)
+		doc.pdf.CellFormat(0, 5, doc.tr(template.SignatoryTitle), "", 1, "C", false, 0, "")
+	}
+
+	qr, err := qrcode.Encode(verifyURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	doc.pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	doc.pdf.ImageOptions("qr", pageW-50, pageH-50, 30, 30, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	doc.pdf.SetXY(20, pageH-32)
+	doc.pdf.SetFont("Helvetica", "", 9)
+	doc.pdf.CellFormat(0, 5, doc.tr("Certificate No. "+cert.Number), "", 1, "L", false, 0, "")
+	doc.pdf.SetX(20)
+	doc.pdf.CellFormat(0, 5, doc.tr("Verify at "+verifyURL), "", 1, "L", false, 0, "")
+
+	return doc.bytes()
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// defaultRoomHours is the weekly bookable time of a room created without one
+const defaultRoomHours = 60
+
+// RoomService handles room operations
+type RoomService struct {
+	db *gorm.DB
+}
+
+// NewRoomService creates a new room service
+func NewRoomService(db *gorm.DB) *RoomService {
+	return &RoomService{db: db}
+}
+
+// Create creates a room with a unique name
+func (s *RoomService) Create(ctx context.Context, req dto.CreateRoomRequest) (*dto.RoomResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkName(name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	room := models.Room{
+		ID:             uuid.New(),
+		Name:           name,
+		Building:       strings.TrimSpace(req.Building),
+		Capacity:       req.Capacity,
+		Equipment:      normalizeEquipment(req.Equipment),
+		IsActive:       true,
+		AvailableHours: req.AvailableHours,
+	}
+	if room.AvailableHours == 0 {
+		room.AvailableHours = defaultRoomHours
+	}
+
+	if err := s.db.Create(&room).Error; err != nil {
+		return nil, errors.DatabaseError("creating room", err)
+	}
+	return s.toResponse(&room), nil
+}
+
+// Update updates a room. A new name is copied to the timetables, exams and
+// events that book it; a smaller capacity must still seat their groups.
+func (s *RoomService) Update(ctx context.Context, id uuid.UUID, req dto.UpdateRoomRequest) (*dto.RoomResponse, error) {
+	room, err := loadRoom(s.db, &id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != room.Name {
+			if err := s.checkName(name, room.ID); err != nil {
+				return nil, err
+			}
+			room.Name = name
+			renamed = true
+		}
+	}
+	if req.Building != nil {
+		room.Building = strings.TrimSpace(*req.Building)
+	}
+	if req.Equipment != nil {
+		room.Equipment = normalizeEquipment(req.Equipment)
+	}
+	if req.AvailableHours != nil {
+		room.AvailableHours = *req.AvailableHours
+	}
+	if req.IsActive != nil {
+		room.IsActive = *req.IsActive
+	}
+	if req.Capacity != nil && *req.Capacity < room.Capacity {
+		room.Capacity = *req.Capacity
+		var timetables []models.Timetable
+		if err := s.db.Where("room_id = ? OR id IN (?)", room.ID,
+			s.db.Model(&models.TimetableSlot{}).Select("timetable_id").Where("room_id = ?", room.ID)).
+			Find(&timetables).Error; err != nil {
+			return nil, errors.DatabaseError("finding room timetables", err)
+		}
+		for _, timetable := range timetables {
+			headcount, err := timetableHeadcount(s.db, timetable.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err := ensureRoomFits(room, headcount); err != nil {
+				return nil, err
+			}
+		}
+	} else if req.Capacity != nil {
+		room.Capacity = *req.Capacity
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(room).Error; err != nil {
+			return errors.DatabaseError("updating room", err)
+		}
+		if !renamed {
+			return nil
+		}
+		if err := tx.Model(&models.Timetable{}).Where("room_id = ?", room.ID).Update("classroom", room.Name).Error; err != nil {
+			return errors.DatabaseError("renaming timetable classroom", err)
+		}
+		if err := tx.Model(&models.TimetableSlot{}).Where("room_id = ?", room.ID).Update("classroom", room.Name).Error; err != nil {
+			return errors.DatabaseError("renaming timetable slot classroom", err)
+		}
+		if err := tx.Model(&models.Exam{}).Where("room_id = ?", room.ID).Update("location", room.Name).Error; err != nil {
+			return errors.DatabaseError("renaming exam location", err)
+		}
+		if err := tx.Model(&models.Event{}).Where("room_id = ?", room.ID).Update("location", room.Name).Error; err != nil {
+			return errors.DatabaseError("renaming event location", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(room), nil
+}
+
+// GetByID returns a room
+func (s *RoomService) GetByID(ctx context.Context, id uuid.UUID) (*dto.RoomResponse, error) {
+	room, err := loadRoom(s.db, &id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(room), nil
+}
+
+// GetAll lists rooms matching the filter, ordered by building and name
+func (s *RoomService) GetAll(ctx context.Context, filter dto.RoomFilter) ([]dto.RoomResponse, error) {
+	query := s.db.Model(&models.Room{})
+	if filter.Building != "" {
+		query = query.Where("LOWER(building) = ?", strings.ToLower(filter.Building))
+	}
+	if filter.MinCapacity > 0 {
+		query = query.Where("capacity >= ?", filter.MinCapacity)
+	}
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+
+	var rooms []models.Room
+	if err := query.Order("building ASC, name ASC").Find(&rooms).Error; err != nil {
+		return nil, errors.DatabaseError("listing rooms", err)
+	}
+
+	// Equipment is stored as a JSON list, so it is matched here
+	required := normalizeEquipment(strings.Split(filter.Equipment, ","))
+	responses := make([]dto.RoomResponse, 0, len(rooms))
+	for i := range rooms {
+		if rooms[i].HasEquipment(required...) {
+			responses = append(responses, *s.toResponse(&rooms[i]))
+		}
+	}
+	return responses, nil
+}
+
+// Delete deletes a room no timetable or upcoming exam or event books;
+// rooms still referenced can be deactivated instead
+func (s *RoomService) Delete(ctx context.Context, id uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.Timetable{}).Where("room_id = ?", id).Count(&count).Error; err != nil {
+		return errors.DatabaseError("checking room usage", err)
+	}
+	if count == 0 {
+		if err := s.db.Model(&models.TimetableSlot{}).Where("room_id = ?", id).Count(&count).Error; err != nil {
+			return errors.DatabaseError("checking room usage", err)
+		}
+	}
+	if count > 0 {
+		return errors.New(errors.ErrCodeResourceInUse, "Cannot delete room used by timetables")
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&models.Exam{}).
+		Where("room_id = ? AND end_time > ? AND status != ?", id, now, models.ExamStatusCancelled).
+		Count(&count).Error; err != nil {
+		return errors.DatabaseError("checking room usage", err)
+	}
+	if count == 0 {
+		events, err := eventsBetween(s.db, func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "start_time", "end_time", "is_recurring", "recurrence_rule").Where("room_id = ?", id)
+		}, now, time.Time{})
+		if err != nil {
+			return err
+		}
+		count = int64(len(events))
+	}
+	if count > 0 {
+		return errors.New(errors.ErrCodeResourceInUse, "Cannot delete room booked by upcoming exams or events")
+	}
+
+	result := s.db.Delete(&models.Room{}, "id = ?", id)
+	if result.Error != nil {
+		return errors.DatabaseError("deleting room", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFoundWithID("Room", id.String())
+	}
+	return nil
+}
+
+// Utilization reports booked hours against available hours for every active
+// room in the week (Monday to Sunday) containing the given date. Lessons come
+// from the slots of timetables used by at least one group.
+func (s *RoomService) Utilization(ctx context.Context, week time.Time) (*dto.RoomUtilizationReport, error) {
+	weekStart := dateOnly(week).AddDate(0, 0, -((int(week.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	var rooms []models.Room
+	if err := s.db.Where("is_active = ?", true).Order("building ASC, name ASC").Find(&rooms).Error; err != nil {
+		return nil, errors.DatabaseError("listing rooms", err)
+	}
+	usage := make(map[uuid.UUID]*dto.RoomUtilization, len(rooms))
+	report := &dto.RoomUtilizationReport{
+		WeekStart: weekStart,
+		WeekEnd:   weekEnd.AddDate(0, 0, -1),
+		Rooms:     make([]dto.RoomUtilization, len(rooms)),
+	}
+	for i, room := range rooms {
+		report.Rooms[i] = dto.RoomUtilization{
+			RoomID:         room.ID,
+			Name:           room.Name,
+			Building:       room.Building,
+			Capacity:       room.Capacity,
+			AvailableHours: room.AvailableHours,
+		}
+		usage[room.ID] = &report.Rooms[i]
+	}
+
+	var timetables []models.Timetable
+	if err := s.db.Preload("Slots").Where("id IN (?)", s.db.Model(&models.Group{}).Select("timetable_id")).
+		Find(&timetables).Error; err != nil {
+		return nil, errors.DatabaseError("finding room timetables", err)
+	}
+	for i := range timetables {
+		timetable := &timetables[i]
+		for j := range timetable.Slots {
+			slot := &timetable.Slots[j]
+			roomID := slot.RoomID
+			if roomID == nil && slot.Classroom == "" {
+				roomID = timetable.RoomID
+			}
+			if roomID == nil {
+				continue
+			}
+			if room := usage[*roomID]; room != nil {
+				room.LessonHours += float64(slot.EndMinute-slot.StartMinute) / 60
+			}
+		}
+	}
+
+	var exams []models.Exam
+	if err := s.db.Select("id", "room_id", "start_time", "end_time").
+		Where("room_id IS NOT NULL AND status != ? AND start_time < ? AND end_time > ?", models.ExamStatusCancelled, weekEnd, weekStart).
+		Find(&exams).Error; err != nil {
+		return nil, errors.DatabaseError("finding room exams", err)
+	}
+	for _, exam := range exams {
+		if room := usage[*exam.RoomID]; room != nil {
+			room.ExamHours += overlapHours(exam.StartTime, exam.EndTime, weekStart, weekEnd)
+		}
+	}
+
+	events, err := eventsBetween(s.db, func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "type", "room_id", "start_time", "end_time", "all_day", "is_recurring", "recurrence_rule").
+			Where("room_id IS NOT NULL")
+	}, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		room := usage[*event.RoomID]
+		if room == nil || !eventOccupiesSchedule(event.Type) {
+			continue
+		}
+		if event.AllDay {
+			// An all-day booking takes the room's whole bookable day
+			for _, span := range spansBetween(event.StartTime, event.EndTime) {
+				if !span.Date.Before(weekStart) && span.Date.Before(weekEnd) {
+					room.EventHours += room.AvailableHours / 7
+				}
+			}
+			continue
+		}
+		room.EventHours += overlapHours(event.StartTime, event.EndTime, weekStart, weekEnd)
+	}
+
+	for i := range report.Rooms {
+		room := &report.Rooms[i]
+		room.LessonHours = roundHours(room.LessonHours)
+		room.ExamHours = roundHours(room.ExamHours)
+		room.EventHours = roundHours(room.EventHours)
+		room.BookedHours = roundHours(room.LessonHours + room.ExamHours + room.EventHours)
+		room.UtilizationPercent = utilizationPercent(room.BookedHours, room.AvailableHours)
+		report.AvailableHours += room.AvailableHours
+		report.BookedHours += room.BookedHours
+	}
+	report.BookedHours = roundHours(report.BookedHours)
+	report.UtilizationPercent = utilizationPercent(report.BookedHours, report.AvailableHours)
+
+	sort.SliceStable(report.Rooms, func(i, j int) bool {
+		return report.Rooms[i].UtilizationPercent > report.Rooms[j].UtilizationPercent
+	})
+	return report, nil
+}
+
+// checkName rejects a blank name or one another room already uses, ignoring case
+func (s *RoomService) checkName(name string, id uuid.UUID) error {
+	if name == "" {
+		return errors.Validation("Room name is required")
+	}
+	var count int64
+	if err := s.db.Model(&models.Room{}).Where("LOWER(name) = ? AND id != ?", strings.ToLower(name), id).Count(&count).Error; err != nil {
+		return errors.DatabaseError("checking room name", err)
+	}
+	if count > 0 {
+		return errors.DuplicateEntry("Room", "name")
+	}
+	return nil
+}
+
+func (s *RoomService) toResponse(r *models.Room) *dto.RoomResponse {
+	equipment := r.Equipment
+	if equipment == nil {
+		equipment = []string{}
+	}
+	return &dto.RoomResponse{
+		ID:             r.ID,
+		Name:           r.Name,
+		Building:       r.Building,
+		Capacity:       r.Capacity,
+		Equipment:      equipment,
+		AvailableHours: r.AvailableHours,
+		IsActive:       r.IsActive,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+// normalizeEquipment lower-cases and de-duplicates equipment tags
+func normalizeEquipment(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// loadRoom finds a room by ID; a nil ID means no room
+func loadRoom(db *gorm.DB, id *uuid.UUID) (*models.Room, error) {
+	if id == nil {
+		return nil, nil
+	}
+	var room models.Room
+	if err := db.First(&room, "id = ?", *id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Room", id.String())
+		}
+		return nil, errors.DatabaseError("finding room", err)
+	}
+	return &room, nil
+}
+
+// resolveRoom finds the room a booking names, either by ID or by a free-text
+// name matching a room regardless of case. It returns nil for locations that
+// are not rooms, and rejects inactive rooms.
+func resolveRoom(db *gorm.DB, id *uuid.UUID, name string) (*models.Room, error) {
+	room, err := loadRoom(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, nil
+		}
+		var rooms []models.Room
+		if err := db.Where("LOWER(name) = ?", strings.ToLower(name)).Limit(1).Find(&rooms).Error; err != nil {
+			return nil, errors.DatabaseError("finding room", err)
+		}
+		if len(rooms) == 0 {
+			return nil, nil
+		}
+		room = &rooms[0]
+	}
+	if !room.IsActive {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Room %s is not active", room.Name))
+	}
+	return room, nil
+}
+
+// groupHeadcount counts the students enrolled in the groups
+func groupHeadcount(db *gorm.DB, groupIDs ...uuid.UUID) (int, error) {
+	if len(groupIDs) == 0 {
+		return 0, nil
+	}
+	var count int64
+	if err := db.Model(&models.Student{}).Where("group_id IN ?", groupIDs).Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("counting group students", err)
+	}
+	return int(count), nil
+}
+
+// timetableHeadcount counts the students of every group sharing a timetable;
+// they meet in its classroom together
+func timetableHeadcount(db *gorm.DB, timetableID uuid.UUID) (int, error) {
+	var count int64
+	if err := db.Model(&models.Student{}).
+		Where("group_id IN (?)", db.Model(&models.Group{}).Select("id").Where("timetable_id = ?", timetableID)).
+		Count(&count).Error; err != nil {
+		return 0, errors.DatabaseError("counting timetable students", err)
+	}
+	return int(count), nil
+}
+
+// ensureTimetableRoomsFit checks every room a timetable's slots meet in can
+// seat the headcount. The timetable's slots must be loaded.
+func ensureTimetableRoomsFit(db *gorm.DB, tt *models.Timetable, headcount int) error {
+	roomIDs := make(map[uuid.UUID]bool)
+	for i := range tt.Slots {
+		slot := &tt.Slots[i]
+		switch {
+		case slot.RoomID != nil:
+			roomIDs[*slot.RoomID] = true
+		case slot.Classroom == "" && tt.RoomID != nil:
+			roomIDs[*tt.RoomID] = true
+		}
+	}
+	for id := range roomIDs {
+		room, err := loadRoom(db, &id)
+		if err != nil {
+			return err
+		}
+		if err := ensureRoomFits(room, headcount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureRoomFits rejects a headcount larger than the room's seats
+func ensureRoomFits(room *models.Room, headcount int) error {
+	if room == nil || headcount <= room.Capacity {
+		return nil
+	}
+	return errors.New(errors.ErrCodeCapacityExceeded,
+		fmt.Sprintf("Room %s seats %d but %d students are enrolled", room.Name, room.Capacity, headcount)).
+		WithDetail("room_id", room.ID).
+		WithDetail("capacity", room.Capacity).
+		WithDetail("headcount", headcount)
+}
+
+// overlapHours returns the hours of [start, end) inside [from, to)
+func overlapHours(start, end, from, to time.Time) float64 {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours()
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func utilizationPercent(booked, available float64) float64 {
+	if available <= 0 {
+		return 0
+	}
+	return math.Round(booked/available*1000) / 10
+}
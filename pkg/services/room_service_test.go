@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomService_CapacityAndUtilization(t *testing.T) {
+	db := setupTestDB()
+	rooms := NewRoomService(db)
+	timetables := NewTimetableService(db)
+	students := NewStudentService(db)
+	ctx := context.Background()
+
+	room, err := rooms.Create(ctx, dto.CreateRoomRequest{Name: "Room 1", Capacity: 2, Equipment: []string{"Projector", " projector", "Whiteboard"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"projector", "whiteboard"}, room.Equipment)
+	assert.Equal(t, float64(defaultRoomHours), room.AvailableHours)
+
+	_, err = rooms.Create(ctx, dto.CreateRoomRequest{Name: "room 1", Capacity: 10})
+	assert.Error(t, err)
+
+	found, err := rooms.GetAll(ctx, dto.RoomFilter{Equipment: "Projector"})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	found, err = rooms.GetAll(ctx, dto.RoomFilter{Equipment: "projector,sink"})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	// A classroom name is matched to the room regardless of case
+	timetable, err := timetables.Create(ctx, dto.CreateTimetableRequest{StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed", Classroom: "ROOM 1"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, timetable.RoomID) {
+		assert.Equal(t, room.ID, *timetable.RoomID)
+	}
+	assert.Equal(t, "Room 1", timetable.Classroom)
+
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&group)
+
+	// The group may have ten places, but the room seats two
+	for i, phone := range []string{"992900000041", "992900000042", "992900000043"} {
+		_, err = students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Student", Surname: "Test", Phone: phone})
+		if i < 2 {
+			assert.NoError(t, err)
+			continue
+		}
+		if assert.Error(t, err) {
+			assert.Equal(t, errors.ErrCodeCapacityExceeded, err.(*errors.AppError).Code)
+		}
+	}
+
+	one := 1
+	_, err = rooms.Update(ctx, room.ID, dto.UpdateRoomRequest{Capacity: &one})
+	assert.Error(t, err)
+
+	// Renaming the room renames the timetable's classroom
+	name := "Lab 1"
+	_, err = rooms.Update(ctx, room.ID, dto.UpdateRoomRequest{Name: &name})
+	assert.NoError(t, err)
+	var stored models.Timetable
+	db.First(&stored, "id = ?", timetable.ID)
+	assert.Equal(t, "Lab 1", stored.Classroom)
+
+	// Two 2-hour lessons a week out of 60 available hours
+	report, err := rooms.Utilization(ctx, time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), report.WeekStart)
+	if assert.Len(t, report.Rooms, 1) {
+		assert.Equal(t, 4.0, report.Rooms[0].LessonHours)
+		assert.Equal(t, 6.7, report.Rooms[0].UtilizationPercent)
+	}
+
+	err = rooms.Delete(ctx, room.ID)
+	assert.Error(t, err)
+}
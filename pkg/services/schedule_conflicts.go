@@ -0,0 +1,449 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Kinds of bookings compared by the schedule conflict checker
+const (
+	bookingLesson    = "lesson"
+	bookingTimetable = "timetable"
+	bookingExam      = "exam"
+	bookingEvent     = "event"
+)
+
+// maxBookingDays bounds how many days of a long exam or event are checked
+const maxBookingDays = 31
+
+// scheduleSpan is a wall-clock interval within one calendar day
+type scheduleSpan struct {
+	Date  time.Time // Midnight UTC, like ClassSession.Date
+	Start int       // Minutes since midnight
+	End   int
+}
+
+// scheduleBooking is anything that occupies a room, a teacher, a group or
+// students for a while: a lesson, a weekly timetable slot, an exam or a
+// calendar event. Weekly bookings set Weekdays; all others set Spans.
+type scheduleBooking struct {
+	Kind        string
+	ID          uuid.UUID // Zero for bookings that are not stored yet
+	TimetableID uuid.UUID
+	Title       string
+	GroupID     uuid.UUID
+	TeacherID   uuid.UUID
+	Room        string
+	StudentIDs  []uuid.UUID // Students attending outside their own group
+
+	Spans []scheduleSpan
+
+	Weekdays map[time.Weekday]bool
+	Days     string
+	Start    int
+	End      int
+}
+
+// lessonBooking books a single lesson
+func lessonBooking(id, groupID, teacherID uuid.UUID, date time.Time, startTime, endTime, room string) scheduleBooking {
+	return scheduleBooking{
+		Kind:      bookingLesson,
+		ID:        id,
+		GroupID:   groupID,
+		TeacherID: teacherID,
+		Room:      room,
+		Spans:     []scheduleSpan{{Date: dateOnly(date), Start: timeToMinutes(startTime), End: timeToMinutes(endTime)}},
+	}
+}
+
+// timetableBookings books each weekly slot of a timetable, for the group
+// using it when one is given. The timetable's slots must be loaded.
+func timetableBookings(tt *models.Timetable, group *models.Group) []scheduleBooking {
+	bookings := make([]scheduleBooking, len(tt.Slots))
+	for i := range tt.Slots {
+		slot := &tt.Slots[i]
+		bookings[i] = scheduleBooking{
+			Kind:        bookingTimetable,
+			TimetableID: tt.ID,
+			Room:        tt.SlotClassroom(slot),
+			Weekdays:    map[time.Weekday]bool{slot.Weekday.TimeWeekday(): true},
+			Days:        slot.Weekday.Label(),
+			Start:       slot.StartMinute,
+			End:         slot.EndMinute,
+		}
+		if group != nil {
+			bookings[i].Title = group.Name
+			bookings[i].GroupID = group.ID
+			bookings[i].TeacherID = group.TeacherID
+		}
+	}
+	return bookings
+}
+
+// examBooking books an exam's room and group
+func examBooking(exam *models.Exam) scheduleBooking {
+	return scheduleBooking{
+		Kind:    bookingExam,
+		ID:      exam.ID,
+		Title:   exam.Title,
+		GroupID: exam.GroupID,
+		Room:    exam.Location,
+		Spans:   spansBetween(exam.StartTime, exam.EndTime),
+	}
+}
+
+// eventBooking books an event's room, group and teacher
+func eventBooking(event *models.Event) scheduleBooking {
+	booking := scheduleBooking{
+		Kind:  bookingEvent,
+		ID:    event.ID,
+		Title: event.Title,
+		Room:  event.Location,
+		Spans: spansBetween(event.StartTime, event.EndTime),
+	}
+	if event.AllDay {
+		for i := range booking.Spans {
+			booking.Spans[i].Start, booking.Spans[i].End = 0, 24*60
+		}
+	}
+	if event.GroupID != nil {
+		booking.GroupID = *event.GroupID
+	}
+	if event.TeacherID != nil {
+		booking.TeacherID = *event.TeacherID
+	}
+	return booking
+}
+
+// eventOccupiesSchedule reports whether events of a type take up rooms and
+// people; holidays and deadlines only mark the calendar
+func eventOccupiesSchedule(eventType models.EventType) bool {
+	return eventType != models.EventTypeHoliday && eventType != models.EventTypeDeadline
+}
+
+// spansBetween splits a time range into wall-clock spans per calendar day
+func spansBetween(start, end time.Time) []scheduleSpan {
+	start, end = start.In(time.Local), end.In(time.Local)
+	spans := make([]scheduleSpan, 0, 1)
+	day := dateOnly(start)
+	last := dateOnly(end)
+	for i := 0; i < maxBookingDays && !day.After(last); i++ {
+		span := scheduleSpan{Date: day, Start: 0, End: 24 * 60}
+		if day.Equal(dateOnly(start)) {
+			span.Start = start.Hour()*60 + start.Minute()
+		}
+		if day.Equal(last) {
+			span.End = end.Hour()*60 + end.Minute()
+		}
+		if span.End > span.Start {
+			spans = append(spans, span)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return spans
+}
+
+// dateOnly returns the calendar date of t as midnight UTC
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekly reports whether the booking repeats every week
+func (b scheduleBooking) weekly() bool {
+	return b.Weekdays != nil
+}
+
+// isLesson reports whether the booking is regular teaching
+func (b scheduleBooking) isLesson() bool {
+	return b.Kind == bookingLesson || b.Kind == bookingTimetable
+}
+
+// overlapDates returns the dates on which two bookings overlap. Two weekly
+// bookings that overlap return a single zero date.
+func (b scheduleBooking) overlapDates(other scheduleBooking) []time.Time {
+	switch {
+	case b.weekly() && other.weekly():
+		if b.Start >= other.End || other.Start >= b.End {
+			return nil
+		}
+		for day := range b.Weekdays {
+			if other.Weekdays[day] {
+				return []time.Time{{}}
+			}
+		}
+		return nil
+	case b.weekly():
+		return other.overlapDates(b)
+	}
+
+	var dates []time.Time
+	for _, span := range b.Spans {
+		if other.weekly() {
+			if other.Weekdays[span.Date.Weekday()] && span.Start < other.End && other.Start < span.End {
+				dates = append(dates, span.Date)
+			}
+			continue
+		}
+		for _, o := range other.Spans {
+			if span.Date.Equal(o.Date) && span.Start < o.End && o.Start < span.End {
+				dates = append(dates, span.Date)
+				break
+			}
+		}
+	}
+	return dates
+}
+
+// conflictReason reports which shared resource makes two overlapping
+// bookings clash, or "" if they share none. An exam or event held for a group
+// during its own lesson is not a clash.
+func (b scheduleBooking) conflictReason(other scheduleBooking, studentGroups map[uuid.UUID]bool) string {
+	sameGroup := b.GroupID != uuid.Nil && b.GroupID == other.GroupID
+	if sameGroup && b.isLesson() != other.isLesson() {
+		return ""
+	}
+	switch {
+	case sameGroup:
+		return "group"
+	case b.TeacherID != uuid.Nil && b.TeacherID == other.TeacherID:
+		return "teacher"
+	case b.Room != "" && strings.EqualFold(b.Room, other.Room):
+		return "room"
+	case other.GroupID != uuid.Nil && studentGroups[other.GroupID]:
+		return "student"
+	}
+	return ""
+}
+
+// conflict describes the booking as a clash on the given date
+func (b scheduleBooking) conflict(reason string, date time.Time) dto.ScheduleConflict {
+	c := dto.ScheduleConflict{
+		Kind:   b.Kind,
+		Title:  b.Title,
+		Room:   b.Room,
+		Reason: reason,
+	}
+	if b.ID != uuid.Nil {
+		id := b.ID
+		switch b.Kind {
+		case bookingLesson:
+			c.SessionID = &id
+		case bookingExam:
+			c.ExamID = &id
+		case bookingEvent:
+			c.EventID = &id
+		}
+	}
+	if b.TimetableID != uuid.Nil {
+		id := b.TimetableID
+		c.TimetableID = &id
+	}
+	if b.GroupID != uuid.Nil {
+		id := b.GroupID
+		c.GroupID = &id
+	}
+	if b.TeacherID != uuid.Nil {
+		id := b.TeacherID
+		c.TeacherID = &id
+	}
+
+	start, end := b.Start, b.End
+	if !b.weekly() {
+		for _, span := range b.Spans {
+			if span.Date.Equal(date) {
+				start, end = span.Start, span.End
+				break
+			}
+		}
+	}
+	c.StartTime, c.EndTime = minutesToTime(start), minutesToTime(end)
+	if !date.IsZero() {
+		c.Date = date.Format("2006-01-02")
+	}
+	if b.weekly() {
+		if date.IsZero() {
+			c.Days = b.Days
+		} else {
+			c.Kind = bookingLesson // A timetabled lesson on that date
+		}
+	}
+	return c
+}
+
+// minutesToTime formats minutes since midnight as "HH:MM"
+func minutesToTime(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// findScheduleConflicts returns every booking that overlaps the candidate and
+// shares its room, teacher, group or students. Lessons, timetabled lessons
+// not generated yet, exams and calendar events are all considered; bookings
+// with an excluded ID (or, for timetables, an excluded group or timetable ID)
+// are ignored.
+func findScheduleConflicts(db *gorm.DB, candidate scheduleBooking, exclude ...uuid.UUID) ([]dto.ScheduleConflict, error) {
+	excluded := make(map[uuid.UUID]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+
+	studentGroups := make(map[uuid.UUID]bool)
+	if len(candidate.StudentIDs) > 0 {
+		var groupIDs []uuid.UUID
+		if err := db.Model(&models.Student{}).Where("id IN ?", candidate.StudentIDs).Pluck("group_id", &groupIDs).Error; err != nil {
+			return nil, errors.DatabaseError("finding student groups", err)
+		}
+		for _, id := range groupIDs {
+			studentGroups[id] = true
+		}
+	}
+
+	conflicts := make([]dto.ScheduleConflict, 0)
+	check := func(other scheduleBooking, skipDate func(time.Time) bool) {
+		for _, date := range candidate.overlapDates(other) {
+			if skipDate != nil && skipDate(date) {
+				continue
+			}
+			if reason := candidate.conflictReason(other, studentGroups); reason != "" {
+				conflicts = append(conflicts, other.conflict(reason, date))
+			}
+			return
+		}
+	}
+
+	// Generated lessons; weekly candidates are compared with timetables instead
+	generated := make(map[string]bool)
+	if !candidate.weekly() && len(candidate.Spans) > 0 {
+		var sessions []models.ClassSession
+		if err := db.Where("date >= ? AND date <= ?", candidate.Spans[0].Date, candidate.Spans[len(candidate.Spans)-1].Date).
+			Find(&sessions).Error; err != nil {
+			return nil, errors.DatabaseError("finding class sessions", err)
+		}
+		for _, session := range sessions {
+			generated[session.GroupID.String()+dateOnly(session.Date).Format("2006-01-02")+session.StartTime] = true
+			if excluded[session.ID] || !session.AcceptsAttendance() {
+				continue
+			}
+			booking := lessonBooking(session.ID, session.GroupID, session.TeacherID, session.Date, session.StartTime, session.EndTime, session.Room)
+			if session.TimetableID != nil {
+				booking.TimetableID = *session.TimetableID
+			}
+			check(booking, nil)
+		}
+	}
+
+	// Weekly timetable slots, with the groups that use them
+	var timetables []models.Timetable
+	if err := db.Preload("Groups").Preload("Slots").Find(&timetables).Error; err != nil {
+		return nil, errors.DatabaseError("finding timetables", err)
+	}
+	for i := range timetables {
+		tt := &timetables[i]
+		// Groups sharing one timetable share its slot
+		if candidate.weekly() && candidate.TimetableID == tt.ID {
+			continue
+		}
+		bookings := make([]scheduleBooking, 0, len(tt.Groups))
+		for j := range tt.Groups {
+			if !excluded[tt.Groups[j].ID] {
+				bookings = append(bookings, timetableBookings(tt, &tt.Groups[j])...)
+			}
+		}
+		if len(tt.Groups) == 0 && !excluded[tt.ID] {
+			bookings = append(bookings, timetableBookings(tt, nil)...)
+		}
+		for _, booking := range bookings {
+			groupID, start := booking.GroupID.String(), minutesToTime(booking.Start)
+			check(booking, func(date time.Time) bool {
+				return generated[groupID+date.Format("2006-01-02")+start]
+			})
+		}
+	}
+
+	// Exams and events; weekly candidates are checked against upcoming ones
+	from, to := time.Now(), time.Time{}
+	if !candidate.weekly() {
+		if len(candidate.Spans) == 0 {
+			return conflicts, nil
+		}
+		from = candidate.Spans[0].Date.AddDate(0, 0, -1)
+		to = candidate.Spans[len(candidate.Spans)-1].Date.AddDate(0, 0, 2)
+	}
+
+	examQuery := db.Select("id", "title", "group_id", "start_time", "end_time", "location", "status").
+		Where("status <> ? AND end_time > ?", models.ExamStatusCancelled, from)
+	if !to.IsZero() {
+		examQuery = examQuery.Where("start_time < ?", to)
+	}
+
+	var exams []models.Exam
+	if err := examQuery.Find(&exams).Error; err != nil {
+		return nil, errors.DatabaseError("finding exams", err)
+	}
+	for i := range exams {
+		if !excluded[exams[i].ID] {
+			check(examBooking(&exams[i]), nil)
+		}
+	}
+
+	// Recurring events count on every occurrence
+	events, err := eventsBetween(db, bookedEvents, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if !excluded[events[i].ID] {
+			check(eventBooking(&events[i]), nil)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// bookedEvents selects the events that occupy rooms and people, with the
+// columns needed to book them
+func bookedEvents(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "title", "type", "group_id", "teacher_id", "start_time", "end_time", "all_day", "location",
+		"is_recurring", "recurrence_rule").
+		Where("type NOT IN ?", []models.EventType{models.EventTypeHoliday, models.EventTypeDeadline})
+}
+
+// ensureNoTimetableConflicts checks every weekly slot of a timetable, for
+// the group using it when one is given, and reports all clashes together
+func ensureNoTimetableConflicts(db *gorm.DB, tt *models.Timetable, group *models.Group, exclude ...uuid.UUID) error {
+	conflicts := make([]dto.ScheduleConflict, 0)
+	for _, booking := range timetableBookings(tt, group) {
+		found, err := findScheduleConflicts(db, booking, exclude...)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, found...)
+	}
+	return scheduleConflictError(bookingTimetable, conflicts)
+}
+
+// ensureNoScheduleConflicts returns a schedule conflict error listing every
+// clash of the booking, or nil when it is free
+func ensureNoScheduleConflicts(db *gorm.DB, candidate scheduleBooking, exclude ...uuid.UUID) error {
+	conflicts, err := findScheduleConflicts(db, candidate, exclude...)
+	if err != nil {
+		return err
+	}
+	return scheduleConflictError(candidate.Kind, conflicts)
+}
+
+// scheduleConflictError wraps found clashes in an ErrCodeScheduleConflict
+// error with the clashes in details.conflicts
+func scheduleConflictError(kind string, conflicts []dto.ScheduleConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return errors.New(errors.ErrCodeScheduleConflict, fmt.Sprintf("The %s conflicts with %d existing booking(s)", kind, len(conflicts))).
+		WithDetail("conflicts", conflicts)
+}
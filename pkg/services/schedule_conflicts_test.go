@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleConflicts(t *testing.T) {
+	db := setupTestDB()
+	groups := NewGroupService(db)
+	ctx := context.Background()
+
+	teacher := models.Teacher{Name: "Aziz", Surname: "Rahimov", Phone: "992900000031"}
+	db.Create(&teacher)
+	course := models.Course{Title: "Go", Duration: 3}
+	db.Create(&course)
+	morning := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed"}
+	db.Create(&morning)
+	overlapping := models.Timetable{Classroom: "Room 202", StartTime: "10:00", EndTime: "12:00", Days: "Mon"}
+	db.Create(&overlapping)
+	evening := models.Timetable{Classroom: "Room 202", StartTime: "18:00", EndTime: "20:00", Days: "Mon"}
+	db.Create(&evening)
+	assert.NoError(t, MigrateTimetableSlots(db))
+
+	req := dto.CreateGroupRequest{Name: "GO-1", StartDate: time.Now(), CourseID: course.ID, TeacherID: teacher.ID, TimetableID: morning.ID, Capacity: 10}
+	first, err := groups.Create(ctx, req)
+	assert.NoError(t, err)
+
+	// The teacher cannot take a second group at an overlapping time
+	req.Name, req.TimetableID = "GO-2", overlapping.ID
+	_, err = groups.Create(ctx, req)
+	if assert.Error(t, err) {
+		appErr, ok := err.(*errors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, errors.ErrCodeScheduleConflict, appErr.Code)
+		conflicts := appErr.Details["conflicts"].([]dto.ScheduleConflict)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, "teacher", conflicts[0].Reason)
+		assert.Equal(t, first.ID, *conflicts[0].GroupID)
+	}
+
+	req.TimetableID = evening.ID
+	_, err = groups.Create(ctx, req)
+	assert.NoError(t, err)
+
+	// An exam for another group cannot take a room booked by a timetable;
+	// 2026-11-02 is a Monday
+	start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+	exam := models.Exam{ID: uuid.New(), Title: "Midterm", GroupID: uuid.New(), Location: "Room 101", StartTime: start, EndTime: start.Add(time.Hour)}
+	err = ensureNoScheduleConflicts(db, examBooking(&exam))
+	assert.Error(t, err)
+
+	exam.Location = "Room 303"
+	assert.NoError(t, ensureNoScheduleConflicts(db, examBooking(&exam)))
+
+	// The group's own exam during its lesson is not a clash
+	exam.GroupID, exam.Location = first.ID, "Room 101"
+	assert.NoError(t, ensureNoScheduleConflicts(db, examBooking(&exam)))
+}
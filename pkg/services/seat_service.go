@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultSeatHoldTTL is how long a seat is held when no expiry is given
+const defaultSeatHoldTTL = 48 * time.Hour
+
+// SeatService reports group seat availability and holds seats for pending
+// enrollments. Every enrollment path allocates its seats through
+// allocateSeats, which locks the group row so concurrent enrollments cannot
+// take the same last seat.
+type SeatService struct {
+	db *gorm.DB
+}
+
+// NewSeatService creates a new seat service
+func NewSeatService(db *gorm.DB) *SeatService {
+	return &SeatService{db: db}
+}
+
+// GetAvailability returns a group's capacity, enrolled students and active holds
+func (s *SeatService) GetAvailability(ctx context.Context, groupID uuid.UUID) (*dto.GroupAvailabilityResponse, error) {
+	var group models.Group
+	if err := s.db.First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+
+	now := time.Now()
+	var enrolled int64
+	if err := s.db.Model(&models.Student{}).Where("group_id = ?", groupID).Count(&enrolled).Error; err != nil {
+		return nil, errors.DatabaseError("counting students", err)
+	}
+	var holds []models.SeatHold
+	if err := activeSeatHolds(s.db, groupID, now).Order("expires_at ASC").Find(&holds).Error; err != nil {
+		return nil, errors.DatabaseError("finding seat holds", err)
+	}
+
+	available := group.Capacity - int(enrolled) - len(holds)
+	if available < 0 {
+		available = 0
+	}
+	responses := make([]dto.SeatHoldResponse, len(holds))
+	for i := range holds {
+		responses[i] = *s.toResponse(&holds[i], now)
+	}
+	return &dto.GroupAvailabilityResponse{
+		GroupID:   group.ID,
+		Capacity:  group.Capacity,
+		Enrolled:  int(enrolled),
+		Held:      len(holds),
+		Available: available,
+		Holds:     responses,
+	}, nil
+}
+
+// HoldSeat holds a free seat of a group, e.g. while a first payment is pending
+func (s *SeatService) HoldSeat(ctx context.Context, groupID uuid.UUID, req dto.CreateSeatHoldRequest, createdBy *uuid.UUID) (*dto.SeatHoldResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(defaultSeatHoldTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.Validation("expires_at must be in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	hold := models.SeatHold{
+		ID:         uuid.New(),
+		GroupID:    groupID,
+		Reason:     req.Reason,
+		HolderName: req.HolderName,
+		InvoiceID:  req.InvoiceID,
+		Notes:      req.Notes,
+		ExpiresAt:  expiresAt,
+		CreatedBy:  createdBy,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return createSeatHold(tx, &hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(&hold, now), nil
+}
+
+// ReleaseHold gives a held seat back to the group
+func (s *SeatService) ReleaseHold(ctx context.Context, id uuid.UUID) (*dto.SeatHoldResponse, error) {
+	var hold models.SeatHold
+	if err := s.db.First(&hold, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Seat hold", id.String())
+		}
+		return nil, errors.DatabaseError("finding seat hold", err)
+	}
+
+	now := time.Now()
+	if !hold.IsActive(now) {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, "The seat hold is no longer active")
+	}
+	hold.ReleasedAt = &now
+	if err := s.db.Model(&hold).Update("released_at", now).Error; err != nil {
+		return nil, errors.DatabaseError("releasing seat hold", err)
+	}
+	return s.toResponse(&hold, now), nil
+}
+
+// lockGroup loads a group and locks its row for the rest of the transaction
+func lockGroup(tx *gorm.DB, groupID uuid.UUID) (*models.Group, error) {
+	var group models.Group
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+	return &group, nil
+}
+
+// activeSeatHolds scopes a query to a group's holds that still keep a seat
+func activeSeatHolds(db *gorm.DB, groupID uuid.UUID, now time.Time) *gorm.DB {
+	return db.Model(&models.SeatHold{}).
+		Where("group_id = ? AND consumed_at IS NULL AND released_at IS NULL AND expires_at > ?", groupID, now)
+}
+
+// allocateSeats locks a group and checks it can take seats more students,
+// counting enrolled students and active holds against its capacity and the
+// headcount against its classrooms. The hold being consumed by this
+// enrollment, if any, must belong to the group and no longer counts. It must
+// run inside a transaction; the lock is held until it ends.
+func allocateSeats(tx *gorm.DB, groupID uuid.UUID, seats int, holdID *uuid.UUID) (*models.Group, error) {
+	group, err := lockGroup(tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	held := activeSeatHolds(tx, groupID, now)
+	if holdID != nil {
+		var hold models.SeatHold
+		if err := tx.First(&hold, "id = ?", *holdID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NotFoundWithID("Seat hold", holdID.String())
+			}
+			return nil, errors.DatabaseError("finding seat hold", err)
+		}
+		if hold.GroupID != groupID {
+			return nil, errors.Validation("The seat hold is for another group")
+		}
+		if !hold.IsActive(now) {
+			return nil, errors.New(errors.ErrCodeInvalidOperation, "The seat hold is no longer active")
+		}
+		held = held.Where("id <> ?", hold.ID)
+	}
+
+	var enrolled, holds int64
+	if err := tx.Model(&models.Student{}).Where("group_id = ?", groupID).Count(&enrolled).Error; err != nil {
+		return nil, errors.DatabaseError("checking group capacity", err)
+	}
+	if err := held.Count(&holds).Error; err != nil {
+		return nil, errors.DatabaseError("counting seat holds", err)
+	}
+	if int(enrolled+holds)+seats > group.Capacity {
+		return nil, errors.New(errors.ErrCodeCapacityExceeded, "Group capacity exceeded").
+			WithDetail("capacity", group.Capacity).
+			WithDetail("enrolled", enrolled).
+			WithDetail("held", holds)
+	}
+
+	// The group's classrooms must seat the new students
+	var timetable models.Timetable
+	if err := tx.Preload("Slots").First(&timetable, "id = ?", group.TimetableID).Error; err == nil {
+		headcount, err := timetableHeadcount(tx, timetable.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := ensureTimetableRoomsFit(tx, &timetable, headcount+seats); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+// consumeSeatHold marks a hold as taken up by the student enrolled on it
+func consumeSeatHold(tx *gorm.DB, holdID, studentID uuid.UUID) error {
+	if err := tx.Model(&models.SeatHold{}).Where("id = ?", holdID).Updates(map[string]interface{}{
+		"consumed_at": time.Now(),
+		"student_id":  studentID,
+	}).Error; err != nil {
+		return errors.DatabaseError("consuming seat hold", err)
+	}
+	return nil
+}
+
+// createSeatHold allocates a seat for a new hold. It must run inside a
+// transaction.
+func createSeatHold(tx *gorm.DB, hold *models.SeatHold) error {
+	if _, err := allocateSeats(tx, hold.GroupID, 1, nil); err != nil {
+		return err
+	}
+	if hold.ID == uuid.Nil {
+		hold.ID = uuid.New()
+	}
+	if err := tx.Create(hold).Error; err != nil {
+		return errors.DatabaseError("holding seat", err)
+	}
+	return nil
+}
+
+// releaseWaitlistHolds releases the seats held for a waitlist entry's offers
+func releaseWaitlistHolds(db *gorm.DB, waitlistID uuid.UUID) error {
+	if err := db.Model(&models.SeatHold{}).
+		Where("waitlist_id = ? AND consumed_at IS NULL AND released_at IS NULL", waitlistID).
+		Update("released_at", time.Now()).Error; err != nil {
+		return errors.DatabaseError("releasing seat holds", err)
+	}
+	return nil
+}
+
+// toResponse converts a seat hold to its DTO
+func (s *SeatService) toResponse(h *models.SeatHold, now time.Time) *dto.SeatHoldResponse {
+	return &dto.SeatHoldResponse{
+		ID:         h.ID,
+		GroupID:    h.GroupID,
+		Reason:     h.Reason,
+		HolderName: h.HolderName,
+		WaitlistID: h.WaitlistID,
+		InvoiceID:  h.InvoiceID,
+		Notes:      h.Notes,
+		ExpiresAt:  h.ExpiresAt,
+		ConsumedAt: h.ConsumedAt,
+		StudentID:  h.StudentID,
+		ReleasedAt: h.ReleasedAt,
+		Active:     h.IsActive(now),
+		CreatedAt:  h.CreatedAt,
+	}
+}
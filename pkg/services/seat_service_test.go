@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatService_HoldsAndAllocation(t *testing.T) {
+	db := setupTestDB()
+	seats := NewSeatService(db)
+	students := NewStudentService(db)
+	bulk := NewBulkService(db)
+	ctx := context.Background()
+
+	group := models.Group{Name: "GO-1", Capacity: 3}
+	db.Create(&group)
+	_, err := students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "First", Surname: "Student", Phone: "992900000061"})
+	assert.NoError(t, err)
+
+	hold, err := seats.HoldSeat(ctx, group.ID, dto.CreateSeatHoldRequest{Reason: models.SeatHoldPayment, HolderName: "Pending Payer"}, nil)
+	assert.NoError(t, err)
+	assert.True(t, hold.Active)
+
+	availability, err := seats.GetAvailability(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, availability.Enrolled)
+	assert.Equal(t, 1, availability.Held)
+	assert.Equal(t, 1, availability.Available)
+
+	// Bulk imports are all-or-nothing against the free seats
+	resp, err := bulk.BulkCreateStudents(ctx, dto.BulkCreateStudentsRequest{GroupID: group.ID, Students: []dto.CreateStudentRequest{
+		{Name: "Bulk", Surname: "One", Phone: "992900000062"},
+		{Name: "Bulk", Surname: "Two", Phone: "992900000063"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.TotalCreated)
+	assert.Equal(t, 2, resp.TotalFailed)
+
+	_, err = students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Second", Surname: "Student", Phone: "992900000064"})
+	assert.NoError(t, err)
+
+	// Only the holder can take the last seat now
+	_, err = students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Walk", Surname: "In", Phone: "992900000065"})
+	assert.True(t, errors.IsCapacityExceeded(err))
+	payer, err := students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Pending", Surname: "Payer", Phone: "992900000066", HoldID: &hold.ID})
+	assert.NoError(t, err)
+
+	var consumed models.SeatHold
+	db.First(&consumed, "id = ?", hold.ID)
+	assert.NotNil(t, consumed.ConsumedAt)
+	assert.Equal(t, payer.ID, *consumed.StudentID)
+
+	_, err = seats.ReleaseHold(ctx, hold.ID)
+	assert.Error(t, err)
+	_, err = seats.HoldSeat(ctx, group.ID, dto.CreateSeatHoldRequest{Reason: models.SeatHoldManual, HolderName: "Late"}, nil)
+	assert.True(t, errors.IsCapacityExceeded(err))
+
+	// Expired holds stop counting
+	past := time.Now().Add(-time.Hour)
+	db.Create(&models.SeatHold{GroupID: group.ID, Reason: models.SeatHoldManual, ExpiresAt: past})
+	availability, err = seats.GetAvailability(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, availability.Enrolled)
+	assert.Equal(t, 0, availability.Held)
+	assert.Empty(t, availability.Holds)
+}
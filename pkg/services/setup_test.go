@@ -16,13 +16,55 @@ func setupTestDB() *gorm.DB {
 	err = db.AutoMigrate(
 		&models.Assignment{},
 		&models.AssignmentSubmission{},
+		&models.SubmissionAttachment{},
 		&models.Group{},
 		&models.Course{},
 		&models.Teacher{},
+		&models.TeacherAvailability{},
 		&models.Student{},
 		&models.Waitlist{},
+		&models.SeatHold{},
 		&models.Parent{},
 		&models.ParentStudent{},
+		&models.GradingScale{},
+		&models.GradingScaleVersion{},
+		&models.CertificateCounter{},
+		&models.Timetable{},
+		&models.TimetableSlot{},
+		&models.TimetableProposal{},
+		&models.Room{},
+		&models.ClassSession{},
+		&models.MakeUpCredit{},
+		&models.AttendanceAlert{},
+		&models.AttendanceAlertSettings{},
+		&models.Attendance{},
+		&models.Exam{},
+		&models.ExamResult{},
+		&models.Event{},
+		&models.CalendarFeed{},
+		&models.AcademicTerm{},
+		&models.TermBreak{},
+		&models.Holiday{},
+		&models.CoursePrerequisite{},
+		&models.SyllabusModule{},
+		&models.SyllabusLesson{},
+		&models.SessionSyllabusLesson{},
+		&models.Lead{},
+		&models.LeadActivity{},
+		&models.User{},
+		&models.Application{},
+		&models.AccountInvite{},
+		&models.Document{},
+		&models.Notification{},
+		&models.Certificate{},
+		&models.ReportCard{},
+		&models.Grade{},
+		&models.Invoice{},
+		&models.RecurringInvoice{},
+		&models.RecordMerge{},
+		&models.AuditLog{},
+		&models.TrialLesson{},
+		&models.Payment{},
 	)
 	if err != nil {
 		panic("failed to migrate database")
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
@@ -17,6 +18,7 @@ type StudentService interface {
 	Create(ctx context.Context, groupID string, req dto.CreateStudentRequest) (*dto.StudentResponse, error)
 	Update(ctx context.Context, id string, req dto.UpdateStudentRequest) (*dto.StudentResponse, error)
 	Delete(ctx context.Context, id string) error
+	Transfer(ctx context.Context, id string, req dto.TransferStudentRequest, requestedBy uuid.UUID) (*dto.StudentResponse, error)
 	GetByID(ctx context.Context, id string) (*dto.StudentResponse, error)
 	GetAll(ctx context.Context, groupID string, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
 	GetAllGlobal(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
@@ -37,52 +39,61 @@ func (s *studentService) Create(ctx context.Context, groupID string, req dto.Cre
 		"group_id": groupID,
 	}).Info().Msg("fetching all students globally")
 
-	// Validate group exists
-	var group models.Group
-	if err := s.db.First(&group, "id = ?", groupID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NotFoundWithID("Group", groupID)
-		}
-		return nil, errors.DatabaseError("checking group existence", err)
-	}
-
-	// Check group capacity
-	var studentCount int64
-	if err := s.db.Model(&models.Student{}).Where("group_id = ?", groupID).Count(&studentCount).Error; err != nil {
-		return nil, errors.DatabaseError("checking group capacity", err)
-	}
-
-	if int(studentCount) >= group.Capacity {
-		return nil, errors.New(errors.ErrCodeCapacityExceeded, "Group capacity exceeded")
+	groupUUID, err := uuid.Parse(groupID)
+	if err != nil {
+		return nil, errors.NotFoundWithID("Group", groupID)
 	}
 
-	// Check email uniqueness
-	if req.Email != "" {
-		var count int64
-		if err := s.db.Model(&models.Student{}).Where("email = ?", req.Email).Count(&count).Error; err != nil {
-			return nil, errors.DatabaseError("checking email existence", err)
+	var student models.Student
+	var warnings []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Locks the group until the student is created
+		group, err := allocateSeats(tx, groupUUID, 1, req.HoldID)
+		if err != nil {
+			return err
 		}
-		if count > 0 {
-			return nil, errors.DuplicateEntry("Student", "email")
+
+		// Check email uniqueness
+		if req.Email != "" {
+			var count int64
+			if err := tx.Model(&models.Student{}).Where("email = ?", req.Email).Count(&count).Error; err != nil {
+				return errors.DatabaseError("checking email existence", err)
+			}
+			if count > 0 {
+				return errors.DuplicateEntry("Student", "email")
+			}
 		}
-	}
 
-	student := models.Student{
-		Name:    req.Name,
-		Surname: req.Surname,
-		Phone:   req.Phone,
-		Email:   req.Email,
-		GroupID: uuid.MustParse(groupID),
-	}
+		// Enrolling without a course's prerequisites is allowed but flagged
+		if warnings, err = prerequisiteWarnings(tx, group.CourseID, req.Email, req.Phone); err != nil {
+			return err
+		}
 
-	if err := s.db.Create(&student).Error; err != nil {
-		return nil, errors.DatabaseError("creating student", err)
+		student = models.Student{
+			Name:    req.Name,
+			Surname: req.Surname,
+			Phone:   req.Phone,
+			Email:   req.Email,
+			GroupID: group.ID,
+		}
+		if err := tx.Create(&student).Error; err != nil {
+			return errors.DatabaseError("creating student", err)
+		}
+		if req.HoldID != nil {
+			return consumeSeatHold(tx, *req.HoldID, student.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Reload to get group data
 	s.db.Preload("Group").First(&student, "id = ?", student.ID)
 
-	return s.toResponse(&student), nil
+	response := s.toResponse(&student)
+	response.Warnings = warnings
+	return response, nil
 }
 
 func (s *studentService) Update(ctx context.Context, id string, req dto.UpdateStudentRequest) (*dto.StudentResponse, error) {
@@ -128,6 +139,66 @@ func (s *studentService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Transfer moves a student to another group with a free seat and records the
+// completed transfer
+func (s *studentService) Transfer(ctx context.Context, id string, req dto.TransferStudentRequest, requestedBy uuid.UUID) (*dto.StudentResponse, error) {
+	var student models.Student
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&student, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFoundWithID("Student", id)
+			}
+			return errors.DatabaseError("finding student", err)
+		}
+		if student.GroupID == req.ToGroupID {
+			return errors.New(errors.ErrCodeInvalidOperation, "The student is already in this group")
+		}
+
+		// Locks the destination until the student has moved
+		group, err := allocateSeats(tx, req.ToGroupID, 1, req.HoldID)
+		if err != nil {
+			return err
+		}
+
+		reason := req.Reason
+		if reason == "" {
+			reason = models.ReasonOther
+		}
+		now := time.Now()
+		transfer := models.StudentTransfer{
+			ID:            uuid.New(),
+			StudentID:     student.ID,
+			FromGroupID:   student.GroupID,
+			ToGroupID:     group.ID,
+			Status:        models.TransferCompleted,
+			Reason:        reason,
+			Notes:         req.Notes,
+			RequestedAt:   now,
+			EffectiveDate: now,
+			ApprovedAt:    &now,
+			CompletedAt:   &now,
+			RequestedBy:   requestedBy,
+		}
+		if err := tx.Create(&transfer).Error; err != nil {
+			return errors.DatabaseError("recording transfer", err)
+		}
+
+		if err := tx.Model(&student).Update("group_id", group.ID).Error; err != nil {
+			return errors.DatabaseError("transferring student", err)
+		}
+		if req.HoldID != nil {
+			return consumeSeatHold(tx, *req.HoldID, student.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.Preload("Group").First(&student, "id = ?", student.ID)
+	return s.toResponse(&student), nil
+}
+
 func (s *studentService) GetByID(ctx context.Context, id string) (*dto.StudentResponse, error) {
 	var student models.Student
 	if err := s.db.Preload("Group").First(&student, "id = ?", id).Error; err != nil {
@@ -218,6 +289,7 @@ func (s *studentService) toResponse(st *models.Student) *dto.StudentResponse {
 		Phone:     st.Phone,
 		Email:     st.Email,
 		GroupID:   st.GroupID,
+		LeadID:    st.LeadID,
 		CreatedAt: st.CreatedAt,
 		UpdatedAt: st.UpdatedAt,
 		Group: dto.GroupSimple{
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// SyllabusService manages course syllabi and the lessons teachers cover in
+// each class session
+type SyllabusService struct {
+	db *gorm.DB
+}
+
+// NewSyllabusService creates a new syllabus service
+func NewSyllabusService(db *gorm.DB) *SyllabusService {
+	return &SyllabusService{db: db}
+}
+
+// Get returns the syllabus of a course in order
+func (s *SyllabusService) Get(ctx context.Context, courseID uuid.UUID) (*dto.SyllabusResponse, error) {
+	if err := s.ensureCourse(courseID); err != nil {
+		return nil, err
+	}
+	modules, err := s.loadModules(courseID)
+	if err != nil {
+		return nil, err
+	}
+	return toSyllabusResponse(courseID, modules, nil), nil
+}
+
+// Replace replaces the syllabus of a course. Modules and lessons are kept
+// by ID, so lessons already covered keep their records, and may move
+// between modules. Those left out are deleted.
+func (s *SyllabusService) Replace(ctx context.Context, courseID uuid.UUID, req dto.UpdateSyllabusRequest) (*dto.SyllabusResponse, error) {
+	if err := s.ensureCourse(courseID); err != nil {
+		return nil, err
+	}
+	existing, err := s.loadModules(courseID)
+	if err != nil {
+		return nil, err
+	}
+	knownModules := make(map[uuid.UUID]bool)
+	knownLessons := make(map[uuid.UUID]bool)
+	for _, m := range existing {
+		knownModules[m.ID] = true
+		for _, l := range m.Lessons {
+			knownLessons[l.ID] = true
+		}
+	}
+
+	// Resolve IDs before touching anything
+	keptModules := make(map[uuid.UUID]bool)
+	keptLessons := make(map[uuid.UUID]bool)
+	modules := make([]models.SyllabusModule, len(req.Modules))
+	for i, m := range req.Modules {
+		module := models.SyllabusModule{ID: uuid.New(), CourseID: courseID, Position: i + 1,
+			Week: m.Week, Title: m.Title, Description: m.Description}
+		if m.ID != nil {
+			if !knownModules[*m.ID] {
+				return nil, errors.Validation(fmt.Sprintf("Module %s is not part of this course's syllabus", *m.ID))
+			}
+			if keptModules[*m.ID] {
+				return nil, errors.Validation(fmt.Sprintf("Module %s is listed twice", *m.ID))
+			}
+			module.ID = *m.ID
+			keptModules[module.ID] = true
+		}
+		for j, l := range m.Lessons {
+			lesson := models.SyllabusLesson{ID: uuid.New(), ModuleID: module.ID, CourseID: courseID,
+				Position: j + 1, Title: l.Title, Description: l.Description}
+			if l.ID != nil {
+				if !knownLessons[*l.ID] {
+					return nil, errors.Validation(fmt.Sprintf("Lesson %s is not part of this course's syllabus", *l.ID))
+				}
+				if keptLessons[*l.ID] {
+					return nil, errors.Validation(fmt.Sprintf("Lesson %s is listed twice", *l.ID))
+				}
+				lesson.ID = *l.ID
+				keptLessons[lesson.ID] = true
+			}
+			module.Lessons = append(module.Lessons, lesson)
+		}
+		modules[i] = module
+	}
+
+	var droppedLessons []uuid.UUID
+	for id := range knownLessons {
+		if !keptLessons[id] {
+			droppedLessons = append(droppedLessons, id)
+		}
+	}
+	var droppedModules []uuid.UUID
+	for id := range knownModules {
+		if !keptModules[id] {
+			droppedModules = append(droppedModules, id)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Save first so lessons moving out of a dropped module are not
+		// deleted with it
+		for i := range modules {
+			module := modules[i]
+			if err := tx.Omit("Lessons").Save(&module).Error; err != nil {
+				return errors.DatabaseError("saving syllabus module", err)
+			}
+			for j := range module.Lessons {
+				if err := tx.Save(&module.Lessons[j]).Error; err != nil {
+					return errors.DatabaseError("saving syllabus lesson", err)
+				}
+			}
+		}
+		if len(droppedLessons) > 0 {
+			if err := tx.Where("lesson_id IN ?", droppedLessons).Delete(&models.SessionSyllabusLesson{}).Error; err != nil {
+				return errors.DatabaseError("deleting lesson coverage", err)
+			}
+			if err := tx.Where("id IN ?", droppedLessons).Delete(&models.SyllabusLesson{}).Error; err != nil {
+				return errors.DatabaseError("deleting syllabus lessons", err)
+			}
+		}
+		if len(droppedModules) > 0 {
+			if err := tx.Where("id IN ?", droppedModules).Delete(&models.SyllabusModule{}).Error; err != nil {
+				return errors.DatabaseError("deleting syllabus modules", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, courseID)
+}
+
+// GetSessionLessons lists the syllabus lessons covered in a class session
+func (s *SyllabusService) GetSessionLessons(ctx context.Context, sessionID uuid.UUID) (*dto.SessionSyllabusResponse, error) {
+	var session models.ClassSession
+	if err := s.db.Select("id").First(&session, "id = ?", sessionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Class session", sessionID.String())
+		}
+		return nil, errors.DatabaseError("finding class session", err)
+	}
+	return s.sessionLessons(sessionID)
+}
+
+// SetSessionLessons records the syllabus lessons a teacher covered in a
+// class session, replacing earlier ticks. The lessons must belong to the
+// syllabus of the group's course.
+func (s *SyllabusService) SetSessionLessons(ctx context.Context, sessionID uuid.UUID, req dto.SetSessionSyllabusRequest, userID uuid.UUID) (*dto.SessionSyllabusResponse, error) {
+	var session models.ClassSession
+	if err := s.db.Preload("Group").First(&session, "id = ?", sessionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Class session", sessionID.String())
+		}
+		return nil, errors.DatabaseError("finding class session", err)
+	}
+	if session.Status == models.SessionCancelled || session.Status == models.SessionRescheduled {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("The lesson is %s; nothing was covered in it", session.Status))
+	}
+
+	lessonIDs := make([]uuid.UUID, 0, len(req.LessonIDs))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range req.LessonIDs {
+		if !seen[id] {
+			seen[id] = true
+			lessonIDs = append(lessonIDs, id)
+		}
+	}
+	if len(lessonIDs) > 0 {
+		if session.Group == nil {
+			return nil, errors.NotFound("Group")
+		}
+		var count int64
+		if err := s.db.Model(&models.SyllabusLesson{}).
+			Where("id IN ? AND course_id = ?", lessonIDs, session.Group.CourseID).
+			Count(&count).Error; err != nil {
+			return nil, errors.DatabaseError("checking syllabus lessons", err)
+		}
+		if int(count) != len(lessonIDs) {
+			return nil, errors.Validation("Lessons must belong to the syllabus of the group's course")
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", sessionID).Delete(&models.SessionSyllabusLesson{}).Error; err != nil {
+			return errors.DatabaseError("clearing session lessons", err)
+		}
+		for _, id := range lessonIDs {
+			covered := models.SessionSyllabusLesson{SessionID: sessionID, LessonID: id, GroupID: session.GroupID, CoveredBy: &userID}
+			if err := tx.Create(&covered).Error; err != nil {
+				return errors.DatabaseError("recording session lesson", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionLessons(sessionID)
+}
+
+// GetGroupProgress reports which syllabus lessons a group has covered and
+// the next one due. Lessons ticked in cancelled or rescheduled sessions do
+// not count.
+func (s *SyllabusService) GetGroupProgress(ctx context.Context, groupID uuid.UUID) (*dto.SyllabusProgressResponse, error) {
+	var group models.Group
+	if err := s.db.Select("id", "course_id").First(&group, "id = ?", groupID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Group", groupID.String())
+		}
+		return nil, errors.DatabaseError("finding group", err)
+	}
+	modules, err := s.loadModules(group.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		LessonID  uuid.UUID
+		SessionID uuid.UUID
+		Date      time.Time
+	}
+	if err := s.db.Table("session_syllabus_lessons").
+		Select("session_syllabus_lessons.lesson_id, session_syllabus_lessons.session_id, class_sessions.date").
+		Joins("JOIN class_sessions ON class_sessions.id = session_syllabus_lessons.session_id AND class_sessions.deleted_at IS NULL").
+		Where("session_syllabus_lessons.group_id = ? AND class_sessions.status NOT IN ?", groupID,
+			[]models.ClassSessionStatus{models.SessionCancelled, models.SessionRescheduled}).
+		Order("class_sessions.date, class_sessions.start_time").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.DatabaseError("finding covered lessons", err)
+	}
+	coverage := make(map[uuid.UUID]dto.SyllabusLessonResponse)
+	for _, row := range rows {
+		if _, ok := coverage[row.LessonID]; !ok {
+			sessionID := row.SessionID
+			coverage[row.LessonID] = dto.SyllabusLessonResponse{Covered: true, SessionID: &sessionID, CoveredOn: row.Date.Format("2006-01-02")}
+		}
+	}
+
+	syllabus := toSyllabusResponse(group.CourseID, modules, coverage)
+	progress := &dto.SyllabusProgressResponse{
+		GroupID:      groupID,
+		CourseID:     group.CourseID,
+		TotalLessons: syllabus.TotalLessons,
+		Modules:      syllabus.Modules,
+	}
+	for _, m := range syllabus.Modules {
+		for i := range m.Lessons {
+			if m.Lessons[i].Covered {
+				progress.CoveredLessons++
+			} else if progress.NextLesson == nil {
+				next := m.Lessons[i]
+				progress.NextLesson = &next
+			}
+		}
+	}
+	if progress.TotalLessons > 0 {
+		progress.Percent = math.Round(float64(progress.CoveredLessons)/float64(progress.TotalLessons)*1000) / 10
+	}
+	return progress, nil
+}
+
+func (s *SyllabusService) ensureCourse(courseID uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.Course{}).Where("id = ?", courseID).Count(&count).Error; err != nil {
+		return errors.DatabaseError("finding course", err)
+	}
+	if count == 0 {
+		return errors.NotFoundWithID("Course", courseID.String())
+	}
+	return nil
+}
+
+func (s *SyllabusService) loadModules(courseID uuid.UUID) ([]models.SyllabusModule, error) {
+	var modules []models.SyllabusModule
+	if err := s.db.Where("course_id = ?", courseID).Order("position").
+		Preload("Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Find(&modules).Error; err != nil {
+		return nil, errors.DatabaseError("loading syllabus", err)
+	}
+	return modules, nil
+}
+
+func (s *SyllabusService) sessionLessons(sessionID uuid.UUID) (*dto.SessionSyllabusResponse, error) {
+	var lessons []models.SyllabusLesson
+	if err := s.db.Joins("JOIN session_syllabus_lessons ON session_syllabus_lessons.lesson_id = syllabus_lessons.id").
+		Joins("JOIN syllabus_modules ON syllabus_modules.id = syllabus_lessons.module_id").
+		Where("session_syllabus_lessons.session_id = ?", sessionID).
+		Order("syllabus_modules.position, syllabus_lessons.position").
+		Find(&lessons).Error; err != nil {
+		return nil, errors.DatabaseError("finding session lessons", err)
+	}
+	response := &dto.SessionSyllabusResponse{SessionID: sessionID, Lessons: make([]dto.SyllabusLessonResponse, len(lessons))}
+	for i := range lessons {
+		response.Lessons[i] = toSyllabusLessonResponse(&lessons[i])
+	}
+	return response, nil
+}
+
+// toSyllabusResponse converts modules to a response, merging in lesson
+// coverage when given
+func toSyllabusResponse(courseID uuid.UUID, modules []models.SyllabusModule, coverage map[uuid.UUID]dto.SyllabusLessonResponse) *dto.SyllabusResponse {
+	response := &dto.SyllabusResponse{CourseID: courseID, Modules: make([]dto.SyllabusModuleResponse, len(modules))}
+	for i, m := range modules {
+		module := dto.SyllabusModuleResponse{ID: m.ID, Position: m.Position, Week: m.Week, Title: m.Title,
+			Description: m.Description, Lessons: make([]dto.SyllabusLessonResponse, len(m.Lessons))}
+		for j := range m.Lessons {
+			lesson := toSyllabusLessonResponse(&m.Lessons[j])
+			if covered, ok := coverage[lesson.ID]; ok {
+				lesson.Covered, lesson.SessionID, lesson.CoveredOn = true, covered.SessionID, covered.CoveredOn
+			}
+			module.Lessons[j] = lesson
+		}
+		response.TotalLessons += len(m.Lessons)
+		response.Modules[i] = module
+	}
+	return response
+}
+
+func toSyllabusLessonResponse(l *models.SyllabusLesson) dto.SyllabusLessonResponse {
+	return dto.SyllabusLessonResponse{ID: l.ID, Position: l.Position, Title: l.Title, Description: l.Description}
+}
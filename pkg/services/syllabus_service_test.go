@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseService_Prerequisites(t *testing.T) {
+	db := setupTestDB()
+	courses := NewCourseService(db)
+	students := NewStudentService(db)
+	ctx := context.Background()
+
+	beginner, err := courses.Create(ctx, dto.CreateCourseRequest{Title: "Go Beginner", MonthlyFee: 100, Duration: 3, Level: models.LevelBeginner, Category: "Programming"})
+	assert.NoError(t, err)
+	intermediate, err := courses.Create(ctx, dto.CreateCourseRequest{Title: "Go Intermediate", MonthlyFee: 120, Duration: 3,
+		Level: models.LevelIntermediate, Category: "Programming", PrerequisiteIDs: []uuid.UUID{beginner.ID}})
+	assert.NoError(t, err)
+	advanced, err := courses.Create(ctx, dto.CreateCourseRequest{Title: "Go Advanced", MonthlyFee: 150, Duration: 3,
+		Level: models.LevelAdvanced, Category: "Programming", PrerequisiteIDs: []uuid.UUID{intermediate.ID}})
+	assert.NoError(t, err)
+	if assert.Len(t, advanced.Prerequisites, 1) {
+		assert.Equal(t, "Go Intermediate", advanced.Prerequisites[0].Title)
+	}
+
+	// Beginner cannot require Advanced: Advanced → Intermediate → Beginner
+	prerequisites := []uuid.UUID{advanced.ID}
+	_, err = courses.Update(ctx, beginner.ID.String(), dto.UpdateCourseRequest{Title: "Go Beginner", MonthlyFee: 100, Duration: 3,
+		PrerequisiteIDs: &prerequisites})
+	if assert.Error(t, err) {
+		appErr := err.(*errors.AppError)
+		assert.Equal(t, errors.ErrCodeInvalidOperation, appErr.Code)
+		assert.Equal(t, "Go Beginner → Go Advanced → Go Intermediate → Go Beginner", appErr.Details["cycle"])
+	}
+	prerequisites = []uuid.UUID{beginner.ID}
+	_, err = courses.Update(ctx, beginner.ID.String(), dto.UpdateCourseRequest{Title: "Go Beginner", MonthlyFee: 100, Duration: 3,
+		PrerequisiteIDs: &prerequisites})
+	assert.Error(t, err)
+
+	listed, err := courses.GetAll(ctx, dto.PaginationRequest{Page: 1, PageSize: 10, SortBy: "title", Order: "asc"}, dto.CourseFilter{Level: models.LevelAdvanced})
+	assert.NoError(t, err)
+	assert.Len(t, listed.Data, 1)
+
+	// Enrolling without the prerequisite warns; with a certificate it does not
+	group := models.Group{Name: "GO-INT-1", CourseID: intermediate.ID, Capacity: 10}
+	db.Create(&group)
+	student, err := students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Farid", Surname: "Saidov", Phone: "992900000091"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`Prerequisite "Go Beginner" has not been completed`}, student.Warnings)
+
+	db.Create(&models.Certificate{ID: uuid.New(), Number: "CERT-1", VerificationCode: "ABCD", StudentID: student.ID,
+		CourseID: beginner.ID, CompletionDate: time.Now(), Status: models.CertificateIssued, IssuedAt: time.Now()})
+	warnings, err := prerequisiteWarnings(db, intermediate.ID, "", "992900000091")
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestSyllabusService(t *testing.T) {
+	db := setupTestDB()
+	syllabi := NewSyllabusService(db)
+	ctx := context.Background()
+	teacher := uuid.New()
+
+	course := models.Course{Title: "Go Beginner"}
+	db.Create(&course)
+	group := models.Group{Name: "GO-1", CourseID: course.ID, Capacity: 10}
+	db.Create(&group)
+
+	syllabus, err := syllabi.Replace(ctx, course.ID, dto.UpdateSyllabusRequest{Modules: []dto.SyllabusModuleRequest{
+		{Title: "Basics", Week: 1, Lessons: []dto.SyllabusLessonRequest{{Title: "Variables"}, {Title: "Loops"}}},
+		{Title: "Functions", Week: 2, Lessons: []dto.SyllabusLessonRequest{{Title: "Closures"}}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, syllabus.TotalLessons)
+	variables := syllabus.Modules[0].Lessons[0]
+	loops := syllabus.Modules[0].Lessons[1]
+
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	held := models.ClassSession{ID: uuid.New(), GroupID: group.ID, Date: date, StartTime: "09:00", EndTime: "10:30", Status: models.SessionHeld}
+	db.Create(&held)
+	cancelled := models.ClassSession{ID: uuid.New(), GroupID: group.ID, Date: date.AddDate(0, 0, 2), StartTime: "09:00", EndTime: "10:30", Status: models.SessionCancelled}
+	db.Create(&cancelled)
+
+	ticked, err := syllabi.SetSessionLessons(ctx, held.ID, dto.SetSessionSyllabusRequest{LessonIDs: []uuid.UUID{loops.ID, variables.ID}}, teacher)
+	assert.NoError(t, err)
+	if assert.Len(t, ticked.Lessons, 2) {
+		assert.Equal(t, "Variables", ticked.Lessons[0].Title)
+	}
+	_, err = syllabi.SetSessionLessons(ctx, cancelled.ID, dto.SetSessionSyllabusRequest{LessonIDs: []uuid.UUID{variables.ID}}, teacher)
+	assert.Error(t, err)
+
+	other := models.SyllabusLesson{ID: uuid.New(), ModuleID: uuid.New(), CourseID: uuid.New(), Title: "Elsewhere"}
+	db.Create(&other)
+	_, err = syllabi.SetSessionLessons(ctx, held.ID, dto.SetSessionSyllabusRequest{LessonIDs: []uuid.UUID{other.ID}}, teacher)
+	assert.Error(t, err)
+
+	progress, err := syllabi.GetGroupProgress(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.CoveredLessons)
+	assert.Equal(t, 66.7, progress.Percent)
+	if assert.NotNil(t, progress.NextLesson) {
+		assert.Equal(t, "Closures", progress.NextLesson.Title)
+	}
+	assert.Equal(t, "2026-03-02", progress.Modules[0].Lessons[0].CoveredOn)
+
+	// Reordering keeps coverage; dropping a lesson removes it
+	first := syllabus.Modules[0]
+	syllabus, err = syllabi.Replace(ctx, course.ID, dto.UpdateSyllabusRequest{Modules: []dto.SyllabusModuleRequest{
+		{ID: &first.ID, Title: "Basics", Week: 1, Lessons: []dto.SyllabusLessonRequest{{ID: &loops.ID, Title: "Loops"}}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, syllabus.TotalLessons)
+	progress, err = syllabi.GetGroupProgress(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, progress.CoveredLessons)
+	assert.Nil(t, progress.NextLesson)
+
+	_, err = syllabi.Replace(ctx, course.ID, dto.UpdateSyllabusRequest{Modules: []dto.SyllabusModuleRequest{
+		{ID: &other.ID, Title: "Foreign"},
+	}})
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
@@ -17,7 +18,9 @@ type TeacherService interface {
 	Update(ctx context.Context, id string, req dto.UpdateTeacherRequest) (*dto.TeacherResponse, error)
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*dto.TeacherResponse, error)
-	GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error)
+	GetAll(ctx context.Context, req dto.PaginationRequest, status string) (*dto.PaginatedResponse, error)
+	GetWorkload(ctx context.Context, id string, week string) (*dto.TeacherWorkloadResponse, error)
+	GetWorkloads(ctx context.Context, week string, status string) ([]dto.TeacherWorkloadResponse, error)
 }
 
 type teacherService struct {
@@ -43,11 +46,26 @@ func (s *teacherService) Create(ctx context.Context, req dto.CreateTeacherReques
 		}
 	}
 
+	availability, err := buildTeacherAvailability(req.Availability)
+	if err != nil {
+		return nil, err
+	}
+	status := req.Status
+	if status == "" {
+		status = models.TeacherActive
+	}
+
 	teacher := models.Teacher{
-		Name:    req.Name,
-		Surname: req.Surname,
-		Phone:   req.Phone,
-		Email:   req.Email,
+		Name:           req.Name,
+		Surname:        req.Surname,
+		Phone:          req.Phone,
+		Email:          req.Email,
+		Status:         status,
+		Qualifications: req.Qualifications,
+		Subjects:       req.Subjects,
+		HourlyRate:     req.HourlyRate,
+		MaxWeeklyHours: req.MaxWeeklyHours,
+		Availability:   availability,
 	}
 
 	if err := s.db.Create(&teacher).Error; err != nil {
@@ -59,7 +77,7 @@ func (s *teacherService) Create(ctx context.Context, req dto.CreateTeacherReques
 
 func (s *teacherService) Update(ctx context.Context, id string, req dto.UpdateTeacherRequest) (*dto.TeacherResponse, error) {
 	var teacher models.Teacher
-	if err := s.db.First(&teacher, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Availability").First(&teacher, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundWithID("Teacher", id)
 		}
@@ -81,9 +99,63 @@ func (s *teacherService) Update(ctx context.Context, id string, req dto.UpdateTe
 	teacher.Surname = req.Surname
 	teacher.Phone = req.Phone
 	teacher.Email = req.Email
+	if req.Status != "" {
+		teacher.Status = req.Status
+	}
+	if req.Qualifications != nil {
+		teacher.Qualifications = req.Qualifications
+	}
+	if req.Subjects != nil {
+		teacher.Subjects = req.Subjects
+	}
+	if req.HourlyRate != nil {
+		teacher.HourlyRate = *req.HourlyRate
+	}
+	rescheduled := req.Availability != nil || req.MaxWeeklyHours != nil
+	if req.MaxWeeklyHours != nil {
+		teacher.MaxWeeklyHours = *req.MaxWeeklyHours
+	}
+	if req.Availability != nil {
+		availability, err := buildTeacherAvailability(req.Availability)
+		if err != nil {
+			return nil, err
+		}
+		for i := range availability {
+			availability[i].TeacherID = teacher.ID
+		}
+		teacher.Availability = availability
+	}
+
+	// The teacher's current groups must still fit the new availability and limit
+	if rescheduled {
+		slots, err := teacherTimetableSlots(s.db, teacher.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := ensureTeacherFits(&teacher, slots, nil); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.db.Save(&teacher).Error; err != nil {
-		return nil, errors.DatabaseError("updating teacher", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Availability").Save(&teacher).Error; err != nil {
+			return errors.DatabaseError("updating teacher", err)
+		}
+		if req.Availability == nil {
+			return nil
+		}
+		if err := tx.Where("teacher_id = ?", teacher.ID).Delete(&models.TeacherAvailability{}).Error; err != nil {
+			return errors.DatabaseError("clearing teacher availability", err)
+		}
+		if len(teacher.Availability) > 0 {
+			if err := tx.Create(&teacher.Availability).Error; err != nil {
+				return errors.DatabaseError("saving teacher availability", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.toResponse(&teacher), nil
@@ -102,7 +174,7 @@ func (s *teacherService) Delete(ctx context.Context, id string) error {
 
 func (s *teacherService) GetByID(ctx context.Context, id string) (*dto.TeacherResponse, error) {
 	var teacher models.Teacher
-	if err := s.db.Preload("Groups").First(&teacher, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Groups").Preload("Availability").First(&teacher, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundWithID("Teacher", id)
 		}
@@ -112,7 +184,7 @@ func (s *teacherService) GetByID(ctx context.Context, id string) (*dto.TeacherRe
 	return s.toResponse(&teacher), nil
 }
 
-func (s *teacherService) GetAll(ctx context.Context, req dto.PaginationRequest) (*dto.PaginatedResponse, error) {
+func (s *teacherService) GetAll(ctx context.Context, req dto.PaginationRequest, status string) (*dto.PaginatedResponse, error) {
 	var teachers []models.Teacher
 	var total int64
 
@@ -122,6 +194,9 @@ func (s *teacherService) GetAll(ctx context.Context, req dto.PaginationRequest)
 		search := "%" + strings.ToLower(req.Search) + "%"
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(surname) LIKE ? OR LOWER(email) LIKE ?", search, search, search)
 	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, errors.DatabaseError("counting teachers", err)
@@ -131,6 +206,7 @@ func (s *teacherService) GetAll(ctx context.Context, req dto.PaginationRequest)
 		Offset(req.GetOffset()).
 		Limit(req.GetLimit()).
 		Preload("Groups").
+		Preload("Availability").
 		Find(&teachers).Error; err != nil {
 		return nil, errors.DatabaseError("listing teachers", err)
 	}
@@ -158,14 +234,44 @@ func (s *teacherService) toResponse(t *models.Teacher) *dto.TeacherResponse {
 		}
 	}
 
+	windows := append([]models.TeacherAvailability{}, t.Availability...)
+	sort.Slice(windows, func(i, j int) bool {
+		di, dj := (int(windows[i].Weekday.TimeWeekday())+6)%7, (int(windows[j].Weekday.TimeWeekday())+6)%7
+		if di != dj {
+			return di < dj
+		}
+		return windows[i].StartMinute < windows[j].StartMinute
+	})
+	availability := make([]dto.TeacherAvailabilityResponse, len(windows))
+	for i, w := range windows {
+		availability[i] = dto.TeacherAvailabilityResponse{
+			Weekday:   w.Weekday,
+			StartTime: models.FormatClock(w.StartMinute),
+			EndTime:   models.FormatClock(w.EndMinute),
+		}
+	}
+	qualifications, subjects := t.Qualifications, t.Subjects
+	if qualifications == nil {
+		qualifications = []string{}
+	}
+	if subjects == nil {
+		subjects = []string{}
+	}
+
 	return &dto.TeacherResponse{
-		ID:        t.ID,
-		Name:      t.Name,
-		Surname:   t.Surname,
-		Phone:     t.Phone,
-		Email:     t.Email,
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: t.UpdatedAt,
-		Groups:    groups,
+		ID:             t.ID,
+		Name:           t.Name,
+		Surname:        t.Surname,
+		Phone:          t.Phone,
+		Email:          t.Email,
+		Status:         t.Status,
+		Qualifications: qualifications,
+		Subjects:       subjects,
+		HourlyRate:     t.HourlyRate,
+		MaxWeeklyHours: teacherMaxWeeklyHours(t),
+		Availability:   availability,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+		Groups:         groups,
 	}
 }
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeacherService_AvailabilityLimitsAndWorkload(t *testing.T) {
+	db := setupTestDB()
+	teachers := NewTeacherService(db)
+	groups := NewGroupService(db)
+	ctx := context.Background()
+
+	teacher, err := teachers.Create(ctx, dto.CreateTeacherRequest{
+		Name: "Nilufar", Surname: "Saidova", Phone: "992900000091",
+		Subjects: []string{"Go"}, HourlyRate: 50, MaxWeeklyHours: 6,
+		Availability: []dto.TeacherAvailabilityRequest{
+			{Weekday: "Wed", StartTime: "09:00", EndTime: "13:00"},
+			{Weekday: "Mon", StartTime: "09:00", EndTime: "13:00"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TeacherActive, teacher.Status)
+	assert.Equal(t, 6.0, teacher.MaxWeeklyHours)
+	if assert.Len(t, teacher.Availability, 2) {
+		assert.Equal(t, models.Monday, teacher.Availability[0].Weekday)
+	}
+	_, err = teachers.Create(ctx, dto.CreateTeacherRequest{Name: "Bad", Surname: "Window", Phone: "992900000092",
+		Availability: []dto.TeacherAvailabilityRequest{{Weekday: "Mon", StartTime: "12:00", EndTime: "10:00"}}})
+	assert.True(t, errors.IsValidation(err))
+
+	course := models.Course{Title: "Go", Duration: 3}
+	db.Create(&course)
+	morning := models.Timetable{Classroom: "Room 101", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed"}
+	db.Create(&morning)
+	late := models.Timetable{Classroom: "Room 101", StartTime: "11:00", EndTime: "13:00", Days: "Wed"}
+	db.Create(&late)
+	extra := models.Timetable{Classroom: "Room 202", StartTime: "11:00", EndTime: "13:00", Days: "Mon"}
+	db.Create(&extra)
+	evening := models.Timetable{Classroom: "Room 202", StartTime: "18:00", EndTime: "20:00", Days: "Tue"}
+	db.Create(&evening)
+	assert.NoError(t, MigrateTimetableSlots(db))
+
+	req := dto.CreateGroupRequest{Name: "GO-1", StartDate: time.Now(), CourseID: course.ID, TeacherID: teacher.ID, TimetableID: morning.ID, Capacity: 10}
+	first, err := groups.Create(ctx, req)
+	assert.NoError(t, err)
+
+	// Outside the teacher's windows
+	req.Name, req.TimetableID = "GO-2", evening.ID
+	_, err = groups.Create(ctx, req)
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeScheduleConflict, err.(*errors.AppError).Code)
+	}
+
+	req.TimetableID = late.ID
+	_, err = groups.Create(ctx, req)
+	assert.NoError(t, err)
+
+	// Six hours a week is the limit
+	req.Name, req.TimetableID = "GO-3", extra.ID
+	_, err = groups.Create(ctx, req)
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.ErrCodeInvalidOperation, err.(*errors.AppError).Code)
+	}
+
+	onLeave, err := teachers.Create(ctx, dto.CreateTeacherRequest{Name: "Jamshed", Surname: "Karimov", Phone: "992900000093",
+		Status: models.TeacherOnLeave})
+	assert.NoError(t, err)
+	req.TeacherID = onLeave.ID
+	_, err = groups.Create(ctx, req)
+	assert.Error(t, err)
+
+	// Narrowing the windows cannot strand the Wednesday lessons
+	_, err = teachers.Update(ctx, teacher.ID.String(), dto.UpdateTeacherRequest{Name: "Nilufar", Surname: "Saidova", Phone: "992900000091",
+		Availability: []dto.TeacherAvailabilityRequest{{Weekday: "Mon", StartTime: "09:00", EndTime: "13:00"}}})
+	assert.Error(t, err)
+	updated, err := teachers.Update(ctx, teacher.ID.String(), dto.UpdateTeacherRequest{Name: "Nilufar", Surname: "Saidova", Phone: "992900000091",
+		Availability: []dto.TeacherAvailabilityRequest{}})
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Availability)
+	assert.Equal(t, []string{"Go"}, updated.Subjects)
+
+	// Week of Monday 2030-01-07 with a 90 minute exam on Tuesday
+	examStart := time.Date(2030, 1, 8, 10, 0, 0, 0, time.UTC)
+	db.Omit("Metadata").Create(&models.Exam{ID: uuid.New(), Title: "Midterm", Type: models.ExamTypeMidterm, Status: models.ExamStatusScheduled,
+		CourseID: course.ID, GroupID: first.ID, StartTime: examStart, EndTime: examStart.Add(90 * time.Minute), Duration: 90, TotalMarks: 100, PassingMarks: 50})
+	workload, err := teachers.GetWorkload(ctx, teacher.ID.String(), "2030-01-10")
+	assert.NoError(t, err)
+	assert.Equal(t, "2030-01-07", workload.WeekStart)
+	assert.Equal(t, "2030-01-13", workload.WeekEnd)
+	assert.Equal(t, 6.0, workload.TimetableHours)
+	assert.Equal(t, 6.0, workload.LessonHours)
+	assert.Equal(t, 1.5, workload.ExamHours)
+	assert.Equal(t, 7.5, workload.TotalHours)
+	assert.True(t, workload.Overloaded)
+	assert.Len(t, workload.Groups, 2)
+	assert.Len(t, workload.Exams, 1)
+
+	workloads, err := teachers.GetWorkloads(ctx, "2030-01-10", "")
+	assert.NoError(t, err)
+	if assert.Len(t, workloads, 1, "teachers on leave are left out") {
+		assert.Equal(t, teacher.ID, workloads[0].TeacherID)
+	}
+
+	metrics, err := NewAnalyticsService(db).GetDashboardMetrics(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.TotalTeachers)
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// defaultTeacherMaxWeeklyHours limits teachers without a limit of their own
+const defaultTeacherMaxWeeklyHours = 40
+
+// teacherMaxWeeklyHours returns the weekly hours limit in effect for a teacher
+func teacherMaxWeeklyHours(t *models.Teacher) float64 {
+	if t.MaxWeeklyHours > 0 {
+		return t.MaxWeeklyHours
+	}
+	return defaultTeacherMaxWeeklyHours
+}
+
+// buildTeacherAvailability validates requested availability windows.
+// Windows overlapping on the same day are rejected.
+func buildTeacherAvailability(requests []dto.TeacherAvailabilityRequest) ([]models.TeacherAvailability, error) {
+	windows := make([]models.TeacherAvailability, 0, len(requests))
+	for i, req := range requests {
+		weekday, err := models.ParseWeekday(req.Weekday)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("availability %d: %s", i+1, err))
+		}
+		start, end, err := parseTimeRange(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("availability %d: %s", i+1, err.(*errors.AppError).Message))
+		}
+		for _, other := range windows {
+			if other.Weekday == weekday && start < other.EndMinute && other.StartMinute < end {
+				return nil, errors.Validation(fmt.Sprintf("availability %d overlaps another window on %s", i+1, weekday.Label()))
+			}
+		}
+		windows = append(windows, models.TeacherAvailability{
+			ID:          uuid.New(),
+			Weekday:     weekday,
+			StartMinute: start,
+			EndMinute:   end,
+		})
+	}
+	return windows, nil
+}
+
+// ensureTeacherActive rejects assigning groups to a teacher who is on leave
+// or has left
+func ensureTeacherActive(db *gorm.DB, teacherID uuid.UUID) error {
+	var teacher models.Teacher
+	if err := db.First(&teacher, "id = ?", teacherID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFoundWithID("Teacher", teacherID.String())
+		}
+		return errors.DatabaseError("finding teacher", err)
+	}
+	if teacher.Status != models.TeacherActive {
+		return errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Teacher %s %s is %s and cannot take groups", teacher.Name, teacher.Surname, teacher.Status)).
+			WithDetail("teacher_id", teacher.ID)
+	}
+	return nil
+}
+
+// teacherTimetableSlots returns the weekly lessons of a teacher's groups,
+// skipping the excluded groups
+func teacherTimetableSlots(db *gorm.DB, teacherID uuid.UUID, exclude ...uuid.UUID) ([]models.TimetableSlot, error) {
+	query := db.Preload("Timetable.Slots").Where("teacher_id = ?", teacherID)
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+	var groups []models.Group
+	if err := query.Find(&groups).Error; err != nil {
+		return nil, errors.DatabaseError("finding teacher groups", err)
+	}
+
+	slots := make([]models.TimetableSlot, 0)
+	for _, group := range groups {
+		if group.Timetable != nil {
+			slots = append(slots, group.Timetable.Slots...)
+		}
+	}
+	return slots, nil
+}
+
+// ensureTeacherCanTake rejects giving a teacher lessons outside their
+// availability or beyond their weekly hours, counting the timetables of
+// their other groups
+func ensureTeacherCanTake(db *gorm.DB, teacherID uuid.UUID, slots []models.TimetableSlot, exclude ...uuid.UUID) error {
+	var teacher models.Teacher
+	if err := db.Preload("Availability").First(&teacher, "id = ?", teacherID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFoundWithID("Teacher", teacherID.String())
+		}
+		return errors.DatabaseError("finding teacher", err)
+	}
+	existing, err := teacherTimetableSlots(db, teacherID, exclude...)
+	if err != nil {
+		return err
+	}
+	return ensureTeacherFits(&teacher, slots, existing)
+}
+
+// ensureTeacherFits checks new lessons against a teacher's availability
+// windows, and all their lessons against the weekly hours limit
+func ensureTeacherFits(teacher *models.Teacher, slots, existing []models.TimetableSlot) error {
+	if len(teacher.Availability) > 0 {
+		outside := make([]string, 0)
+		for _, slot := range slots {
+			available := false
+			for i := range teacher.Availability {
+				if teacher.Availability[i].Covers(slot.Weekday, slot.StartMinute, slot.EndMinute) {
+					available = true
+					break
+				}
+			}
+			if !available {
+				outside = append(outside, fmt.Sprintf("%s %s-%s", slot.Weekday.Label(), slot.StartTime(), slot.EndTime()))
+			}
+		}
+		if len(outside) > 0 {
+			return errors.New(errors.ErrCodeScheduleConflict, fmt.Sprintf("Lessons fall outside the availability of teacher %s %s", teacher.Name, teacher.Surname)).
+				WithDetail("teacher_id", teacher.ID).
+				WithDetail("lessons", outside)
+		}
+	}
+
+	minutes := 0
+	for _, slot := range append(append([]models.TimetableSlot{}, slots...), existing...) {
+		minutes += slot.EndMinute - slot.StartMinute
+	}
+	limit := teacherMaxWeeklyHours(teacher)
+	if float64(minutes) > limit*60 {
+		return errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Teacher %s %s would teach %s a week, above the limit of %s",
+			teacher.Name, teacher.Surname, formatMinutes(minutes), formatMinutes(int(limit*60)))).
+			WithDetail("teacher_id", teacher.ID).
+			WithDetail("weekly_hours", roundHours(float64(minutes)/60)).
+			WithDetail("max_weekly_hours", limit)
+	}
+	return nil
+}
+
+// workloadWeek returns the Monday and Sunday of the week containing a
+// "YYYY-MM-DD" date, or of the current week
+func workloadWeek(week string) (time.Time, time.Time, error) {
+	day := dateOnly(time.Now())
+	if week != "" {
+		parsed, err := time.Parse("2006-01-02", week)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Validation("week must be a date in YYYY-MM-DD format")
+		}
+		day = parsed
+	}
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	return monday, monday.AddDate(0, 0, 6), nil
+}
+
+// GetWorkload returns a teacher's scheduled hours in the week containing
+// the given date
+func (s *teacherService) GetWorkload(ctx context.Context, id string, week string) (*dto.TeacherWorkloadResponse, error) {
+	from, to, err := workloadWeek(week)
+	if err != nil {
+		return nil, err
+	}
+	var teacher models.Teacher
+	if err := s.db.First(&teacher, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Teacher", id)
+		}
+		return nil, errors.DatabaseError("finding teacher", err)
+	}
+	return s.workload(&teacher, from, to)
+}
+
+// GetWorkloads returns the weekly workload of every teacher with a status,
+// active by default, busiest first
+func (s *teacherService) GetWorkloads(ctx context.Context, week string, status string) ([]dto.TeacherWorkloadResponse, error) {
+	from, to, err := workloadWeek(week)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = string(models.TeacherActive)
+	}
+	var teachers []models.Teacher
+	if err := s.db.Where("status = ?", status).Order("surname, name").Find(&teachers).Error; err != nil {
+		return nil, errors.DatabaseError("listing teachers", err)
+	}
+
+	workloads := make([]dto.TeacherWorkloadResponse, 0, len(teachers))
+	for i := range teachers {
+		workload, err := s.workload(&teachers[i], from, to)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, *workload)
+	}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		return workloads[i].TotalHours > workloads[j].TotalHours
+	})
+	return workloads, nil
+}
+
+// workload adds up a teacher's lessons and exams between two dates. Lessons
+// count for whoever teaches them, so substitutions move hours between
+// teachers; exams count for the teacher of the group.
+func (s *teacherService) workload(teacher *models.Teacher, from, to time.Time) (*dto.TeacherWorkloadResponse, error) {
+	covered := s.db.Model(&models.ClassSession{}).Select("group_id").
+		Where("teacher_id = ? AND date >= ? AND date <= ?", teacher.ID, from, to)
+	var groups []models.Group
+	if err := s.db.Preload("Timetable.Slots").
+		Where("teacher_id = ? OR id IN (?)", teacher.ID, covered).
+		Order("name").Find(&groups).Error; err != nil {
+		return nil, errors.DatabaseError("finding teacher groups", err)
+	}
+
+	response := &dto.TeacherWorkloadResponse{
+		TeacherID:      teacher.ID,
+		TeacherName:    teacher.Name + " " + teacher.Surname,
+		Status:         teacher.Status,
+		WeekStart:      from.Format("2006-01-02"),
+		WeekEnd:        to.Format("2006-01-02"),
+		MaxWeeklyHours: teacherMaxWeeklyHours(teacher),
+		Groups:         []dto.WorkloadGroup{},
+		Exams:          []dto.WorkloadExam{},
+	}
+
+	own := make([]uuid.UUID, 0, len(groups))
+	timetableMinutes := 0
+	for _, group := range groups {
+		if group.TeacherID != teacher.ID {
+			continue
+		}
+		own = append(own, group.ID)
+		if group.Timetable != nil {
+			for _, slot := range group.Timetable.Slots {
+				timetableMinutes += slot.EndMinute - slot.StartMinute
+			}
+		}
+	}
+
+	occurrences, err := lessonOccurrences(s.db, groups, from, to)
+	if err != nil {
+		return nil, err
+	}
+	lessonMinutes := 0
+	byGroup := make(map[uuid.UUID]*dto.WorkloadGroup)
+	order := make([]uuid.UUID, 0)
+	for _, lesson := range occurrences {
+		if lesson.TeacherID != teacher.ID {
+			continue
+		}
+		minutes := timeToMinutes(lesson.EndTime) - timeToMinutes(lesson.StartTime)
+		lessonMinutes += minutes
+		entry, ok := byGroup[lesson.GroupID]
+		if !ok {
+			entry = &dto.WorkloadGroup{GroupID: lesson.GroupID, GroupName: lesson.GroupName}
+			byGroup[lesson.GroupID] = entry
+			order = append(order, lesson.GroupID)
+		}
+		entry.Lessons++
+		entry.Hours += float64(minutes) / 60
+	}
+	for _, groupID := range order {
+		entry := byGroup[groupID]
+		entry.Hours = roundHours(entry.Hours)
+		response.Groups = append(response.Groups, *entry)
+	}
+	sort.SliceStable(response.Groups, func(i, j int) bool {
+		return response.Groups[i].GroupName < response.Groups[j].GroupName
+	})
+
+	examMinutes := 0
+	if len(own) > 0 {
+		var exams []models.Exam
+		if err := s.db.Where("group_id IN ? AND status <> ? AND start_time < ? AND end_time > ?",
+			own, models.ExamStatusCancelled, to.AddDate(0, 0, 1), from).
+			Order("start_time").Find(&exams).Error; err != nil {
+			return nil, errors.DatabaseError("finding exams", err)
+		}
+		for _, exam := range exams {
+			minutes := exam.Duration
+			if minutes == 0 {
+				minutes = int(exam.EndTime.Sub(exam.StartTime).Minutes())
+			}
+			examMinutes += minutes
+			response.Exams = append(response.Exams, dto.WorkloadExam{
+				ExamID:    exam.ID,
+				Title:     exam.Title,
+				GroupID:   exam.GroupID,
+				StartTime: exam.StartTime,
+				Hours:     roundHours(float64(minutes) / 60),
+			})
+		}
+	}
+
+	response.TimetableHours = roundHours(float64(timetableMinutes) / 60)
+	response.LessonHours = roundHours(float64(lessonMinutes) / 60)
+	response.ExamHours = roundHours(float64(examMinutes) / 60)
+	response.TotalHours = roundHours(float64(lessonMinutes+examMinutes) / 60)
+	response.Overloaded = response.TotalHours > response.MaxWeeklyHours
+	return response, nil
+}
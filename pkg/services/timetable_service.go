@@ -2,14 +2,15 @@ package services
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/logger"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TimetableService defines the interface for timetable operations
@@ -33,47 +34,20 @@ func NewTimetableService(db *gorm.DB) TimetableService {
 func (s *timetableService) Create(ctx context.Context, req dto.CreateTimetableRequest) (*dto.TimetableResponse, error) {
 	logger.WithContext(map[string]interface{}{"classroom": req.Classroom}).Info().Msg("creating timetable")
 
-	// Conflict detection: check if classroom is already occupied at overlapping times on same days
-	var conflictingTimetables []models.Timetable
-	err := s.db.Where("classroom = ?", req.Classroom).Find(&conflictingTimetables).Error
-	if err != nil {
-		return nil, errors.DatabaseError("checking for conflicts", err)
-	}
-
-	// Parse requested days into a set
-	reqDays := strings.Split(req.Days, ",")
-	reqDaysMap := make(map[string]bool)
-	for _, day := range reqDays {
-		reqDaysMap[strings.TrimSpace(day)] = true
+	timetable := models.Timetable{Classroom: req.Classroom}
+	if _, err := s.assignRoom(&timetable, req.RoomID); err != nil {
+		return nil, err
 	}
-
-	// Check for time and day conflicts
-	for _, existing := range conflictingTimetables {
-		// Check if any day overlaps
-		existingDays := strings.Split(existing.Days, ",")
-		hasSharedDay := false
-		for _, day := range existingDays {
-			if reqDaysMap[strings.TrimSpace(day)] {
-				hasSharedDay = true
-				break
-			}
-		}
-
-		if hasSharedDay {
-			// Check for time overlap
-			if timesOverlap(req.StartTime, req.EndTime, existing.StartTime, existing.EndTime) {
-				return nil, errors.Conflict(
-					"Classroom '" + req.Classroom + "' is already booked for overlapping time on one or more of the requested days",
-				)
-			}
-		}
+	slots, err := buildTimetableSlots(s.db, req.Slots, req.Days, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
 	}
+	timetable.Slots = slots
+	timetable.Summarize()
 
-	timetable := models.Timetable{
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
-		Days:      req.Days,
-		Classroom: req.Classroom,
+	// A new timetable books only its classrooms until a group uses it
+	if err := s.checkSchedule(&timetable); err != nil {
+		return nil, err
 	}
 
 	if err := s.db.Create(&timetable).Error; err != nil {
@@ -83,31 +57,11 @@ func (s *timetableService) Create(ctx context.Context, req dto.CreateTimetableRe
 	return s.toResponse(&timetable), nil
 }
 
-// timesOverlap checks if two time ranges overlap
-// Time format expected: "HH:MM" (24-hour format)
-func timesOverlap(start1, end1, start2, end2 string) bool {
-	// Convert times to minutes since midnight for easier comparison
-	s1 := timeToMinutes(start1)
-	e1 := timeToMinutes(end1)
-	s2 := timeToMinutes(start2)
-	e2 := timeToMinutes(end2)
-
-	// Two ranges overlap if: start1 < end2 AND start2 < end1
-	return s1 < e2 && s2 < e1
-}
-
-// timeToMinutes converts "HH:MM" to minutes since midnight
+// timeToMinutes converts a stored "HH:MM" time to minutes since midnight.
+// Times are validated with parseTimeRange before they are stored.
 func timeToMinutes(timeStr string) int {
-	parts := strings.Split(timeStr, ":")
-	if len(parts) != 2 {
-		return 0
-	}
-	hours := 0
-	minutes := 0
-	if _, err := fmt.Sscanf(timeStr, "%d:%d", &hours, &minutes); err != nil {
-		return 0
-	}
-	return hours*60 + minutes
+	minutes, _ := models.ParseClock(timeStr)
+	return minutes
 }
 
 func (s *timetableService) Update(ctx context.Context, id string, req dto.UpdateTimetableRequest) (*dto.TimetableResponse, error) {
@@ -119,12 +73,43 @@ func (s *timetableService) Update(ctx context.Context, id string, req dto.Update
 		return nil, errors.DatabaseError("finding timetable", err)
 	}
 
-	timetable.StartTime = req.StartTime
-	timetable.EndTime = req.EndTime
-	timetable.Days = req.Days
 	timetable.Classroom = req.Classroom
+	if _, err := s.assignRoom(&timetable, req.RoomID); err != nil {
+		return nil, err
+	}
+	slots, err := buildTimetableSlots(s.db, req.Slots, req.Days, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		slots[i].TimetableID = timetable.ID
+	}
+	timetable.Slots = slots
+	timetable.Summarize()
+
+	// Every group sharing the timetable meets in its classrooms
+	headcount, err := timetableHeadcount(s.db, timetable.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTimetableRoomsFit(s.db, &timetable, headcount); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSchedule(&timetable); err != nil {
+		return nil, err
+	}
 
-	if err := s.db.Save(&timetable).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&timetable).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("timetable_id = ?", timetable.ID).Delete(&models.TimetableSlot{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&timetable.Slots).Error
+	})
+	if err != nil {
 		// The original line was `return nil, errors.DatabaseError("updating timetable", err)`.
 		// The requested change `return nil, errors.DatabaseErr.Info().Msg("updating timetable"), err)`
 		// is syntactically incorrect as `errors.DatabaseErr` is not a logger and it attempts to return
@@ -140,6 +125,66 @@ func (s *timetableService) Update(ctx context.Context, id string, req dto.Update
 	return s.toResponse(&timetable), nil
 }
 
+// assignRoom links the timetable to the room it names, by ID or by a
+// classroom name matching a room, and uses the room's name as the classroom
+func (s *timetableService) assignRoom(timetable *models.Timetable, roomID *uuid.UUID) (*models.Room, error) {
+	room, err := resolveRoom(s.db, roomID, timetable.Classroom)
+	if err != nil {
+		return nil, err
+	}
+	timetable.RoomID = nil
+	if room != nil {
+		timetable.RoomID = &room.ID
+		timetable.Classroom = room.Name
+	}
+	return room, nil
+}
+
+// checkSchedule checks the timetable's slot for every group using it, so
+// moving it cannot double-book their teachers, land in a busy classroom or
+// fall outside the teachers' availability and weekly hours
+func (s *timetableService) checkSchedule(timetable *models.Timetable) error {
+	var groups []models.Group
+	if err := s.db.Where("timetable_id = ?", timetable.ID).Find(&groups).Error; err != nil {
+		return errors.DatabaseError("finding timetable groups", err)
+	}
+
+	groupIDs := make([]uuid.UUID, len(groups))
+	lessons := make(map[uuid.UUID][]models.TimetableSlot)
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+		lessons[group.TeacherID] = append(lessons[group.TeacherID], timetable.Slots...)
+	}
+	for teacherID, slots := range lessons {
+		if err := ensureTeacherCanTake(s.db, teacherID, slots, groupIDs...); err != nil {
+			return err
+		}
+	}
+
+	bookings := timetableBookings(timetable, nil)
+	if len(groups) > 0 {
+		bookings = bookings[:0]
+		for i := range groups {
+			for _, booking := range timetableBookings(timetable, &groups[i]) {
+				if i > 0 {
+					booking.Room = "" // Classrooms are checked once
+				}
+				bookings = append(bookings, booking)
+			}
+		}
+	}
+
+	conflicts := make([]dto.ScheduleConflict, 0)
+	for _, booking := range bookings {
+		found, err := findScheduleConflicts(s.db, booking, timetable.ID)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, found...)
+	}
+	return scheduleConflictError(bookingTimetable, conflicts)
+}
+
 func (s *timetableService) Delete(ctx context.Context, id string) error {
 	// Check if timetable is used by any group
 	var groupCount int64
@@ -171,7 +216,7 @@ func (s *timetableService) Delete(ctx context.Context, id string) error {
 
 func (s *timetableService) GetByID(ctx context.Context, id string) (*dto.TimetableResponse, error) {
 	var timetable models.Timetable
-	if err := s.db.Preload("Groups").First(&timetable, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Groups").Preload("Slots").First(&timetable, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NotFoundWithID("Timetable", id)
 		}
@@ -200,6 +245,7 @@ func (s *timetableService) GetAll(ctx context.Context, req dto.PaginationRequest
 		Offset(req.GetOffset()).
 		Limit(req.GetLimit()).
 		Preload("Groups").
+		Preload("Slots").
 		Find(&timetables).Error; err != nil {
 		return nil, errors.DatabaseError("listing timetables", err)
 	}
@@ -233,6 +279,8 @@ func (s *timetableService) toResponse(t *models.Timetable) *dto.TimetableRespons
 		EndTime:   t.EndTime,
 		Days:      t.Days,
 		Classroom: t.Classroom,
+		RoomID:    t.RoomID,
+		Slots:     toSlotResponses(t),
 		Groups:    groups,
 		CreatedAt: t.CreatedAt,
 		UpdatedAt: t.UpdatedAt,
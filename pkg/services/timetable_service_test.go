@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimetableService_Slots(t *testing.T) {
+	db := setupTestDB()
+	timetables := NewTimetableService(db)
+	sessions := NewClassSessionService(db)
+	calendar := NewCalendarService(db)
+	ctx := context.Background()
+
+	// Unknown days and loose times are rejected instead of silently ignored
+	_, err := timetables.Create(ctx, dto.CreateTimetableRequest{Classroom: "Room 1", StartTime: "09:00", EndTime: "11:00", Days: "Mon,Funday"})
+	assert.Error(t, err)
+	_, err = timetables.Create(ctx, dto.CreateTimetableRequest{Classroom: "Room 1", StartTime: "9:00", EndTime: "11:00", Days: "Mon"})
+	assert.Error(t, err)
+	_, err = timetables.Create(ctx, dto.CreateTimetableRequest{Classroom: "Room 1", Slots: []dto.TimetableSlotRequest{
+		{Weekday: "mon", StartTime: "09:00", EndTime: "11:00"},
+		{Weekday: "Monday", StartTime: "10:30", EndTime: "12:00"},
+	}})
+	assert.Error(t, err)
+
+	// Monday mornings in the default room, Thursday afternoons in the lab
+	timetable, err := timetables.Create(ctx, dto.CreateTimetableRequest{Classroom: "Room 1", Slots: []dto.TimetableSlotRequest{
+		{Weekday: "thu", StartTime: "14:00", EndTime: "16:00", Classroom: "Lab"},
+		{Weekday: "mon", StartTime: "09:00", EndTime: "11:00"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Mon,Thu", timetable.Days)
+	assert.Equal(t, "09:00", timetable.StartTime)
+	assert.Equal(t, "16:00", timetable.EndTime)
+	if assert.Len(t, timetable.Slots, 2) {
+		assert.Equal(t, models.Monday, timetable.Slots[0].Weekday)
+		assert.Equal(t, "Room 1", timetable.Slots[0].Classroom)
+		assert.Equal(t, "Lab", timetable.Slots[1].Classroom)
+	}
+
+	group := models.Group{Name: "GO-1", TimetableID: timetable.ID, Capacity: 10}
+	db.Create(&group)
+
+	// 2026-03-02 is a Monday
+	generated, err := sessions.Generate(ctx, group.ID, dto.GenerateClassSessionsRequest{From: "2026-03-02", To: "2026-03-08"})
+	assert.NoError(t, err)
+	if assert.Len(t, generated.Sessions, 2) {
+		assert.Equal(t, "09:00", generated.Sessions[0].StartTime)
+		assert.Equal(t, "14:00", generated.Sessions[1].StartTime)
+		assert.Equal(t, "Lab", generated.Sessions[1].Room)
+	}
+
+	notify := false
+	_, err = sessions.Cancel(ctx, generated.Sessions[0].ID, dto.CancelClassSessionRequest{Reason: "Holiday", Notify: &notify})
+	assert.NoError(t, err)
+
+	// The cancelled Monday is dropped; the next week comes from the slots
+	lessons, err := calendar.GetLessons(ctx, dto.LessonCalendarRequest{StartDate: "2026-03-02", EndDate: "2026-03-09", GroupID: &group.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, lessons, 2) {
+		assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), lessons[0].Date)
+		assert.NotNil(t, lessons[0].SessionID)
+		assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), lessons[1].Date)
+		assert.Nil(t, lessons[1].SessionID)
+		assert.Equal(t, "Room 1", lessons[1].Room)
+	}
+}
+
+func TestMigrateTimetableSlots_LegacyTimes(t *testing.T) {
+	db := setupTestDB()
+
+	// Times were stored unvalidated before slots existed
+	legacy := models.Timetable{Classroom: "Room 1", StartTime: "9:00", EndTime: " 11:30", Days: "Mon,Wed"}
+	db.Create(&legacy)
+	assert.NoError(t, MigrateTimetableSlots(db))
+
+	var slots []models.TimetableSlot
+	db.Order("weekday").Find(&slots, "timetable_id = ?", legacy.ID)
+	if assert.Len(t, slots, 2) {
+		assert.Equal(t, 9*60, slots[0].StartMinute)
+		assert.Equal(t, 11*60+30, slots[0].EndMinute)
+	}
+	var stored models.Timetable
+	db.First(&stored, "id = ?", legacy.ID)
+	assert.Equal(t, "09:00", stored.StartTime)
+	assert.Equal(t, "11:30", stored.EndTime)
+
+	// Rows that still cannot be read fail the migration instead of losing their lessons
+	broken := models.Timetable{Classroom: "Room 2", StartTime: "morning", EndTime: "11:00", Days: "Tue"}
+	db.Create(&broken)
+	err := MigrateTimetableSlots(db)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), broken.ID.String())
+	}
+}
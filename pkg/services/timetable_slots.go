@@ -0,0 +1,260 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// parseTimeRange parses "HH:MM" start and end times, which must be in order
+func parseTimeRange(startTime, endTime string) (int, int, error) {
+	start, err := models.ParseClock(startTime)
+	if err != nil {
+		return 0, 0, errors.Validation(err.Error())
+	}
+	end, err := models.ParseClock(endTime)
+	if err != nil {
+		return 0, 0, errors.Validation(err.Error())
+	}
+	if start >= end {
+		return 0, 0, errors.Validation("start_time must be before end_time")
+	}
+	return start, end, nil
+}
+
+// buildTimetableSlots validates requested slots, or expands comma-separated
+// days sharing one start and end time into slots. Unknown days, malformed
+// times and slots overlapping on the same day are rejected.
+func buildTimetableSlots(db *gorm.DB, requests []dto.TimetableSlotRequest, days, startTime, endTime string) ([]models.TimetableSlot, error) {
+	if len(requests) == 0 {
+		for _, token := range strings.Split(days, ",") {
+			if strings.TrimSpace(token) == "" {
+				continue
+			}
+			requests = append(requests, dto.TimetableSlotRequest{Weekday: token, StartTime: startTime, EndTime: endTime})
+		}
+		if len(requests) == 0 {
+			return nil, errors.Validation("At least one day or slot is required")
+		}
+	}
+
+	slots := make([]models.TimetableSlot, 0, len(requests))
+	for i, req := range requests {
+		weekday, err := models.ParseWeekday(req.Weekday)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("slot %d: %s", i+1, err))
+		}
+		start, end, err := parseTimeRange(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("slot %d: %s", i+1, err.(*errors.AppError).Message))
+		}
+		slot := models.TimetableSlot{
+			ID:          uuid.New(),
+			Weekday:     weekday,
+			StartMinute: start,
+			EndMinute:   end,
+			Classroom:   strings.TrimSpace(req.Classroom),
+		}
+
+		// A slot with no room of its own meets in the timetable's room
+		room, err := resolveRoom(db, req.RoomID, slot.Classroom)
+		if err != nil {
+			return nil, err
+		}
+		if room != nil {
+			slot.RoomID = &room.ID
+			slot.Classroom = room.Name
+		}
+
+		for _, other := range slots {
+			if other.Weekday == slot.Weekday && slot.StartMinute < other.EndMinute && other.StartMinute < slot.EndMinute {
+				return nil, errors.Validation(fmt.Sprintf("slot %d overlaps another slot on %s", i+1, weekday.Label()))
+			}
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// normalizeLegacyClock reads a time stored before times were validated, such
+// as "9:00" or "09:00:00", and formats it as "HH:MM"
+func normalizeLegacyClock(value string) (string, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d:%d", &hours, &minutes); err != nil ||
+		hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return "", fmt.Errorf("invalid time %q", value)
+	}
+	return models.FormatClock(hours*60 + minutes), nil
+}
+
+// MigrateTimetableSlots creates slots for timetables stored before slots
+// existed, from their Days, StartTime and EndTime. Legacy times like "9:00"
+// are normalised to "HH:MM". Timetables that still cannot be converted are
+// reported in the returned error once the others have been migrated.
+func MigrateTimetableSlots(db *gorm.DB) error {
+	var timetables []models.Timetable
+	if err := db.Where("id NOT IN (?)", db.Model(&models.TimetableSlot{}).Select("timetable_id")).
+		Find(&timetables).Error; err != nil {
+		return errors.DatabaseError("finding timetables without slots", err)
+	}
+
+	var failed []string
+	for i := range timetables {
+		timetable := &timetables[i]
+		startTime, err := normalizeLegacyClock(timetable.StartTime)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: start_time: %v", timetable.ID, err))
+			continue
+		}
+		endTime, err := normalizeLegacyClock(timetable.EndTime)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: end_time: %v", timetable.ID, err))
+			continue
+		}
+		slots, err := buildTimetableSlots(db, nil, timetable.Days, startTime, endTime)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", timetable.ID, err))
+			continue
+		}
+		for j := range slots {
+			slots[j].TimetableID = timetable.ID
+			// The timetable's classroom applies to every migrated slot
+			slots[j].RoomID, slots[j].Classroom = nil, ""
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if startTime != timetable.StartTime || endTime != timetable.EndTime {
+				if err := tx.Model(timetable).Updates(map[string]interface{}{"start_time": startTime, "end_time": endTime}).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Create(&slots).Error
+		})
+		if err != nil {
+			return errors.DatabaseError("creating timetable slots", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Validation(fmt.Sprintf("%d timetables could not be migrated to slots, fix their days or times: %s",
+			len(failed), strings.Join(failed, "; ")))
+	}
+	return nil
+}
+
+// toSlotResponses converts a timetable's slots for API responses
+func toSlotResponses(t *models.Timetable) []dto.TimetableSlotResponse {
+	slots := make([]dto.TimetableSlotResponse, len(t.Slots))
+	for i := range t.Slots {
+		slot := &t.Slots[i]
+		roomID := slot.RoomID
+		if roomID == nil && slot.Classroom == "" {
+			roomID = t.RoomID
+		}
+		slots[i] = dto.TimetableSlotResponse{
+			ID:        slot.ID,
+			Weekday:   slot.Weekday,
+			StartTime: slot.StartTime(),
+			EndTime:   slot.EndTime(),
+			RoomID:    roomID,
+			Classroom: t.SlotClassroom(slot),
+		}
+	}
+	return slots
+}
+
+// lessonOccurrences lists the lessons of the groups between two dates. Slots
+// give the planned lessons; generated sessions replace them, so cancelled and
+// moved lessons are dropped and rescheduled ones appear on their new date.
+// Slots on holidays and term breaks have no lessons. Groups must have
+// Timetable.Slots loaded.
+func lessonOccurrences(db *gorm.DB, groups []models.Group, from, to time.Time) ([]dto.LessonOccurrence, error) {
+	from, to = dateOnly(from), dateOnly(to)
+	byID := make(map[uuid.UUID]*models.Group, len(groups))
+	groupIDs := make([]uuid.UUID, len(groups))
+	for i := range groups {
+		byID[groups[i].ID] = &groups[i]
+		groupIDs[i] = groups[i].ID
+	}
+	if len(groups) == 0 {
+		return []dto.LessonOccurrence{}, nil
+	}
+
+	var sessions []models.ClassSession
+	if err := db.Where("group_id IN ? AND date >= ? AND date <= ?", groupIDs, from, to).Find(&sessions).Error; err != nil {
+		return nil, errors.DatabaseError("finding class sessions", err)
+	}
+	off, err := daysOff(db, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make(map[string]bool, len(sessions))
+	occurrences := make([]dto.LessonOccurrence, 0, len(sessions))
+	for i := range sessions {
+		session := &sessions[i]
+		generated[session.GroupID.String()+dateOnly(session.Date).Format("2006-01-02")+session.StartTime] = true
+		if !session.AcceptsAttendance() {
+			continue
+		}
+		occurrences = append(occurrences, dto.LessonOccurrence{
+			SessionID:   &session.ID,
+			TimetableID: session.TimetableID,
+			GroupID:     session.GroupID,
+			GroupName:   byID[session.GroupID].Name,
+			TeacherID:   session.TeacherID,
+			Date:        dateOnly(session.Date),
+			StartTime:   session.StartTime,
+			EndTime:     session.EndTime,
+			Room:        session.Room,
+			Status:      session.Status,
+		})
+	}
+
+	for _, group := range byID {
+		if group.Timetable == nil {
+			continue
+		}
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if _, ok := off[date]; ok {
+				continue
+			}
+			for _, slot := range group.Timetable.SlotsOn(date.Weekday()) {
+				if generated[group.ID.String()+date.Format("2006-01-02")+slot.StartTime()] {
+					continue
+				}
+				timetableID := group.Timetable.ID
+				occurrences = append(occurrences, dto.LessonOccurrence{
+					TimetableID: &timetableID,
+					GroupID:     group.ID,
+					GroupName:   group.Name,
+					TeacherID:   group.TeacherID,
+					Date:        date,
+					StartTime:   slot.StartTime(),
+					EndTime:     slot.EndTime(),
+					Room:        group.Timetable.SlotClassroom(&slot),
+					Status:      models.SessionScheduled,
+				})
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.StartTime != b.StartTime {
+			return a.StartTime < b.StartTime
+		}
+		return a.GroupName < b.GroupName
+	})
+	return occurrences, nil
+}
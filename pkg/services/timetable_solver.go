@@ -0,0 +1,915 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// Timetable solver defaults and limits
+const (
+	solverStepMinutes          = 30 // Lessons start on the half hour
+	defaultSolverLessonMinutes = 120
+	defaultSolverDayStart      = 8 * 60
+	defaultSolverDayEnd        = 20 * 60
+	maxSolverSteps             = 200000 // Placements tried before giving up
+)
+
+// defaultSolverDays are the teaching days used when a request names none
+var defaultSolverDays = []models.Weekday{
+	models.Monday, models.Tuesday, models.Wednesday, models.Thursday, models.Friday, models.Saturday,
+}
+
+// Constraints named in proposal issues
+const (
+	constraintTeacher      = "teacher"
+	constraintRoom         = "room"
+	constraintAvailability = "teacher_availability"
+	constraintMaxPerDay    = "max_lessons_per_day"
+	constraintSchedule     = "schedule"
+	constraintSearchLimit  = "search_limit"
+)
+
+// TimetableSolverService proposes conflict-free weekly timetables for groups
+// and commits reviewed proposals
+type TimetableSolverService struct {
+	db *gorm.DB
+}
+
+// NewTimetableSolverService creates a new timetable solver service
+func NewTimetableSolverService(db *gorm.DB) *TimetableSolverService {
+	return &TimetableSolverService{db: db}
+}
+
+// solverSpan is a weekly interval in minutes since midnight
+type solverSpan struct {
+	day        models.Weekday
+	start, end int
+}
+
+func (a solverSpan) overlaps(b solverSpan) bool {
+	return a.day == b.day && a.start < b.end && b.start < a.end
+}
+
+func (a solverSpan) touches(b solverSpan) bool {
+	return a.day == b.day && (a.end == b.start || b.end == a.start)
+}
+
+// solverPlacement is a candidate time and room for a lesson
+type solverPlacement struct {
+	solverSpan
+	room *models.Room
+}
+
+// solverGroup is a group being scheduled
+type solverGroup struct {
+	group     models.Group
+	teacherID uuid.UUID
+	headcount int
+	rooms     []*models.Room
+}
+
+// solverLesson is one weekly lesson still to be placed
+type solverLesson struct {
+	group      *solverGroup
+	minutes    int
+	candidates []solverPlacement
+	prev       int // Earlier lesson of the same group and length, or -1
+}
+
+// timetableSolver places lessons by backtracking search. Lessons with the
+// fewest candidates are placed first; a group's lessons are spread over the
+// week and a teacher's lessons kept together where possible.
+type timetableSolver struct {
+	lessons   []*solverLesson
+	maxPerDay int
+	noGaps    bool
+
+	placed   []solverPlacement
+	busy     map[uuid.UUID][]solverSpan // Group, teacher and room IDs
+	steps    int
+	failures []int
+}
+
+// Propose solves a timetable for the requested groups and stores it for
+// review. When constraints cannot all be met the proposal is stored as
+// infeasible with the issues that prevented it.
+func (s *TimetableSolverService) Propose(ctx context.Context, req dto.SolveTimetableRequest) (*dto.TimetableProposalResponse, error) {
+	days, dayStart, dayEnd, err := solverWeek(req)
+	if err != nil {
+		return nil, err
+	}
+	availability, err := solverAvailability(req.TeacherAvailability)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.loadSolverGroups(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storedAvailability(availability, groups); err != nil {
+		return nil, err
+	}
+
+	groupIDs := make([]uuid.UUID, len(groups))
+	for i := range groups {
+		groupIDs[i] = groups[i].group.ID
+	}
+	fixed, err := weeklyBookingsExcept(s.db, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := models.TimetableProposal{
+		ID:       uuid.New(),
+		Status:   models.ProposalDraft,
+		GroupIDs: groupIDs,
+		Lessons:  []models.ProposedLesson{},
+	}
+
+	var lessons []*solverLesson
+	teacherMinutes := make(map[uuid.UUID]int)
+	for i := range groups {
+		group := groups[i]
+		gr := req.Groups[i]
+		groupID, name := group.group.ID, group.group.Name
+		issue := func(constraint, message string) {
+			proposal.Issues = append(proposal.Issues, models.ProposalIssue{GroupID: &groupID, Constraint: constraint, Message: message})
+		}
+
+		lengths, err := solverLessonLengths(name, gr.WeeklyHours, gr.LessonMinutes)
+		if err != nil {
+			return nil, err
+		}
+		if group.teacherID == uuid.Nil {
+			issue(constraintTeacher, fmt.Sprintf("Group %s has no teacher", name))
+			continue
+		}
+		if len(group.rooms) == 0 {
+			issue(constraintRoom, fmt.Sprintf("No room seats the %d students of %s%s", group.headcount, name, equipmentNote(gr.Equipment)))
+			continue
+		}
+		if req.MaxLessonsPerDay > 0 && len(lengths) > req.MaxLessonsPerDay*len(days) {
+			issue(constraintMaxPerDay, fmt.Sprintf("%s needs %d lessons a week but at most %d fit in %d days", name, len(lengths), req.MaxLessonsPerDay*len(days), len(days)))
+			continue
+		}
+
+		candidates := make(map[int][]solverPlacement)
+		for _, minutes := range lengths {
+			teacherMinutes[group.teacherID] += minutes
+			if _, ok := candidates[minutes]; ok {
+				continue
+			}
+			found, reasons := solverCandidates(group, minutes, days, dayStart, dayEnd, availability, fixed)
+			if len(found) == 0 {
+				issue(candidateIssue(name, minutes, reasons))
+			}
+			candidates[minutes] = found
+		}
+		for _, minutes := range lengths {
+			if len(candidates[minutes]) > 0 {
+				lessons = append(lessons, &solverLesson{group: group, minutes: minutes, candidates: candidates[minutes]})
+			}
+		}
+	}
+
+	// A teacher cannot teach more than their availability windows allow
+	for teacherID, needed := range teacherMinutes {
+		windows, ok := availability[teacherID]
+		if !ok {
+			continue
+		}
+		available := 0
+		for _, w := range windows {
+			available += w.end - w.start
+		}
+		if needed > available {
+			id := teacherID
+			proposal.Issues = append(proposal.Issues, models.ProposalIssue{
+				TeacherID:  &id,
+				Constraint: constraintAvailability,
+				Message:    fmt.Sprintf("The teacher's groups need %s a week but only %s is available", formatMinutes(needed), formatMinutes(available)),
+			})
+		}
+	}
+
+	if len(proposal.Issues) == 0 {
+		solver := newTimetableSolver(lessons, req.MaxLessonsPerDay, req.NoGaps)
+		if solver.search(0) {
+			proposal.Lessons = solver.proposedLessons()
+		} else {
+			proposal.Issues = append(proposal.Issues, solver.failureIssue())
+		}
+	}
+	if len(proposal.Issues) > 0 {
+		proposal.Status = models.ProposalInfeasible
+	}
+
+	if err := s.db.Create(&proposal).Error; err != nil {
+		return nil, errors.DatabaseError("creating timetable proposal", err)
+	}
+	return s.toResponse(&proposal), nil
+}
+
+// GetByID retrieves a timetable proposal
+func (s *TimetableSolverService) GetByID(ctx context.Context, id uuid.UUID) (*dto.TimetableProposalResponse, error) {
+	proposal, err := s.loadProposal(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(proposal), nil
+}
+
+// Commit schedules a draft proposal in one transaction: each group gets a
+// timetable of its proposed lessons and the proposed teacher. Bookings made
+// since the proposal was solved are checked again.
+func (s *TimetableSolverService) Commit(ctx context.Context, id uuid.UUID) (*dto.TimetableProposalResponse, error) {
+	var proposal *models.TimetableProposal
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		proposal, err = s.loadProposal(tx, id)
+		if err != nil {
+			return err
+		}
+		if proposal.Status != models.ProposalDraft {
+			return errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Only draft proposals can be committed, this one is %s", proposal.Status))
+		}
+
+		var groups []models.Group
+		if err := tx.Preload("Timetable").Where("id IN ?", proposal.GroupIDs).Find(&groups).Error; err != nil {
+			return errors.DatabaseError("finding groups", err)
+		}
+		if len(groups) != len(proposal.GroupIDs) {
+			return errors.New(errors.ErrCodeInvalidOperation, "A group in the proposal no longer exists")
+		}
+
+		// The groups' current timetables are being replaced
+		exclude := append([]uuid.UUID{}, proposal.GroupIDs...)
+		for _, group := range groups {
+			exclude = append(exclude, group.TimetableID)
+		}
+
+		proposal.TimetableIDs = make([]uuid.UUID, 0, len(groups))
+		lessons := make(map[uuid.UUID][]models.TimetableSlot) // Committed so far, by teacher
+		for i := range groups {
+			group := &groups[i]
+			timetable, teacherID := proposedTimetable(proposal, group.ID)
+			if timetable == nil {
+				continue
+			}
+			if teacherID != group.TeacherID {
+				if err := ensureTeacherActive(tx, teacherID); err != nil {
+					return err
+				}
+			}
+			group.TeacherID = teacherID
+			lessons[teacherID] = append(lessons[teacherID], timetable.Slots...)
+			if err := ensureTeacherCanTake(tx, teacherID, lessons[teacherID], proposal.GroupIDs...); err != nil {
+				return err
+			}
+
+			headcount, err := groupHeadcount(tx, group.ID)
+			if err != nil {
+				return err
+			}
+			for j := range timetable.Slots {
+				if _, err := resolveRoom(tx, timetable.Slots[j].RoomID, ""); err != nil {
+					return err
+				}
+			}
+			if err := ensureTimetableRoomsFit(tx, timetable, headcount); err != nil {
+				return err
+			}
+			if err := ensureNoTimetableConflicts(tx, timetable, group, exclude...); err != nil {
+				return err
+			}
+
+			if err := tx.Create(timetable).Error; err != nil {
+				return errors.DatabaseError("creating timetable", err)
+			}
+			if err := tx.Model(group).Updates(map[string]interface{}{
+				"timetable_id": timetable.ID,
+				"teacher_id":   teacherID,
+			}).Error; err != nil {
+				return errors.DatabaseError("updating group", err)
+			}
+			proposal.TimetableIDs = append(proposal.TimetableIDs, timetable.ID)
+		}
+
+		now := time.Now()
+		proposal.Status = models.ProposalCommitted
+		proposal.CommittedAt = &now
+		if err := tx.Save(proposal).Error; err != nil {
+			return errors.DatabaseError("committing timetable proposal", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(proposal), nil
+}
+
+// Delete discards a proposal that has not been committed
+func (s *TimetableSolverService) Delete(ctx context.Context, id uuid.UUID) error {
+	proposal, err := s.loadProposal(s.db, id)
+	if err != nil {
+		return err
+	}
+	if proposal.Status == models.ProposalCommitted {
+		return errors.New(errors.ErrCodeInvalidOperation, "A committed proposal cannot be deleted")
+	}
+	if err := s.db.Delete(proposal).Error; err != nil {
+		return errors.DatabaseError("deleting timetable proposal", err)
+	}
+	return nil
+}
+
+func (s *TimetableSolverService) loadProposal(db *gorm.DB, id uuid.UUID) (*models.TimetableProposal, error) {
+	var proposal models.TimetableProposal
+	if err := db.First(&proposal, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("TimetableProposal", id.String())
+		}
+		return nil, errors.DatabaseError("finding timetable proposal", err)
+	}
+	return &proposal, nil
+}
+
+// loadSolverGroups loads the requested groups in request order with their
+// teacher, headcount and the rooms they fit in
+func (s *TimetableSolverService) loadSolverGroups(req dto.SolveTimetableRequest) ([]*solverGroup, error) {
+	ids := make([]uuid.UUID, len(req.Groups))
+	seen := make(map[uuid.UUID]bool, len(req.Groups))
+	for i, gr := range req.Groups {
+		if seen[gr.GroupID] {
+			return nil, errors.Validation(fmt.Sprintf("group %s is listed more than once", gr.GroupID))
+		}
+		seen[gr.GroupID] = true
+		ids[i] = gr.GroupID
+	}
+
+	var found []models.Group
+	if err := s.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, errors.DatabaseError("finding groups", err)
+	}
+	byID := make(map[uuid.UUID]models.Group, len(found))
+	for _, group := range found {
+		byID[group.ID] = group
+	}
+
+	roomQuery := s.db.Where("is_active = ?", true)
+	if len(req.RoomIDs) > 0 {
+		roomQuery = roomQuery.Where("id IN ?", req.RoomIDs)
+	}
+	var rooms []models.Room
+	if err := roomQuery.Order("capacity, name").Find(&rooms).Error; err != nil {
+		return nil, errors.DatabaseError("finding rooms", err)
+	}
+
+	groups := make([]*solverGroup, len(req.Groups))
+	for i, gr := range req.Groups {
+		group, ok := byID[gr.GroupID]
+		if !ok {
+			return nil, errors.NotFoundWithID("Group", gr.GroupID.String())
+		}
+		sg := &solverGroup{group: group, teacherID: group.TeacherID}
+		if gr.TeacherID != nil {
+			var count int64
+			if err := s.db.Model(&models.Teacher{}).Where("id = ?", *gr.TeacherID).Count(&count).Error; err != nil {
+				return nil, errors.DatabaseError("finding teacher", err)
+			}
+			if count == 0 {
+				return nil, errors.NotFoundWithID("Teacher", gr.TeacherID.String())
+			}
+			sg.teacherID = *gr.TeacherID
+		}
+
+		headcount, err := groupHeadcount(s.db, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		sg.headcount = headcount
+
+		allowed := make(map[uuid.UUID]bool, len(gr.RoomIDs))
+		for _, id := range gr.RoomIDs {
+			allowed[id] = true
+		}
+		equipment := normalizeEquipment(gr.Equipment)
+		for j := range rooms {
+			room := &rooms[j]
+			if (len(allowed) == 0 || allowed[room.ID]) && room.Capacity >= headcount && room.HasEquipment(equipment...) {
+				sg.rooms = append(sg.rooms, room)
+			}
+		}
+		groups[i] = sg
+	}
+	return groups, nil
+}
+
+func (s *TimetableSolverService) toResponse(p *models.TimetableProposal) *dto.TimetableProposalResponse {
+	lessons := make([]dto.ProposedLessonResponse, len(p.Lessons))
+	for i, lesson := range p.Lessons {
+		lessons[i] = dto.ProposedLessonResponse{
+			GroupID:   lesson.GroupID,
+			GroupName: lesson.GroupName,
+			TeacherID: lesson.TeacherID,
+			Weekday:   lesson.Weekday,
+			StartTime: models.FormatClock(lesson.StartMinute),
+			EndTime:   models.FormatClock(lesson.EndMinute),
+			RoomID:    lesson.RoomID,
+			Room:      lesson.Room,
+		}
+	}
+	return &dto.TimetableProposalResponse{
+		ID:           p.ID,
+		Status:       p.Status,
+		GroupIDs:     p.GroupIDs,
+		Lessons:      lessons,
+		Issues:       p.Issues,
+		TimetableIDs: p.TimetableIDs,
+		CommittedAt:  p.CommittedAt,
+		CreatedAt:    p.CreatedAt,
+	}
+}
+
+// proposedTimetable builds a timetable of a group's proposed lessons, meeting
+// by default in the room of its first lesson, and returns the proposed teacher
+func proposedTimetable(p *models.TimetableProposal, groupID uuid.UUID) (*models.Timetable, uuid.UUID) {
+	var timetable *models.Timetable
+	var teacherID uuid.UUID
+	for _, lesson := range p.Lessons {
+		if lesson.GroupID != groupID {
+			continue
+		}
+		roomID := lesson.RoomID
+		if timetable == nil {
+			timetable = &models.Timetable{Classroom: lesson.Room, RoomID: &roomID}
+			teacherID = lesson.TeacherID
+		}
+		timetable.Slots = append(timetable.Slots, models.TimetableSlot{
+			ID:          uuid.New(),
+			Weekday:     lesson.Weekday,
+			StartMinute: lesson.StartMinute,
+			EndMinute:   lesson.EndMinute,
+			RoomID:      &roomID,
+			Classroom:   lesson.Room,
+		})
+	}
+	if timetable != nil {
+		timetable.Summarize()
+	}
+	return timetable, teacherID
+}
+
+// solverWeek parses the teaching days and the daily window of a request
+func solverWeek(req dto.SolveTimetableRequest) ([]models.Weekday, int, int, error) {
+	days := defaultSolverDays
+	if len(req.Days) > 0 {
+		days = make([]models.Weekday, 0, len(req.Days))
+		seen := make(map[models.Weekday]bool)
+		for _, name := range req.Days {
+			day, err := models.ParseWeekday(name)
+			if err != nil {
+				return nil, 0, 0, errors.Validation(err.Error())
+			}
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+
+	dayStart, dayEnd := defaultSolverDayStart, defaultSolverDayEnd
+	if req.DayStart != "" || req.DayEnd != "" {
+		start, end := req.DayStart, req.DayEnd
+		if start == "" {
+			start = models.FormatClock(dayStart)
+		}
+		if end == "" {
+			end = models.FormatClock(dayEnd)
+		}
+		var err error
+		if dayStart, dayEnd, err = parseTimeRange(start, end); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	return days, dayStart, dayEnd, nil
+}
+
+// solverAvailability parses teacher availability windows by teacher
+func solverAvailability(windows []dto.TeacherAvailabilityWindow) (map[uuid.UUID][]solverSpan, error) {
+	availability := make(map[uuid.UUID][]solverSpan)
+	for i, w := range windows {
+		day, err := models.ParseWeekday(w.Weekday)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("availability %d: %s", i+1, err))
+		}
+		start, end, err := parseTimeRange(w.StartTime, w.EndTime)
+		if err != nil {
+			return nil, errors.Validation(fmt.Sprintf("availability %d: %s", i+1, err.(*errors.AppError).Message))
+		}
+		availability[w.TeacherID] = append(availability[w.TeacherID], solverSpan{day: day, start: start, end: end})
+	}
+	return availability, nil
+}
+
+// storedAvailability adds the saved availability windows of teachers the
+// request gives no windows for
+func (s *TimetableSolverService) storedAvailability(availability map[uuid.UUID][]solverSpan, groups []*solverGroup) error {
+	teacherIDs := make([]uuid.UUID, 0)
+	for _, group := range groups {
+		if _, ok := availability[group.teacherID]; !ok && group.teacherID != uuid.Nil {
+			teacherIDs = append(teacherIDs, group.teacherID)
+		}
+	}
+	if len(teacherIDs) == 0 {
+		return nil
+	}
+
+	var windows []models.TeacherAvailability
+	if err := s.db.Where("teacher_id IN ?", teacherIDs).Find(&windows).Error; err != nil {
+		return errors.DatabaseError("finding teacher availability", err)
+	}
+	for _, w := range windows {
+		availability[w.TeacherID] = append(availability[w.TeacherID], solverSpan{day: w.Weekday, start: w.StartMinute, end: w.EndMinute})
+	}
+	return nil
+}
+
+// solverLessonLengths splits a group's weekly hours into lessons; time left
+// over after whole lessons becomes one shorter lesson
+func solverLessonLengths(group string, weeklyHours float64, lessonMinutes int) ([]int, error) {
+	if lessonMinutes == 0 {
+		lessonMinutes = defaultSolverLessonMinutes
+	}
+	weekly := int(math.Round(weeklyHours * 60))
+	if weekly%solverStepMinutes != 0 || lessonMinutes%solverStepMinutes != 0 {
+		return nil, errors.Validation(fmt.Sprintf("group %s: weekly hours and lesson length must be whole multiples of %d minutes", group, solverStepMinutes))
+	}
+	var lengths []int
+	for ; weekly >= lessonMinutes; weekly -= lessonMinutes {
+		lengths = append(lengths, lessonMinutes)
+	}
+	if weekly > 0 {
+		lengths = append(lengths, weekly)
+	}
+	return lengths, nil
+}
+
+// weeklyBookingsExcept collects what the solver must work around: the weekly
+// slots of groups not being scheduled and upcoming exams and events
+func weeklyBookingsExcept(db *gorm.DB, groupIDs []uuid.UUID) ([]scheduleBooking, error) {
+	excluded := make(map[uuid.UUID]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		excluded[id] = true
+	}
+
+	var bookings []scheduleBooking
+	var timetables []models.Timetable
+	if err := db.Preload("Groups").Preload("Slots").Find(&timetables).Error; err != nil {
+		return nil, errors.DatabaseError("finding timetables", err)
+	}
+	for i := range timetables {
+		tt := &timetables[i]
+		if len(tt.Groups) == 0 {
+			bookings = append(bookings, timetableBookings(tt, nil)...)
+		}
+		for j := range tt.Groups {
+			if !excluded[tt.Groups[j].ID] {
+				bookings = append(bookings, timetableBookings(tt, &tt.Groups[j])...)
+			}
+		}
+	}
+
+	now := time.Now()
+	var exams []models.Exam
+	if err := db.Select("id", "title", "group_id", "start_time", "end_time", "location", "status").
+		Where("status <> ? AND end_time > ?", models.ExamStatusCancelled, now).Find(&exams).Error; err != nil {
+		return nil, errors.DatabaseError("finding exams", err)
+	}
+	for i := range exams {
+		bookings = append(bookings, examBooking(&exams[i]))
+	}
+
+	events, err := eventsBetween(db, bookedEvents, now, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		bookings = append(bookings, eventBooking(&events[i]))
+	}
+	return bookings, nil
+}
+
+// solverCandidates lists every day, start and room a lesson of the group
+// could take on its own. Rejected options are counted by reason.
+func solverCandidates(group *solverGroup, minutes int, days []models.Weekday, dayStart, dayEnd int,
+	availability map[uuid.UUID][]solverSpan, fixed []scheduleBooking) ([]solverPlacement, map[string]int) {
+	windows, limited := availability[group.teacherID]
+	reasons := make(map[string]int)
+	var candidates []solverPlacement
+	for _, day := range days {
+		for start := dayStart; start+minutes <= dayEnd; start += solverStepMinutes {
+			span := solverSpan{day: day, start: start, end: start + minutes}
+			if limited && !solverCovered(windows, span) {
+				reasons[constraintAvailability]++
+				continue
+			}
+			for _, room := range group.rooms {
+				candidate := scheduleBooking{
+					Kind:      bookingTimetable,
+					GroupID:   group.group.ID,
+					TeacherID: group.teacherID,
+					Room:      room.Name,
+					Weekdays:  map[time.Weekday]bool{day.TimeWeekday(): true},
+					Start:     span.start,
+					End:       span.end,
+				}
+				if reason := solverClash(candidate, fixed); reason != "" {
+					reasons[reason]++
+					continue
+				}
+				candidates = append(candidates, solverPlacement{solverSpan: span, room: room})
+			}
+		}
+	}
+	return candidates, reasons
+}
+
+// solverClash returns why a weekly booking clashes with a fixed booking
+func solverClash(candidate scheduleBooking, fixed []scheduleBooking) string {
+	for _, other := range fixed {
+		if len(candidate.overlapDates(other)) == 0 {
+			continue
+		}
+		if reason := candidate.conflictReason(other, nil); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// solverCovered reports whether a span lies within one availability window
+func solverCovered(windows []solverSpan, span solverSpan) bool {
+	for _, w := range windows {
+		if w.day == span.day && w.start <= span.start && span.end <= w.end {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateIssue explains why no slot in the week suits a lesson
+func candidateIssue(group string, minutes int, reasons map[string]int) (string, string) {
+	if len(reasons) == 1 && reasons[constraintAvailability] > 0 {
+		return constraintAvailability, fmt.Sprintf("The teacher of %s has no %s window within the teaching day", group, formatMinutes(minutes))
+	}
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range []string{constraintAvailability, "teacher", "room", "group", "student"} {
+		if reasons[reason] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", strings.ReplaceAll(reason, "_", " "), reasons[reason]))
+		}
+	}
+	return constraintSchedule, fmt.Sprintf("No free slot for a %s lesson of %s; options rejected by %s", formatMinutes(minutes), group, strings.Join(parts, ", "))
+}
+
+// equipmentNote describes required equipment for an issue message
+func equipmentNote(equipment []string) string {
+	if len(equipment) == 0 {
+		return ""
+	}
+	return " with " + strings.Join(normalizeEquipment(equipment), ", ")
+}
+
+// formatMinutes formats a duration in minutes as e.g. "1h30m"
+func formatMinutes(minutes int) string {
+	return strings.TrimSuffix((time.Duration(minutes) * time.Minute).String(), "0s")
+}
+
+func newTimetableSolver(lessons []*solverLesson, maxPerDay int, noGaps bool) *timetableSolver {
+	// Most constrained lessons first, longer ones before shorter ones
+	sort.SliceStable(lessons, func(i, j int) bool {
+		a, b := lessons[i], lessons[j]
+		if len(a.candidates) != len(b.candidates) {
+			return len(a.candidates) < len(b.candidates)
+		}
+		return a.minutes > b.minutes
+	})
+	// A group's interchangeable lessons are placed in weekly order, so the
+	// same timetable is not searched once per ordering of them
+	last := make(map[string]int)
+	for i, lesson := range lessons {
+		key := fmt.Sprintf("%s/%d", lesson.group.group.ID, lesson.minutes)
+		lesson.prev = -1
+		if j, ok := last[key]; ok {
+			lesson.prev = j
+		}
+		last[key] = i
+	}
+
+	return &timetableSolver{
+		lessons:   lessons,
+		maxPerDay: maxPerDay,
+		noGaps:    noGaps,
+		placed:    make([]solverPlacement, len(lessons)),
+		busy:      make(map[uuid.UUID][]solverSpan),
+		failures:  make([]int, len(lessons)),
+	}
+}
+
+// search places lessons from i onwards, reporting whether all fit
+func (s *timetableSolver) search(i int) bool {
+	if i == len(s.lessons) {
+		return true
+	}
+	lesson := s.lessons[i]
+	for _, p := range s.options(i) {
+		if s.steps >= maxSolverSteps {
+			return false
+		}
+		s.steps++
+		s.place(lesson, p, i)
+		if s.search(i + 1) {
+			return true
+		}
+		s.unplace(lesson, p)
+	}
+	s.failures[i]++
+	return false
+}
+
+// options returns the candidates for lesson i that fit what is placed so
+// far, best first
+func (s *timetableSolver) options(i int) []solverPlacement {
+	lesson := s.lessons[i]
+	groupID, teacherID := lesson.group.group.ID, lesson.group.teacherID
+
+	type option struct {
+		solverPlacement
+		groupDay, teacherDay int
+		adjacent             bool
+	}
+	var options []option
+	for _, p := range lesson.candidates {
+		if lesson.prev >= 0 && !solverBefore(s.placed[lesson.prev].solverSpan, p.solverSpan) {
+			continue
+		}
+		if s.clashes(p.room.ID, p.solverSpan) {
+			continue
+		}
+		groupDay, groupOK := s.fits(groupID, p.solverSpan)
+		teacherDay, teacherOK := s.fits(teacherID, p.solverSpan)
+		if !groupOK || !teacherOK {
+			continue
+		}
+		options = append(options, option{
+			solverPlacement: p,
+			groupDay:        groupDay,
+			teacherDay:      teacherDay,
+			adjacent:        s.adjacent(teacherID, p.solverSpan),
+		})
+	}
+
+	sort.SliceStable(options, func(a, b int) bool {
+		x, y := options[a], options[b]
+		if x.groupDay != y.groupDay {
+			return x.groupDay < y.groupDay
+		}
+		if x.adjacent != y.adjacent {
+			return x.adjacent
+		}
+		if x.teacherDay != y.teacherDay {
+			return x.teacherDay < y.teacherDay
+		}
+		return false // Candidates are already by day, time and room size
+	})
+	placements := make([]solverPlacement, len(options))
+	for j := range options {
+		placements[j] = options[j].solverPlacement
+	}
+	return placements
+}
+
+// fits checks a span against a group's or teacher's lessons on that day:
+// no overlap, the daily limit and, with no gaps, touching one of them. It
+// returns how many lessons are already on that day.
+func (s *timetableSolver) fits(id uuid.UUID, span solverSpan) (int, bool) {
+	sameDay, touching := 0, false
+	for _, other := range s.busy[id] {
+		if other.day != span.day {
+			continue
+		}
+		if other.overlaps(span) {
+			return 0, false
+		}
+		sameDay++
+		touching = touching || other.touches(span)
+	}
+	if s.maxPerDay > 0 && sameDay >= s.maxPerDay {
+		return sameDay, false
+	}
+	if s.noGaps && sameDay > 0 && !touching {
+		return sameDay, false
+	}
+	return sameDay, true
+}
+
+func (s *timetableSolver) clashes(id uuid.UUID, span solverSpan) bool {
+	for _, other := range s.busy[id] {
+		if other.overlaps(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *timetableSolver) adjacent(id uuid.UUID, span solverSpan) bool {
+	for _, other := range s.busy[id] {
+		if other.touches(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *timetableSolver) place(lesson *solverLesson, p solverPlacement, i int) {
+	s.placed[i] = p
+	for _, id := range []uuid.UUID{lesson.group.group.ID, lesson.group.teacherID, p.room.ID} {
+		s.busy[id] = append(s.busy[id], p.solverSpan)
+	}
+}
+
+func (s *timetableSolver) unplace(lesson *solverLesson, p solverPlacement) {
+	for _, id := range []uuid.UUID{lesson.group.group.ID, lesson.group.teacherID, p.room.ID} {
+		spans := s.busy[id]
+		s.busy[id] = spans[:len(spans)-1]
+	}
+}
+
+// proposedLessons returns the placed lessons by group, day and time
+func (s *timetableSolver) proposedLessons() []models.ProposedLesson {
+	lessons := make([]models.ProposedLesson, len(s.lessons))
+	for i, lesson := range s.lessons {
+		p := s.placed[i]
+		lessons[i] = models.ProposedLesson{
+			GroupID:     lesson.group.group.ID,
+			GroupName:   lesson.group.group.Name,
+			TeacherID:   lesson.group.teacherID,
+			Weekday:     p.day,
+			StartMinute: p.start,
+			EndMinute:   p.end,
+			RoomID:      p.room.ID,
+			Room:        p.room.Name,
+		}
+	}
+	sort.SliceStable(lessons, func(i, j int) bool {
+		a, b := lessons[i], lessons[j]
+		if a.GroupName != b.GroupName {
+			return a.GroupName < b.GroupName
+		}
+		return solverBefore(solverSpan{a.Weekday, a.StartMinute, a.EndMinute}, solverSpan{b.Weekday, b.StartMinute, b.EndMinute})
+	})
+	return lessons
+}
+
+// failureIssue names the group whose lessons the search most often could
+// not place
+func (s *timetableSolver) failureIssue() models.ProposalIssue {
+	worst := 0
+	for i := range s.failures {
+		if s.failures[i] > s.failures[worst] {
+			worst = i
+		}
+	}
+	group := s.lessons[worst].group.group
+	issue := models.ProposalIssue{GroupID: &group.ID, Constraint: constraintSchedule,
+		Message: fmt.Sprintf("The lessons of %s cannot be placed together with the other groups without a clash; add rooms, widen teacher availability or relax the daily limits", group.Name)}
+	if s.steps >= maxSolverSteps {
+		issue.Constraint = constraintSearchLimit
+		issue.Message = fmt.Sprintf("No timetable was found after trying %d placements; %s was the hardest group to place. Solve fewer groups at once or relax the constraints", maxSolverSteps, group.Name)
+	}
+	return issue
+}
+
+// solverBefore orders spans by weekday and start time
+func solverBefore(a, b solverSpan) bool {
+	if a.day != b.day {
+		return weekdayIndex(a.day) < weekdayIndex(b.day)
+	}
+	return a.start < b.start
+}
+
+// weekdayIndex returns 0 for Monday through 6 for Sunday
+func weekdayIndex(day models.Weekday) int {
+	return (int(day.TimeWeekday()) + 6) % 7
+}
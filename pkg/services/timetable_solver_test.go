@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimetableSolverService_ProposeAndCommit(t *testing.T) {
+	db := setupTestDB()
+	solver := NewTimetableSolverService(db)
+	rooms := NewRoomService(db)
+	ctx := context.Background()
+
+	roomA, _ := rooms.Create(ctx, dto.CreateRoomRequest{Name: "Room A", Capacity: 10})
+	lab, _ := rooms.Create(ctx, dto.CreateRoomRequest{Name: "Lab", Capacity: 20, Equipment: []string{"projector"}})
+
+	aziz := models.Teacher{Name: "Aziz", Surname: "Rahimov", Phone: "992900000051"}
+	db.Create(&aziz)
+	other := models.Teacher{Name: "Olim", Surname: "Karimov", Phone: "992900000052"}
+	db.Create(&other)
+
+	// Another group already holds Room A on Monday mornings
+	booked := models.Timetable{Classroom: "Room A", RoomID: &roomA.ID, StartTime: "09:00", EndTime: "11:00", Days: "Mon"}
+	db.Create(&booked)
+	assert.NoError(t, MigrateTimetableSlots(db))
+	db.Create(&models.Group{Name: "JS-1", TeacherID: other.ID, TimetableID: booked.ID, Capacity: 10})
+
+	goGroup := models.Group{Name: "GO-1", TeacherID: aziz.ID, Capacity: 10}
+	db.Create(&goGroup)
+	for _, phone := range []string{"992900000053", "992900000054"} {
+		db.Create(&models.Student{GroupID: goGroup.ID, Name: "Student", Surname: "Test", Phone: phone})
+	}
+	designGroup := models.Group{Name: "UI-1", TeacherID: aziz.ID, Capacity: 10}
+	db.Create(&designGroup)
+
+	req := dto.SolveTimetableRequest{
+		Groups: []dto.SolverGroupRequest{
+			{GroupID: goGroup.ID, WeeklyHours: 4},
+			{GroupID: designGroup.ID, WeeklyHours: 2, Equipment: []string{"Projector"}},
+		},
+		TeacherAvailability: []dto.TeacherAvailabilityWindow{
+			{TeacherID: aziz.ID, Weekday: "mon", StartTime: "09:00", EndTime: "13:00"},
+			{TeacherID: aziz.ID, Weekday: "tue", StartTime: "09:00", EndTime: "11:00"},
+		},
+		NoGaps: true,
+	}
+
+	// Eight hours do not fit into six available ones
+	req.Groups[0].WeeklyHours = 8
+	infeasible, err := solver.Propose(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ProposalInfeasible, infeasible.Status)
+	if assert.Len(t, infeasible.Issues, 1) {
+		assert.Equal(t, constraintAvailability, infeasible.Issues[0].Constraint)
+	}
+	_, err = solver.Commit(ctx, infeasible.ID)
+	assert.Error(t, err)
+
+	req.Groups[0].WeeklyHours = 4
+	proposal, err := solver.Propose(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ProposalDraft, proposal.Status)
+	assert.Empty(t, proposal.Issues)
+	if assert.Len(t, proposal.Lessons, 3) {
+		// GO-1 avoids the booked room and meets on both days; UI-1 needs the lab
+		assert.Equal(t, dto.ProposedLessonResponse{GroupID: goGroup.ID, GroupName: "GO-1", TeacherID: aziz.ID,
+			Weekday: models.Monday, StartTime: "11:00", EndTime: "13:00", RoomID: roomA.ID, Room: "Room A"}, proposal.Lessons[0])
+		assert.Equal(t, models.Tuesday, proposal.Lessons[1].Weekday)
+		assert.Equal(t, "Lab", proposal.Lessons[2].Room)
+		assert.Equal(t, "09:00", proposal.Lessons[2].StartTime)
+	}
+
+	committed, err := solver.Commit(ctx, proposal.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ProposalCommitted, committed.Status)
+	assert.Len(t, committed.TimetableIDs, 2)
+
+	var stored models.Group
+	db.Preload("Timetable.Slots").First(&stored, "id = ?", designGroup.ID)
+	if assert.NotNil(t, stored.Timetable) && assert.Len(t, stored.Timetable.Slots, 1) {
+		assert.Equal(t, lab.ID, *stored.Timetable.RoomID)
+		assert.Equal(t, "Mon", stored.Timetable.Days)
+	}
+
+	// A proposal is committed once and then kept
+	_, err = solver.Commit(ctx, proposal.ID)
+	assert.Error(t, err)
+	assert.Error(t, solver.Delete(ctx, proposal.ID))
+	assert.NoError(t, solver.Delete(ctx, infeasible.ID))
+}
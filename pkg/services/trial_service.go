@@ -0,0 +1,552 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"gorm.io/gorm"
+)
+
+// TrialService books free trial lessons for leads and waitlist prospects,
+// records how they went and reports how many became paying students
+type TrialService struct {
+	db *gorm.DB
+}
+
+// NewTrialService creates a new trial service
+func NewTrialService(db *gorm.DB) *TrialService {
+	return &TrialService{db: db}
+}
+
+// Book books a trial lesson in a group. The prospect joins one of the
+// group's lessons as a guest, so the lesson must have a free seat next to the
+// enrolled students and other guests. Booking moves a new or contacted lead
+// to the trial stage.
+func (s *TrialService) Book(ctx context.Context, req dto.BookTrialRequest, bookedBy *uuid.UUID) (*dto.TrialResponse, error) {
+	if (req.LeadID == nil) == (req.WaitlistID == nil) {
+		return nil, errors.Validation("Exactly one of lead_id or waitlist_id is required")
+	}
+	if req.SessionID == nil && req.Date == "" {
+		return nil, errors.Validation("date or session_id is required")
+	}
+
+	trial := models.TrialLesson{
+		ID:         uuid.New(),
+		LeadID:     req.LeadID,
+		WaitlistID: req.WaitlistID,
+		GroupID:    req.GroupID,
+		Status:     models.TrialBooked,
+		Notes:      req.Notes,
+		BookedBy:   bookedBy,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		group, err := lockGroup(tx, req.GroupID)
+		if err != nil {
+			return err
+		}
+
+		var lead *models.Lead
+		if req.LeadID != nil {
+			if lead, err = loadLead(tx, *req.LeadID); err != nil {
+				return err
+			}
+			if lead.Stage.IsClosed() || lead.ConvertedAt != nil {
+				return errors.New(errors.ErrCodeInvalidOperation, "Closed leads cannot book a trial lesson")
+			}
+			trial.ProspectName = strings.TrimSpace(lead.FirstName + " " + lead.LastName)
+			trial.ProspectEmail = lead.Email
+			trial.ProspectPhone = lead.Phone
+		} else {
+			var entry models.Waitlist
+			if err := tx.First(&entry, "id = ?", *req.WaitlistID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return errors.NotFoundWithID("Waitlist entry", req.WaitlistID.String())
+				}
+				return errors.DatabaseError("finding waitlist entry", err)
+			}
+			if entry.StudentID != nil {
+				return errors.Validation("Trial lessons are for prospects; the waitlist entry is an existing student")
+			}
+			if entry.Status != models.WaitlistPending && entry.Status != models.WaitlistNotified {
+				return errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("The waitlist entry is %s", entry.Status))
+			}
+			trial.ProspectName = entry.ProspectName
+			trial.ProspectEmail = entry.ProspectEmail
+			trial.ProspectPhone = entry.ProspectPhone
+		}
+
+		session, err := s.resolveSession(tx, group.ID, req)
+		if err != nil {
+			return err
+		}
+
+		var booked int64
+		if err := prospectTrials(tx, &trial).Where("session_id = ?", session.ID).Count(&booked).Error; err != nil {
+			return errors.DatabaseError("checking trial bookings", err)
+		}
+		if booked > 0 {
+			return errors.Conflict("The prospect already has a trial booked for this lesson")
+		}
+
+		// Guests sit with the enrolled students
+		var enrolled, guests int64
+		if err := tx.Model(&models.Student{}).Where("group_id = ?", group.ID).Count(&enrolled).Error; err != nil {
+			return errors.DatabaseError("checking group capacity", err)
+		}
+		if err := tx.Model(&models.TrialLesson{}).Where("session_id = ? AND status IN ?", session.ID,
+			[]models.TrialStatus{models.TrialBooked, models.TrialAttended}).Count(&guests).Error; err != nil {
+			return errors.DatabaseError("counting trial guests", err)
+		}
+		if int(enrolled+guests) >= group.Capacity {
+			return errors.New(errors.ErrCodeCapacityExceeded, "The lesson has no free seat for a trial").
+				WithDetail("capacity", group.Capacity).
+				WithDetail("enrolled", enrolled).
+				WithDetail("guests", guests)
+		}
+
+		trial.SessionID = session.ID
+		trial.CourseID = group.CourseID
+		trial.TeacherID = session.TeacherID
+		trial.Date = session.Date
+		if err := tx.Create(&trial).Error; err != nil {
+			return errors.DatabaseError("booking trial lesson", err)
+		}
+
+		if lead != nil && (lead.Stage == models.LeadNew || lead.Stage == models.LeadContacted) {
+			notes := fmt.Sprintf("Trial lesson booked with %s on %s", group.Name, session.Date.Format("2006-01-02"))
+			return moveLeadStage(tx, lead, models.LeadTrial, "", notes, bookedBy)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, trial.ID)
+}
+
+// GetByID retrieves a trial lesson
+func (s *TrialService) GetByID(ctx context.Context, id uuid.UUID) (*dto.TrialResponse, error) {
+	trial, err := s.find(s.db.Preload("Group").Preload("Session").Preload("Course").Preload("Teacher"), id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(trial), nil
+}
+
+// List lists trial lessons, latest first
+func (s *TrialService) List(ctx context.Context, filter dto.TrialFilter) ([]dto.TrialResponse, error) {
+	query := s.db.Model(&models.TrialLesson{})
+	if filter.GroupID != "" {
+		query = query.Where("group_id = ?", filter.GroupID)
+	}
+	if filter.TeacherID != "" {
+		query = query.Where("teacher_id = ?", filter.TeacherID)
+	}
+	if filter.LeadID != "" {
+		query = query.Where("lead_id = ?", filter.LeadID)
+	}
+	if filter.WaitlistID != "" {
+		query = query.Where("waitlist_id = ?", filter.WaitlistID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	query, err := trialDateRange(query, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var trials []models.TrialLesson
+	if err := query.Preload("Group").Preload("Session").Preload("Course").Preload("Teacher").
+		Order("date DESC, created_at DESC").Find(&trials).Error; err != nil {
+		return nil, errors.DatabaseError("listing trial lessons", err)
+	}
+
+	responses := make([]dto.TrialResponse, len(trials))
+	for i := range trials {
+		responses[i] = *s.toResponse(&trials[i])
+	}
+	return responses, nil
+}
+
+// RecordOutcome records whether the prospect came to the lesson and the
+// teacher's assessment. A lead's history gets a note with the feedback.
+func (s *TrialService) RecordOutcome(ctx context.Context, id uuid.UUID, req dto.RecordTrialOutcomeRequest, recordedBy *uuid.UUID) (*dto.TrialResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		trial, err := s.find(tx.Preload("Session"), id)
+		if err != nil {
+			return err
+		}
+		if trial.Status == models.TrialCancelled {
+			return errors.New(errors.ErrCodeInvalidOperation, "The trial lesson was cancelled")
+		}
+		if trial.Date.After(dateOnly(time.Now())) {
+			return errors.New(errors.ErrCodeInvalidOperation, "The outcome can be recorded once the lesson has taken place")
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"feedback":    req.Feedback,
+			"recorded_by": recordedBy,
+			"recorded_at": now,
+		}
+		if *req.Attended {
+			if req.Outcome == "" {
+				return errors.Validation("outcome is required when the prospect attended")
+			}
+			updates["status"] = models.TrialAttended
+			updates["outcome"] = req.Outcome
+			if trial.Session != nil {
+				if err := markSessionHeld(tx, trial.Session); err != nil {
+					return err
+				}
+			}
+		} else {
+			updates["status"] = models.TrialNoShow
+			updates["outcome"] = ""
+		}
+		if err := tx.Model(&models.TrialLesson{}).Where("id = ?", trial.ID).Updates(updates).Error; err != nil {
+			return errors.DatabaseError("recording trial outcome", err)
+		}
+
+		if trial.LeadID == nil {
+			return nil
+		}
+		summary := "Did not attend the trial lesson"
+		if *req.Attended {
+			summary = fmt.Sprintf("Attended the trial lesson: %s", strings.ReplaceAll(string(req.Outcome), "_", " "))
+		}
+		activity := models.LeadActivity{
+			ID:         uuid.New(),
+			LeadID:     *trial.LeadID,
+			UserID:     recordedBy,
+			Type:       models.LeadActivityNote,
+			Summary:    summary,
+			Details:    req.Feedback,
+			OccurredAt: now,
+		}
+		if err := tx.Create(&activity).Error; err != nil {
+			return errors.DatabaseError("creating lead activity", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Cancel cancels a booked trial lesson, freeing its seat
+func (s *TrialService) Cancel(ctx context.Context, id uuid.UUID, req dto.CancelTrialRequest) (*dto.TrialResponse, error) {
+	trial, err := s.find(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if trial.Status != models.TrialBooked {
+		return nil, errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("Cannot cancel a trial that is %s", trial.Status))
+	}
+
+	if err := s.db.Model(&models.TrialLesson{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":              models.TrialCancelled,
+		"cancellation_reason": req.Reason,
+		"cancelled_at":        time.Now(),
+	}).Error; err != nil {
+		return nil, errors.DatabaseError("cancelling trial lesson", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// GetConversionReport counts booked, attended and converted trials in total,
+// per teacher and per course. An attended trial converts when its lead or
+// waitlist prospect has since become a student with a completed payment.
+func (s *TrialService) GetConversionReport(ctx context.Context, filter dto.TrialConversionFilter) (*dto.TrialConversionReport, error) {
+	query := s.db.Model(&models.TrialLesson{}).Where("status <> ?", models.TrialCancelled)
+	if filter.CourseID != "" {
+		query = query.Where("course_id = ?", filter.CourseID)
+	}
+	if filter.TeacherID != "" {
+		query = query.Where("teacher_id = ?", filter.TeacherID)
+	}
+	query, err := trialDateRange(query, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var trials []models.TrialLesson
+	if err := query.Preload("Course").Preload("Teacher").Order("date ASC").Find(&trials).Error; err != nil {
+		return nil, errors.DatabaseError("loading trial lessons", err)
+	}
+	converted, err := convertedTrials(s.db, trials)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.TrialConversionReport{From: filter.From, To: filter.To}
+	teachers := make(map[uuid.UUID]*dto.TrialConversionRow)
+	courses := make(map[uuid.UUID]*dto.TrialConversionRow)
+	for i := range trials {
+		trial := &trials[i]
+		teacher, ok := teachers[trial.TeacherID]
+		if !ok {
+			teacher = &dto.TrialConversionRow{ID: &trial.TeacherID}
+			if trial.Teacher != nil {
+				teacher.Name = strings.TrimSpace(trial.Teacher.Name + " " + trial.Teacher.Surname)
+			}
+			teachers[trial.TeacherID] = teacher
+		}
+		course, ok := courses[trial.CourseID]
+		if !ok {
+			course = &dto.TrialConversionRow{ID: &trial.CourseID}
+			if trial.Course != nil {
+				course.Name = trial.Course.Title
+			}
+			courses[trial.CourseID] = course
+		}
+
+		for _, row := range []*dto.TrialConversionRow{&report.Total, teacher, course} {
+			row.Booked++
+			switch trial.Status {
+			case models.TrialAttended:
+				row.Attended++
+				if converted[trial.ID] {
+					row.Converted++
+				}
+			case models.TrialNoShow:
+				row.NoShows++
+			}
+		}
+	}
+
+	report.Total.ConversionRate = trialConversionRate(&report.Total)
+	report.ByTeacher = sortedConversionRows(teachers)
+	report.ByCourse = sortedConversionRows(courses)
+	return report, nil
+}
+
+// resolveSession finds the lesson a trial is booked into, by session or by
+// date. Lessons in the past, cancelled or moved are rejected.
+func (s *TrialService) resolveSession(tx *gorm.DB, groupID uuid.UUID, req dto.BookTrialRequest) (*models.ClassSession, error) {
+	var date time.Time
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, errors.Validation("Invalid date format (YYYY-MM-DD)")
+		}
+		date = parsed
+	} else {
+		var session models.ClassSession
+		if err := tx.First(&session, "id = ? AND group_id = ?", *req.SessionID, groupID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New(errors.ErrCodeNotFound, "Class session not found in this group")
+			}
+			return nil, errors.DatabaseError("finding class session", err)
+		}
+		date = session.Date
+	}
+	if date.Before(dateOnly(time.Now())) {
+		return nil, errors.Validation("Trial lessons cannot be booked in the past")
+	}
+	return sessionForAttendance(tx, groupID, date, req.SessionID)
+}
+
+// find loads a trial lesson
+func (s *TrialService) find(db *gorm.DB, id uuid.UUID) (*models.TrialLesson, error) {
+	var trial models.TrialLesson
+	if err := db.First(&trial, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFoundWithID("Trial lesson", id.String())
+		}
+		return nil, errors.DatabaseError("finding trial lesson", err)
+	}
+	return &trial, nil
+}
+
+// prospectTrials scopes a query to the active trials of a trial's prospect
+func prospectTrials(db *gorm.DB, trial *models.TrialLesson) *gorm.DB {
+	query := db.Model(&models.TrialLesson{}).Where("status IN ?", []models.TrialStatus{models.TrialBooked, models.TrialAttended})
+	if trial.LeadID != nil {
+		return query.Where("lead_id = ?", *trial.LeadID)
+	}
+	return query.Where("waitlist_id = ?", *trial.WaitlistID)
+}
+
+// trialDateRange limits a trial query to lessons between two dates
+func trialDateRange(query *gorm.DB, from, to string) (*gorm.DB, error) {
+	if from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, errors.Validation("Invalid from date format (YYYY-MM-DD)")
+		}
+		query = query.Where("date >= ?", date)
+	}
+	if to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, errors.Validation("Invalid to date format (YYYY-MM-DD)")
+		}
+		query = query.Where("date <= ?", date)
+	}
+	return query, nil
+}
+
+// convertedTrials reports which attended trials belong to a prospect who
+// has become a paying student: a lead converted to a student directly or
+// through an enrolled application, or a waitlist prospect who was enrolled
+func convertedTrials(db *gorm.DB, trials []models.TrialLesson) (map[uuid.UUID]bool, error) {
+	var leadIDs, waitlistIDs []uuid.UUID
+	for _, trial := range trials {
+		if trial.Status != models.TrialAttended {
+			continue
+		}
+		if trial.LeadID != nil {
+			leadIDs = append(leadIDs, *trial.LeadID)
+		}
+		if trial.WaitlistID != nil {
+			waitlistIDs = append(waitlistIDs, *trial.WaitlistID)
+		}
+	}
+
+	type link struct {
+		ProspectID uuid.UUID
+		StudentID  uuid.UUID
+	}
+	var links []link
+	if len(leadIDs) > 0 {
+		var found []link
+		if err := db.Model(&models.Lead{}).Select("id AS prospect_id, student_id").
+			Where("id IN ? AND student_id IS NOT NULL", leadIDs).Scan(&found).Error; err != nil {
+			return nil, errors.DatabaseError("finding converted leads", err)
+		}
+		links = append(links, found...)
+		found = nil
+		if err := db.Model(&models.Student{}).Select("lead_id AS prospect_id, id AS student_id").
+			Where("lead_id IN ?", leadIDs).Scan(&found).Error; err != nil {
+			return nil, errors.DatabaseError("finding students of leads", err)
+		}
+		links = append(links, found...)
+		found = nil
+		if err := db.Model(&models.Application{}).Select("lead_id AS prospect_id, enrolled_as AS student_id").
+			Where("lead_id IN ? AND enrolled_as IS NOT NULL", leadIDs).Scan(&found).Error; err != nil {
+			return nil, errors.DatabaseError("finding enrolled applications", err)
+		}
+		links = append(links, found...)
+	}
+	if len(waitlistIDs) > 0 {
+		var found []link
+		if err := db.Model(&models.Waitlist{}).Select("id AS prospect_id, student_id").
+			Where("id IN ? AND student_id IS NOT NULL", waitlistIDs).Scan(&found).Error; err != nil {
+			return nil, errors.DatabaseError("finding enrolled waitlist entries", err)
+		}
+		links = append(links, found...)
+	}
+
+	converted := make(map[uuid.UUID]bool)
+	if len(links) == 0 {
+		return converted, nil
+	}
+	studentIDs := make([]uuid.UUID, len(links))
+	for i, l := range links {
+		studentIDs[i] = l.StudentID
+	}
+	var paying []uuid.UUID
+	if err := db.Model(&models.Payment{}).Distinct("student_id").
+		Where("student_id IN ? AND status = ?", studentIDs, models.PaymentCompleted).
+		Pluck("student_id", &paying).Error; err != nil {
+		return nil, errors.DatabaseError("finding paying students", err)
+	}
+	paid := make(map[uuid.UUID]bool, len(paying))
+	for _, id := range paying {
+		paid[id] = true
+	}
+	prospects := make(map[uuid.UUID]bool)
+	for _, l := range links {
+		if paid[l.StudentID] {
+			prospects[l.ProspectID] = true
+		}
+	}
+
+	for _, trial := range trials {
+		if trial.Status != models.TrialAttended {
+			continue
+		}
+		if (trial.LeadID != nil && prospects[*trial.LeadID]) || (trial.WaitlistID != nil && prospects[*trial.WaitlistID]) {
+			converted[trial.ID] = true
+		}
+	}
+	return converted, nil
+}
+
+// trialConversionRate returns the percentage of attended trials that converted
+func trialConversionRate(row *dto.TrialConversionRow) float64 {
+	if row.Attended == 0 {
+		return 0
+	}
+	return math.Round(float64(row.Converted)/float64(row.Attended)*10000) / 100
+}
+
+// sortedConversionRows returns report rows by name with their rates filled in
+func sortedConversionRows(rows map[uuid.UUID]*dto.TrialConversionRow) []dto.TrialConversionRow {
+	sorted := make([]dto.TrialConversionRow, 0, len(rows))
+	for _, row := range rows {
+		row.ConversionRate = trialConversionRate(row)
+		sorted = append(sorted, *row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// toResponse converts a trial lesson to its DTO
+func (s *TrialService) toResponse(t *models.TrialLesson) *dto.TrialResponse {
+	response := &dto.TrialResponse{
+		ID:                 t.ID,
+		LeadID:             t.LeadID,
+		WaitlistID:         t.WaitlistID,
+		ProspectName:       t.ProspectName,
+		ProspectEmail:      t.ProspectEmail,
+		ProspectPhone:      t.ProspectPhone,
+		GroupID:            t.GroupID,
+		SessionID:          t.SessionID,
+		CourseID:           t.CourseID,
+		TeacherID:          t.TeacherID,
+		Date:               t.Date.Format("2006-01-02"),
+		Status:             t.Status,
+		Notes:              t.Notes,
+		Outcome:            t.Outcome,
+		Feedback:           t.Feedback,
+		RecordedBy:         t.RecordedBy,
+		RecordedAt:         t.RecordedAt,
+		CancellationReason: t.CancellationReason,
+		CancelledAt:        t.CancelledAt,
+		BookedBy:           t.BookedBy,
+		CreatedAt:          t.CreatedAt,
+	}
+	if t.Group != nil {
+		response.GroupName = t.Group.Name
+	}
+	if t.Session != nil {
+		response.StartTime = t.Session.StartTime
+		response.EndTime = t.Session.EndTime
+		response.Room = t.Session.Room
+	}
+	if t.Course != nil {
+		response.CourseTitle = t.Course.Title
+	}
+	if t.Teacher != nil {
+		response.TeacherName = strings.TrimSpace(t.Teacher.Name + " " + t.Teacher.Surname)
+	}
+	return response
+}
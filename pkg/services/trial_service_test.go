@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrialService_BookingOutcomeAndConversion(t *testing.T) {
+	db := setupTestDB()
+	trials := NewTrialService(db)
+	attendance := NewAttendanceService(db)
+	ctx := context.Background()
+
+	teacher := models.Teacher{Name: "Farida", Surname: "Nazarova", Phone: "992900000081"}
+	db.Create(&teacher)
+	course := models.Course{Title: "Go Basics"}
+	db.Create(&course)
+	group := models.Group{Name: "GO-1", CourseID: course.ID, TeacherID: teacher.ID, Capacity: 3}
+	db.Create(&group)
+	db.Create(&models.Student{GroupID: group.ID, Name: "Enrolled", Surname: "Student", Phone: "992900000082"})
+	today := dateOnly(time.Now())
+	session := models.ClassSession{ID: uuid.New(), GroupID: group.ID, TeacherID: teacher.ID, Date: today,
+		StartTime: "09:00", EndTime: "11:00", Status: models.SessionScheduled}
+	db.Create(&session)
+
+	lead := models.Lead{ID: uuid.New(), FirstName: "Sino", LastName: "Rahimova", Phone: "992900000083",
+		Source: models.LeadSourcePhone, Stage: models.LeadNew, StageChangedAt: time.Now()}
+	db.Create(&lead)
+	entry := models.Waitlist{ID: uuid.New(), GroupID: group.ID, CourseID: course.ID, ProspectName: "Daler Saidov",
+		Status: models.WaitlistPending, RequestedAt: time.Now()}
+	db.Create(&entry)
+
+	booked, err := trials.Book(ctx, dto.BookTrialRequest{LeadID: &lead.ID, GroupID: group.ID, SessionID: &session.ID}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sino Rahimova", booked.ProspectName)
+	assert.Equal(t, teacher.ID, booked.TeacherID)
+	var staged models.Lead
+	db.First(&staged, "id = ?", lead.ID)
+	assert.Equal(t, models.LeadTrial, staged.Stage)
+
+	_, err = trials.Book(ctx, dto.BookTrialRequest{LeadID: &lead.ID, GroupID: group.ID, SessionID: &session.ID}, nil)
+	assert.Error(t, err)
+
+	guest, err := trials.Book(ctx, dto.BookTrialRequest{WaitlistID: &entry.ID, GroupID: group.ID, Date: today.Format("2006-01-02")}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, guest.SessionID)
+
+	// One student and two guests fill the lesson
+	other := models.Lead{ID: uuid.New(), FirstName: "Late", Phone: "992900000084",
+		Source: models.LeadSourcePhone, Stage: models.LeadContacted, StageChangedAt: time.Now()}
+	db.Create(&other)
+	_, err = trials.Book(ctx, dto.BookTrialRequest{LeadID: &other.ID, GroupID: group.ID, SessionID: &session.ID}, nil)
+	assert.True(t, errors.IsCapacityExceeded(err))
+
+	sheet, err := attendance.GetAttendanceSheet(ctx, group.ID.String(), today.Format("2006-01-02"), nil)
+	assert.NoError(t, err)
+	assert.Len(t, sheet.Students, 1)
+	assert.Len(t, sheet.Guests, 2)
+
+	attended, absent := true, false
+	_, err = trials.RecordOutcome(ctx, booked.ID, dto.RecordTrialOutcomeRequest{Attended: &attended}, nil)
+	assert.Error(t, err, "an attended trial needs an outcome")
+	recorded, err := trials.RecordOutcome(ctx, booked.ID, dto.RecordTrialOutcomeRequest{
+		Attended: &attended, Outcome: models.TrialRecommended, Feedback: "Strong basics"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TrialAttended, recorded.Status)
+	_, err = trials.RecordOutcome(ctx, guest.ID, dto.RecordTrialOutcomeRequest{Attended: &absent}, nil)
+	assert.NoError(t, err)
+
+	// The lead enrolls and pays
+	student := models.Student{GroupID: group.ID, Name: "Sino", Surname: "Rahimova", Phone: "992900000083", LeadID: &lead.ID}
+	db.Create(&student)
+	report, err := trials.GetConversionReport(ctx, dto.TrialConversionFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Total.Converted, "enrolled without paying")
+
+	db.Create(&models.Payment{ID: uuid.New(), StudentID: student.ID, Amount: 100, Method: "cash",
+		Status: models.PaymentCompleted, PaymentDate: time.Now()})
+	report, err = trials.GetConversionReport(ctx, dto.TrialConversionFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Total.Booked)
+	assert.Equal(t, 1, report.Total.Attended)
+	assert.Equal(t, 1, report.Total.NoShows)
+	assert.Equal(t, 1, report.Total.Converted)
+	assert.Equal(t, 100.0, report.Total.ConversionRate)
+	assert.Len(t, report.ByTeacher, 1)
+	assert.Equal(t, "Farida Nazarova", report.ByTeacher[0].Name)
+	assert.Len(t, report.ByCourse, 1)
+	assert.Equal(t, "Go Basics", report.ByCourse[0].Name)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"golang.org/x/crypto/bcrypt"
@@ -46,6 +47,7 @@ func (s *userService) Create(ctx context.Context, email, password string, role m
 	}
 
 	user := &models.User{
+		ID:        uuid.New(),
 		Email:     email,
 		Password:  string(hashedPassword),
 		Role:      role,
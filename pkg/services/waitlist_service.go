@@ -3,22 +3,46 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
+	"github.com/softclub-go-0-0/crm-service/pkg/logger"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// defaultWaitlistOfferTTL is how long a seat offer stays open when neither
+// the configuration nor the request sets an expiry
+const defaultWaitlistOfferTTL = 48 * time.Hour
+
+// waitlistQueueOrder ranks pending entries: urgent before high before normal,
+// then by queue position
+const waitlistQueueOrder = "CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 ELSE 2 END, position ASC, requested_at ASC"
+
 // WaitlistService handles waitlist operations
 type WaitlistService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	offerURL      string
+	offerTTL      time.Duration
+	notifications *NotificationService
 }
 
-// NewWaitlistService creates a new waitlist service
-func NewWaitlistService(db *gorm.DB) *WaitlistService {
-	return &WaitlistService{db: db}
+// NewWaitlistService creates a new waitlist service. Seat offers link to
+// offerURL followed by the offer token and stay open for offerTTL.
+func NewWaitlistService(db *gorm.DB, offerURL string, offerTTL time.Duration) *WaitlistService {
+	if offerTTL <= 0 {
+		offerTTL = defaultWaitlistOfferTTL
+	}
+	return &WaitlistService{
+		db:            db,
+		offerURL:      strings.TrimRight(offerURL, "/"),
+		offerTTL:      offerTTL,
+		notifications: NewNotificationService(db),
+	}
 }
 
 // AddToWaitlist adds a student or prospect to the waitlist
@@ -91,17 +115,46 @@ func (s *WaitlistService) ProcessWaitlistEntry(ctx context.Context, id uuid.UUID
 	}
 
 	now := time.Now()
+	wasOffered := entry.Status == models.WaitlistNotified
 
 	switch req.Action {
 	case "notify":
-		entry.Status = models.WaitlistNotified
-		entry.NotifiedAt = &now
-		entry.ExpiresAt = req.ExpiresAt
-		// TODO: Send notification logic here
+		expiresAt := now.Add(s.offerTTL)
+		if req.ExpiresAt != nil {
+			expiresAt = *req.ExpiresAt
+		}
+		if err := markOffered(&entry, now, expiresAt); err != nil {
+			return nil, err
+		}
+		// Hold the seat when one is free; a full group is notified without one
+		hold := models.SeatHold{
+			GroupID:    entry.GroupID,
+			Reason:     models.SeatHoldWaitlistOffer,
+			HolderName: entry.ProspectName,
+			WaitlistID: &entry.ID,
+			ExpiresAt:  expiresAt,
+		}
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return createSeatHold(tx, &hold)
+		})
+		if err != nil && !errors.IsCapacityExceeded(err) && !errors.IsNotFound(err) {
+			return nil, err
+		}
 	case "enroll":
-		entry.Status = models.WaitlistEnrolled
-		entry.EnrolledAt = &now
-		// TODO: Trigger enrollment logic (create student if prospect, add to group)
+		var freed []uuid.UUID
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if freed, err = enrollWaitlistEntry(ctx, tx, &entry, now); err != nil {
+				return err
+			}
+			return tx.Save(&entry).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, groupID := range freed {
+			s.SeatsFreed(ctx, groupID)
+		}
 	case "decline":
 		entry.Status = models.WaitlistDeclined
 	case "cancel":
@@ -119,19 +172,376 @@ func (s *WaitlistService) ProcessWaitlistEntry(ctx context.Context, id uuid.UUID
 		return nil, fmt.Errorf("failed to process waitlist entry: %w", err)
 	}
 
+	if req.Action == "notify" {
+		s.sendOffer(ctx, &entry)
+	}
+
 	// Reorder remaining pending entries if this one is removed from queue
 	if entry.Status != models.WaitlistPending && entry.Status != models.WaitlistNotified {
 		s.reorderWaitlist(entry.GroupID)
 	}
 
+	// A seat held by a declined or cancelled offer goes to the next in line
+	if wasOffered && (entry.Status == models.WaitlistDeclined || entry.Status == models.WaitlistCancelled) {
+		if err := releaseWaitlistHolds(s.db, entry.ID); err != nil {
+			return nil, err
+		}
+		s.SeatsFreed(ctx, entry.GroupID)
+	}
+
 	return s.toResponse(&entry), nil
 }
 
+// OfferOpenSeats offers each free seat of a group to the next pending entry
+// in the queue. Every offer holds its seat until it expires; expired offers
+// are closed first so their seats move on down the queue.
+func (s *WaitlistService) OfferOpenSeats(ctx context.Context, groupID uuid.UUID) ([]dto.WaitlistResponse, error) {
+	now := time.Now()
+	var offered []models.Waitlist
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the group so concurrent seat releases cannot offer a seat twice
+		if _, err := lockGroup(tx, groupID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Waitlist{}).
+			Where("group_id = ? AND status = ? AND expires_at < ?", groupID, models.WaitlistNotified, now).
+			Update("status", models.WaitlistExpired).Error; err != nil {
+			return errors.DatabaseError("expiring seat offers", err)
+		}
+
+		var queue []models.Waitlist
+		if err := tx.Where("group_id = ? AND status = ?", groupID, models.WaitlistPending).
+			Order(waitlistQueueOrder).Find(&queue).Error; err != nil {
+			return errors.DatabaseError("finding waitlist entries", err)
+		}
+		for i := range queue {
+			entry := &queue[i]
+			if err := markOffered(entry, now, now.Add(s.offerTTL)); err != nil {
+				return err
+			}
+			hold := models.SeatHold{
+				GroupID:    groupID,
+				Reason:     models.SeatHoldWaitlistOffer,
+				HolderName: entry.ProspectName,
+				WaitlistID: &entry.ID,
+				ExpiresAt:  *entry.ExpiresAt,
+			}
+			if err := createSeatHold(tx, &hold); err != nil {
+				if errors.IsCapacityExceeded(err) {
+					break
+				}
+				return err
+			}
+			if err := tx.Save(entry).Error; err != nil {
+				return errors.DatabaseError("offering seat", err)
+			}
+			offered = append(offered, *entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WaitlistResponse, len(offered))
+	for i := range offered {
+		s.sendOffer(ctx, &offered[i])
+		responses[i] = *s.toResponse(&offered[i])
+	}
+	return responses, nil
+}
+
+// SeatsFreed reacts to a seat becoming available in a group, e.g. when a
+// student leaves or the capacity is raised. Failures are logged rather than
+// returned so they never undo the change that freed the seat.
+func (s *WaitlistService) SeatsFreed(ctx context.Context, groupID uuid.UUID) {
+	if _, err := s.OfferOpenSeats(ctx, groupID); err != nil && !errors.IsNotFound(err) {
+		logger.Errorf("failed to offer freed seats of group %s: %v", groupID, err)
+	}
+}
+
+// ExpireOffers closes seat offers whose deadline has passed and cascades
+// their seats to the next entries in line. It returns the number of groups
+// whose offers moved on.
+func (s *WaitlistService) ExpireOffers(ctx context.Context, now time.Time) (int, error) {
+	var groupIDs []uuid.UUID
+	if err := s.db.Model(&models.Waitlist{}).
+		Where("status = ? AND expires_at < ?", models.WaitlistNotified, now).
+		Distinct().Pluck("group_id", &groupIDs).Error; err != nil {
+		return 0, errors.DatabaseError("finding expired seat offers", err)
+	}
+
+	for _, groupID := range groupIDs {
+		if _, err := s.OfferOpenSeats(ctx, groupID); err != nil {
+			return 0, err
+		}
+		s.reorderWaitlist(groupID)
+	}
+	return len(groupIDs), nil
+}
+
+// GetOffer returns what a prospect may see about their seat offer
+func (s *WaitlistService) GetOffer(ctx context.Context, token string) (*dto.WaitlistOfferResponse, error) {
+	entry, err := s.findOffer(s.db, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.toOfferResponse(entry), nil
+}
+
+// AcceptOffer enrolls the prospect behind an open seat offer, creating their
+// student record when they are not a student yet
+func (s *WaitlistService) AcceptOffer(ctx context.Context, token string) (*dto.WaitlistOfferResponse, error) {
+	now := time.Now()
+	var entry *models.Waitlist
+	var freed []uuid.UUID
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if entry, err = s.lockOffer(tx, token); err != nil {
+			return err
+		}
+		if err := ensureOfferOpen(entry, now); err != nil {
+			return err
+		}
+		freed, err = enrollWaitlistEntry(ctx, tx, entry, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(entry).Error; err != nil {
+			return errors.DatabaseError("accepting seat offer", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, groupID := range freed {
+		s.SeatsFreed(ctx, groupID)
+	}
+	return s.toOfferResponse(entry), nil
+}
+
+// DeclineOffer declines an open seat offer and passes the seat on
+func (s *WaitlistService) DeclineOffer(ctx context.Context, token string) (*dto.WaitlistOfferResponse, error) {
+	var entry *models.Waitlist
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if entry, err = s.lockOffer(tx, token); err != nil {
+			return err
+		}
+		if err := ensureOfferOpen(entry, time.Now()); err != nil {
+			return err
+		}
+
+		entry.Status = models.WaitlistDeclined
+		if err := tx.Model(&models.Waitlist{}).Where("id = ?", entry.ID).Update("status", entry.Status).Error; err != nil {
+			return errors.DatabaseError("declining seat offer", err)
+		}
+		return releaseWaitlistHolds(tx, entry.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reorderWaitlist(entry.GroupID)
+	s.SeatsFreed(ctx, entry.GroupID)
+	return s.toOfferResponse(entry), nil
+}
+
+// lockOffer locks the waitlist entry a seat offer token belongs to for the
+// rest of the transaction and loads it, so two answers to the same offer
+// cannot both find it open
+func (s *WaitlistService) lockOffer(tx *gorm.DB, token string) (*models.Waitlist, error) {
+	if token != "" {
+		var locked models.Waitlist
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&locked, "offer_token = ?", token).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return nil, errors.DatabaseError("locking seat offer", err)
+		}
+	}
+	return s.findOffer(tx, token)
+}
+
+// findOffer loads the waitlist entry a seat offer token belongs to
+func (s *WaitlistService) findOffer(db *gorm.DB, token string) (*models.Waitlist, error) {
+	if token == "" {
+		return nil, errors.NotFound("Seat offer")
+	}
+
+	var entry models.Waitlist
+	if err := db.Preload("Group").Preload("Course").First(&entry, "offer_token = ?", token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("Seat offer")
+		}
+		return nil, errors.DatabaseError("finding seat offer", err)
+	}
+	return &entry, nil
+}
+
+// ensureOfferOpen rejects offers that were already answered or have expired
+func ensureOfferOpen(entry *models.Waitlist, now time.Time) error {
+	if entry.Status != models.WaitlistNotified {
+		return errors.New(errors.ErrCodeInvalidOperation, fmt.Sprintf("This seat offer is no longer open (%s)", entry.Status))
+	}
+	if entry.ExpiresAt != nil && entry.ExpiresAt.Before(now) {
+		return errors.New(errors.ErrCodeInvalidOperation, "This seat offer has expired")
+	}
+	return nil
+}
+
+// markOffered turns a waitlist entry into an open seat offer with a fresh
+// accept/decline token
+func markOffered(entry *models.Waitlist, now, expiresAt time.Time) error {
+	token, err := generateSecureToken(24)
+	if err != nil {
+		return errors.Internal("failed to generate offer token", err)
+	}
+	entry.Status = models.WaitlistNotified
+	entry.NotifiedAt = &now
+	entry.ExpiresAt = &expiresAt
+	entry.OfferToken = token
+	return nil
+}
+
+// enrollWaitlistEntry places the person behind a waitlist entry in its group,
+// taking up the seat held for its offer. Existing students are transferred;
+// prospects become students, reusing a student already on file with the same
+// email. It returns the groups that lost a student on the way.
+func enrollWaitlistEntry(ctx context.Context, tx *gorm.DB, entry *models.Waitlist, now time.Time) ([]uuid.UUID, error) {
+	var freed []uuid.UUID
+	students := NewStudentService(tx)
+
+	var student models.Student
+	switch {
+	case entry.StudentID != nil:
+		if err := tx.First(&student, "id = ?", *entry.StudentID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NotFoundWithID("Student", entry.StudentID.String())
+			}
+			return nil, errors.DatabaseError("finding student", err)
+		}
+	case entry.ProspectEmail != "":
+		if err := tx.Where("email = ?", entry.ProspectEmail).Limit(1).Find(&student).Error; err != nil {
+			return nil, errors.DatabaseError("finding student", err)
+		}
+	}
+
+	// An open offer's hold is taken up by the enrollment
+	var hold models.SeatHold
+	if err := activeSeatHolds(tx, entry.GroupID, now).Where("waitlist_id = ?", entry.ID).
+		Limit(1).Find(&hold).Error; err != nil {
+		return nil, errors.DatabaseError("finding seat hold", err)
+	}
+	var holdID *uuid.UUID
+	if hold.ID != uuid.Nil {
+		holdID = &hold.ID
+	}
+
+	if student.ID == uuid.Nil {
+		name, surname := splitProspectName(entry.ProspectName)
+		if name == "" {
+			return nil, errors.Validation("The waitlist entry has no prospect name to enroll")
+		}
+		created, err := students.Create(ctx, entry.GroupID.String(), dto.CreateStudentRequest{
+			Name:    name,
+			Surname: surname,
+			Phone:   entry.ProspectPhone,
+			Email:   entry.ProspectEmail,
+			HoldID:  holdID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		student.ID = created.ID
+	} else if student.GroupID != entry.GroupID {
+		if _, err := students.Transfer(ctx, student.ID.String(), dto.TransferStudentRequest{
+			ToGroupID: entry.GroupID,
+			Reason:    models.ReasonOther,
+			Notes:     "Enrolled from the waitlist",
+			HoldID:    holdID,
+		}, uuid.Nil); err != nil {
+			return nil, err
+		}
+		freed = append(freed, student.GroupID)
+	} else if holdID != nil {
+		// Already in the group; the held seat is not needed
+		if err := releaseWaitlistHolds(tx, entry.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	entry.StudentID = &student.ID
+	entry.Status = models.WaitlistEnrolled
+	entry.EnrolledAt = &now
+	return freed, nil
+}
+
+// splitProspectName splits a prospect's full name into a first name and surname
+func splitProspectName(fullName string) (string, string) {
+	fields := strings.Fields(fullName)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return fields[0], strings.Join(fields[1:], " ")
+}
+
+// sendOffer tells the person behind a waitlist entry that a seat is theirs
+// if they accept it before the offer expires
+func (s *WaitlistService) sendOffer(ctx context.Context, entry *models.Waitlist) {
+	name, email, phone := entry.ProspectName, entry.ProspectEmail, entry.ProspectPhone
+	if entry.StudentID != nil {
+		var student models.Student
+		if err := s.db.First(&student, "id = ?", *entry.StudentID).Error; err == nil {
+			name = strings.TrimSpace(student.Name + " " + student.Surname)
+			if student.Email != "" {
+				email = student.Email
+			}
+			if student.Phone != "" {
+				phone = student.Phone
+			}
+		}
+	}
+
+	groupName := "the group"
+	var group models.Group
+	if err := s.db.First(&group, "id = ?", entry.GroupID).Error; err == nil {
+		groupName = group.Name
+	}
+
+	link := s.offerURL + "/" + entry.OfferToken
+	message := fmt.Sprintf("Hello %s,\n\nA seat has opened up in %s and it is yours if you want it. Accept or decline the offer before %s: %s",
+		name, groupName, entry.ExpiresAt.Format("02 Jan 2006 15:04"), link)
+
+	req := dto.SendNotificationRequest{
+		Subject:   fmt.Sprintf("A seat is available in %s", groupName),
+		Message:   message,
+		StudentID: entry.StudentID,
+		Metadata:  map[string]interface{}{"waitlist_id": entry.ID.String(), "offer_url": link},
+	}
+	switch {
+	case email != "":
+		req.Type, req.Recipient = models.NotificationEmail, email
+	case phone != "":
+		req.Type, req.Recipient = models.NotificationSMS, phone
+	default:
+		logger.Warnf("waitlist entry %s has no contact details for its seat offer", entry.ID)
+		return
+	}
+
+	if _, err := s.notifications.SendNotification(ctx, req); err != nil {
+		logger.Warnf("failed to send seat offer for waitlist entry %s: %v", entry.ID, err)
+	}
+}
+
 // reorderWaitlist re-calculates positions for a group's waitlist
 func (s *WaitlistService) reorderWaitlist(groupID uuid.UUID) {
 	var entries []models.Waitlist
 	s.db.Where("group_id = ? AND status = ?", groupID, models.WaitlistPending).
-		Order("priority DESC, requested_at ASC").
+		Order(waitlistQueueOrder).
 		Find(&entries)
 
 	for i, entry := range entries {
@@ -185,3 +595,21 @@ func (s *WaitlistService) toResponse(w *models.Waitlist) *dto.WaitlistResponse {
 	// Load relations if needed (omitted for brevity, usually done via Preload in Get methods)
 	return resp
 }
+
+// toOfferResponse converts a seat offer to its public DTO
+func (s *WaitlistService) toOfferResponse(w *models.Waitlist) *dto.WaitlistOfferResponse {
+	resp := &dto.WaitlistOfferResponse{
+		ProspectName: w.ProspectName,
+		Status:       w.Status,
+		ExpiresAt:    w.ExpiresAt,
+		EnrolledAt:   w.EnrolledAt,
+	}
+	if w.Group != nil {
+		resp.GroupName = w.Group.Name
+		resp.StartDate = &w.Group.StartDate
+	}
+	if w.Course != nil {
+		resp.CourseTitle = w.Course.Title
+	}
+	return resp
+}
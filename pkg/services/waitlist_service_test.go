@@ -2,18 +2,21 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/softclub-go-0-0/crm-service/pkg/dto"
+	"github.com/softclub-go-0-0/crm-service/pkg/errors"
 	"github.com/softclub-go-0-0/crm-service/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestWaitlistService_AddToWaitlist(t *testing.T) {
 	db := setupTestDB()
-	service := NewWaitlistService(db)
+	service := NewWaitlistService(db, "", 0)
 
 	groupID := uuid.New()
 	courseID := uuid.New()
@@ -37,7 +40,7 @@ func TestWaitlistService_AddToWaitlist(t *testing.T) {
 
 func TestWaitlistService_ProcessWaitlistEntry(t *testing.T) {
 	db := setupTestDB()
-	service := NewWaitlistService(db)
+	service := NewWaitlistService(db, "", 0)
 
 	// Create entry
 	entry := models.Waitlist{
@@ -61,3 +64,124 @@ func TestWaitlistService_ProcessWaitlistEntry(t *testing.T) {
 	assert.Equal(t, models.WaitlistNotified, resp.Status)
 	assert.NotNil(t, resp.NotifiedAt)
 }
+
+func TestWaitlistService_SeatOfferCascade(t *testing.T) {
+	db := setupTestDB()
+	service := NewWaitlistService(db, "https://example.com/offers/", time.Hour)
+	students := NewStudentService(db)
+	ctx := context.Background()
+
+	group := models.Group{Name: "GO-1", Capacity: 1}
+	db.Create(&group)
+	seated, err := students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Seated", Surname: "Student", Phone: "992900000051"})
+	assert.NoError(t, err)
+
+	first, _ := service.AddToWaitlist(ctx, dto.CreateWaitlistRequest{GroupID: group.ID, CourseID: group.CourseID,
+		ProspectName: "Jane Doe", ProspectEmail: "jane@example.com", ProspectPhone: "992900000052"})
+	urgent, _ := service.AddToWaitlist(ctx, dto.CreateWaitlistRequest{GroupID: group.ID, CourseID: group.CourseID,
+		ProspectName: "Ali Karimov", ProspectPhone: "992900000053", Priority: models.PriorityUrgent})
+
+	// The group is full, so nothing is offered
+	offered, err := service.OfferOpenSeats(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, offered)
+
+	// The freed seat goes to the urgent entry, although it joined later
+	assert.NoError(t, students.Delete(ctx, seated.ID.String()))
+	service.SeatsFreed(ctx, group.ID)
+
+	var entry models.Waitlist
+	db.First(&entry, "id = ?", urgent.ID)
+	assert.Equal(t, models.WaitlistNotified, entry.Status)
+	assert.NotEmpty(t, entry.OfferToken)
+	var sms models.Notification
+	assert.NoError(t, db.First(&sms, "recipient = ?", "992900000053").Error)
+	assert.Contains(t, sms.Message, "https://example.com/offers/"+entry.OfferToken)
+
+	// The open offer holds the seat
+	offered, err = service.OfferOpenSeats(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, offered)
+	_, err = students.Create(ctx, group.ID.String(), dto.CreateStudentRequest{Name: "Walk", Surname: "In", Phone: "992900000054"})
+	assert.True(t, errors.IsCapacityExceeded(err))
+
+	// Once it expires, the seat cascades to the next entry
+	past := time.Now().Add(-time.Minute)
+	db.Model(&entry).Update("expires_at", past)
+	db.Model(&models.SeatHold{}).Where("waitlist_id = ?", entry.ID).Update("expires_at", past)
+	_, err = service.AcceptOffer(ctx, entry.OfferToken)
+	assert.Error(t, err)
+	moved, err := service.ExpireOffers(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	var expired, next models.Waitlist
+	db.First(&expired, "id = ?", urgent.ID)
+	assert.Equal(t, models.WaitlistExpired, expired.Status)
+	db.First(&next, "id = ?", first.ID)
+	assert.Equal(t, models.WaitlistNotified, next.Status)
+
+	// Accepting enrolls the prospect as a new student
+	offer, err := service.AcceptOffer(ctx, next.OfferToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.WaitlistEnrolled, offer.Status)
+	assert.Equal(t, "GO-1", offer.GroupName)
+
+	var student models.Student
+	assert.NoError(t, db.First(&student, "email = ?", "jane@example.com").Error)
+	assert.Equal(t, group.ID, student.GroupID)
+	assert.Equal(t, "Jane", student.Name)
+	assert.Equal(t, "Doe", student.Surname)
+
+	// The offer's hold was taken up by the enrollment
+	var hold models.SeatHold
+	assert.NoError(t, db.First(&hold, "waitlist_id = ?", next.ID).Error)
+	assert.NotNil(t, hold.ConsumedAt)
+	assert.Equal(t, student.ID, *hold.StudentID)
+
+	_, err = service.DeclineOffer(ctx, next.OfferToken)
+	assert.Error(t, err)
+}
+
+func TestWaitlistService_DeclineOfferIsAtomic(t *testing.T) {
+	db := setupTestDB()
+	service := NewWaitlistService(db, "https://example.com/offers/", time.Hour)
+	ctx := context.Background()
+
+	group := models.Group{Name: "GO-1", Capacity: 1}
+	db.Create(&group)
+	waiting, _ := service.AddToWaitlist(ctx, dto.CreateWaitlistRequest{GroupID: group.ID, CourseID: group.CourseID,
+		ProspectName: "Jane Doe", ProspectEmail: "jane@example.com", ProspectPhone: "992900000061"})
+	offered, err := service.OfferOpenSeats(ctx, group.ID)
+	assert.NoError(t, err)
+	assert.Len(t, offered, 1)
+	var entry models.Waitlist
+	db.First(&entry, "id = ?", waiting.ID)
+
+	// Releasing the hold fails, so the offer stays open
+	assert.NoError(t, db.Callback().Update().Before("gorm:update").Register("test:fail_hold_release", func(tx *gorm.DB) {
+		if tx.Statement.Table == "seat_holds" {
+			tx.AddError(fmt.Errorf("connection lost"))
+		}
+	}))
+	_, err = service.DeclineOffer(ctx, entry.OfferToken)
+	assert.Error(t, err)
+	db.Callback().Update().Remove("test:fail_hold_release")
+
+	var open models.Waitlist
+	db.First(&open, "id = ?", entry.ID)
+	assert.Equal(t, models.WaitlistNotified, open.Status)
+
+	offer, err := service.DeclineOffer(ctx, entry.OfferToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.WaitlistDeclined, offer.Status)
+	var hold models.SeatHold
+	assert.NoError(t, db.First(&hold, "waitlist_id = ?", entry.ID).Error)
+	assert.NotNil(t, hold.ReleasedAt)
+
+	// The link cannot be answered twice
+	_, err = service.DeclineOffer(ctx, entry.OfferToken)
+	assert.Error(t, err)
+	_, err = service.AcceptOffer(ctx, entry.OfferToken)
+	assert.Error(t, err)
+}
@@ -23,6 +23,13 @@ import (
 )
 
 func setupRouter() *gin.Engine {
+	router, _ := setupRouterWithDB()
+	return router
+}
+
+// setupRouterWithDB also returns the database, for tests that seed records
+// the API cannot create on SQLite
+func setupRouterWithDB() (*gin.Engine, *gorm.DB) {
 	// Set up test config
 	os.Setenv("SERVER_ENVIRONMENT", "test")
 	os.Setenv("LOG_LEVEL", "debug")
@@ -40,10 +47,17 @@ func setupRouter() *gin.Engine {
 	// Auto Migrate all models
 	err = db.AutoMigrate(
 		&models.Teacher{},
+		&models.TeacherAvailability{},
 		&models.Course{},
 		&models.Student{},
 		&models.Group{},
 		&models.Timetable{},
+		&models.TimetableSlot{},
+		&models.TimetableProposal{},
+		&models.Room{},
+		&models.SeatHold{},
+		&models.RecordMerge{},
+		&models.TrialLesson{},
 		&models.Attendance{},
 		&models.Grade{},
 		&models.User{},
@@ -58,9 +72,26 @@ func setupRouter() *gin.Engine {
 		&models.Document{},
 		&models.Message{},
 		&models.Event{},
+		&models.CalendarFeed{},
+		&models.AcademicTerm{},
+		&models.TermBreak{},
+		&models.Holiday{},
+		&models.CoursePrerequisite{},
+		&models.SyllabusModule{},
+		&models.SyllabusLesson{},
+		&models.SessionSyllabusLesson{},
+		&models.Lead{},
+		&models.LeadActivity{},
 		&models.Application{},
+		&models.AccountInvite{},
 		&models.Exam{},
 		&models.ExamResult{},
+		&models.GradingScale{},
+		&models.GradingScaleVersion{},
+		&models.ClassSession{},
+		&models.MakeUpCredit{},
+		&models.AttendanceAlert{},
+		&models.AttendanceAlertSettings{},
 	)
 	if err != nil {
 		panic("failed to migrate database: " + err.Error())
@@ -86,15 +117,31 @@ func setupRouter() *gin.Engine {
 	messageService := services.NewMessageService(db)
 	analyticsService := services.NewAnalyticsService(db)
 	calendarService := services.NewCalendarService(db)
-	applicationService := services.NewApplicationService(db)
+	accountInviteService := services.NewAccountInviteService(db, "http://localhost:8080/public/account/invites", 0)
+	applicationService := services.NewApplicationService(db, accountInviteService)
 	examService := services.NewExamService(db)
 	portalService := services.NewPortalService(db)
 	parentService := services.NewParentService(db)
 	assignmentService := services.NewAssignmentService(db)
-	waitlistService := services.NewWaitlistService(db)
+	waitlistService := services.NewWaitlistService(db, "http://localhost:8080/public/waitlist/offers", 48*time.Hour)
 	bulkService := services.NewBulkService(db)
 	recurringInvoiceService := services.NewRecurringInvoiceService(db)
 	advancedSearchService := services.NewAdvancedSearchService(db)
+	gradingScaleService := services.NewGradingScaleService(db)
+	reportCardService := services.NewReportCardService(db)
+	certificateService := services.NewCertificateService(db)
+	classSessionService := services.NewClassSessionService(db)
+	attendanceAlertService := services.NewAttendanceAlertService(db)
+	roomService := services.NewRoomService(db)
+	timetableSolverService := services.NewTimetableSolverService(db)
+	academicTermService := services.NewAcademicTermService(db)
+	syllabusService := services.NewSyllabusService(db)
+	leadService := services.NewLeadService(db)
+	intakeService := services.NewIntakeService(db, "test-secret", "http://localhost:8080/public/applications/status")
+	seatService := services.NewSeatService(db)
+	duplicateService := services.NewDuplicateService(db)
+	trialService := services.NewTrialService(db)
+	calendarFeedService := services.NewCalendarFeedService(db, "UTC")
 
 	h := handlers.NewHandler(
 		teacherService,
@@ -125,6 +172,22 @@ func setupRouter() *gin.Engine {
 		bulkService,
 		recurringInvoiceService,
 		advancedSearchService,
+		gradingScaleService,
+		reportCardService,
+		certificateService,
+		classSessionService,
+		attendanceAlertService,
+		roomService,
+		timetableSolverService,
+		calendarFeedService,
+		academicTermService,
+		syllabusService,
+		leadService,
+		intakeService,
+		seatService,
+		duplicateService,
+		trialService,
+		accountInviteService,
 	)
 
 	gin.SetMode(gin.TestMode)
@@ -152,9 +215,33 @@ func setupRouter() *gin.Engine {
 		}
 	}
 
-	return router
+	// Stands in for AuthMiddleware, which stores the caller's ID under user_id
+	authenticated := router.Group("/")
+	authenticated.Use(func(c *gin.Context) {
+		if userID := c.GetHeader(testUserHeader); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	leads := authenticated.Group("/leads")
+	{
+		leads.POST("/", h.CreateLead)
+		leads.POST("/:leadID/stage", h.ChangeLeadStage)
+		leads.POST("/:leadID/activities", h.CreateLeadActivity)
+		leads.POST("/:leadID/convert", h.ConvertLead)
+	}
+	exams := authenticated.Group("/exams")
+	{
+		exams.POST("/", h.CreateExam)
+		exams.POST("/:examID/results", h.SubmitExamResult)
+	}
+
+	return router, db
 }
 
+// testUserHeader carries the authenticated user's ID in tests
+const testUserHeader = "X-Test-User-ID"
+
 func TestCRMWorkflow(t *testing.T) {
 	router := setupRouter()
 
@@ -209,15 +296,28 @@ func TestCRMWorkflow(t *testing.T) {
 	studentID := performRequest(t, router, "POST", fmt.Sprintf("/groups/%s/students/", groupID), studentReq)
 	assert.NotEmpty(t, studentID)
 
-	// 6. Mark Attendance
+	// 6. Mark Attendance for the most recent Monday lesson
+	lessonDate := time.Now()
+	for lessonDate.Weekday() != time.Monday {
+		lessonDate = lessonDate.AddDate(0, 0, -1)
+	}
 	attendanceReq := dto.CreateAttendanceRequest{
 		StudentID: parseUUID(studentID),
-		Date:      time.Now().Format("2006-01-02"),
+		Date:      lessonDate.Format("2006-01-02"),
 		Status:    "present",
 		Notes:     "On time",
 	}
 	performRequest(t, router, "POST", fmt.Sprintf("/groups/%s/attendance", groupID), attendanceReq)
 
+	// Tuesday has no lesson in the timetable, so attendance is rejected
+	attendanceReq.Date = lessonDate.AddDate(0, 0, 1).Format("2006-01-02")
+	jsonValue, _ := json.Marshal(attendanceReq)
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/groups/%s/attendance", groupID), bytes.NewBuffer(jsonValue))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
 	// 7. Add Grade
 	gradeReq := dto.CreateGradeRequest{
 		StudentID: parseUUID(studentID),
@@ -229,10 +329,96 @@ func TestCRMWorkflow(t *testing.T) {
 	performRequest(t, router, "POST", fmt.Sprintf("/groups/%s/grades", groupID), gradeReq)
 }
 
+func TestLeadPipeline(t *testing.T) {
+	router := setupRouter()
+	userID := uuid.New().String()
+
+	teacherID := performRequest(t, router, "POST", "/teachers", dto.CreateTeacherRequest{Name: "Zarina", Surname: "Aliyeva", Phone: "992900000011"})
+	courseID := performRequest(t, router, "POST", "/courses", dto.CreateCourseRequest{Title: "Python", MonthlyFee: 80, Duration: 3})
+	timetableID := performRequest(t, router, "POST", "/timetables", dto.CreateTimetableRequest{StartTime: "14:00", EndTime: "16:00", Days: "Tue,Thu", Classroom: "Room 3"})
+	groupID := performRequest(t, router, "POST", "/groups/", dto.CreateGroupRequest{Name: "PY-1", StartDate: time.Now(),
+		CourseID: parseUUID(courseID), TeacherID: parseUUID(teacherID), TimetableID: parseUUID(timetableID), Capacity: 10})
+
+	leadReq := dto.CreateLeadRequest{FirstName: "Rustam", LastName: "Nazarov", Phone: "992900000012", Source: models.LeadSourceWalkIn}
+
+	// Without a user the pipeline is closed
+	jsonValue, _ := json.Marshal(leadReq)
+	req, _ := http.NewRequest("POST", "/leads/", bytes.NewBuffer(jsonValue))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	leadID := performRequestAs(t, router, userID, "POST", "/leads/", leadReq)
+	assert.NotEmpty(t, leadID)
+	performRequestAs(t, router, userID, "POST", fmt.Sprintf("/leads/%s/activities", leadID),
+		dto.CreateLeadActivityRequest{Type: models.LeadActivityCall, Summary: "Asked about evening groups"})
+	performRequestAs(t, router, userID, "POST", fmt.Sprintf("/leads/%s/stage", leadID), dto.ChangeLeadStageRequest{Stage: models.LeadWon})
+	group := parseUUID(groupID)
+	performRequestAs(t, router, userID, "POST", fmt.Sprintf("/leads/%s/convert", leadID), dto.ConvertLeadRequest{Target: "student", GroupID: &group})
+}
+
+func TestExamResultEntry(t *testing.T) {
+	router, db := setupRouterWithDB()
+	userID := uuid.New().String()
+
+	teacherID := performRequest(t, router, "POST", "/teachers", dto.CreateTeacherRequest{Name: "Aziz", Surname: "Rahimov", Phone: "992900000021"})
+	courseID := performRequest(t, router, "POST", "/courses", dto.CreateCourseRequest{Title: "Go", MonthlyFee: 100, Duration: 3})
+	timetableID := performRequest(t, router, "POST", "/timetables", dto.CreateTimetableRequest{StartTime: "09:00", EndTime: "11:00", Days: "Mon,Wed", Classroom: "Room 5"})
+	groupID := performRequest(t, router, "POST", "/groups/", dto.CreateGroupRequest{Name: "GO-9", StartDate: time.Now(),
+		CourseID: parseUUID(courseID), TeacherID: parseUUID(teacherID), TimetableID: parseUUID(timetableID), Capacity: 10})
+	studentID := performRequest(t, router, "POST", fmt.Sprintf("/groups/%s/students/", groupID),
+		dto.CreateStudentRequest{Name: "Laylo", Surname: "Saidova", Phone: "992900000022"})
+
+	status := func(userID, path string, body interface{}) int {
+		jsonValue, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonValue))
+		req.Header.Set("Content-Type", "application/json")
+		if userID != "" {
+			req.Header.Set(testUserHeader, userID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Creating an exam needs a user; SQLite cannot store its metadata, so
+	// only the authentication is checked here
+	start := time.Now().Add(-2 * time.Hour)
+	examReq := dto.CreateExamRequest{Title: "Midterm", Type: models.ExamTypeMidterm, CourseID: parseUUID(courseID), GroupID: parseUUID(groupID),
+		StartTime: start, EndTime: start.Add(time.Hour), Duration: 60, TotalMarks: 100, PassingMarks: 50}
+	assert.Equal(t, http.StatusUnauthorized, status("", "/exams/", examReq))
+	assert.NotEqual(t, http.StatusUnauthorized, status(userID, "/exams/", examReq))
+
+	exam := models.Exam{ID: uuid.New(), Title: "Midterm", Type: models.ExamTypeMidterm, Status: models.ExamStatusCompleted,
+		CourseID: parseUUID(courseID), GroupID: parseUUID(groupID), StartTime: start, EndTime: start.Add(time.Hour),
+		Duration: 60, TotalMarks: 100, PassingMarks: 50}
+	assert.NoError(t, db.Omit("Metadata").Create(&exam).Error)
+
+	resultReq := dto.SubmitExamResultRequest{StudentID: parseUUID(studentID), MarksObtained: 80}
+	path := fmt.Sprintf("/exams/%s/results", exam.ID)
+	assert.Equal(t, http.StatusUnauthorized, status("", path, resultReq))
+	performRequestAs(t, router, userID, "POST", path, resultReq)
+
+	var result models.ExamResult
+	assert.NoError(t, db.First(&result, "exam_id = ?", exam.ID).Error)
+	assert.Equal(t, 80.0, result.MarksObtained)
+	assert.Equal(t, userID, result.GradedBy.String())
+}
+
 func performRequest(t *testing.T, router *gin.Engine, method, path string, body interface{}) string {
+	return performRequestAs(t, router, "", method, path, body)
+}
+
+// performRequestAs performs a request authenticated as a user, or
+// anonymously when userID is empty
+func performRequestAs(t *testing.T, router *gin.Engine, userID, method, path string, body interface{}) string {
 	jsonValue, _ := json.Marshal(body)
 	req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonValue))
 	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set(testUserHeader, userID)
+	}
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 